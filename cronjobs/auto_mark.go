@@ -2,26 +2,28 @@ package cronjobs
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/services/transactions"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func HandleTransactionAutoMark(extReq request.ExternalRequest, db postgresql.Databases) {
-	query := fmt.Sprintf(`LOWER(status) <> '%v'`, strings.ToLower(transactions.GetTransactionStatus("d")))
-	txb := models.Transaction{}
-	transactionsSlice, err := txb.GetAllByQuery(db.Transaction, query)
+func HandleTransactionAutoMark(extReq request.ExternalRequest, repo repository.Repositories) {
+	transactionsSlice, err := repo.Transactions.ListByFilter(models.TransactionFilter{
+		ExcludeStatuses: []string{transactions.GetTransactionStatus("d")},
+	})
 	if err != nil {
 		extReq.Logger.Error("error getting transactions: ", err.Error())
 		return
 	}
 
 	for _, tx := range transactionsSlice {
+		if stopping() {
+			return
+		}
 		business, _ := transactions.GetBusinessProfileByAccountID(extReq, extReq.Logger, tx.BusinessID)
 		if business.AutoTransactionStatusSettings {
 			dueDate, err := utility.UnFormatDueDate(tx.DueDate)
@@ -30,7 +32,7 @@ func HandleTransactionAutoMark(extReq request.ExternalRequest, db postgresql.Dat
 			} else {
 				if dueDate.After(time.Now()) {
 					user, _ := transactions.GetUserWithAccountID(extReq, tx.BusinessID)
-					_, err := transactions.TransactionDeliveredService(extReq, extReq.Logger, db, models.TransactionDeliveredRequest{
+					_, err := transactions.TransactionDeliveredService(extReq, extReq.Logger, repo, models.TransactionDeliveredRequest{
 						TransactionID: tx.TransactionID,
 						MilestoneID:   tx.MilestoneID,
 					}, user)
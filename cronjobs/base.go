@@ -1,14 +1,20 @@
 package cronjobs
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/vesicash/transactions-ms/external/request"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/internal/tracing"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -18,12 +24,43 @@ var (
 		"transactions-auto-close":       {CronJob: HandleTransactionAutoClose, Interval: time.Hour * 24},
 		"transaction-close":             {CronJob: HandleTransactionClose, Interval: time.Minute * 10},
 		"update-status":                 {CronJob: HandleUpdateStatus, Interval: time.Minute * 10},
+		"rate-limit-cleanup":            {CronJob: HandleRateLimitCleanup, Interval: time.Hour},
+		"request-nonce-cleanup":         {CronJob: HandleRequestNonceCleanup, Interval: time.Hour},
+		"deleted-transaction-purge":     {CronJob: HandleDeletedTransactionPurge, Interval: time.Hour * 24},
+		"domain-metrics":                {CronJob: HandleDomainMetrics, Interval: time.Minute * 5},
 	}
 
 	stopSignals = map[string]chan bool{}
+
+	// shutdown is closed by Shutdown. Schedulers stop on it, and jobs check it between items so a
+	// pass is never cut off halfway through a transaction.
+	shutdown     = make(chan struct{})
+	shutdownOnce sync.Once
+	schedulers   sync.WaitGroup
+
+	cronJobDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cronjob_duration_seconds",
+			Help:    "Duration of cron job runs, by job and outcome (success or panic).",
+			Buckets: []float64{0.1, 0.5, 1, 5, 15, 30, 60, 300},
+		},
+		[]string{"job", "outcome"},
+	)
+
+	cronJobLastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cronjob_last_success_timestamp_seconds",
+			Help: "Unix time the job last ran to completion.",
+		},
+		[]string{"job"},
+	)
 )
 
-type CronJob func(extReq request.ExternalRequest, db postgresql.Databases)
+func init() {
+	prometheus.MustRegister(cronJobDuration, cronJobLastSuccess)
+}
+
+type CronJob func(extReq request.ExternalRequest, repo repository.Repositories)
 
 type CronJobObject struct {
 	CronJob  CronJob
@@ -40,7 +77,7 @@ type UpdateCronJobRequest struct {
 	IntervalBase   string `json:"interval_base" validate:"required,oneof=second minute hour day week month year"`
 }
 
-func UpdateCronJobInterval(extReq request.ExternalRequest, db postgresql.Databases, jobName string, number int, base string) error {
+func UpdateCronJobInterval(extReq request.ExternalRequest, repo repository.Repositories, jobName string, number int, base string) error {
 	var (
 		interval time.Duration
 	)
@@ -80,30 +117,64 @@ func UpdateCronJobInterval(extReq request.ExternalRequest, db postgresql.Databas
 	return nil
 }
 
-func Scheduler(extReq request.ExternalRequest, db postgresql.Databases, mutex *sync.Mutex, jobName string, cronJob CronJob, interval time.Duration) {
+func Scheduler(extReq request.ExternalRequest, repo repository.Repositories, mutex *sync.Mutex, jobName string, cronJob CronJob, interval time.Duration) {
+	defer schedulers.Done()
+	stop := stopSignals[jobName]
+	markJobStarted(jobName, interval)
 	for {
-		select {
-		default:
+		if !stopping() {
 			mutex.Lock()
-			cronJob(extReq, db)
+			runCronJob(extReq, repo, jobName, cronJob)
 			mutex.Unlock()
-			time.Sleep(interval)
-		case <-stopSignals[jobName]:
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-stop:
 			// The stop signal has been received
+			markJobStopped(jobName)
 			utility.LogAndPrint(extReq.Logger, fmt.Sprintf("%v cronjob has been stopped", jobName))
 			return
+		case <-shutdown:
+			markJobStopped(jobName)
+			utility.LogAndPrint(extReq.Logger, fmt.Sprintf("%v cronjob stopped for shutdown", jobName))
+			return
 		}
 	}
 }
 
-func StartCronJob(extReq request.ExternalRequest, db postgresql.Databases, jobName string) {
+// runCronJob runs one pass of a job in its own trace. A pass that panics is logged and counted, and
+// the job carries on at its next interval.
+func runCronJob(extReq request.ExternalRequest, repo repository.Repositories, jobName string, cronJob CronJob) {
+	ctx, span := tracing.Tracer().Start(context.Background(), "cronjob."+jobName, trace.WithAttributes(attribute.String("cronjob.name", jobName)))
+	started := time.Now()
+	defer func() {
+		outcome := "success"
+		if r := recover(); r != nil {
+			outcome = "panic"
+			extReq.Logger.Error(fmt.Sprintf("%v cronjob panicked: %v", jobName, r))
+			span.SetStatus(codes.Error, fmt.Sprint(r))
+		} else {
+			cronJobLastSuccess.WithLabelValues(jobName).SetToCurrentTime()
+		}
+		cronJobDuration.WithLabelValues(jobName, outcome).Observe(time.Since(started).Seconds())
+		markJobRun(jobName, outcome)
+		span.End()
+	}()
+	cronJob(extReq.WithContext(ctx), repo.WithContext(ctx))
+}
+
+func StartCronJob(extReq request.ExternalRequest, repo repository.Repositories, jobName string) {
 	mutex := &sync.Mutex{}
 	jobName = strings.ToLower(jobName)
 	cronJob, ok := cronJobs[jobName]
-	if ok {
+	if ok && stopping() {
+		utility.LogAndPrint(extReq.Logger, fmt.Sprintf("not starting cronjob %s, shutting down", jobName))
+	} else if ok {
 		stopSignals[jobName] = make(chan bool)
+		schedulers.Add(1)
 		utility.LogAndPrint(extReq.Logger, fmt.Sprintf("starting cronjob: %s, interval:%v", jobName, cronJob.Interval))
-		go Scheduler(extReq, db, mutex, jobName, cronJob.CronJob, cronJob.Interval)
+		go Scheduler(extReq, repo, mutex, jobName, cronJob.CronJob, cronJob.Interval)
 	} else {
 		utility.LogAndPrint(extReq.Logger, fmt.Sprintf("Cronjob not found: %s", jobName))
 	}
@@ -111,21 +182,52 @@ func StartCronJob(extReq request.ExternalRequest, db postgresql.Databases, jobNa
 
 func StopCronJob(jobName string) {
 	jobName = strings.ToLower(jobName)
-	stopSignals[jobName] <- true
+	select {
+	case stopSignals[jobName] <- true:
+	case <-shutdown:
+	}
 }
 
-func RestartCronJob(extReq request.ExternalRequest, db postgresql.Databases, jobName string) {
+func RestartCronJob(extReq request.ExternalRequest, repo repository.Repositories, jobName string) {
 	StopCronJob(jobName)
-	StartCronJob(extReq, db, jobName)
+	StartCronJob(extReq, repo, jobName)
 }
 
-func SetupCronJobs(extReq request.ExternalRequest, db postgresql.Databases, selectedJobs []string) {
+func SetupCronJobs(extReq request.ExternalRequest, repo repository.Repositories, selectedJobs []string) {
 	// mutex := &sync.Mutex{}
 	for _, v := range selectedJobs {
 		jobName := strings.ToLower(v)
-		StartCronJob(extReq, db, jobName)
-		RestartCronJob(extReq, db, jobName)
+		StartCronJob(extReq, repo, jobName)
+		RestartCronJob(extReq, repo, jobName)
 		// StopCronJob(jobName)
 	}
-	select {}
+	<-shutdown
+}
+
+// Shutdown stops every scheduler and waits for running jobs to finish the item they are on. It
+// returns ctx's error if the jobs have not finished by the time ctx is done.
+func Shutdown(ctx context.Context) error {
+	shutdownOnce.Do(func() { close(shutdown) })
+
+	done := make(chan struct{})
+	go func() {
+		schedulers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func stopping() bool {
+	select {
+	case <-shutdown:
+		return true
+	default:
+		return false
+	}
 }
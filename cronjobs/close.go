@@ -7,38 +7,34 @@ import (
 
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/services/transactions"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func HandleTransactionClose(extReq request.ExternalRequest, db postgresql.Databases) {
-	nowTimeFormatted := time.Now().Format("2006-01-02 15:04:05")
-	query := fmt.Sprintf(`
-	due_date < '%v' 
-	and LOWER(status) = '%v' 
-	and LOWER(status) = '%v' 
-	and LOWER(status) = '%v' 
-	and LOWER(status) = '%v'`,
-		nowTimeFormatted,
-		strings.ToLower(transactions.GetTransactionStatus("closed")),
-		strings.ToLower(transactions.GetTransactionStatus("cr")),
-		strings.ToLower(transactions.GetTransactionStatus("cnf")),
-		strings.ToLower(transactions.GetTransactionStatus("cdc")),
-	)
-	txb := models.Transaction{}
-	transactionsSlice, err := txb.GetAllByQueryWithLimit(db.Transaction, query, 20)
+func HandleTransactionClose(extReq request.ExternalRequest, repo repository.Repositories) {
+	transactionsSlice, err := repo.Transactions.ListByFilter(models.TransactionFilter{
+		ExcludeStatuses: []string{
+			transactions.GetTransactionStatus("closed"),
+			transactions.GetTransactionStatus("cr"),
+			transactions.GetTransactionStatus("cnf"),
+			transactions.GetTransactionStatus("cdc"),
+		},
+		DueBefore: time.Now(),
+		Limit:     20,
+	})
 	if err != nil {
 		extReq.Logger.Error("error getting transactions: ", err.Error())
 		return
 	}
 
 	for _, tx := range transactionsSlice {
-		pty := models.TransactionParty{TransactionID: tx.TransactionID}
+		if stopping() {
+			return
+		}
 		amountPaid := tx.AmountPaid
 		transactionStatus := tx.Status
-		transactionCurrency := tx.Currency
-		parties, err := pty.GetAllByTransactionID(db.Transaction)
+		parties, err := repo.Parties.ListByTransactionID(tx.TransactionID)
 		if err != nil {
 			extReq.Logger.Error(fmt.Errorf("error getting parties for transaction %v", tx.TransactionID))
 		} else {
@@ -47,8 +43,7 @@ func HandleTransactionClose(extReq request.ExternalRequest, db postgresql.Databa
 				if !strings.EqualFold(party.Status, "accepted") {
 					// money has not been paid
 					// close transaction by setting status to closed
-					tx.Status = transactions.GetTransactionStatus("closed")
-					tx.UpdateAllFields(db.Transaction)
+					updateStatus(extReq, repo, &tx, "closed")
 					continueProcess = false
 				}
 			}
@@ -56,13 +51,10 @@ func HandleTransactionClose(extReq request.ExternalRequest, db postgresql.Databa
 			if continueProcess {
 				if amountPaid > 0 {
 					// do refund
-					refund(extReq, db, amountPaid, transactionCurrency, tx)
-					tx.Status = transactions.GetTransactionStatus("cr")
-					tx.UpdateAllFields(db.Transaction)
+					closeWithRefund(extReq, repo, &tx)
 					continueProcess = false
 				} else {
-					tx.Status = transactions.GetTransactionStatus("cnf")
-					tx.UpdateAllFields(db.Transaction)
+					updateStatus(extReq, repo, &tx, "cnf")
 				}
 			}
 
@@ -73,9 +65,7 @@ func HandleTransactionClose(extReq request.ExternalRequest, db postgresql.Databa
 						extReq.Logger.Error(fmt.Sprintf("error parsing due date %v for transaction %v", tx.DueDate, tx.TransactionID))
 					} else {
 						if dueDate.Before(time.Now()) {
-							refund(extReq, db, amountPaid, transactionCurrency, tx)
-							tx.Status = transactions.GetTransactionStatus("cr")
-							tx.UpdateAllFields(db.Transaction)
+							closeWithRefund(extReq, repo, &tx)
 						}
 					}
 					continueProcess = false
@@ -84,20 +74,18 @@ func HandleTransactionClose(extReq request.ExternalRequest, db postgresql.Databa
 
 			if continueProcess {
 				if statusInList(transactionStatus, []string{"dr", "ip", "af", "sr"}) {
-					refund(extReq, db, amountPaid, transactionCurrency, tx)
-					tx.Status = transactions.GetTransactionStatus("cr")
-					tx.UpdateAllFields(db.Transaction)
+					closeWithRefund(extReq, repo, &tx)
 					continueProcess = false
 				}
 			}
 
 			if continueProcess {
 				if statusInList(transactionStatus, []string{"anf", "draft"}) {
-					tx.Status = transactions.GetTransactionStatus("closed")
-					tx.UpdateAllFields(db.Transaction)
+					updateStatus(extReq, repo, &tx, "closed")
 					continueProcess = false
 				}
 			}
+			transactions.RecordStatusChange(tx, transactionStatus)
 		}
 
 	}
@@ -113,9 +101,17 @@ func statusInList(txStatus string, statusCodes []string) bool {
 	return false
 }
 
-func refund(extReq request.ExternalRequest, db postgresql.Databases, amountPaid float64, transactionCurrency string, transaction models.Transaction) {
-	buyer := models.TransactionParty{TransactionID: transaction.TransactionID, Role: "buyer"}
-	_, err := buyer.GetTransactionPartyByTransactionIDAndRole(db.Transaction)
+// closeWithRefund claims tx by moving it to closed and refunded before refunding the buyer, so a
+// run racing with another, or with a request that moved tx on first, never refunds a transaction it
+// did not close. The refund is of the row as claimed.
+func closeWithRefund(extReq request.ExternalRequest, repo repository.Repositories, tx *models.Transaction) {
+	if updateStatus(extReq, repo, tx, "cr") {
+		refund(extReq, repo, tx.AmountPaid, tx.Currency, *tx)
+	}
+}
+
+func refund(extReq request.ExternalRequest, repo repository.Repositories, amountPaid float64, transactionCurrency string, transaction models.Transaction) {
+	buyer, _, err := repo.Parties.GetByTransactionIDAndRole(transaction.TransactionID, "buyer")
 	if err != nil {
 		extReq.Logger.Error(fmt.Sprintf("error getting buyer party for transaction %v", transaction.TransactionID))
 		return
@@ -124,15 +120,16 @@ func refund(extReq request.ExternalRequest, db postgresql.Databases, amountPaid
 	recipientCurrency := strings.ToUpper(transactionCurrency)
 	senderCurrency := "ESCROW_" + strings.ToUpper(transactionCurrency)
 
-	_, err = transactions.DebitWallet(extReq, db, amountPaid, senderCurrency, buyer.AccountID, "no", "no", transaction.TransactionID)
+	_, err = transactions.DebitWallet(extReq, amountPaid, senderCurrency, buyer.AccountID, "no", "no", transaction.TransactionID)
 	if err != nil {
 		extReq.Logger.Error(fmt.Sprintf("error debiting buyer %v, walletcurrency:%v for transaction %v", buyer.AccountID, senderCurrency, transaction.TransactionID))
 		return
 	}
 
-	_, err = transactions.CreditWallet(extReq, db, amountPaid, recipientCurrency, buyer.AccountID, true, "no", "no", transaction.TransactionID)
+	_, err = transactions.CreditWallet(extReq, amountPaid, recipientCurrency, buyer.AccountID, true, "no", "no", transaction.TransactionID)
 	if err != nil {
 		extReq.Logger.Error(fmt.Sprintf("error crediting buyer %v, walletcurrency:%v for transaction %v", buyer.AccountID, recipientCurrency, transaction.TransactionID))
 		return
 	}
+	transactions.RecordRefund(transactionCurrency, amountPaid)
 }
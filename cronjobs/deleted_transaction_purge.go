@@ -0,0 +1,21 @@
+package cronjobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/services/transactions"
+)
+
+// HandleDeletedTransactionPurge permanently removes the transactions deleted longer ago than they
+// can be restored.
+func HandleDeletedTransactionPurge(extReq request.ExternalRequest, repo repository.Repositories) {
+	purged, err := repo.Transactions.PurgeDeleted(time.Now().Add(-transactions.DeletedTransactionRetention()))
+	if err != nil {
+		extReq.Logger.Error("error purging deleted transactions: ", err.Error())
+		return
+	}
+	extReq.Logger.Info(fmt.Sprintf("purged %v deleted transaction milestones", purged))
+}
@@ -0,0 +1,22 @@
+package cronjobs
+
+import (
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/services/transactions"
+)
+
+// HandleDomainMetrics refreshes the gauges for transactions by status and undecided disputes.
+func HandleDomainMetrics(extReq request.ExternalRequest, repo repository.Repositories) {
+	transactionCounts, err := repo.Transactions.CountByStatus()
+	if err != nil {
+		extReq.Logger.Error("error counting transactions: ", err.Error())
+		return
+	}
+	undecidedDisputes, err := repo.Disputes.CountUndecided()
+	if err != nil {
+		extReq.Logger.Error("error counting disputes: ", err.Error())
+		return
+	}
+	transactions.RecordDomainCounts(transactionCounts, undecidedDisputes)
+}
@@ -3,25 +3,28 @@ package cronjobs
 import (
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/services/transactions"
 )
 
-func HandleTransactionInspectionPeriod(extReq request.ExternalRequest, db postgresql.Databases) {
-	query := fmt.Sprintf(`LOWER(status) = '%v'`, strings.ToLower(transactions.GetTransactionStatus("d")))
-	txb := models.Transaction{}
-	transactionsSlice, err := txb.GetAllByQueryWithLimit(db.Transaction, query, 100)
+func HandleTransactionInspectionPeriod(extReq request.ExternalRequest, repo repository.Repositories) {
+	transactionsSlice, err := repo.Transactions.ListByFilter(models.TransactionFilter{
+		Statuses: []string{transactions.GetTransactionStatus("d")},
+		Limit:    100,
+	})
 	if err != nil {
 		extReq.Logger.Error("error getting transactions: ", err.Error())
 		return
 	}
 
 	for _, tx := range transactionsSlice {
+		if stopping() {
+			return
+		}
 		inspectionPeriodUnix, err := strconv.Atoi(tx.InspectionPeriod)
 		if err != nil {
 			extReq.Logger.Error(fmt.Sprintf("error parsing inspectionperiod %v for transaction %v", tx.InspectionPeriod, tx.TransactionID))
@@ -34,7 +37,7 @@ func HandleTransactionInspectionPeriod(extReq request.ExternalRequest, db postgr
 				} else {
 					user, _ := transactions.GetUserWithAccountID(extReq, tx.BusinessID)
 					if payment.IsPaid {
-						_, err := transactions.SatisfiedService(extReq, extReq.Logger, db, tx.TransactionID, user)
+						_, err := transactions.SatisfiedService(extReq, extReq.Logger, repo, tx.TransactionID, user)
 						if err != nil {
 							extReq.Logger.Error(fmt.Sprintf("error making transaction %v as satisfied", tx.TransactionID))
 						} else {
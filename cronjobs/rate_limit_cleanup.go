@@ -0,0 +1,19 @@
+package cronjobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+)
+
+// HandleRateLimitCleanup drops the rate limit counters of windows that have ended.
+func HandleRateLimitCleanup(extReq request.ExternalRequest, repo repository.Repositories) {
+	deleted, err := repo.RateLimits.DeleteExpired(time.Now())
+	if err != nil {
+		extReq.Logger.Error("error deleting expired rate limit counters: ", err.Error())
+		return
+	}
+	extReq.Logger.Info(fmt.Sprintf("deleted %v expired rate limit counters", deleted))
+}
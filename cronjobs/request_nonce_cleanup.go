@@ -0,0 +1,20 @@
+package cronjobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+)
+
+// HandleRequestNonceCleanup drops the nonces of signed requests whose timestamps are now too old to
+// be accepted anyway.
+func HandleRequestNonceCleanup(extReq request.ExternalRequest, repo repository.Repositories) {
+	deleted, err := repo.Nonces.DeleteExpired(time.Now())
+	if err != nil {
+		extReq.Logger.Error("error deleting expired request nonces: ", err.Error())
+		return
+	}
+	extReq.Logger.Info(fmt.Sprintf("deleted %v expired request nonces", deleted))
+}
@@ -0,0 +1,91 @@
+package cronjobs
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// staleAfter is how many intervals a running job may go without finishing a pass before it is
+// reported as unhealthy.
+const staleAfter = 2
+
+// JobStatus is what the scheduler knows about a job.
+type JobStatus struct {
+	Name        string     `json:"name"`
+	Running     bool       `json:"running"`
+	Interval    string     `json:"interval"`
+	LastRun     *time.Time `json:"last_run,omitempty"`
+	LastOutcome string     `json:"last_outcome,omitempty"`
+	Healthy     bool       `json:"healthy"`
+}
+
+type jobState struct {
+	running     bool
+	interval    time.Duration
+	lastRun     time.Time
+	lastOutcome string
+}
+
+var (
+	jobStatesMu sync.Mutex
+	jobStates   = map[string]*jobState{}
+)
+
+func getJobState(jobName string) *jobState {
+	state, ok := jobStates[jobName]
+	if !ok {
+		state = &jobState{}
+		jobStates[jobName] = state
+	}
+	return state
+}
+
+func markJobStarted(jobName string, interval time.Duration) {
+	jobStatesMu.Lock()
+	defer jobStatesMu.Unlock()
+	state := getJobState(jobName)
+	state.running, state.interval = true, interval
+}
+
+func markJobStopped(jobName string) {
+	jobStatesMu.Lock()
+	defer jobStatesMu.Unlock()
+	getJobState(jobName).running = false
+}
+
+func markJobRun(jobName, outcome string) {
+	jobStatesMu.Lock()
+	defer jobStatesMu.Unlock()
+	state := getJobState(jobName)
+	state.lastRun, state.lastOutcome = time.Now(), outcome
+}
+
+// Statuses reports every job that has been started since the service came up. A running job is
+// healthy while its last pass did not panic and finished within staleAfter intervals.
+func Statuses() []JobStatus {
+	jobStatesMu.Lock()
+	defer jobStatesMu.Unlock()
+
+	statuses := []JobStatus{}
+	for name, state := range jobStates {
+		status := JobStatus{
+			Name:        name,
+			Running:     state.running,
+			Interval:    state.interval.String(),
+			LastOutcome: state.lastOutcome,
+			Healthy:     true,
+		}
+		if !state.lastRun.IsZero() {
+			lastRun := state.lastRun
+			status.LastRun = &lastRun
+		}
+		if state.running {
+			stale := !state.lastRun.IsZero() && time.Since(state.lastRun) > staleAfter*state.interval
+			status.Healthy = state.lastOutcome != "panic" && !stale
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -1,43 +1,53 @@
 package cronjobs
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
-	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/services/transactions"
 )
 
-func HandleUpdateStatus(extReq request.ExternalRequest, db postgresql.Databases) {
-	query := fmt.Sprintf(`LOWER(status) = '%v'`, strings.ToLower(transactions.GetTransactionStatus("da")))
-	txb := models.Transaction{}
-	transactionsSlice, err := txb.GetAllByQueryWithLimit(db.Transaction, query, 20)
+func HandleUpdateStatus(extReq request.ExternalRequest, repo repository.Repositories) {
+	transactionsSlice, err := repo.Transactions.ListByFilter(models.TransactionFilter{
+		Statuses: []string{transactions.GetTransactionStatus("da")},
+		Limit:    20,
+	})
 	if err != nil {
 		extReq.Logger.Error("error getting transactions: ", err.Error())
 		return
 	}
 
 	for _, tx := range transactionsSlice {
+		if stopping() {
+			return
+		}
 		extReq.Logger.Info(fmt.Sprintf("processing update status job for transaction with id: %v", tx.ID))
-		createActivityLog(extReq, db, tx, "cdp")
+		if !createActivityLog(extReq, repo, &tx, "cdp") {
+			continue
+		}
 		_, err := transactions.ListPayment(extReq, tx.TransactionID)
 		if err != nil {
 			extReq.Logger.Error("error getting payment record for transaction %v", tx.TransactionID)
+		} else if sendTransactionConfirmed(extReq, repo, tx) != nil {
+			createActivityLog(extReq, repo, &tx, "cmdp")
 		} else {
-			sendTransactionConfirmed(extReq, db, tx)
-			createActivityLog(extReq, db, tx, "cdc")
+			createActivityLog(extReq, repo, &tx, "cdc")
 		}
 	}
 }
 
-func createActivityLog(extReq request.ExternalRequest, db postgresql.Databases, tx models.Transaction, statusCode string) {
+// createActivityLog moves tx to the status statusCode stands for and logs it, reporting false when
+// the transaction could not be moved.
+func createActivityLog(extReq request.ExternalRequest, repo repository.Repositories, tx *models.Transaction, statusCode string) bool {
 	transactionType := tx.Type
-	tx.Status = transactions.GetTransactionStatus(statusCode)
-	tx.UpdateAllFields(db.Transaction)
+	previousStatus := tx.Status
+	if !updateStatus(extReq, repo, tx, statusCode) {
+		return false
+	}
+	transactions.RecordStatusChange(*tx, previousStatus)
 
 	var transactionTitle string
 	transactionTitleSlice := strings.Split(tx.Title, ";")
@@ -49,47 +59,60 @@ func createActivityLog(extReq request.ExternalRequest, db postgresql.Databases,
 		cType = "current transaction"
 	}
 
-	var description string
+	var description, eventType string
 	if strings.EqualFold(statusCode, "cdp") {
+		eventType = models.ActivityPaymentProcessing
 		description = fmt.Sprintf("Payment for %v is currently being processed", cType)
 	} else if strings.EqualFold(statusCode, "cdc") {
+		eventType = models.ActivityPaymentDisbursed
 		description = fmt.Sprintf("Payment for %v is disbursed successfully", cType)
+	} else if strings.EqualFold(statusCode, "cmdp") {
+		eventType = models.ActivityTransactionStatusChange
+		description = fmt.Sprintf("Payment for %v could not be disbursed and is pending manual disbursement", cType)
 	}
 
 	activityLog := models.ActivityLog{
-		TransactionID: tx.TransactionID,
-		Description:   description,
+		TransactionID:  tx.TransactionID,
+		MilestoneID:    tx.MilestoneID,
+		EventType:      eventType,
+		ActorRole:      "system",
+		PreviousStatus: previousStatus,
+		NewStatus:      tx.Status,
+		Description:    description,
 	}
-	activityLog.CreateActivityLog(db.Transaction)
+	repo.ActivityLogs.Create(&activityLog)
+	return true
 }
 
-func sendTransactionConfirmed(extReq request.ExternalRequest, db postgresql.Databases, transaction models.Transaction) {
-	buyer := models.TransactionParty{TransactionID: transaction.TransactionID, Role: "buyer"}
-	_, err := buyer.GetTransactionPartyByTransactionIDAndRole(db.Transaction)
+// updateStatus moves tx from the status it was read at to the one statusCode stands for. An update
+// racing with another is retried on the reloaded row, unless that row has left the status it was
+// read at, which means someone else acted on it first. It reports false, after logging why, when tx
+// was not moved.
+func updateStatus(extReq request.ExternalRequest, repo repository.Repositories, tx *models.Transaction, statusCode string) bool {
+	from, to := tx.Status, transactions.GetTransactionStatus(statusCode)
+	updated, err := transactions.UpdateTransactionWithRetry(repo, tx, func(t *models.Transaction) bool {
+		if t.Status != from {
+			return false
+		}
+		t.Status = to
+		return true
+	})
 	if err != nil {
-		extReq.Logger.Error(fmt.Sprintf("error getting buyer party for transaction %v", transaction.TransactionID))
-		return
+		extReq.Logger.Error(fmt.Sprintf("error updating status of transaction %v to %v: %v", tx.TransactionID, to, err.Error()))
+		return false
 	}
-
-	var milestoneRecipients []models.MileStoneRecipient
-	transactionCurrency := strings.ToUpper(transaction.Currency)
-	err = json.Unmarshal([]byte(transaction.Recipients), &milestoneRecipients)
-	if err != nil {
-		extReq.Logger.Error(fmt.Sprintf("error unmarshaling recipients for transaction %v", transaction.TransactionID))
-		return
+	if !updated {
+		extReq.Logger.Info(fmt.Sprintf("transaction %v moved to %v before it could be set to %v", tx.TransactionID, tx.Status, to))
 	}
+	return updated
+}
 
-	for _, recipient := range milestoneRecipients {
-		_, err := extReq.SendExternalRequest(request.WalletTransfer, external_models.WalletTransferRequest{
-			SenderAccountID:    buyer.AccountID,
-			RecipientAccountID: recipient.AccountID,
-			FinalAmount:        recipient.Amount,
-			SenderCurrency:     "ESCROW_" + transactionCurrency,
-			RecipientCurrency:  transactionCurrency,
-		})
-		if err != nil {
-			extReq.Logger.Error(fmt.Sprintf("wallet transfer error for recipient %v, transaction %v, error: %v", recipient.AccountID, transaction.TransactionID, err.Error()))
-		}
+// sendTransactionConfirmed releases the funds of transaction and returns why, after logging it,
+// when they could not be released.
+func sendTransactionConfirmed(extReq request.ExternalRequest, repo repository.Repositories, transaction models.Transaction) error {
+	err := transactions.ReleaseMilestoneFunds(extReq, repo, transaction)
+	if err != nil {
+		extReq.Logger.Error(fmt.Sprintf("error releasing funds for transaction %v: %v", transaction.TransactionID, err.Error()))
 	}
-
+	return err
 }
@@ -0,0 +1,189 @@
+package external
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/config"
+)
+
+const (
+	ServiceAuth         = "auth"
+	ServiceNotification = "notification"
+	ServicePayment      = "payment"
+	ServiceAppruve      = "appruve"
+	ServiceIPStack      = "ipstack"
+	ServiceMonnify      = "monnify"
+	ServiceRave         = "rave"
+)
+
+var (
+	defaultTimeout          = 15 * time.Second
+	defaultMaxRetries       = 2
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+	retryBaseDelay          = 200 * time.Millisecond
+	retryMaxDelay           = 2 * time.Second
+
+	ErrCircuitOpen = errors.New("circuit breaker open")
+)
+
+// transport is shared by every outbound request so connections to the same host are pooled.
+var transport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   20,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   5 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[time.Duration]*http.Client{}
+
+	breakersMu sync.Mutex
+	breakers   = map[string]*circuitBreaker{}
+)
+
+// ClientPolicy is how requests to one service are sent.
+type ClientPolicy struct {
+	Timeout          time.Duration
+	MaxRetries       int
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// GetClientPolicy reads the policy for service from the HTTP_CLIENT_* settings, falling back to
+// defaults for anything that is not configured.
+func GetClientPolicy(service string) ClientPolicy {
+	policy := ClientPolicy{
+		Timeout:          defaultTimeout,
+		MaxRetries:       defaultMaxRetries,
+		BreakerThreshold: defaultBreakerThreshold,
+		BreakerCooldown:  defaultBreakerCooldown,
+	}
+
+	conf := config.GetConfig()
+	if conf == nil {
+		return policy
+	}
+	c := conf.HttpClient
+	if c.Timeout > 0 {
+		policy.Timeout = time.Duration(c.Timeout) * time.Second
+	}
+	if timeout, ok := c.ServiceTimeouts[service]; ok && timeout > 0 {
+		policy.Timeout = time.Duration(timeout) * time.Second
+	}
+	if c.MaxRetries > 0 {
+		policy.MaxRetries = c.MaxRetries
+	}
+	if c.BreakerThreshold > 0 {
+		policy.BreakerThreshold = c.BreakerThreshold
+	}
+	if c.BreakerCooldown > 0 {
+		policy.BreakerCooldown = time.Duration(c.BreakerCooldown) * time.Second
+	}
+	return policy
+}
+
+func getHttpClient(timeout time.Duration) *http.Client {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	client, ok := clients[timeout]
+	if !ok {
+		client = &http.Client{Timeout: timeout, Transport: transport}
+		clients[timeout] = client
+	}
+	return client
+}
+
+func getCircuitBreaker(host string, policy ClientPolicy) *circuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	breaker, ok := breakers[host]
+	if !ok {
+		breaker = &circuitBreaker{threshold: policy.BreakerThreshold, cooldown: policy.BreakerCooldown}
+		breakers[host] = breaker
+	}
+	return breaker
+}
+
+// ResetCircuitBreakers closes every breaker, forgetting past failures.
+func ResetCircuitBreakers() {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	breakers = map[string]*circuitBreaker{}
+}
+
+// circuitBreaker stops requests to a host after threshold consecutive failures. Once cooldown has
+// passed a single probe request is let through; its outcome closes or reopens the breaker.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *circuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay is exponential backoff with full jitter.
+func retryDelay(attempt int) time.Duration {
+	ceiling := retryBaseDelay << attempt
+	if ceiling <= 0 || ceiling > retryMaxDelay {
+		ceiling = retryMaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
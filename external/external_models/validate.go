@@ -1,19 +1,5 @@
 package external_models
 
-type ValidateOnDBReq struct {
-	Table string      `json:"table"`
-	Type  string      `json:"type"`
-	Query string      `json:"query"`
-	Value interface{} `json:"value"`
-}
-
-type ValidateOnDBReqModel struct {
-	Status  string `json:"status"`
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    bool   `json:"data"`
-}
-
 type ValidateAuthorizationReq struct {
 	Type               string `validate:"required" json:"type"`
 	AuthorizationToken string `json:"authorization-token"`
@@ -32,4 +18,8 @@ type ValidateAuthorizationDataModel struct {
 	Status  bool   `json:"status"`
 	Message string `json:"message"`
 	Data    User   `json:"data"`
+	// Scopes are the permissions granted to the API key that was validated.
+	Scopes []string `json:"scopes"`
+	// Plan is the plan of the account or business that was authorized.
+	Plan string `json:"plan"`
 }
@@ -0,0 +1,160 @@
+package fakes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+)
+
+// AuthClient answers auth requests from its fields. A nil field makes the matching call fail, the
+// way an unreachable auth service would.
+type AuthClient struct {
+	User                     *external_models.User
+	UserProfile              *external_models.UserProfile
+	BusinessProfile          *external_models.BusinessProfile
+	Country                  *external_models.Country
+	BusinessCharge           *external_models.BusinessCharge
+	BankDetail               *external_models.BankDetail
+	UsersCredential          *external_models.UsersCredential
+	Authorize                *external_models.Authorize
+	ValidateAuthorizationRes *external_models.ValidateAuthorizationDataModel
+	AccessToken              external_models.AccessToken
+	// AccessTokens, when set, resolves GetAccessTokenByKey by public or private key instead of
+	// returning AccessToken.
+	AccessTokens map[string]external_models.AccessToken
+	// Credentials, when set, resolves ValidateAuthorization by bearer token or private key instead
+	// of returning ValidateAuthorizationRes.
+	Credentials map[string]external_models.User
+}
+
+func (a *AuthClient) GetUser(data external_models.GetUserRequestModel) (external_models.User, error) {
+	if a.User == nil {
+		return external_models.User{}, fmt.Errorf("user not provided")
+	}
+	return *a.User, nil
+}
+
+func (a *AuthClient) GetUserCredential(data external_models.GetUserCredentialModel) (external_models.GetUserCredentialResponse, error) {
+	return a.userCredential()
+}
+
+func (a *AuthClient) CreateUserCredential(data external_models.CreateUserCredentialModel) (external_models.GetUserCredentialResponse, error) {
+	return a.userCredential()
+}
+
+func (a *AuthClient) UpdateUserCredential(data external_models.UpdateUserCredentialModel) (external_models.GetUserCredentialResponse, error) {
+	return a.userCredential()
+}
+
+func (a *AuthClient) userCredential() (external_models.GetUserCredentialResponse, error) {
+	if a.UsersCredential == nil {
+		return external_models.GetUserCredentialResponse{Status: "error", Code: http.StatusBadRequest, Message: "user not provided"}, fmt.Errorf("user not provided")
+	}
+	return external_models.GetUserCredentialResponse{Status: "success", Code: http.StatusOK, Message: "success", Data: *a.UsersCredential}, nil
+}
+
+func (a *AuthClient) GetUserProfile(data external_models.GetUserProfileModel) (external_models.UserProfile, error) {
+	if a.UserProfile == nil {
+		return external_models.UserProfile{}, fmt.Errorf("user profile not provided")
+	}
+	return *a.UserProfile, nil
+}
+
+func (a *AuthClient) GetBusinessProfile(data external_models.GetBusinessProfileModel) (external_models.BusinessProfile, error) {
+	if a.BusinessProfile == nil {
+		return external_models.BusinessProfile{}, fmt.Errorf("business profile not provided")
+	}
+	return *a.BusinessProfile, nil
+}
+
+func (a *AuthClient) GetCountry(data external_models.GetCountryModel) (external_models.Country, error) {
+	if a.Country == nil {
+		return external_models.Country{}, fmt.Errorf("country not provided")
+	}
+	return *a.Country, nil
+}
+
+func (a *AuthClient) GetBankDetails(data external_models.GetBankDetailModel) (external_models.BankDetail, error) {
+	if a.BankDetail == nil {
+		return external_models.BankDetail{}, fmt.Errorf("BankDetail not provided")
+	}
+	return *a.BankDetail, nil
+}
+
+func (a *AuthClient) GetAccessToken() (external_models.AccessToken, error) {
+	return a.AccessToken, nil
+}
+
+func (a *AuthClient) GetAccessTokenByKey(key string) (external_models.AccessToken, error) {
+	if a.AccessTokens != nil {
+		accessToken, ok := a.AccessTokens[key]
+		if !ok {
+			return external_models.AccessToken{}, fmt.Errorf("access token not found")
+		}
+		return accessToken, nil
+	}
+	return a.AccessToken, nil
+}
+
+func (a *AuthClient) ValidateAuthorization(data external_models.ValidateAuthorizationReq) (external_models.ValidateAuthorizationDataModel, error) {
+	if a.Credentials != nil {
+		credential := data.AuthorizationToken
+		if credential == "" {
+			credential = data.VPrivateKey
+		}
+		user, ok := a.Credentials[credential]
+		if !ok {
+			return external_models.ValidateAuthorizationDataModel{Status: false, Message: "invalid credentials"}, nil
+		}
+		return external_models.ValidateAuthorizationDataModel{Status: true, Message: "authorized", Data: user}, nil
+	}
+	if a.ValidateAuthorizationRes == nil {
+		return external_models.ValidateAuthorizationDataModel{}, fmt.Errorf("validate authorization response not provided")
+	}
+	return *a.ValidateAuthorizationRes, nil
+}
+
+func (a *AuthClient) GetAuthorize(data external_models.GetAuthorizeModel) (external_models.GetAuthorizeResponse, error) {
+	return a.authorize()
+}
+
+func (a *AuthClient) CreateAuthorize(data external_models.CreateAuthorizeModel) (external_models.GetAuthorizeResponse, error) {
+	return a.authorize()
+}
+
+func (a *AuthClient) UpdateAuthorize(data external_models.UpdateAuthorizeModel) (external_models.GetAuthorizeResponse, error) {
+	return a.authorize()
+}
+
+func (a *AuthClient) authorize() (external_models.GetAuthorizeResponse, error) {
+	if a.Authorize == nil {
+		return external_models.GetAuthorizeResponse{Status: "error", Code: http.StatusBadRequest, Message: "authorize not provided"}, fmt.Errorf("authorize not provided")
+	}
+	return external_models.GetAuthorizeResponse{Status: "success", Code: http.StatusOK, Message: "success", Data: *a.Authorize}, nil
+}
+
+func (a *AuthClient) SetUserAuthorizationRequiredStatus(data external_models.SetUserAuthorizationRequiredStatusModel) (bool, error) {
+	return true, nil
+}
+
+func (a *AuthClient) GetBusinessCharge(data external_models.GetBusinessChargeModel) (external_models.BusinessCharge, error) {
+	if a.BusinessCharge == nil {
+		return external_models.BusinessCharge{}, fmt.Errorf("businessCharge not provided")
+	}
+	return *a.BusinessCharge, nil
+}
+
+func (a *AuthClient) InitBusinessCharge(data external_models.InitBusinessChargeModel) (external_models.BusinessCharge, error) {
+	if a.BusinessCharge == nil {
+		return external_models.BusinessCharge{}, fmt.Errorf("businessCharge not provided")
+	}
+	return *a.BusinessCharge, nil
+}
+
+func (a *AuthClient) SignupUser(data external_models.CreateUserRequestModel) (external_models.User, error) {
+	if a.User == nil {
+		return external_models.User{}, fmt.Errorf("user not provided")
+	}
+	return *a.User, nil
+}
@@ -0,0 +1,54 @@
+// Package fakes holds in-memory implementations of the external clients for use in tests.
+package fakes
+
+import (
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+var (
+	_ request.AuthClient         = (*AuthClient)(nil)
+	_ request.PaymentClient      = (*PaymentClient)(nil)
+	_ request.NotificationClient = (*NotificationClient)(nil)
+	_ request.MonnifyClient      = (*MonnifyClient)(nil)
+	_ request.AppruveClient      = (*AppruveClient)(nil)
+	_ request.RaveClient         = (*RaveClient)(nil)
+	_ request.IPStackClient      = (*IPStackClient)(nil)
+)
+
+// Clients is one fake per external service.
+type Clients struct {
+	Auth         *AuthClient
+	Payment      *PaymentClient
+	Notification *NotificationClient
+	Monnify      *MonnifyClient
+	Appruve      *AppruveClient
+	Rave         *RaveClient
+	IPStack      *IPStackClient
+}
+
+func New() *Clients {
+	return &Clients{
+		Auth:         &AuthClient{},
+		Payment:      &PaymentClient{},
+		Notification: &NotificationClient{},
+		Monnify:      &MonnifyClient{},
+		Appruve:      &AppruveClient{},
+		Rave:         &RaveClient{},
+		IPStack:      &IPStackClient{},
+	}
+}
+
+// ExternalRequest returns an ExternalRequest backed by the fakes.
+func (c *Clients) ExternalRequest(logger *utility.Logger) request.ExternalRequest {
+	return request.ExternalRequest{
+		Logger:       logger,
+		Auth:         c.Auth,
+		Payment:      c.Payment,
+		Notification: c.Notification,
+		Monnify:      c.Monnify,
+		Appruve:      c.Appruve,
+		Rave:         c.Rave,
+		IPStack:      c.IPStack,
+	}
+}
@@ -0,0 +1,101 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+)
+
+// Notification is a request the notification fake received.
+type Notification struct {
+	Name string
+	Data interface{}
+}
+
+// NotificationClient accepts every notification and keeps it so tests can check what was sent.
+type NotificationClient struct {
+	mu   sync.Mutex
+	sent []Notification
+}
+
+// Sent returns the notifications received so far, oldest first.
+func (n *NotificationClient) Sent() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Notification{}, n.sent...)
+}
+
+func (n *NotificationClient) record(name string, data interface{}) (interface{}, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, Notification{Name: name, Data: data})
+	return nil, nil
+}
+
+func (n *NotificationClient) SendVerificationEmail(data external_models.EmailNotificationRequest) (interface{}, error) {
+	return n.record("send_verification_email", data)
+}
+
+func (n *NotificationClient) SendWelcomeEmail(data external_models.AccountIDRequestModel) (interface{}, error) {
+	return n.record("send_welcome_email", data)
+}
+
+func (n *NotificationClient) SendEmailVerifiedNotification(data external_models.AccountIDRequestModel) (interface{}, error) {
+	return n.record("send_email_verified_notification", data)
+}
+
+func (n *NotificationClient) SendSMSToPhone(data external_models.SMSToPhoneNotificationRequest) (interface{}, error) {
+	return n.record("send_sms_to_phone", data)
+}
+
+func (n *NotificationClient) VerificationFailedNotification(data external_models.VerificationFailedModel) (interface{}, error) {
+	return n.record("verification_failed_notification", data)
+}
+
+func (n *NotificationClient) VerificationSuccessfulNotification(data external_models.VerificationSuccessfulModel) (interface{}, error) {
+	return n.record("verification_successful_notification", data)
+}
+
+func (n *NotificationClient) SendAuthorizedNotification(data external_models.AuthorizeNotificationRequest) (interface{}, error) {
+	return n.record("send_authorized_notification", data)
+}
+
+func (n *NotificationClient) SendAuthorizationNotification(data external_models.AuthorizeNotificationRequest) (interface{}, error) {
+	return n.record("send_authorization_notification", data)
+}
+
+func (n *NotificationClient) SendNewTransactionNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	return n.record("send_new_transaction_notification", data)
+}
+
+func (n *NotificationClient) SendTransactionAcceptedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	return n.record("send_transaction_accepted_notification", data)
+}
+
+func (n *NotificationClient) SendTransactionRejectedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	return n.record("send_transaction_rejected_notification", data)
+}
+
+func (n *NotificationClient) SendTransactionDeliveredRejectedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	return n.record("send_transaction_delivered_rejected_notification", data)
+}
+
+func (n *NotificationClient) SendDisputeOpenedNotification(data external_models.TransactionIDAccountIDRequestModel) (interface{}, error) {
+	return n.record("send_dispute_opened_notification", data)
+}
+
+func (n *NotificationClient) SendTransactionDeliveredNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	return n.record("send_transaction_delivered_notification", data)
+}
+
+func (n *NotificationClient) SendDueDateProposalNotification(data external_models.DueDateExtensionProposalRequestModel) (interface{}, error) {
+	return n.record("send_due_date_proposal_notification", data)
+}
+
+func (n *NotificationClient) SendDueDateExtendedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	return n.record("send_due_date_extended_notification", data)
+}
+
+func (n *NotificationClient) SendTransactionDeliveredAcceptedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	return n.record("send_transaction_delivered_accepted_notification", data)
+}
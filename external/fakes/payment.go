@@ -0,0 +1,71 @@
+package fakes
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+)
+
+// PaymentClient answers payment requests from its fields and keeps the wallet requests it receives.
+type PaymentClient struct {
+	Payment        *external_models.Payment
+	ListPaymentObj *external_models.ListPayment
+
+	mu        sync.Mutex
+	Refunds   []string
+	Transfers []external_models.WalletTransferRequest
+	Debits    []external_models.DebitWalletRequest
+	Credits   []external_models.CreditWalletRequest
+}
+
+func (p *PaymentClient) CreatePayment(data external_models.CreatePaymentRequestWithToken) (external_models.Payment, error) {
+	if p.Payment == nil {
+		return external_models.Payment{}, fmt.Errorf("payment not provided")
+	}
+	return *p.Payment, nil
+}
+
+func (p *PaymentClient) ListPayment(transactionID string) (external_models.ListPayment, error) {
+	if p.ListPaymentObj == nil {
+		return external_models.ListPayment{}, fmt.Errorf("ListPayment not provided")
+	}
+	return *p.ListPaymentObj, nil
+}
+
+func (p *PaymentClient) RequestManualRefund(transactionID string) (map[string]interface{}, error) {
+	p.mu.Lock()
+	p.Refunds = append(p.Refunds, transactionID)
+	p.mu.Unlock()
+	return nil, nil
+}
+
+func (p *PaymentClient) WalletTransfer(data external_models.WalletTransferRequest) (interface{}, error) {
+	p.mu.Lock()
+	p.Transfers = append(p.Transfers, data)
+	p.mu.Unlock()
+	return external_models.WalletTransferResponse{}, nil
+}
+
+func (p *PaymentClient) DebitWallet(data external_models.DebitWalletRequest) (external_models.WalletBalance, error) {
+	p.mu.Lock()
+	p.Debits = append(p.Debits, data)
+	p.mu.Unlock()
+	return p.walletBalance(data.BusinessID, data.Currency), nil
+}
+
+func (p *PaymentClient) CreditWallet(data external_models.CreditWalletRequest) (external_models.WalletBalance, error) {
+	p.mu.Lock()
+	p.Credits = append(p.Credits, data)
+	p.mu.Unlock()
+	return p.walletBalance(data.BusinessID, data.Currency), nil
+}
+
+func (p *PaymentClient) walletBalance(accountID int, currency string) external_models.WalletBalance {
+	return external_models.WalletBalance{
+		ID:        100,
+		AccountID: accountID,
+		Available: 1000000,
+		Currency:  currency,
+	}
+}
@@ -0,0 +1,54 @@
+package fakes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+)
+
+// MonnifyClient fails every call when Base64Key is empty, as the real client does without its key.
+type MonnifyClient struct {
+	Base64Key string
+}
+
+func (m *MonnifyClient) Login() (string, error) {
+	if m.Base64Key == "" {
+		return "", fmt.Errorf("monnify base64 key not in env: %v", m.Base64Key)
+	}
+	return "", nil
+}
+
+func (m *MonnifyClient) MatchBvnDetails(data external_models.MonnifyMatchBvnDetailsReq) (bool, error) {
+	if _, err := m.Login(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type AppruveClient struct{}
+
+func (a *AppruveClient) VerifyID(data external_models.AppruveReqModelFirst) (int, error) {
+	return http.StatusOK, nil
+}
+
+type RaveClient struct {
+	AccountName string
+}
+
+func (r *RaveClient) ResolveBankAccount(data external_models.ResolveAccountRequest) (string, error) {
+	if r.AccountName == "" {
+		return "", fmt.Errorf("account name not provided")
+	}
+	return r.AccountName, nil
+}
+
+type IPStackClient struct{}
+
+func (i *IPStackClient) ResolveIP(ip string) (external_models.IPStackResolveIPResponse, error) {
+	return external_models.IPStackResolveIPResponse{
+		Ip:          ip,
+		City:        "city",
+		CountryName: "name",
+	}, nil
+}
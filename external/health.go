@@ -0,0 +1,29 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Ping checks that the service at baseURL answers its health endpoint. It skips the retries and
+// circuit breakers of SendRequest so a readiness probe sees the service as it is right now.
+func Ping(ctx context.Context, baseURL string) error {
+	if baseURL == "" {
+		return fmt.Errorf("service url not configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/v2/health", nil)
+	if err != nil {
+		return err
+	}
+	res, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("health check returned status %v", res.StatusCode)
+	}
+	return nil
+}
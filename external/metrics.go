@@ -0,0 +1,28 @@
+package external
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var requestDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "external_request_duration_seconds",
+		Help:    "Duration of requests to other services, retries included, by service, request name and outcome (success or error).",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	},
+	[]string{"service", "request", "outcome"},
+)
+
+func init() {
+	prometheus.MustRegister(requestDuration)
+}
+
+func observeRequest(service, name string, started time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	requestDuration.WithLabelValues(service, name, outcome).Observe(time.Since(started).Seconds())
+}
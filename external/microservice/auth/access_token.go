@@ -19,12 +19,13 @@ func (r *RequestObj) GetAccessToken() (external_models.AccessToken, error) {
 		"v-app":        appKey,
 	}
 
-	err := r.getNewSendRequestObject(nil, headers, "").SendRequest(&outBoundResponse)
+	// the response carries the private key, so only its status is logged
+	err := r.getNewSendRequestObject(nil, headers, "").WithSensitive().SendRequest(&outBoundResponse)
 	if err != nil {
-		logger.Error("get access_token", outBoundResponse, err)
+		logger.Error("get access_token", outBoundResponse.Status, outBoundResponse.Message, err)
 		return outBoundResponse.Data, err
 	}
-	logger.Info("get access_token", outBoundResponse)
+	logger.Info("get access_token", outBoundResponse.Status, outBoundResponse.Message)
 
 	return outBoundResponse.Data, nil
 }
@@ -39,7 +40,7 @@ func (r *RequestObj) GetAccessTokenByKey() (external_models.AccessToken, error)
 
 	data, ok := idata.(string)
 	if !ok {
-		logger.Error("get access token by key", idata, "request data format error")
+		logger.Error("get access token by key", "request data format error")
 		return outBoundResponse.Data, fmt.Errorf("request data format error")
 	}
 
@@ -48,12 +49,13 @@ func (r *RequestObj) GetAccessTokenByKey() (external_models.AccessToken, error)
 		"v-app":        appKey,
 	}
 
-	err := r.getNewSendRequestObject(nil, headers, fmt.Sprintf("/%v", data)).SendRequest(&outBoundResponse)
+	// the key is in the path and the response carries the private key, so only its status is logged
+	err := r.getNewSendRequestObject(nil, headers, fmt.Sprintf("/%v", data)).WithSensitive().SendRequest(&outBoundResponse)
 	if err != nil {
-		logger.Error("get access token by key", outBoundResponse, err)
+		logger.Error("get access token by key", outBoundResponse.Status, outBoundResponse.Message, err)
 		return outBoundResponse.Data, err
 	}
-	logger.Info("get access token by key", outBoundResponse)
+	logger.Info("get access token by key", outBoundResponse.Status, outBoundResponse.Message)
 
 	return outBoundResponse.Data, nil
 }
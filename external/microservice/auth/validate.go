@@ -7,37 +7,6 @@ import (
 	"github.com/vesicash/transactions-ms/internal/config"
 )
 
-func (r *RequestObj) ValidateOnAuth() (bool, error) {
-
-	var (
-		appKey           = config.GetConfig().App.Key
-		outBoundResponse external_models.ValidateOnDBReqModel
-		logger           = r.Logger
-		idata            = r.RequestData
-	)
-
-	data, ok := idata.(external_models.ValidateOnDBReq)
-	if !ok {
-		logger.Error("validate on auth", idata, "request data format error")
-		return false, fmt.Errorf("request data format error")
-	}
-
-	headers := map[string]string{
-		"Content-Type": "application/json",
-		"v-app":        appKey,
-	}
-
-	logger.Info("validate on auth", data)
-	err := r.getNewSendRequestObject(data, headers, "").SendRequest(&outBoundResponse)
-	if err != nil {
-		logger.Error("validate on auth", outBoundResponse, err.Error())
-		return false, err
-	}
-	logger.Info("validate on auth", outBoundResponse)
-
-	return outBoundResponse.Data, nil
-}
-
 func (r *RequestObj) ValidateAuthorization() (external_models.ValidateAuthorizationDataModel, error) {
 
 	var (
@@ -1,6 +1,7 @@
 package payment
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vesicash/transactions-ms/external"
@@ -17,6 +18,7 @@ type RequestObj struct {
 	RequestData  interface{}
 	DecodeMethod string
 	Logger       *utility.Logger
+	Ctx          context.Context
 }
 
 var (
@@ -25,7 +27,7 @@ var (
 )
 
 func (r *RequestObj) getNewSendRequestObject(data interface{}, headers map[string]string, urlprefix string) *external.SendRequestObject {
-	return external.GetNewSendRequestObject(r.Logger, r.Name, r.Path, r.Method, urlprefix, r.DecodeMethod, headers, r.SuccessCode, data)
+	return external.GetNewSendRequestObject(r.Logger, external.ServicePayment, r.Name, r.Path, r.Method, urlprefix, r.DecodeMethod, headers, r.SuccessCode, data).WithContext(r.Ctx)
 }
 
 func (r *RequestObj) getAccessTokenObject() *auth.RequestObj {
@@ -40,5 +42,6 @@ func (r *RequestObj) getAccessTokenObject() *auth.RequestObj {
 		DecodeMethod: JsonDecodeMethod,
 		RequestData:  nil,
 		Logger:       r.Logger,
+		Ctx:          r.Ctx,
 	}
 }
@@ -0,0 +1,128 @@
+package request
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/microservice/auth"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+type authClient struct {
+	logger *utility.Logger
+	ctx    context.Context
+}
+
+func NewAuthClient(logger *utility.Logger) AuthClient {
+	return authClient{logger: logger, ctx: context.Background()}
+}
+
+func (c authClient) withContext(ctx context.Context) AuthClient {
+	c.ctx = ctx
+	return c
+}
+
+func (c authClient) request(name, path, method string, successCode int, data interface{}) auth.RequestObj {
+	return auth.RequestObj{
+		Name:         name,
+		Path:         fmt.Sprintf("%v%v", config.GetConfig().Microservices.Auth, path),
+		Method:       method,
+		SuccessCode:  successCode,
+		DecodeMethod: JsonDecodeMethod,
+		RequestData:  data,
+		Logger:       c.logger,
+		Ctx:          c.ctx,
+	}
+}
+
+func (c authClient) GetUser(data external_models.GetUserRequestModel) (external_models.User, error) {
+	obj := c.request("get_user", "/v2/get_user", "POST", 200, data)
+	return obj.GetUser()
+}
+
+func (c authClient) GetUserCredential(data external_models.GetUserCredentialModel) (external_models.GetUserCredentialResponse, error) {
+	obj := c.request("get_user_credential", "/v2/get_user_credentials", "POST", 200, data)
+	return obj.GetUserCredential()
+}
+
+func (c authClient) CreateUserCredential(data external_models.CreateUserCredentialModel) (external_models.GetUserCredentialResponse, error) {
+	obj := c.request("create_user_credential", "/v2/create_user_credentials", "POST", 200, data)
+	return obj.CreateUserCredential()
+}
+
+func (c authClient) UpdateUserCredential(data external_models.UpdateUserCredentialModel) (external_models.GetUserCredentialResponse, error) {
+	obj := c.request("update_user_credential", "/v2/update_user_credentials", "POST", 200, data)
+	return obj.UpdateUserCredential()
+}
+
+func (c authClient) GetUserProfile(data external_models.GetUserProfileModel) (external_models.UserProfile, error) {
+	obj := c.request("get_user_profile", "/v2/get_user_profile", "POST", 200, data)
+	return obj.GetUserProfile()
+}
+
+func (c authClient) GetBusinessProfile(data external_models.GetBusinessProfileModel) (external_models.BusinessProfile, error) {
+	obj := c.request("get_business_profile", "/v2/get_business_profile", "POST", 200, data)
+	return obj.GetBusinessProfile()
+}
+
+func (c authClient) GetCountry(data external_models.GetCountryModel) (external_models.Country, error) {
+	obj := c.request("get_country", "/v2/get_country", "POST", 200, data)
+	return obj.GetCountry()
+}
+
+func (c authClient) GetBankDetails(data external_models.GetBankDetailModel) (external_models.BankDetail, error) {
+	obj := c.request("get_bank_details", "/v2/get_bank_detail", "POST", 200, data)
+	return obj.GetBankDetails()
+}
+
+func (c authClient) GetAccessToken() (external_models.AccessToken, error) {
+	obj := c.request("get_access_token", "/v2/get_access_token", "GET", 200, nil)
+	return obj.GetAccessToken()
+}
+
+func (c authClient) ValidateAuthorization(data external_models.ValidateAuthorizationReq) (external_models.ValidateAuthorizationDataModel, error) {
+	obj := c.request("validate_authorization", "/v2/validate_authorization", "POST", 200, data)
+	return obj.ValidateAuthorization()
+}
+
+func (c authClient) GetAuthorize(data external_models.GetAuthorizeModel) (external_models.GetAuthorizeResponse, error) {
+	obj := c.request("get_authorize", "/v2/get_authorize", "POST", 200, data)
+	return obj.GetAuthorize()
+}
+
+func (c authClient) CreateAuthorize(data external_models.CreateAuthorizeModel) (external_models.GetAuthorizeResponse, error) {
+	obj := c.request("create_authorize", "/v2/create_authorize", "POST", 200, data)
+	return obj.CreateAuthorize()
+}
+
+func (c authClient) UpdateAuthorize(data external_models.UpdateAuthorizeModel) (external_models.GetAuthorizeResponse, error) {
+	obj := c.request("update_authorize", "/v2/update_authorize", "POST", 200, data)
+	return obj.UpdateAuthorize()
+}
+
+func (c authClient) SetUserAuthorizationRequiredStatus(data external_models.SetUserAuthorizationRequiredStatusModel) (bool, error) {
+	obj := c.request("set_user_authorization_required_status", "/v2/set_authorization_required", "POST", 200, data)
+	return obj.SetUserAuthorizationRequiredStatus()
+}
+
+func (c authClient) GetBusinessCharge(data external_models.GetBusinessChargeModel) (external_models.BusinessCharge, error) {
+	obj := c.request("get_business_charge", "/v2/get_business_charge", "POST", 201, data)
+	return obj.GetBusinessCharge()
+}
+
+func (c authClient) InitBusinessCharge(data external_models.InitBusinessChargeModel) (external_models.BusinessCharge, error) {
+	obj := c.request("init_business_charge", "/v2/init_business_charge", "POST", 200, data)
+	return obj.InitBusinessCharge()
+}
+
+func (c authClient) SignupUser(data external_models.CreateUserRequestModel) (external_models.User, error) {
+	obj := c.request("signup_user", "/v2/signup", "POST", 201, data)
+	return obj.SignupUser()
+}
+
+func (c authClient) GetAccessTokenByKey(key string) (external_models.AccessToken, error) {
+	obj := c.request("get_access_token_by_key", "/v2/get_access_token_by_key", "GET", 200, key)
+	return obj.GetAccessTokenByKey()
+}
@@ -0,0 +1,212 @@
+package request
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/internal/config"
+)
+
+var (
+	defaultLookupCacheTTL        = 5 * time.Minute
+	defaultLookupCacheCountryTTL = time.Hour
+	defaultLookupCacheMaxEntries = 10000
+
+	lookupCacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "lookup_cache_requests_total",
+			Help: "Lookups answered by the auth lookup cache, by result (hit, miss or shared).",
+		},
+		[]string{"lookup", "result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(lookupCacheRequests)
+}
+
+// LookupCachePolicy bounds how long and how many lookups are kept.
+type LookupCachePolicy struct {
+	TTL        time.Duration
+	CountryTTL time.Duration
+	MaxEntries int
+}
+
+// GetLookupCachePolicy reads the LOOKUP_CACHE_* settings, falling back to defaults for anything that
+// is not configured.
+func GetLookupCachePolicy() LookupCachePolicy {
+	policy := LookupCachePolicy{
+		TTL:        defaultLookupCacheTTL,
+		CountryTTL: defaultLookupCacheCountryTTL,
+		MaxEntries: defaultLookupCacheMaxEntries,
+	}
+
+	conf := config.GetConfig()
+	if conf == nil {
+		return policy
+	}
+	c := conf.LookupCache
+	if c.TTL > 0 {
+		policy.TTL = time.Duration(c.TTL) * time.Second
+	}
+	if c.CountryTTL > 0 {
+		policy.CountryTTL = time.Duration(c.CountryTTL) * time.Second
+	}
+	if c.MaxEntries > 0 {
+		policy.MaxEntries = c.MaxEntries
+	}
+	return policy
+}
+
+// cachedAuthClient answers user, business profile and country lookups from memory, passing every other
+// call straight to the wrapped client.
+type cachedAuthClient struct {
+	AuthClient
+	users            *lookupCache[external_models.GetUserRequestModel, external_models.User]
+	businessProfiles *lookupCache[external_models.GetBusinessProfileModel, external_models.BusinessProfile]
+	countries        *lookupCache[external_models.GetCountryModel, external_models.Country]
+}
+
+// NewCachedAuthClient puts a read-through cache in front of client's user, business profile and
+// country lookups. Failed lookups and empty results are not cached. The auth service has no endpoint
+// that returns several users at once, so callers needing many users still make one GetUser call per
+// account id; the cache only saves the calls for ids it has seen recently.
+func NewCachedAuthClient(client AuthClient, policy LookupCachePolicy) AuthClient {
+	return &cachedAuthClient{
+		AuthClient:       client,
+		users:            newLookupCache[external_models.GetUserRequestModel, external_models.User]("user", policy.TTL, policy.MaxEntries),
+		businessProfiles: newLookupCache[external_models.GetBusinessProfileModel, external_models.BusinessProfile]("business_profile", policy.TTL, policy.MaxEntries),
+		countries:        newLookupCache[external_models.GetCountryModel, external_models.Country]("country", policy.CountryTTL, policy.MaxEntries),
+	}
+}
+
+// withContext binds the wrapped client to ctx. The caches are shared with c.
+func (c *cachedAuthClient) withContext(ctx context.Context) AuthClient {
+	bound := *c
+	bound.AuthClient = bindContext(c.AuthClient, ctx)
+	return &bound
+}
+
+func (c *cachedAuthClient) GetUser(data external_models.GetUserRequestModel) (external_models.User, error) {
+	return c.users.get(data, func() (external_models.User, bool, error) {
+		user, err := c.AuthClient.GetUser(data)
+		return user, user.ID != 0, err
+	})
+}
+
+func (c *cachedAuthClient) GetBusinessProfile(data external_models.GetBusinessProfileModel) (external_models.BusinessProfile, error) {
+	return c.businessProfiles.get(data, func() (external_models.BusinessProfile, bool, error) {
+		profile, err := c.AuthClient.GetBusinessProfile(data)
+		return profile, profile.ID != 0, err
+	})
+}
+
+func (c *cachedAuthClient) GetCountry(data external_models.GetCountryModel) (external_models.Country, error) {
+	return c.countries.get(data, func() (external_models.Country, bool, error) {
+		country, err := c.AuthClient.GetCountry(data)
+		return country, country.ID != 0, err
+	})
+}
+
+// lookupCache is a size-bounded LRU cache whose entries expire after ttl. Concurrent misses for the same
+// key share a single call to the loader.
+type lookupCache[K comparable, V any] struct {
+	name       string
+	ttl        time.Duration
+	maxEntries int
+
+	mu       sync.Mutex
+	entries  map[K]*list.Element
+	order    *list.List
+	inflight map[K]*lookupCall[V]
+}
+
+type lookupEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+type lookupCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newLookupCache[K comparable, V any](name string, ttl time.Duration, maxEntries int) *lookupCache[K, V] {
+	return &lookupCache[K, V]{
+		name:       name,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    map[K]*list.Element{},
+		order:      list.New(),
+		inflight:   map[K]*lookupCall[V]{},
+	}
+}
+
+// get returns the cached value for key, calling load on a miss. load reports whether its value may be
+// cached. If load panics, callers waiting on the same key get an error and the panic is passed on.
+func (c *lookupCache[K, V]) get(key K, load func() (V, bool, error)) (V, error) {
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*lookupEntry[K, V])
+		if time.Now().Before(entry.expiresAt) {
+			c.order.MoveToFront(el)
+			c.mu.Unlock()
+			lookupCacheRequests.WithLabelValues(c.name, "hit").Inc()
+			return entry.value, nil
+		}
+		c.remove(el)
+	}
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		lookupCacheRequests.WithLabelValues(c.name, "shared").Inc()
+		return call.value, call.err
+	}
+	call := &lookupCall[V]{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+	lookupCacheRequests.WithLabelValues(c.name, "miss").Inc()
+
+	cacheable := false
+	defer func() {
+		r := recover()
+		if r != nil {
+			call.err = fmt.Errorf("%v lookup panicked: %v", c.name, r)
+		}
+		c.mu.Lock()
+		delete(c.inflight, key)
+		if call.err == nil && cacheable {
+			c.add(key, call.value)
+		}
+		c.mu.Unlock()
+		close(call.done)
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	call.value, cacheable, call.err = load()
+	return call.value, call.err
+}
+
+func (c *lookupCache[K, V]) add(key K, value V) {
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	c.entries[key] = c.order.PushFront(&lookupEntry[K, V]{key: key, value: value, expiresAt: time.Now().Add(c.ttl)})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *lookupCache[K, V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lookupEntry[K, V]).key)
+}
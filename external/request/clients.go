@@ -0,0 +1,77 @@
+package request
+
+import "github.com/vesicash/transactions-ms/external/external_models"
+
+// AuthClient talks to the auth microservice.
+type AuthClient interface {
+	GetUser(data external_models.GetUserRequestModel) (external_models.User, error)
+	GetUserCredential(data external_models.GetUserCredentialModel) (external_models.GetUserCredentialResponse, error)
+	CreateUserCredential(data external_models.CreateUserCredentialModel) (external_models.GetUserCredentialResponse, error)
+	UpdateUserCredential(data external_models.UpdateUserCredentialModel) (external_models.GetUserCredentialResponse, error)
+	GetUserProfile(data external_models.GetUserProfileModel) (external_models.UserProfile, error)
+	GetBusinessProfile(data external_models.GetBusinessProfileModel) (external_models.BusinessProfile, error)
+	GetCountry(data external_models.GetCountryModel) (external_models.Country, error)
+	GetBankDetails(data external_models.GetBankDetailModel) (external_models.BankDetail, error)
+	GetAccessToken() (external_models.AccessToken, error)
+	ValidateAuthorization(data external_models.ValidateAuthorizationReq) (external_models.ValidateAuthorizationDataModel, error)
+	GetAuthorize(data external_models.GetAuthorizeModel) (external_models.GetAuthorizeResponse, error)
+	CreateAuthorize(data external_models.CreateAuthorizeModel) (external_models.GetAuthorizeResponse, error)
+	UpdateAuthorize(data external_models.UpdateAuthorizeModel) (external_models.GetAuthorizeResponse, error)
+	SetUserAuthorizationRequiredStatus(data external_models.SetUserAuthorizationRequiredStatusModel) (bool, error)
+	GetBusinessCharge(data external_models.GetBusinessChargeModel) (external_models.BusinessCharge, error)
+	InitBusinessCharge(data external_models.InitBusinessChargeModel) (external_models.BusinessCharge, error)
+	SignupUser(data external_models.CreateUserRequestModel) (external_models.User, error)
+	GetAccessTokenByKey(key string) (external_models.AccessToken, error)
+}
+
+// PaymentClient talks to the payment microservice.
+type PaymentClient interface {
+	CreatePayment(data external_models.CreatePaymentRequestWithToken) (external_models.Payment, error)
+	ListPayment(transactionID string) (external_models.ListPayment, error)
+	RequestManualRefund(transactionID string) (map[string]interface{}, error)
+	WalletTransfer(data external_models.WalletTransferRequest) (interface{}, error)
+	DebitWallet(data external_models.DebitWalletRequest) (external_models.WalletBalance, error)
+	CreditWallet(data external_models.CreditWalletRequest) (external_models.WalletBalance, error)
+}
+
+// NotificationClient talks to the notification microservice.
+type NotificationClient interface {
+	SendVerificationEmail(data external_models.EmailNotificationRequest) (interface{}, error)
+	SendWelcomeEmail(data external_models.AccountIDRequestModel) (interface{}, error)
+	SendEmailVerifiedNotification(data external_models.AccountIDRequestModel) (interface{}, error)
+	SendSMSToPhone(data external_models.SMSToPhoneNotificationRequest) (interface{}, error)
+	VerificationFailedNotification(data external_models.VerificationFailedModel) (interface{}, error)
+	VerificationSuccessfulNotification(data external_models.VerificationSuccessfulModel) (interface{}, error)
+	SendAuthorizedNotification(data external_models.AuthorizeNotificationRequest) (interface{}, error)
+	SendAuthorizationNotification(data external_models.AuthorizeNotificationRequest) (interface{}, error)
+	SendNewTransactionNotification(data external_models.TransactionIDRequestModel) (interface{}, error)
+	SendTransactionAcceptedNotification(data external_models.TransactionIDRequestModel) (interface{}, error)
+	SendTransactionRejectedNotification(data external_models.TransactionIDRequestModel) (interface{}, error)
+	SendTransactionDeliveredRejectedNotification(data external_models.TransactionIDRequestModel) (interface{}, error)
+	SendDisputeOpenedNotification(data external_models.TransactionIDAccountIDRequestModel) (interface{}, error)
+	SendTransactionDeliveredNotification(data external_models.TransactionIDRequestModel) (interface{}, error)
+	SendDueDateProposalNotification(data external_models.DueDateExtensionProposalRequestModel) (interface{}, error)
+	SendDueDateExtendedNotification(data external_models.TransactionIDRequestModel) (interface{}, error)
+	SendTransactionDeliveredAcceptedNotification(data external_models.TransactionIDRequestModel) (interface{}, error)
+}
+
+// MonnifyClient talks to Monnify.
+type MonnifyClient interface {
+	Login() (string, error)
+	MatchBvnDetails(data external_models.MonnifyMatchBvnDetailsReq) (bool, error)
+}
+
+// AppruveClient talks to Appruve.
+type AppruveClient interface {
+	VerifyID(data external_models.AppruveReqModelFirst) (int, error)
+}
+
+// RaveClient talks to Rave.
+type RaveClient interface {
+	ResolveBankAccount(data external_models.ResolveAccountRequest) (string, error)
+}
+
+// IPStackClient talks to IPStack.
+type IPStackClient interface {
+	ResolveIP(ip string) (external_models.IPStackResolveIPResponse, error)
+}
@@ -0,0 +1,123 @@
+package request
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/microservice/notification"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+type notificationClient struct {
+	logger *utility.Logger
+	ctx    context.Context
+}
+
+func NewNotificationClient(logger *utility.Logger) NotificationClient {
+	return notificationClient{logger: logger, ctx: context.Background()}
+}
+
+func (c notificationClient) withContext(ctx context.Context) NotificationClient {
+	c.ctx = ctx
+	return c
+}
+
+func (c notificationClient) request(name, path, method string, successCode int, data interface{}) notification.RequestObj {
+	return notification.RequestObj{
+		Name:         name,
+		Path:         fmt.Sprintf("%v%v", config.GetConfig().Microservices.Notification, path),
+		Method:       method,
+		SuccessCode:  successCode,
+		DecodeMethod: JsonDecodeMethod,
+		RequestData:  data,
+		Logger:       c.logger,
+		Ctx:          c.ctx,
+	}
+}
+
+func (c notificationClient) SendVerificationEmail(data external_models.EmailNotificationRequest) (interface{}, error) {
+	obj := c.request("send_verification_email", "/v2/send/send_email_verification_mail", "POST", 200, data)
+	return obj.SendVerificationEmail()
+}
+
+func (c notificationClient) SendWelcomeEmail(data external_models.AccountIDRequestModel) (interface{}, error) {
+	obj := c.request("send_welcome_email", "/v2/send/send_welcome_mail", "POST", 200, data)
+	return obj.SendWelcomeEmail()
+}
+
+func (c notificationClient) SendEmailVerifiedNotification(data external_models.AccountIDRequestModel) (interface{}, error) {
+	obj := c.request("send_email_verified_notification", "/v2/send/send_email_verified_mail", "POST", 200, data)
+	return obj.SendEmailVerifiedNotification()
+}
+
+func (c notificationClient) SendSMSToPhone(data external_models.SMSToPhoneNotificationRequest) (interface{}, error) {
+	obj := c.request("send_sms_to_phone", "/v2/send/send_sms_to_phone", "POST", 200, data)
+	return obj.SendSendSMSToPhone()
+}
+
+func (c notificationClient) VerificationFailedNotification(data external_models.VerificationFailedModel) (interface{}, error) {
+	obj := c.request("verification_failed_notification", "/v2/send/send_verification_failed", "POST", 200, data)
+	return obj.VerificationFailedNotification()
+}
+
+func (c notificationClient) VerificationSuccessfulNotification(data external_models.VerificationSuccessfulModel) (interface{}, error) {
+	obj := c.request("verification_successful_notification", "/v2/send/send_verification_successful", "POST", 200, data)
+	return obj.VerificationSuccessfulNotification()
+}
+
+func (c notificationClient) SendAuthorizedNotification(data external_models.AuthorizeNotificationRequest) (interface{}, error) {
+	obj := c.request("send_authorized_notification", "/v2/send/send_authorized", "POST", 200, data)
+	return obj.SendAuthorizedNotification()
+}
+
+func (c notificationClient) SendAuthorizationNotification(data external_models.AuthorizeNotificationRequest) (interface{}, error) {
+	obj := c.request("send_authorization_notification", "/v2/send/send_authorization", "POST", 200, data)
+	return obj.SendAuthorizationNotification()
+}
+
+func (c notificationClient) SendNewTransactionNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	obj := c.request("send_new_transaction_notification", "/v2/send/send_new_transaction", "POST", 200, data)
+	return obj.SendNewTransactionNotification()
+}
+
+func (c notificationClient) SendTransactionAcceptedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	obj := c.request("send_transaction_accepted_notification", "/v2/send/send_transaction_accepted", "POST", 200, data)
+	return obj.SendTransactionAcceptedNotification()
+}
+
+func (c notificationClient) SendTransactionRejectedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	obj := c.request("send_transaction_rejected_notification", "/v2/send/send_transaction_rejected", "POST", 200, data)
+	return obj.SendTransactionRejectedNotification()
+}
+
+func (c notificationClient) SendTransactionDeliveredRejectedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	obj := c.request("send_transaction_delivered_rejected_notification", "/v2/send/send_transaction_delivered_and_rejected", "POST", 200, data)
+	return obj.SendTransactionDeliveredRejectedNotification()
+}
+
+func (c notificationClient) SendDisputeOpenedNotification(data external_models.TransactionIDAccountIDRequestModel) (interface{}, error) {
+	obj := c.request("send_dispute_opened_notification", "/v2/send/send_dispute_opened", "POST", 200, data)
+	return obj.SendDisputeOpenedNotification()
+}
+
+func (c notificationClient) SendTransactionDeliveredNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	obj := c.request("send_transaction_delivered_notification", "/v2/send/send_transaction_delivered", "POST", 200, data)
+	return obj.SendTransactionDeliveredNotification()
+}
+
+func (c notificationClient) SendDueDateProposalNotification(data external_models.DueDateExtensionProposalRequestModel) (interface{}, error) {
+	obj := c.request("send_due_date_proposal_notification", "/v2/send/send_due_date_proposal", "POST", 200, data)
+	return obj.SendDueDateProposalNotification()
+}
+
+func (c notificationClient) SendDueDateExtendedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	obj := c.request("send_due_date_extended_notification", "/v2/send/send_due_date_extended", "POST", 200, data)
+	return obj.SendDueDateExtendedNotification()
+}
+
+func (c notificationClient) SendTransactionDeliveredAcceptedNotification(data external_models.TransactionIDRequestModel) (interface{}, error) {
+	obj := c.request("send_transaction_delivered_accepted_notification", "/v2/send/send_transaction_delivered_and_accepted", "POST", 200, data)
+	return obj.SendTransactionDeliveredAcceptedNotification()
+}
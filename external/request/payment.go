@@ -0,0 +1,68 @@
+package request
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/microservice/payment"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+type paymentClient struct {
+	logger *utility.Logger
+	ctx    context.Context
+}
+
+func NewPaymentClient(logger *utility.Logger) PaymentClient {
+	return paymentClient{logger: logger, ctx: context.Background()}
+}
+
+func (c paymentClient) withContext(ctx context.Context) PaymentClient {
+	c.ctx = ctx
+	return c
+}
+
+func (c paymentClient) request(name, path, method string, successCode int, data interface{}) payment.RequestObj {
+	return payment.RequestObj{
+		Name:         name,
+		Path:         fmt.Sprintf("%v%v", config.GetConfig().Microservices.Payment, path),
+		Method:       method,
+		SuccessCode:  successCode,
+		DecodeMethod: JsonDecodeMethod,
+		RequestData:  data,
+		Logger:       c.logger,
+		Ctx:          c.ctx,
+	}
+}
+
+func (c paymentClient) CreatePayment(data external_models.CreatePaymentRequestWithToken) (external_models.Payment, error) {
+	obj := c.request("create_payment", "/v2/create", "POST", 201, data)
+	return obj.CreatePayment()
+}
+
+func (c paymentClient) ListPayment(transactionID string) (external_models.ListPayment, error) {
+	obj := c.request("list_payment", "/v2/listByTransactionId", "GET", 200, transactionID)
+	return obj.ListPayment()
+}
+
+func (c paymentClient) RequestManualRefund(transactionID string) (map[string]interface{}, error) {
+	obj := c.request("request_manual_refund", "/v2/disbursement/process/refund", "POST", 200, transactionID)
+	return obj.RequestManualRefund()
+}
+
+func (c paymentClient) WalletTransfer(data external_models.WalletTransferRequest) (interface{}, error) {
+	obj := c.request("wallet_transfer", "/v2/disbursement/wallet/wallet-transfer", "POST", 200, data)
+	return obj.WalletTransfer()
+}
+
+func (c paymentClient) DebitWallet(data external_models.DebitWalletRequest) (external_models.WalletBalance, error) {
+	obj := c.request("debit_wallet", "/v2/wallet/debit", "POST", 200, data)
+	return obj.DebitWallet()
+}
+
+func (c paymentClient) CreditWallet(data external_models.CreditWalletRequest) (external_models.WalletBalance, error) {
+	obj := c.request("credit_wallet", "/v2/wallet/credit", "POST", 200, data)
+	return obj.CreditWallet()
+}
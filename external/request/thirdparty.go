@@ -0,0 +1,147 @@
+package request
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	rave "github.com/vesicash/transactions-ms/external/thirdparty/Rave"
+	"github.com/vesicash/transactions-ms/external/thirdparty/appruve"
+	"github.com/vesicash/transactions-ms/external/thirdparty/ipstack"
+	"github.com/vesicash/transactions-ms/external/thirdparty/monnify"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+type monnifyClient struct {
+	logger *utility.Logger
+	ctx    context.Context
+}
+
+func NewMonnifyClient(logger *utility.Logger) MonnifyClient {
+	return monnifyClient{logger: logger, ctx: context.Background()}
+}
+
+func (c monnifyClient) withContext(ctx context.Context) MonnifyClient {
+	c.ctx = ctx
+	return c
+}
+
+func (c monnifyClient) request(name, path, method string, successCode int, data interface{}) monnify.RequestObj {
+	return monnify.RequestObj{
+		Name:         name,
+		Path:         fmt.Sprintf("%v%v", config.GetConfig().Monnify.MonnifyApi, path),
+		Method:       method,
+		SuccessCode:  successCode,
+		DecodeMethod: JsonDecodeMethod,
+		RequestData:  data,
+		Logger:       c.logger,
+		Ctx:          c.ctx,
+	}
+}
+
+func (c monnifyClient) Login() (string, error) {
+	obj := c.request("monnify_login", "/api/v1/auth/login", "POST", 200, nil)
+	return obj.MonnifyLogin()
+}
+
+func (c monnifyClient) MatchBvnDetails(data external_models.MonnifyMatchBvnDetailsReq) (bool, error) {
+	obj := c.request("monnify_match_bvn_details", "/api/v1/vas/bvn-details-match", "POST", 200, data)
+	return obj.MonnifyMatchBvnDetails()
+}
+
+type appruveClient struct {
+	logger *utility.Logger
+	ctx    context.Context
+}
+
+func NewAppruveClient(logger *utility.Logger) AppruveClient {
+	return appruveClient{logger: logger, ctx: context.Background()}
+}
+
+func (c appruveClient) withContext(ctx context.Context) AppruveClient {
+	c.ctx = ctx
+	return c
+}
+
+func (c appruveClient) request(name, path, method string, successCode int, data interface{}) appruve.RequestObj {
+	return appruve.RequestObj{
+		Name:         name,
+		Path:         fmt.Sprintf("%v%v", config.GetConfig().Appruve.BaseUrl, path),
+		Method:       method,
+		SuccessCode:  successCode,
+		DecodeMethod: JsonDecodeMethod,
+		RequestData:  data,
+		Logger:       c.logger,
+		Ctx:          c.ctx,
+	}
+}
+
+func (c appruveClient) VerifyID(data external_models.AppruveReqModelFirst) (int, error) {
+	obj := c.request("appruve_verify_id", "/v1/verifications", "POST", 200, data)
+	return obj.AppruveVerifyID()
+}
+
+type raveClient struct {
+	logger *utility.Logger
+	ctx    context.Context
+}
+
+func NewRaveClient(logger *utility.Logger) RaveClient {
+	return raveClient{logger: logger, ctx: context.Background()}
+}
+
+func (c raveClient) withContext(ctx context.Context) RaveClient {
+	c.ctx = ctx
+	return c
+}
+
+func (c raveClient) request(name, path, method string, successCode int, data interface{}) rave.RequestObj {
+	return rave.RequestObj{
+		Name:         name,
+		Path:         fmt.Sprintf("%v%v", config.GetConfig().Rave.BaseUrl, path),
+		Method:       method,
+		SuccessCode:  successCode,
+		DecodeMethod: JsonDecodeMethod,
+		RequestData:  data,
+		Logger:       c.logger,
+		Ctx:          c.ctx,
+	}
+}
+
+func (c raveClient) ResolveBankAccount(data external_models.ResolveAccountRequest) (string, error) {
+	obj := c.request("rave_resolve_bank_account", "/v3/accounts/resolve", "POST", 200, data)
+	return obj.RaveResolveBankAccount()
+}
+
+type ipstackClient struct {
+	logger *utility.Logger
+	ctx    context.Context
+}
+
+func NewIPStackClient(logger *utility.Logger) IPStackClient {
+	return ipstackClient{logger: logger, ctx: context.Background()}
+}
+
+func (c ipstackClient) withContext(ctx context.Context) IPStackClient {
+	c.ctx = ctx
+	return c
+}
+
+func (c ipstackClient) request(name, path, method string, successCode int, data interface{}) ipstack.RequestObj {
+	return ipstack.RequestObj{
+		Name:         name,
+		Path:         fmt.Sprintf("%v%v", config.GetConfig().IPStack.BaseUrl, path),
+		Method:       method,
+		SuccessCode:  successCode,
+		DecodeMethod: JsonDecodeMethod,
+		RequestData:  data,
+		Logger:       c.logger,
+		Ctx:          c.ctx,
+	}
+}
+
+func (c ipstackClient) ResolveIP(ip string) (external_models.IPStackResolveIPResponse, error) {
+	obj := c.request("ipstack_resolve_ip", "", "GET", 200, ip)
+	return obj.IpstackResolveIp()
+}
@@ -2,18 +2,31 @@ package external
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/elliotchance/phpserialize"
+	"github.com/vesicash/transactions-ms/internal/tracing"
 	"github.com/vesicash/transactions-ms/utility"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type SendRequestObject struct {
 	Name         string
+	Service      string
 	Logger       *utility.Logger
 	Path         string
 	Method       string
@@ -22,11 +35,23 @@ type SendRequestObject struct {
 	Data         interface{}
 	DecodeMethod string
 	UrlPrefix    string
+	// Idempotent marks a request as safe to retry even though its method is not.
+	Idempotent bool
+	// Ctx is the context the request is sent under. Its trace is continued by the receiving service.
+	Ctx context.Context
+	// Sensitive keeps the URL prefix, data and response body of a request that carries secrets,
+	// like a key lookup, out of the logs.
+	Sensitive bool
+
+	// ResponseCode and ResponseBody hold the last response received for this request.
+	ResponseCode int
+	ResponseBody string
 }
 
-func GetNewSendRequestObject(logger *utility.Logger, name, path, method, urlPrefix, decodeMethod string, headers map[string]string, successCode int, data interface{}) *SendRequestObject {
+func GetNewSendRequestObject(logger *utility.Logger, service, name, path, method, urlPrefix, decodeMethod string, headers map[string]string, successCode int, data interface{}) *SendRequestObject {
 	return &SendRequestObject{
 		Logger:       logger,
+		Service:      service,
 		Name:         name,
 		Path:         path,
 		Method:       method,
@@ -38,21 +63,80 @@ func GetNewSendRequestObject(logger *utility.Logger, name, path, method, urlPref
 	}
 }
 
-var (
-	ResponseCode int
-	ResponseBody string
-)
+// WithContext sets the context the request is sent under.
+func (r *SendRequestObject) WithContext(ctx context.Context) *SendRequestObject {
+	r.Ctx = ctx
+	return r
+}
+
+// WithSensitive marks the request as carrying secrets, see Sensitive.
+func (r *SendRequestObject) WithSensitive() *SendRequestObject {
+	r.Sensitive = true
+	return r
+}
+
+const redacted = "[redacted]"
+
+// secretHeaders are the headers whose values are never logged.
+var secretHeaders = []string{"Authorization", "v-app", "v-private-key", "v-signature"}
 
 var (
 	JsonDecodeMethod    string = "json"
 	PhpSerializerMethod string = "phpserializer"
 )
 
+// ResponseError is returned when the service answers with a status outside the 2xx range.
+type ResponseError struct {
+	Name       string
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("external requests error for request %v, code %v", e.Name, strconv.Itoa(e.StatusCode))
+}
+
+// GetResponseCode returns the status code carried by err, or 0 when the request never got a response.
+func GetResponseCode(err error) int {
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode
+	}
+	return 0
+}
+
+// SendRequest sends the request in a client span named after it and decodes the body into response.
 func (r *SendRequestObject) SendRequest(response interface{}) error {
+	ctx := r.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "external."+r.Name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("request.name", r.Name),
+		semconv.PeerServiceKey.String(r.Service),
+		semconv.HTTPMethodKey.String(r.Method),
+	))
+	defer span.End()
+
+	started := time.Now()
+	err := r.send(ctx, response)
+	observeRequest(r.Service, r.Name, started, err)
+	if r.ResponseCode != 0 {
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(r.ResponseCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (r *SendRequestObject) send(ctx context.Context, response interface{}) error {
 	var (
 		data   = r.Data
 		logger = r.Logger
 		name   = r.Name
+		policy = GetClientPolicy(r.Service)
 		err    error
 	)
 
@@ -61,66 +145,143 @@ func (r *SendRequestObject) SendRequest(response interface{}) error {
 	if err != nil {
 		logger.Error("encoding error", name, err.Error())
 	}
+	payload := buf.Bytes()
 
-	logger.Info("before prefix", name, r.Path, data, buf)
-	if r.UrlPrefix != "" {
-		r.Path += r.UrlPrefix
+	path := r.Path + r.UrlPrefix
+	logPath, logData := path, data
+	if r.Sensitive {
+		logPath, logData = r.Path, redacted
 	}
-	logger.Info("after prefix", name, r.Path, data, buf)
+	logger.Info("request", name, logPath, r.Method, redactHeaders(r.Headers), logData)
 
-	client := &http.Client{}
-	req, err := http.NewRequest(r.Method, r.Path, buf)
+	target, err := url.Parse(path)
 	if err != nil {
 		logger.Error("request creation error", name, err.Error())
 		return err
 	}
+	breaker := getCircuitBreaker(target.Host, policy)
+	trace.SpanFromContext(ctx).SetAttributes(semconv.NetPeerNameKey.String(target.Hostname()))
 
-	for key, value := range r.Headers {
-		req.Header.Add(key, value)
+	retries := 0
+	if r.Idempotent || isIdempotentMethod(r.Method) {
+		retries = policy.MaxRetries
 	}
 
-	logger.Info("request", name, r.Path, r.Method, r.Headers)
+	var (
+		code int
+		body []byte
+	)
+	for attempt := 0; ; attempt++ {
+		if !breaker.allow() {
+			logger.Error("circuit open", name, target.Host)
+			return fmt.Errorf("request %v to %v: %w", name, target.Host, ErrCircuitOpen)
+		}
+
+		code, body, err = r.do(ctx, policy, path, payload)
+		breaker.record(err == nil && code < http.StatusInternalServerError)
 
-	res, err := client.Do(req)
+		retryable := err != nil || isRetryableStatus(code)
+		if !retryable || attempt >= retries {
+			break
+		}
+		delay := retryDelay(attempt)
+		logger.Info("retrying request", name, logPath, attempt+1, delay.String())
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", attempt+1)))
+		if err := sleep(ctx, delay); err != nil {
+			logger.Error("client do", name, err.Error())
+			return err
+		}
+	}
 	if err != nil {
+		var urlErr *url.Error
+		if r.Sensitive && errors.As(err, &urlErr) {
+			urlErr.URL = logPath
+		}
 		logger.Error("client do", name, err.Error())
 		return err
 	}
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		logger.Error("readin body error", name, err.Error())
-		return err
+	r.ResponseCode = code
+	r.ResponseBody = string(body)
+	if r.Sensitive {
+		logger.Info("response body", name, logPath, code, redacted)
+	} else {
+		logger.Info("response body", name, logPath, code, r.ResponseBody)
 	}
 
-	if r.DecodeMethod != PhpSerializerMethod {
-		err = json.Unmarshal(body, response)
-		if err != nil {
-			logger.Error("json decoding error", name, err.Error())
-			return err
+	if code != r.SuccessCode && (code < 200 || code > 299) {
+		// error bodies are decoded when they can be so callers can surface the service's message
+		_ = r.decode(body, response)
+		return &ResponseError{Name: name, StatusCode: code, Body: r.ResponseBody}
+	}
+
+	return r.decode(body, response)
+}
+
+// redactHeaders copies headers with the values of secretHeaders replaced.
+func redactHeaders(headers map[string]string) map[string]string {
+	logged := make(map[string]string, len(headers))
+	for key, value := range headers {
+		for _, secret := range secretHeaders {
+			if strings.EqualFold(key, secret) {
+				value = redacted
+				break
+			}
 		}
+		logged[key] = value
+	}
+	return logged
+}
+
+// sleep waits for d, returning early with the context's error when ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (r *SendRequestObject) do(ctx context.Context, policy ClientPolicy, path string, payload []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, r.Method, path, bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, err
 	}
+	for key, value := range r.Headers {
+		req.Header.Add(key, value)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	logger.Info("response body", name, r.Path, string(body))
+	res, err := getHttpClient(policy.Timeout).Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, err
+	}
+	return res.StatusCode, body, nil
+}
 
+func (r *SendRequestObject) decode(body []byte, response interface{}) error {
 	if r.DecodeMethod == PhpSerializerMethod {
 		err := phpserialize.Unmarshal(body, response)
 		if err != nil {
-			logger.Error("php serializer decoding error", name, err.Error())
+			r.Logger.Error("php serializer decoding error", r.Name, err.Error())
 			return err
 		}
-	}
-
-	defer res.Body.Close()
-	ResponseCode = res.StatusCode
-
-	if res.StatusCode == r.SuccessCode {
 		return nil
 	}
 
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		return fmt.Errorf("external requests error for request %v, code %v", name, strconv.Itoa(res.StatusCode))
+	err := json.Unmarshal(body, response)
+	if err != nil {
+		r.Logger.Error("json decoding error", r.Name, err.Error())
+		return err
 	}
-
 	return nil
 }
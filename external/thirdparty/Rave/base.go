@@ -1,6 +1,7 @@
 package rave
 
 import (
+	"context"
 	"github.com/vesicash/transactions-ms/external"
 	"github.com/vesicash/transactions-ms/utility"
 )
@@ -13,6 +14,7 @@ type RequestObj struct {
 	RequestData  interface{}
 	DecodeMethod string
 	Logger       *utility.Logger
+	Ctx          context.Context
 }
 
 var (
@@ -21,5 +23,5 @@ var (
 )
 
 func (r *RequestObj) getNewSendRequestObject(data interface{}, headers map[string]string, urlprefix string) *external.SendRequestObject {
-	return external.GetNewSendRequestObject(r.Logger, r.Name, r.Path, r.Method, urlprefix, r.DecodeMethod, headers, r.SuccessCode, data)
+	return external.GetNewSendRequestObject(r.Logger, external.ServiceRave, r.Name, r.Path, r.Method, urlprefix, r.DecodeMethod, headers, r.SuccessCode, data).WithContext(r.Ctx)
 }
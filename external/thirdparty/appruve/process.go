@@ -42,16 +42,17 @@ func (r *RequestObj) AppruveVerifyID() (int, error) {
 	logger.Info("appruve_verify_id", data)
 	endpoint := "/" + strings.ToLower(fdata.CountryCode) + "/" + fdata.Endpoint
 
-	err := r.getNewSendRequestObject(data, headers, endpoint).SendRequest(&outBoundResponse)
+	sendRequest := r.getNewSendRequestObject(data, headers, endpoint)
+	err := sendRequest.SendRequest(&outBoundResponse)
 	if err != nil {
 		logger.Error("appruve_verify_id", outBoundResponse, err.Error())
 		code := http.StatusInternalServerError
-		if external.ResponseCode != 0 {
-			code = external.ResponseCode
+		if responseCode := external.GetResponseCode(err); responseCode != 0 {
+			code = responseCode
 		}
 		return code, err
 	}
 	logger.Info("appruve_verify_id", outBoundResponse)
 
-	return external.ResponseCode, nil
+	return sendRequest.ResponseCode, nil
 }
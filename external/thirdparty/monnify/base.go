@@ -1,6 +1,7 @@
 package monnify
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vesicash/transactions-ms/external"
@@ -16,6 +17,7 @@ type RequestObj struct {
 	RequestData  interface{}
 	DecodeMethod string
 	Logger       *utility.Logger
+	Ctx          context.Context
 }
 
 var (
@@ -24,7 +26,7 @@ var (
 )
 
 func (r *RequestObj) getNewSendRequestObject(data interface{}, headers map[string]string, urlprefix string) *external.SendRequestObject {
-	return external.GetNewSendRequestObject(r.Logger, r.Name, r.Path, r.Method, urlprefix, r.DecodeMethod, headers, r.SuccessCode, data)
+	return external.GetNewSendRequestObject(r.Logger, external.ServiceMonnify, r.Name, r.Path, r.Method, urlprefix, r.DecodeMethod, headers, r.SuccessCode, data).WithContext(r.Ctx)
 }
 
 func (r *RequestObj) getMonnifyLoginObject() *RequestObj {
@@ -39,5 +41,6 @@ func (r *RequestObj) getMonnifyLoginObject() *RequestObj {
 		DecodeMethod: JsonDecodeMethod,
 		RequestData:  nil,
 		Logger:       r.Logger,
+		Ctx:          r.Ctx,
 	}
 }
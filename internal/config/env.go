@@ -6,16 +6,22 @@ import (
 )
 
 type Configuration struct {
-	Server        ServerConfiguration
-	Databases     Databases
-	TestDatabases Databases
-	Microservices Microservices
-	App           App
-	Monnify       Monnify
-	Appruve       Appruve
-	Rave          Rave
-	IPStack       IPStack
-	OnePipe       OnePipe
+	Server         ServerConfiguration
+	Databases      Databases
+	TestDatabases  Databases
+	Microservices  Microservices
+	App            App
+	Monnify        Monnify
+	Appruve        Appruve
+	Rave           Rave
+	IPStack        IPStack
+	OnePipe        OnePipe
+	HttpClient     HttpClient
+	LookupCache    LookupCache
+	Tracing        Tracing
+	RateLimit      RateLimit
+	RequestSigning RequestSigning
+	Retention      Retention
 }
 
 type BaseConfig struct {
@@ -26,6 +32,7 @@ type BaseConfig struct {
 	TRUSTED_PROXIES                  string  `mapstructure:"TRUSTED_PROXIES"`
 	EXEMPT_FROM_THROTTLE             string  `mapstructure:"EXEMPT_FROM_THROTTLE"`
 	METRICS_SERVER_PORT              string  `mapstructure:"METRICS_SERVER_PORT"`
+	SHUTDOWN_TIMEOUT                 int     `mapstructure:"SHUTDOWN_TIMEOUT"`
 
 	APP_NAME string `mapstructure:"APP_NAME"`
 	APP_KEY  string `mapstructure:"APP_KEY"`
@@ -111,16 +118,51 @@ type BaseConfig struct {
 	ONEPIPE_SECRET_KEY        string `mapstructure:"ONEPIPE_SECRET_KEY"`
 	ONEPIPE_BASE_URL          string `mapstructure:"ONEPIPE_BASE_URL"`
 	ONEPIPE_VESICASH_BASE_URL string `mapstructure:"ONEPIPE_VESICASH_BASE_URL"`
+
+	HTTP_CLIENT_TIMEOUT           int    `mapstructure:"HTTP_CLIENT_TIMEOUT"`
+	HTTP_CLIENT_SERVICE_TIMEOUTS  string `mapstructure:"HTTP_CLIENT_SERVICE_TIMEOUTS"`
+	HTTP_CLIENT_MAX_RETRIES       int    `mapstructure:"HTTP_CLIENT_MAX_RETRIES"`
+	HTTP_CLIENT_BREAKER_THRESHOLD int    `mapstructure:"HTTP_CLIENT_BREAKER_THRESHOLD"`
+	HTTP_CLIENT_BREAKER_COOLDOWN  int    `mapstructure:"HTTP_CLIENT_BREAKER_COOLDOWN"`
+
+	LOOKUP_CACHE_TTL         int `mapstructure:"LOOKUP_CACHE_TTL"`
+	LOOKUP_CACHE_COUNTRY_TTL int `mapstructure:"LOOKUP_CACHE_COUNTRY_TTL"`
+	LOOKUP_CACHE_MAX_ENTRIES int `mapstructure:"LOOKUP_CACHE_MAX_ENTRIES"`
+
+	TRACING_EXPORTER      string  `mapstructure:"TRACING_EXPORTER"`
+	TRACING_OTLP_ENDPOINT string  `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	TRACING_OTLP_INSECURE bool    `mapstructure:"TRACING_OTLP_INSECURE"`
+	TRACING_SAMPLE_RATIO  float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+
+	RATE_LIMIT_STORE        string `mapstructure:"RATE_LIMIT_STORE"`
+	RATE_LIMIT_WINDOW       int    `mapstructure:"RATE_LIMIT_WINDOW"`
+	RATE_LIMIT_IP           int    `mapstructure:"RATE_LIMIT_IP"`
+	RATE_LIMIT_PLANS        string `mapstructure:"RATE_LIMIT_PLANS"`
+	RATE_LIMIT_DAILY_QUOTAS string `mapstructure:"RATE_LIMIT_DAILY_QUOTAS"`
+
+	SIGNATURE_MAX_SKEW    int    `mapstructure:"SIGNATURE_MAX_SKEW"`
+	SIGNATURE_NONCE_STORE string `mapstructure:"SIGNATURE_NONCE_STORE"`
+
+	DELETED_TRANSACTION_RETENTION_DAYS int `mapstructure:"DELETED_TRANSACTION_RETENTION_DAYS"`
 }
 
 func (config *BaseConfig) SetupConfigurationn() *Configuration {
 	trustedProxies := []string{}
 	exemptFromThrottle := []string{}
+	serviceTimeouts := map[string]int{}
+	rateLimitPlans := map[string]int{}
+	rateLimitDailyQuotas := map[string]int{}
 	json.Unmarshal([]byte(config.TRUSTED_PROXIES), &trustedProxies)
 	json.Unmarshal([]byte(config.EXEMPT_FROM_THROTTLE), &exemptFromThrottle)
+	json.Unmarshal([]byte(config.HTTP_CLIENT_SERVICE_TIMEOUTS), &serviceTimeouts)
+	json.Unmarshal([]byte(config.RATE_LIMIT_PLANS), &rateLimitPlans)
+	json.Unmarshal([]byte(config.RATE_LIMIT_DAILY_QUOTAS), &rateLimitDailyQuotas)
 	if config.SERVER_PORT == "" {
 		config.SERVER_PORT = os.Getenv("PORT")
 	}
+	if config.SHUTDOWN_TIMEOUT <= 0 {
+		config.SHUTDOWN_TIMEOUT = 30
+	}
 	return &Configuration{
 		Server: ServerConfiguration{
 			Port:                      config.SERVER_PORT,
@@ -130,6 +172,7 @@ func (config *BaseConfig) SetupConfigurationn() *Configuration {
 			TrustedProxies:            trustedProxies,
 			ExemptFromThrottle:        exemptFromThrottle,
 			MetricsPort:               config.METRICS_SERVER_PORT,
+			ShutdownTimeout:           config.SHUTDOWN_TIMEOUT,
 		},
 		App: App{
 			Name: config.APP_NAME,
@@ -226,5 +269,37 @@ func (config *BaseConfig) SetupConfigurationn() *Configuration {
 			BaseUrl:         config.ONEPIPE_BASE_URL,
 			VesicashBaseUrl: config.ONEPIPE_VESICASH_BASE_URL,
 		},
+		HttpClient: HttpClient{
+			Timeout:          config.HTTP_CLIENT_TIMEOUT,
+			ServiceTimeouts:  serviceTimeouts,
+			MaxRetries:       config.HTTP_CLIENT_MAX_RETRIES,
+			BreakerThreshold: config.HTTP_CLIENT_BREAKER_THRESHOLD,
+			BreakerCooldown:  config.HTTP_CLIENT_BREAKER_COOLDOWN,
+		},
+		LookupCache: LookupCache{
+			TTL:        config.LOOKUP_CACHE_TTL,
+			CountryTTL: config.LOOKUP_CACHE_COUNTRY_TTL,
+			MaxEntries: config.LOOKUP_CACHE_MAX_ENTRIES,
+		},
+		Tracing: Tracing{
+			Exporter:     config.TRACING_EXPORTER,
+			OtlpEndpoint: config.TRACING_OTLP_ENDPOINT,
+			OtlpInsecure: config.TRACING_OTLP_INSECURE,
+			SampleRatio:  config.TRACING_SAMPLE_RATIO,
+		},
+		RateLimit: RateLimit{
+			Store:       config.RATE_LIMIT_STORE,
+			Window:      config.RATE_LIMIT_WINDOW,
+			IP:          config.RATE_LIMIT_IP,
+			Plans:       rateLimitPlans,
+			DailyQuotas: rateLimitDailyQuotas,
+		},
+		RequestSigning: RequestSigning{
+			MaxSkew:    config.SIGNATURE_MAX_SKEW,
+			NonceStore: config.SIGNATURE_NONCE_STORE,
+		},
+		Retention: Retention{
+			DeletedTransactionDays: config.DELETED_TRANSACTION_RETENTION_DAYS,
+		},
 	}
 }
@@ -0,0 +1,9 @@
+package config
+
+type HttpClient struct {
+	Timeout          int
+	ServiceTimeouts  map[string]int
+	MaxRetries       int
+	BreakerThreshold int
+	BreakerCooldown  int
+}
@@ -0,0 +1,7 @@
+package config
+
+type LookupCache struct {
+	TTL        int
+	CountryTTL int
+	MaxEntries int
+}
@@ -0,0 +1,9 @@
+package config
+
+type RateLimit struct {
+	Store       string
+	Window      int
+	IP          int
+	Plans       map[string]int
+	DailyQuotas map[string]int
+}
@@ -0,0 +1,6 @@
+package config
+
+type RequestSigning struct {
+	MaxSkew    int
+	NonceStore string
+}
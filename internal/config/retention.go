@@ -0,0 +1,5 @@
+package config
+
+type Retention struct {
+	DeletedTransactionDays int
+}
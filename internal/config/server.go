@@ -1,5 +1,8 @@
 package config
 
+// ServiceName identifies this service in metrics and traces.
+const ServiceName = "transactions"
+
 type ServerConfiguration struct {
 	Port                      string
 	Secret                    string
@@ -8,6 +11,7 @@ type ServerConfiguration struct {
 	TrustedProxies            []string
 	ExemptFromThrottle        []string
 	MetricsPort               string
+	ShutdownTimeout           int
 }
 type App struct {
 	Name string
@@ -0,0 +1,8 @@
+package config
+
+type Tracing struct {
+	Exporter     string
+	OtlpEndpoint string
+	OtlpInsecure bool
+	SampleRatio  float64
+}
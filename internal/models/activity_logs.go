@@ -8,18 +8,51 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	ActivityTransactionCreated      = "transaction.created"
+	ActivityTransactionStatusChange = "transaction.status_changed"
+	ActivityPartyStatusChange       = "party.status_changed"
+	ActivityPaymentProcessing       = "payment.processing"
+	ActivityPaymentDisbursed        = "payment.disbursed"
+	ActivityCustom                  = "custom"
+)
+
 type ActivityLog struct {
-	ID            uint      `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
-	TransactionID string    `gorm:"column:transaction_id; type:varchar(255); not null; comment: 12 characters long string" json:"transaction_id"`
-	Description   string    `gorm:"column:description; type:varchar(255); not null" json:"description"`
-	DeletedAt     time.Time `gorm:"column:deleted_at" json:"deleted_at"`
-	CreatedAt     time.Time `gorm:"column:created_at; autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+	ID             uint      `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
+	TransactionID  string    `gorm:"column:transaction_id; type:varchar(255); not null; comment: 12 characters long string" json:"transaction_id"`
+	MilestoneID    string    `gorm:"column:milestone_id; type:varchar(255)" json:"milestone_id"`
+	EventType      string    `gorm:"column:event_type; type:varchar(255); default:custom" json:"event_type"`
+	ActorAccountID int       `gorm:"column:actor_account_id; type:int; comment: account id of the user that performed the action, 0 for system" json:"actor_account_id"`
+	ActorRole      string    `gorm:"column:actor_role; type:varchar(255); comment: role of the actor on the transaction or system" json:"actor_role"`
+	PreviousStatus string    `gorm:"column:previous_status; type:varchar(255)" json:"previous_status"`
+	NewStatus      string    `gorm:"column:new_status; type:varchar(255)" json:"new_status"`
+	Metadata       jsonmap   `gorm:"column:metadata; type:jsonb" json:"metadata"`
+	Description    string    `gorm:"column:description; type:text; not null" json:"description"`
+	DeletedAt      time.Time `gorm:"column:deleted_at" json:"deleted_at"`
+	CreatedAt      time.Time `gorm:"column:created_at; autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
 }
 
 type CreateActivityLogRequest struct {
-	TransactionID string `json:"transaction_id" validate:"required" pgvalidate:"exists=transaction$transactions$transaction_id"`
-	Description   string `json:"description" validate:"required" `
+	TransactionID  string                 `json:"transaction_id" validate:"required" pgvalidate:"exists=transaction$transactions$transaction_id"`
+	Description    string                 `json:"description" validate:"required" `
+	MilestoneID    string                 `json:"milestone_id"`
+	EventType      string                 `json:"event_type"`
+	ActorAccountID int                    `json:"actor_account_id"`
+	ActorRole      string                 `json:"actor_role"`
+	PreviousStatus string                 `json:"previous_status"`
+	NewStatus      string                 `json:"new_status"`
+	Metadata       map[string]interface{} `json:"metadata"`
+}
+
+type ListActivityLogsRequest struct {
+	TransactionID  string
+	BusinessID     int
+	MilestoneID    string
+	EventType      string
+	ActorAccountID int
+	ActorRole      string
+	Status         string
 }
 
 func (a *ActivityLog) GetAllByTransactionID(db *gorm.DB) ([]ActivityLog, error) {
@@ -31,10 +64,95 @@ func (a *ActivityLog) GetAllByTransactionID(db *gorm.DB) ([]ActivityLog, error)
 	return details, nil
 }
 
+func (a *ActivityLog) GetAllByTransactionIDs(db *gorm.DB, transactionIDs []string) ([]ActivityLog, error) {
+	details := []ActivityLog{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_id IN ? ", transactionIDs)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
+func (a *ActivityLog) GetAllByFilters(db *gorm.DB, req ListActivityLogsRequest, paginator postgresql.Pagination) ([]ActivityLog, postgresql.PaginationResponse, error) {
+	var (
+		details = []ActivityLog{}
+		query   = ``
+		args    = []interface{}{}
+	)
+
+	if req.TransactionID != "" {
+		query = addQuery(query, "transaction_id = ?", "AND")
+		args = append(args, req.TransactionID)
+	}
+	if req.BusinessID != 0 {
+		query = addQuery(query, "transaction_id IN (SELECT transactions.transaction_id FROM transactions WHERE transactions.business_id = ?)", "AND")
+		args = append(args, req.BusinessID)
+	}
+	if req.MilestoneID != "" {
+		query = addQuery(query, "milestone_id = ?", "AND")
+		args = append(args, req.MilestoneID)
+	}
+	if req.EventType != "" {
+		query = addQuery(query, "event_type = ?", "AND")
+		args = append(args, req.EventType)
+	}
+	if req.ActorAccountID != 0 {
+		query = addQuery(query, "actor_account_id = ?", "AND")
+		args = append(args, req.ActorAccountID)
+	}
+	if req.ActorRole != "" {
+		query = addQuery(query, "actor_role = ?", "AND")
+		args = append(args, req.ActorRole)
+	}
+	if req.Status != "" {
+		query = addQuery(query, "(LOWER(previous_status) = LOWER(?) OR LOWER(new_status) = LOWER(?))", "AND")
+		args = append(args, req.Status, req.Status)
+	}
+
+	pagination, err := postgresql.SelectAllFromDbOrderByPaginated(db, "id", "desc", paginator, &details, query, args...)
+	if err != nil {
+		return details, pagination, err
+	}
+	return details, pagination, nil
+}
+
 func (a *ActivityLog) CreateActivityLog(db *gorm.DB) error {
+	a.ApplyDefaults()
 	err := postgresql.CreateOneRecord(db, &a)
 	if err != nil {
 		return fmt.Errorf("ActivityLog creation failed: %v", err.Error())
 	}
 	return nil
 }
+
+// ApplyDefaults fills in the event type and description of an entry recorded without them.
+func (a *ActivityLog) ApplyDefaults() {
+	if a.EventType == "" {
+		a.EventType = ActivityCustom
+	}
+	if a.Description == "" {
+		a.Description = a.RenderDescription()
+	}
+}
+
+// RenderDescription builds the human readable sentence shown on the activity feed
+// for entries that were recorded without one.
+func (a *ActivityLog) RenderDescription() string {
+	switch a.EventType {
+	case ActivityTransactionCreated:
+		return "Transaction details have been sent to all invited parties"
+	case ActivityTransactionStatusChange:
+		if a.PreviousStatus != "" {
+			return fmt.Sprintf("Transaction status changed from %v to %v", a.PreviousStatus, a.NewStatus)
+		}
+		return fmt.Sprintf("Transaction status changed to %v", a.NewStatus)
+	case ActivityPartyStatusChange:
+		return fmt.Sprintf("%v has %v transaction invitation", a.ActorRole, a.NewStatus)
+	case ActivityPaymentProcessing:
+		return "Payment is currently being processed"
+	case ActivityPaymentDisbursed:
+		return "Payment is disbursed successfully"
+	default:
+		return "Transaction updated"
+	}
+}
@@ -0,0 +1,294 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"gorm.io/gorm"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	// AuditActionRestore and AuditActionPurge record a soft deleted row being brought back and
+	// being removed for good.
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+
+	AuditEntityTransaction        = "transaction"
+	AuditEntityTransactionParty   = "transaction_party"
+	AuditEntityTransactionBroker  = "transaction_broker"
+	AuditEntityTransactionDispute = "transaction_dispute"
+
+	// auditChainLockKey serialises appends so two writers can never link to the same previous row.
+	auditChainLockKey = 7263310
+	auditPreviousKey  = "audit:previous"
+)
+
+type AuditLog struct {
+	ID             uint      `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
+	EntityType     string    `gorm:"column:entity_type; type:varchar(255); not null" json:"entity_type"`
+	EntityID       string    `gorm:"column:entity_id; type:varchar(255); not null" json:"entity_id"`
+	TransactionID  string    `gorm:"column:transaction_id; type:varchar(255); index" json:"transaction_id"`
+	Action         string    `gorm:"column:action; type:varchar(50); not null" json:"action"`
+	PreviousValues jsonmap   `gorm:"column:previous_values; type:jsonb" json:"previous_values"`
+	NewValues      jsonmap   `gorm:"column:new_values; type:jsonb" json:"new_values"`
+	ActorAccountID int       `gorm:"column:actor_account_id; type:int" json:"actor_account_id"`
+	ActorType      string    `gorm:"column:actor_type; type:varchar(50)" json:"actor_type"`
+	PreviousHash   string    `gorm:"column:previous_hash; type:varchar(64)" json:"previous_hash"`
+	Hash           string    `gorm:"column:hash; type:varchar(64); not null; unique" json:"hash"`
+	CreatedAt      time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+type AuditChainVerification struct {
+	Valid        bool   `json:"valid"`
+	Checked      int    `json:"checked"`
+	BrokenLinkID uint   `json:"broken_link_id,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+func (a *AuditLog) GetAllByTransactionID(db *gorm.DB, paginator postgresql.Pagination) ([]AuditLog, postgresql.PaginationResponse, error) {
+	details := []AuditLog{}
+	pagination, err := postgresql.SelectAllFromDbOrderByPaginated(db, "id", "asc", paginator, &details, "transaction_id = ?", a.TransactionID)
+	if err != nil {
+		return details, pagination, err
+	}
+	return details, pagination, nil
+}
+
+// ComputeHash returns the sha256 of the entry's content chained to the hash of the entry before it.
+func (a *AuditLog) ComputeHash() (string, error) {
+	content, err := json.Marshal(struct {
+		PreviousHash   string                 `json:"previous_hash"`
+		EntityType     string                 `json:"entity_type"`
+		EntityID       string                 `json:"entity_id"`
+		TransactionID  string                 `json:"transaction_id"`
+		Action         string                 `json:"action"`
+		PreviousValues map[string]interface{} `json:"previous_values"`
+		NewValues      map[string]interface{} `json:"new_values"`
+		ActorAccountID int                    `json:"actor_account_id"`
+		ActorType      string                 `json:"actor_type"`
+		CreatedAt      string                 `json:"created_at"`
+	}{
+		PreviousHash:   a.PreviousHash,
+		EntityType:     a.EntityType,
+		EntityID:       a.EntityID,
+		TransactionID:  a.TransactionID,
+		Action:         a.Action,
+		PreviousValues: a.PreviousValues,
+		NewValues:      a.NewValues,
+		ActorAccountID: a.ActorAccountID,
+		ActorType:      a.ActorType,
+		CreatedAt:      a.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Append links the entry to the current head of the chain and stores it. It must run inside
+// a database transaction so the advisory lock is held until the row is committed.
+func (a *AuditLog) Append(db *gorm.DB) error {
+	tx := db.Session(&gorm.Session{NewDB: true})
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey).Error; err != nil {
+		return fmt.Errorf("audit chain lock failed: %v", err.Error())
+	}
+
+	head := AuditLog{}
+	if err := tx.Order("id desc").Limit(1).Find(&head).Error; err != nil {
+		return fmt.Errorf("audit chain head lookup failed: %v", err.Error())
+	}
+
+	a.PreviousHash = head.Hash
+	a.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	hash, err := a.ComputeHash()
+	if err != nil {
+		return fmt.Errorf("audit hash computation failed: %v", err.Error())
+	}
+	a.Hash = hash
+
+	err = postgresql.CreateOneRecord(tx, &a)
+	if err != nil {
+		return fmt.Errorf("audit log creation failed: %v", err.Error())
+	}
+	return nil
+}
+
+// VerifyChain walks the whole log in insertion order and stops at the first entry whose
+// stored hash or link to its predecessor no longer matches.
+func (a *AuditLog) VerifyChain(db *gorm.DB) (AuditChainVerification, error) {
+	var (
+		result       = AuditChainVerification{Valid: true}
+		previousHash = ""
+		batch        = []AuditLog{}
+	)
+
+	err := db.Model(&AuditLog{}).Order("id asc").FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, entry := range batch {
+			result.Checked++
+			if entry.PreviousHash != previousHash {
+				result.Valid, result.BrokenLinkID = false, entry.ID
+				result.Reason = "previous hash does not match the hash of the preceding entry"
+				return errAuditChainBroken
+			}
+
+			hash, err := entry.ComputeHash()
+			if err != nil {
+				return err
+			}
+			if hash != entry.Hash {
+				result.Valid, result.BrokenLinkID = false, entry.ID
+				result.Reason = "entry content does not match its stored hash"
+				return errAuditChainBroken
+			}
+			previousHash = entry.Hash
+		}
+		return nil
+	}).Error
+	if err != nil && err != errAuditChainBroken {
+		return result, err
+	}
+	return result, nil
+}
+
+var errAuditChainBroken = fmt.Errorf("audit chain broken")
+
+func auditCapturePrevious(tx *gorm.DB, model interface{}, id interface{}) error {
+	previous := reflect.New(reflect.TypeOf(model).Elem()).Interface()
+	err := tx.Session(&gorm.Session{NewDB: true}).Where("id = ?", id).Take(previous).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	// hooks receive a session sharing the statement of the running update, so the snapshot
+	// stored here is visible to the matching AfterUpdate call.
+	tx.Statement.Settings.Store(fmt.Sprintf("%v%p", auditPreviousKey, model), previous)
+	return nil
+}
+
+func auditRecord(tx *gorm.DB, entityType, transactionID, action string, id interface{}, model interface{}) error {
+	var previous, current = map[string]interface{}{}, map[string]interface{}{}
+
+	switch action {
+	case AuditActionCreate, AuditActionRestore:
+		current = auditSnapshot(model)
+	case AuditActionDelete, AuditActionPurge:
+		previous = auditSnapshot(model)
+	case AuditActionUpdate:
+		before := map[string]interface{}{}
+		if value, ok := tx.Statement.Settings.Load(fmt.Sprintf("%v%p", auditPreviousKey, model)); ok {
+			before = auditSnapshot(value)
+		}
+		after := auditSnapshot(model)
+		for key, value := range after {
+			if key == "created_at" || key == "updated_at" || reflect.DeepEqual(before[key], value) {
+				continue
+			}
+			previous[key] = before[key]
+			current[key] = value
+		}
+		if len(current) == 0 {
+			return nil
+		}
+	}
+
+	entry := AuditLog{
+		EntityType:     entityType,
+		EntityID:       fmt.Sprintf("%v", id),
+		TransactionID:  transactionID,
+		Action:         action,
+		PreviousValues: previous,
+		NewValues:      current,
+	}
+	// Outside of a request there is no principal and the entry is attributed to the system.
+	if principal, ok := PrincipalFromContext(tx.Statement.Context); ok {
+		entry.ActorAccountID, entry.ActorType = principal.AccountID, principal.AuthType
+	}
+	if entry.ActorType == "" {
+		entry.ActorType = "system"
+	}
+	return entry.Append(tx)
+}
+
+// auditSnapshot round trips the model through json so that stored values hash the same
+// way once they have been read back from the jsonb columns.
+func auditSnapshot(model interface{}) map[string]interface{} {
+	snapshot := map[string]interface{}{}
+	b, err := json.Marshal(model)
+	if err != nil {
+		return snapshot
+	}
+	_ = json.Unmarshal(b, &snapshot)
+	return snapshot
+}
+
+func (t *Transaction) auditEntity() (string, string, interface{}) {
+	return AuditEntityTransaction, t.TransactionID, t.ID
+}
+func (t *Transaction) AfterCreate(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransaction, t.TransactionID, AuditActionCreate, t.ID, t)
+}
+func (t *Transaction) BeforeUpdate(tx *gorm.DB) error {
+	return auditCapturePrevious(tx, t, t.ID)
+}
+func (t *Transaction) AfterUpdate(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransaction, t.TransactionID, AuditActionUpdate, t.ID, t)
+}
+func (t *Transaction) AfterDelete(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransaction, t.TransactionID, AuditActionDelete, t.ID, t)
+}
+
+func (t *TransactionParty) auditEntity() (string, string, interface{}) {
+	return AuditEntityTransactionParty, t.TransactionID, t.ID
+}
+func (t *TransactionParty) AfterCreate(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransactionParty, t.TransactionID, AuditActionCreate, t.ID, t)
+}
+func (t *TransactionParty) BeforeUpdate(tx *gorm.DB) error {
+	return auditCapturePrevious(tx, t, t.ID)
+}
+func (t *TransactionParty) AfterUpdate(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransactionParty, t.TransactionID, AuditActionUpdate, t.ID, t)
+}
+func (t *TransactionParty) AfterDelete(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransactionParty, t.TransactionID, AuditActionDelete, t.ID, t)
+}
+
+func (t *TransactionBroker) auditEntity() (string, string, interface{}) {
+	return AuditEntityTransactionBroker, t.TransactionID, t.ID
+}
+func (t *TransactionBroker) AfterCreate(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransactionBroker, t.TransactionID, AuditActionCreate, t.ID, t)
+}
+func (t *TransactionBroker) BeforeUpdate(tx *gorm.DB) error {
+	return auditCapturePrevious(tx, t, t.ID)
+}
+func (t *TransactionBroker) AfterUpdate(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransactionBroker, t.TransactionID, AuditActionUpdate, t.ID, t)
+}
+func (t *TransactionBroker) AfterDelete(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransactionBroker, t.TransactionID, AuditActionDelete, t.ID, t)
+}
+
+func (t *TransactionDispute) auditEntity() (string, string, interface{}) {
+	return AuditEntityTransactionDispute, t.TransactionID, t.ID
+}
+func (t *TransactionDispute) AfterCreate(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransactionDispute, t.TransactionID, AuditActionCreate, t.ID, t)
+}
+func (t *TransactionDispute) BeforeUpdate(tx *gorm.DB) error {
+	return auditCapturePrevious(tx, t, t.ID)
+}
+func (t *TransactionDispute) AfterUpdate(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransactionDispute, t.TransactionID, AuditActionUpdate, t.ID, t)
+}
+func (t *TransactionDispute) AfterDelete(tx *gorm.DB) error {
+	return auditRecord(tx, AuditEntityTransactionDispute, t.TransactionID, AuditActionDelete, t.ID, t)
+}
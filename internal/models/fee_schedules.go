@@ -0,0 +1,284 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"gorm.io/gorm"
+)
+
+const (
+	ChargeBearerBuyer  = "buyer"
+	ChargeBearerSeller = "seller"
+	ChargeBearerSplit  = "split"
+)
+
+// FeeSchedule is one version of an escrow fee schedule. Schedules are never edited in place: a change
+// is stored as a new row with the same FeeScheduleID and the next Version, so the charge applied to any
+// transaction can be traced back to the exact rules in force when it was created.
+// A BusinessID of 0, an empty Currency or an empty TransactionType matches any value.
+type FeeSchedule struct {
+	ID              uint       `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
+	FeeScheduleID   string     `gorm:"column:fee_schedule_id; type:varchar(255); not null; index; comment: shared by every version of the schedule" json:"fee_schedule_id"`
+	Version         int        `gorm:"column:version; type:int; not null" json:"version"`
+	BusinessID      int        `gorm:"column:business_id; type:int; not null; default:0; index; comment: 0 applies to every business" json:"business_id"`
+	Currency        string     `gorm:"column:currency; type:varchar(255); comment: empty applies to every currency" json:"currency"`
+	TransactionType string     `gorm:"column:transaction_type; type:varchar(255); comment: oneoff, milestone or empty for both" json:"transaction_type"`
+	Tiers           feeTiers   `gorm:"column:tiers; type:jsonb; not null" json:"tiers"`
+	MinCharge       float64    `gorm:"column:min_charge; type:decimal(20,2); default:0" json:"min_charge"`
+	MaxCharge       float64    `gorm:"column:max_charge; type:decimal(20,2); default:0; comment: 0 means no cap" json:"max_charge"`
+	ChargeBearer    string     `gorm:"column:charge_bearer; type:varchar(50); not null; default:buyer" json:"charge_bearer"`
+	BuyerSplit      float64    `gorm:"column:buyer_split; type:decimal(5,2); default:0; comment: percentage of the charge paid by the buyer when the bearer is split" json:"buyer_split"`
+	EffectiveFrom   time.Time  `gorm:"column:effective_from; not null" json:"effective_from"`
+	EffectiveTo     *time.Time `gorm:"column:effective_to" json:"effective_to"`
+	IsActive        bool       `gorm:"column:is_active; type:bool" json:"is_active"`
+	CreatedAt       time.Time  `gorm:"column:created_at; autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+}
+
+// FeeTier applies to amounts up to and including UpTo. The last tier should leave UpTo at 0,
+// which means it has no upper bound.
+type FeeTier struct {
+	UpTo       float64 `json:"up_to" validate:"gte=0"`
+	Percentage float64 `json:"percentage" validate:"gte=0,lte=100"`
+	Flat       float64 `json:"flat" validate:"gte=0"`
+}
+
+type feeTiers []FeeTier
+
+func (t feeTiers) Value() (driver.Value, error) {
+	if t == nil {
+		t = feeTiers{}
+	}
+	return json.Marshal(t)
+}
+
+func (t *feeTiers) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	default:
+		return fmt.Errorf("type assertion to []byte or string failed: %v", value)
+	}
+}
+
+type CreateFeeScheduleRequest struct {
+	BusinessID      int       `json:"business_id" pgvalidate:"exists=auth$business_profiles$account_id"`
+	Currency        string    `json:"currency"`
+	TransactionType string    `json:"transaction_type" validate:"omitempty,oneof=oneoff milestone"`
+	Tiers           []FeeTier `json:"tiers" validate:"required,min=1,dive"`
+	MinCharge       float64   `json:"min_charge" validate:"gte=0"`
+	MaxCharge       float64   `json:"max_charge" validate:"gte=0"`
+	ChargeBearer    string    `json:"charge_bearer" validate:"required,oneof=buyer seller split"`
+	BuyerSplit      float64   `json:"buyer_split" validate:"gte=0,lte=100"`
+	EffectiveFrom   string    `json:"effective_from"`
+	EffectiveTo     string    `json:"effective_to"`
+}
+
+type UpdateFeeScheduleRequest struct {
+	Tiers         []FeeTier `json:"tiers" validate:"omitempty,min=1,dive"`
+	MinCharge     *float64  `json:"min_charge" validate:"omitempty,gte=0"`
+	MaxCharge     *float64  `json:"max_charge" validate:"omitempty,gte=0"`
+	ChargeBearer  string    `json:"charge_bearer" validate:"omitempty,oneof=buyer seller split"`
+	BuyerSplit    *float64  `json:"buyer_split" validate:"omitempty,gte=0,lte=100"`
+	EffectiveFrom string    `json:"effective_from"`
+	EffectiveTo   string    `json:"effective_to"`
+	IsActive      *bool     `json:"is_active"`
+}
+
+type ListFeeSchedulesRequest struct {
+	BusinessID      int
+	Currency        string
+	TransactionType string
+}
+
+// EscrowFee is the escrow charge for an amount together with how it is shared between the parties.
+type EscrowFee struct {
+	Charge        float64 `json:"charge"`
+	BuyerCharge   float64 `json:"buyer_charge"`
+	SellerCharge  float64 `json:"seller_charge"`
+	ChargeBearer  string  `json:"charge_bearer"`
+	FeeScheduleID string  `json:"fee_schedule_id"`
+	Version       int     `json:"version"`
+}
+
+func (f *FeeSchedule) CreateFeeSchedule(db *gorm.DB) error {
+	err := postgresql.CreateOneRecord(db, &f)
+	if err != nil {
+		return fmt.Errorf("fee schedule creation failed: %v", err.Error())
+	}
+	return nil
+}
+
+// GetLatestVersion loads the highest version of the schedule identified by FeeScheduleID.
+func (f *FeeSchedule) GetLatestVersion(db *gorm.DB) (int, error) {
+	err, nilErr := postgresql.SelectLatestFromDb(db, &f, "fee_schedule_id = ?", f.FeeScheduleID)
+	if nilErr != nil {
+		return http.StatusBadRequest, nilErr
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func (f *FeeSchedule) GetAllVersions(db *gorm.DB) ([]FeeSchedule, error) {
+	details := []FeeSchedule{}
+	err := postgresql.SelectAllFromDbOrderBy(db, "version", "asc", &details, "fee_schedule_id = ?", f.FeeScheduleID)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
+// GetAllLatest lists the latest version of every schedule matching the filters.
+func (f *FeeSchedule) GetAllLatest(db *gorm.DB, req ListFeeSchedulesRequest, paginator postgresql.Pagination) ([]FeeSchedule, postgresql.PaginationResponse, error) {
+	var (
+		details = []FeeSchedule{}
+		query   = `version = (SELECT MAX(fs.version) FROM fee_schedules fs WHERE fs.fee_schedule_id = fee_schedules.fee_schedule_id)`
+		args    = []interface{}{}
+	)
+
+	if req.BusinessID != 0 {
+		query = addQuery(query, "business_id = ?", "AND")
+		args = append(args, req.BusinessID)
+	}
+	if req.Currency != "" {
+		query = addQuery(query, "currency = ?", "AND")
+		args = append(args, req.Currency)
+	}
+	if req.TransactionType != "" {
+		query = addQuery(query, "transaction_type = ?", "AND")
+		args = append(args, req.TransactionType)
+	}
+
+	pagination, err := postgresql.SelectAllFromDbOrderByPaginated(db, "id", "desc", paginator, &details, query, args...)
+	if err != nil {
+		return details, pagination, err
+	}
+	return details, pagination, nil
+}
+
+// GetEffective finds the schedule that applies to a business, currency and transaction type at a point
+// in time. Only the latest version of each schedule is considered. A schedule naming the business beats
+// the default one, then one naming the currency, then one naming the transaction type; ties go to the
+// schedule that took effect most recently.
+func (f *FeeSchedule) GetEffective(db *gorm.DB, businessID int, currency, transactionType string, at time.Time) (bool, error) {
+	candidates := []FeeSchedule{}
+	err := db.Where(`version = (SELECT MAX(fs.version) FROM fee_schedules fs WHERE fs.fee_schedule_id = fee_schedules.fee_schedule_id)`).
+		Where("is_active = ?", true).
+		Where("business_id IN ?", []int{0, businessID}).
+		Where("currency IN ?", []string{"", currency}).
+		Where("transaction_type IN ?", []string{"", transactionType}).
+		Where("effective_from <= ?", at).
+		Where("(effective_to IS NULL OR effective_to > ?)", at).
+		Find(&candidates).Error
+	if err != nil {
+		return false, err
+	}
+
+	effective, found := MostSpecificFeeSchedule(candidates)
+	if found {
+		*f = effective
+	}
+	return found, nil
+}
+
+// AppliesTo reports whether the schedule is in force for a business, currency and transaction type at
+// a point in time. It does not check that f is the latest version of its schedule.
+func (f FeeSchedule) AppliesTo(businessID int, currency, transactionType string, at time.Time) bool {
+	return f.IsActive &&
+		(f.BusinessID == 0 || f.BusinessID == businessID) &&
+		(f.Currency == "" || f.Currency == currency) &&
+		(f.TransactionType == "" || f.TransactionType == transactionType) &&
+		!f.EffectiveFrom.After(at) &&
+		(f.EffectiveTo == nil || f.EffectiveTo.After(at))
+}
+
+// MostSpecificFeeSchedule picks the schedule GetEffective would out of candidates that all apply.
+func MostSpecificFeeSchedule(candidates []FeeSchedule) (FeeSchedule, bool) {
+	if len(candidates) == 0 {
+		return FeeSchedule{}, false
+	}
+
+	candidates = append([]FeeSchedule{}, candidates...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.BusinessID != 0) != (b.BusinessID != 0) {
+			return a.BusinessID != 0
+		}
+		if (a.Currency != "") != (b.Currency != "") {
+			return a.Currency != ""
+		}
+		if (a.TransactionType != "") != (b.TransactionType != "") {
+			return a.TransactionType != ""
+		}
+		return a.EffectiveFrom.After(b.EffectiveFrom)
+	})
+	return candidates[0], true
+}
+
+// Charge works out the escrow charge on amount using the smallest tier that covers it, clamped to the
+// schedule's caps, and splits it between buyer and seller according to the charge bearer.
+func (f *FeeSchedule) Charge(amount float64) EscrowFee {
+	var (
+		tiers  = append(feeTiers{}, f.Tiers...)
+		charge float64
+	)
+
+	sort.SliceStable(tiers, func(i, j int) bool {
+		if tiers[i].UpTo == 0 || tiers[j].UpTo == 0 {
+			return tiers[j].UpTo == 0 && tiers[i].UpTo != 0
+		}
+		return tiers[i].UpTo < tiers[j].UpTo
+	})
+	for i, tier := range tiers {
+		// amounts above the last bounded tier are charged at that tier
+		if tier.UpTo == 0 || amount <= tier.UpTo || i == len(tiers)-1 {
+			charge = amount*tier.Percentage/100 + tier.Flat
+			break
+		}
+	}
+
+	if f.MinCharge > 0 && charge < f.MinCharge {
+		charge = f.MinCharge
+	}
+	if f.MaxCharge > 0 && charge > f.MaxCharge {
+		charge = f.MaxCharge
+	}
+
+	fee := EscrowFee{
+		Charge:        charge,
+		ChargeBearer:  f.ChargeBearer,
+		FeeScheduleID: f.FeeScheduleID,
+		Version:       f.Version,
+	}
+	fee.Split(f.BuyerSplit)
+	return fee
+}
+
+// Split divides Charge between buyer and seller. When the bearer is split, buyerSplit is the buyer's
+// percentage of the charge, with 0 meaning an even split.
+func (e *EscrowFee) Split(buyerSplit float64) {
+	switch e.ChargeBearer {
+	case ChargeBearerSeller:
+		e.BuyerCharge, e.SellerCharge = 0, e.Charge
+	case ChargeBearerSplit:
+		share := buyerSplit
+		if share <= 0 {
+			share = 50
+		}
+		e.BuyerCharge = e.Charge * share / 100
+		e.SellerCharge = e.Charge - e.BuyerCharge
+	default:
+		e.ChargeBearer = ChargeBearerBuyer
+		e.BuyerCharge, e.SellerCharge = e.Charge, 0
+	}
+}
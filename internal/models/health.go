@@ -0,0 +1,20 @@
+package models
+
+const (
+	HealthStatusOk       = "ok"
+	HealthStatusDegraded = "degraded"
+	HealthStatusDown     = "down"
+)
+
+type HealthCheck struct {
+	Status    string      `json:"status"`
+	Critical  bool        `json:"critical"`
+	LatencyMs int64       `json:"latency_ms"`
+	Error     string      `json:"error,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
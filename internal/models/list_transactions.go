@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/vesicash/transactions-ms/external/external_models"
+	"gorm.io/gorm"
 )
 
 type TransactionByIDResponse struct {
@@ -23,7 +24,7 @@ type TransactionByIDResponse struct {
 	ShippingFee         float64                     `json:"shipping_fee"`
 	GracePeriod         string                      `json:"grace_period"`
 	Currency            string                      `json:"currency"`
-	DeletedAt           time.Time                   `json:"deleted_at"`
+	DeletedAt           gorm.DeletedAt              `json:"deleted_at"`
 	CreatedAt           time.Time                   `json:"created_at"`
 	UpdatedAt           time.Time                   `json:"updated_at"`
 	BusinessID          int                         `json:"business_id"`
@@ -65,7 +66,7 @@ type TransactionCreateResponse struct {
 	DueDate          string               `json:"due_date"`
 	ShippingFee      float64              `json:"shipping_fee"`
 	Currency         string               `json:"currency"`
-	DeletedAt        time.Time            `json:"deleted_at"`
+	DeletedAt        gorm.DeletedAt       `json:"deleted_at"`
 	CreatedAt        time.Time            `json:"created_at"`
 	UpdatedAt        time.Time            `json:"updated_at"`
 	IsPaylinked      bool                 `json:"is_paylinked"`
@@ -88,6 +89,8 @@ type ListTransactionsRequest struct {
 	Status     string `json:"status"`
 	StatusCode string `json:"status_code"`
 	Filter     string `json:"filter"`
+	// BusinessID limits the listing to one business. It is set from the caller, never the body.
+	BusinessID int `json:"-"`
 }
 type ListTransactionByBusinessRequest struct {
 	BusinessID int    `json:"business_id" validate:"required" pgvalidate:"exists=auth$business_profiles$account_id"`
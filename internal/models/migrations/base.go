@@ -2,16 +2,14 @@ package migrations
 
 import (
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
-	"gorm.io/gorm"
 )
 
-func RunAllMigrations(db postgresql.Databases) {
-
-	// verification migration
-	MigrateModels(db.Transaction, AuthMigrationModels())
-
-}
-
-func MigrateModels(db *gorm.DB, models []interface{}) {
-	_ = db.AutoMigrate(models...)
+// RunAllMigrations applies every pending migration to the transaction database.
+func RunAllMigrations(db postgresql.Databases) error {
+	migrator, err := NewMigrator(db.Transaction)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(0)
+	return err
 }
@@ -0,0 +1,283 @@
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// noTransaction marks a migration, on its first line, that must run outside a transaction, such as
+// one building indexes concurrently. Its statements are run one at a time, split at semicolons that
+// end a line.
+const noTransaction = "-- migrate:no-transaction"
+
+// lockID keys the advisory lock that keeps replicas from migrating at the same time.
+const lockID = 7245190348
+
+// Migration is one version of the schema, read from <version>_<name>.up.sql and the matching
+// .down.sql in the sql directory.
+type Migration struct {
+	Version           int64
+	Name              string
+	Up                string
+	Down              string
+	UpNoTransaction   bool
+	DownNoTransaction bool
+}
+
+// SchemaMigration records an applied migration.
+type SchemaMigration struct {
+	Version   int64     `gorm:"column:version;primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	AppliedAt time.Time `gorm:"column:applied_at;not null" json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+type Migrator struct {
+	Db         *gorm.DB
+	Migrations []Migration
+}
+
+// NewMigrator returns a migrator for the migrations shipped with the service.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	sub, err := fs.Sub(sqlFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := LoadMigrations(sub)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{Db: db, Migrations: migrations}, nil
+}
+
+// LoadMigrations reads the migrations in fsys, ordered by version. Every version needs both an up
+// and a down file.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, name := range names {
+		base := strings.TrimSuffix(path.Base(name), ".sql")
+		direction := path.Ext(base)
+		base = strings.TrimSuffix(base, direction)
+		versionStr, migrationName, ok := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if !ok || err != nil || version <= 0 || (direction != ".up" && direction != ".down") {
+			return nil, fmt.Errorf("migration file %v must be named <version>_<name>.up.sql or <version>_<name>.down.sql", name)
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: migrationName}
+			byVersion[version] = m
+		} else if m.Name != migrationName {
+			return nil, fmt.Errorf("migration %v is named both %v and %v", version, m.Name, migrationName)
+		}
+
+		sql := string(content)
+		if direction == ".up" {
+			m.Up, m.UpNoTransaction = sql, strings.HasPrefix(sql, noTransaction)
+		} else {
+			m.Down, m.DownNoTransaction = sql, strings.HasPrefix(sql, noTransaction)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %v_%v needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies the pending migrations up to and including target, or all of them when target is 0,
+// and returns the ones it applied.
+func (m *Migrator) Up(target int64) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(func(db *gorm.DB, done map[int64]SchemaMigration) error {
+		for _, migration := range m.Migrations {
+			if target > 0 && migration.Version > target {
+				break
+			}
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			err := run(db, migration.Up, migration.UpNoTransaction, func(tx *gorm.DB) error {
+				return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("applying migration %v_%v: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations, newest first, and returns the ones it rolled
+// back.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	var rolledBack []Migration
+	err := m.locked(func(db *gorm.DB, done map[int64]SchemaMigration) error {
+		for i := len(m.Migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			migration := m.Migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			err := run(db, migration.Down, migration.DownNoTransaction, func(tx *gorm.DB) error {
+				return tx.Delete(&SchemaMigration{}, "version = ?", migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("rolling back migration %v_%v: %w", migration.Version, migration.Name, err)
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	done := map[int64]SchemaMigration{}
+	if m.Db.Migrator().HasTable(&SchemaMigration{}) {
+		var err error
+		if done, err = appliedMigrations(m.Db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := []MigrationStatus{}
+	for _, migration := range m.Migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := done[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied, status.AppliedAt = true, &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending lists the migrations that have not been applied.
+func (m *Migrator) Pending() ([]MigrationStatus, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	pending := []MigrationStatus{}
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status)
+		}
+	}
+	return pending, nil
+}
+
+// locked runs fn on a single connection holding the migration lock, with the migrations applied as
+// of taking it.
+func (m *Migrator) locked(fn func(db *gorm.DB, done map[int64]SchemaMigration) error) error {
+	return m.Db.Connection(func(db *gorm.DB) error {
+		if err := db.Exec("SELECT pg_advisory_lock(?)", lockID).Error; err != nil {
+			return fmt.Errorf("taking migration lock: %w", err)
+		}
+		defer db.Exec("SELECT pg_advisory_unlock(?)", lockID)
+
+		if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+			return err
+		}
+		done, err := appliedMigrations(db)
+		if err != nil {
+			return err
+		}
+		return fn(db, done)
+	})
+}
+
+func appliedMigrations(db *gorm.DB) (map[int64]SchemaMigration, error) {
+	var records []SchemaMigration
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	done := map[int64]SchemaMigration{}
+	for _, record := range records {
+		done[record.Version] = record
+	}
+	return done, nil
+}
+
+// run executes sql and then record, in one transaction unless noTx is set.
+func run(db *gorm.DB, sql string, noTx bool, record func(tx *gorm.DB) error) error {
+	if !noTx {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(sql).Error; err != nil {
+				return err
+			}
+			return record(tx)
+		})
+	}
+
+	for _, statement := range SplitStatements(sql) {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return record(db)
+}
+
+// SplitStatements splits sql at semicolons ending a line, leaving out comment lines and empty
+// statements.
+func SplitStatements(sql string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
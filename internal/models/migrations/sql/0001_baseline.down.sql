@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS request_nonces;
+DROP TABLE IF EXISTS rate_limit_counters;
+DROP TABLE IF EXISTS fee_schedules;
+DROP TABLE IF EXISTS rates;
+DROP TABLE IF EXISTS exchange_transactions;
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS activity_logs;
+DROP TABLE IF EXISTS product_transactions;
+DROP TABLE IF EXISTS transactions_rejected;
+DROP TABLE IF EXISTS transaction_states;
+DROP TABLE IF EXISTS transaction_files;
+DROP TABLE IF EXISTS transaction_due_date_extension_requests;
+DROP TABLE IF EXISTS transaction_disputes;
+DROP TABLE IF EXISTS transaction_brokers;
+DROP TABLE IF EXISTS transaction_parties;
+DROP TABLE IF EXISTS transactions;
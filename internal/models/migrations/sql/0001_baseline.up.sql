@@ -0,0 +1,240 @@
+-- Schema as created by gorm AutoMigrate before versioned migrations. Every statement is guarded so
+-- databases that were auto migrated can be baselined without changes.
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id bigserial PRIMARY KEY,
+    transaction_id varchar(255) NOT NULL,
+    parties_id varchar(255) NOT NULL,
+    milestone_id varchar(255),
+    broker_id varchar(255),
+    title varchar(255) NOT NULL,
+    type varchar(255),
+    description text NOT NULL,
+    amount decimal(20,2),
+    status varchar(255) DEFAULT 'Draft',
+    quantity bigint,
+    inspection_period varchar(255),
+    due_date varchar(255),
+    shipping_fee decimal(20,2),
+    grace_period varchar(255),
+    currency varchar(255),
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    business_id bigint,
+    is_paylinked boolean DEFAULT false,
+    country varchar(255),
+    source varchar(255) DEFAULT 'api',
+    trans_ussd_code bigint,
+    recipients varchar(255),
+    dispute_handler varchar(255),
+    amount_paid decimal(20,2),
+    escrow_charge decimal(20,2),
+    escrow_wallet varchar(255) DEFAULT 'no',
+    seller_escrow_charge decimal(20,2) DEFAULT 0,
+    fee_schedule_id varchar(255),
+    fee_schedule_version bigint
+);
+
+CREATE TABLE IF NOT EXISTS transaction_parties (
+    id bigserial PRIMARY KEY,
+    transaction_parties_id varchar(255) NOT NULL,
+    transaction_id varchar(255) NOT NULL,
+    account_id bigint,
+    role varchar(255) NOT NULL,
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    role_capabilities varchar(250) DEFAULT '{"can_view":true,"can_receive":false,"mark_as_done":false,"approve":true}',
+    role_description text,
+    status varchar(255) NOT NULL DEFAULT 'created'
+);
+
+CREATE TABLE IF NOT EXISTS transaction_brokers (
+    id bigserial PRIMARY KEY,
+    transaction_broker_id varchar(255) NOT NULL,
+    transaction_id varchar(255) NOT NULL,
+    broker_charge varchar(255) NOT NULL,
+    broker_charge_bearer varchar(255) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    broker_charge_type varchar(255) NOT NULL DEFAULT 'fixed',
+    is_seller_accepted boolean DEFAULT false,
+    is_buyer_accepted boolean DEFAULT false
+);
+
+CREATE TABLE IF NOT EXISTS transaction_disputes (
+    id bigserial PRIMARY KEY,
+    dispute_id text,
+    transaction_id text,
+    reason text,
+    dispute_status text,
+    decision text,
+    mediator_id text,
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS transaction_due_date_extension_requests (
+    id bigserial PRIMARY KEY,
+    account_id bigint NOT NULL,
+    transaction_id varchar(255) NOT NULL,
+    note text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS transaction_files (
+    id bigserial PRIMARY KEY,
+    transaction_id varchar(255) NOT NULL,
+    account_id bigint,
+    file_type varchar(255),
+    file_url varchar(255) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS transaction_states (
+    id bigserial PRIMARY KEY,
+    account_id bigint,
+    transaction_id text,
+    milestone_id text,
+    status text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS transactions_rejected (
+    id bigserial PRIMARY KEY,
+    account_id bigint NOT NULL,
+    transaction_id varchar(255) NOT NULL,
+    reason varchar(255) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS product_transactions (
+    id bigserial PRIMARY KEY,
+    transaction_id text NOT NULL,
+    product_transaction_id text NOT NULL,
+    title text,
+    quantity bigint,
+    photo text,
+    amount decimal,
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS activity_logs (
+    id bigserial PRIMARY KEY,
+    transaction_id varchar(255) NOT NULL,
+    milestone_id varchar(255),
+    event_type varchar(255) DEFAULT 'custom',
+    actor_account_id bigint,
+    actor_role varchar(255),
+    previous_status varchar(255),
+    new_status varchar(255),
+    metadata jsonb,
+    description text NOT NULL,
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id bigserial PRIMARY KEY,
+    entity_type varchar(255) NOT NULL,
+    entity_id varchar(255) NOT NULL,
+    transaction_id varchar(255),
+    action varchar(50) NOT NULL,
+    previous_values jsonb,
+    new_values jsonb,
+    actor_account_id bigint,
+    actor_type varchar(50),
+    previous_hash varchar(64),
+    hash varchar(64) NOT NULL UNIQUE,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_transaction_id ON audit_logs (transaction_id);
+
+CREATE TABLE IF NOT EXISTS exchange_transactions (
+    id bigserial PRIMARY KEY,
+    account_id varchar(255) NOT NULL,
+    initial_amount decimal(8,5),
+    final_amount decimal(8,2),
+    rate_id bigint NOT NULL,
+    status varchar(255) NOT NULL DEFAULT 'pending',
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS rates (
+    id bigserial PRIMARY KEY,
+    from_currency varchar(255) NOT NULL,
+    to_currency varchar(255) NOT NULL,
+    from_symbol varchar(255) NOT NULL,
+    to_symbol varchar(255) NOT NULL,
+    amount decimal(8,5) NOT NULL,
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    uid varchar(255),
+    initial_amount decimal(8,2)
+);
+
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    id bigserial PRIMARY KEY,
+    fee_schedule_id varchar(255) NOT NULL,
+    version bigint NOT NULL,
+    business_id bigint NOT NULL DEFAULT 0,
+    currency varchar(255),
+    transaction_type varchar(255),
+    tiers jsonb NOT NULL,
+    min_charge decimal(20,2) DEFAULT 0,
+    max_charge decimal(20,2) DEFAULT 0,
+    charge_bearer varchar(50) NOT NULL DEFAULT 'buyer',
+    buyer_split decimal(5,2) DEFAULT 0,
+    effective_from timestamptz NOT NULL,
+    effective_to timestamptz,
+    is_active boolean,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_fee_schedules_fee_schedule_id ON fee_schedules (fee_schedule_id);
+CREATE INDEX IF NOT EXISTS idx_fee_schedules_business_id ON fee_schedules (business_id);
+
+CREATE TABLE IF NOT EXISTS rate_limit_counters (
+    key varchar(255),
+    window_start timestamptz,
+    count bigint NOT NULL DEFAULT 0,
+    expires_at timestamptz,
+    PRIMARY KEY (key, window_start)
+);
+CREATE INDEX IF NOT EXISTS idx_rate_limit_counters_expires_at ON rate_limit_counters (expires_at);
+
+CREATE TABLE IF NOT EXISTS request_nonces (
+    nonce varchar(255) PRIMARY KEY,
+    expires_at timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_request_nonces_expires_at ON request_nonces (expires_at);
+
+-- Columns added to existing tables after they were first auto migrated. CREATE TABLE IF NOT EXISTS
+-- leaves those tables as they are, so databases migrated before the columns existed get them here.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS seller_escrow_charge decimal(20,2) DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee_schedule_id varchar(255);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee_schedule_version bigint;
+
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS milestone_id varchar(255);
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS event_type varchar(255) DEFAULT 'custom';
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS actor_account_id bigint;
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS actor_role varchar(255);
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS previous_status varchar(255);
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS new_status varchar(255);
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS metadata jsonb;
+ALTER TABLE activity_logs ALTER COLUMN description TYPE text;
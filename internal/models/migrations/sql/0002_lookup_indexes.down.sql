@@ -0,0 +1,21 @@
+-- migrate:no-transaction
+
+DROP INDEX CONCURRENTLY IF EXISTS idx_exchange_transactions_status;
+DROP INDEX CONCURRENTLY IF EXISTS idx_exchange_transactions_account_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_activity_logs_transaction_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_product_transactions_transaction_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transactions_rejected_transaction_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_states_transaction_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_files_transaction_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_due_date_extension_requests_transaction_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_disputes_dispute_status;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_disputes_transaction_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_brokers_transaction_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_parties_status;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_parties_account_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_parties_transaction_parties_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transaction_parties_transaction_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transactions_business_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transactions_status;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transactions_parties_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_transactions_transaction_id;
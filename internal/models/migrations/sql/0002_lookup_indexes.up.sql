@@ -0,0 +1,23 @@
+-- migrate:no-transaction
+-- Indexes for the columns transactions and their rows are looked up by. Built concurrently so
+-- writes carry on while they are.
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transactions_transaction_id ON transactions (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transactions_parties_id ON transactions (parties_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transactions_status ON transactions (status);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transactions_business_id ON transactions (business_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_parties_transaction_id ON transaction_parties (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_parties_transaction_parties_id ON transaction_parties (transaction_parties_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_parties_account_id ON transaction_parties (account_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_parties_status ON transaction_parties (status);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_brokers_transaction_id ON transaction_brokers (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_disputes_transaction_id ON transaction_disputes (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_disputes_dispute_status ON transaction_disputes (dispute_status);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_due_date_extension_requests_transaction_id ON transaction_due_date_extension_requests (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_files_transaction_id ON transaction_files (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transaction_states_transaction_id ON transaction_states (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_transactions_rejected_transaction_id ON transactions_rejected (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_product_transactions_transaction_id ON product_transactions (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_activity_logs_transaction_id ON activity_logs (transaction_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_exchange_transactions_account_id ON exchange_transactions (account_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_exchange_transactions_status ON exchange_transactions (status);
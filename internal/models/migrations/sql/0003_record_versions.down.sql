@@ -0,0 +1,4 @@
+ALTER TABLE transaction_disputes DROP COLUMN IF EXISTS version;
+ALTER TABLE transaction_brokers DROP COLUMN IF EXISTS version;
+ALTER TABLE transaction_parties DROP COLUMN IF EXISTS version;
+ALTER TABLE transactions DROP COLUMN IF EXISTS version;
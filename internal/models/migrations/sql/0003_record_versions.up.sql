@@ -0,0 +1,7 @@
+-- Versions for optimistic locking: an update only applies when the row is still at the version it
+-- was read at.
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE transaction_parties ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE transaction_brokers ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE transaction_disputes ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+-- Rows soft deleted since the up migration become visible again to code that ignores deleted_at.
+
+ALTER TABLE transaction_parties DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE transaction_files DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE transaction_brokers DROP COLUMN IF EXISTS deleted_at;
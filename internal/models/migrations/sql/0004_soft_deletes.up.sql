@@ -0,0 +1,21 @@
+-- Soft deletes: a row is deleted once deleted_at is set. The old non-nullable columns were written
+-- with the zero time, which has to become NULL or every existing row would read as deleted.
+
+ALTER TABLE transaction_brokers ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+ALTER TABLE transaction_files ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+
+UPDATE transactions SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE transaction_parties SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE transaction_disputes SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE transaction_due_date_extension_requests SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE transactions_rejected SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE product_transactions SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+
+-- Archiving is per party. Transactions archived the old way, through a sender party on a
+-- transaction in the Deleted status, stay archived for that party.
+ALTER TABLE transaction_parties ADD COLUMN IF NOT EXISTS archived_at timestamptz;
+
+UPDATE transaction_parties SET archived_at = now()
+WHERE role = 'sender' AND archived_at IS NULL AND transaction_id IN (
+    SELECT transaction_id FROM transactions WHERE LOWER(status) = 'deleted'
+);
@@ -0,0 +1,51 @@
+package models
+
+type PayoutPreviewRequest struct {
+	TransactionID      string                    `json:"transaction_id" pgvalidate:"exists=transaction$transactions$transaction_id"`
+	Draft              *CreateTransactionRequest `json:"draft"`
+	BrokerCharge       string                    `json:"broker_charge"`
+	BrokerChargeBearer string                    `json:"broker_charge_bearer" validate:"omitempty,oneof=buyer seller"`
+	BrokerChargeType   string                    `json:"broker_charge_type" validate:"omitempty,oneof=fixed percentage"`
+}
+
+type PayoutPreview struct {
+	TransactionID      string            `json:"transaction_id"`
+	Currency           string            `json:"currency"`
+	MilestonesAmount   float64           `json:"milestones_amount"`
+	ShippingFee        float64           `json:"shipping_fee"`
+	EscrowCharge       float64           `json:"escrow_charge"`
+	SellerEscrowCharge float64           `json:"seller_escrow_charge"`
+	BrokerCharge       float64           `json:"broker_charge"`
+	BrokerChargeBearer string            `json:"broker_charge_bearer"`
+	BrokerAccepted     bool              `json:"broker_accepted"`
+	BuyerTotal         float64           `json:"buyer_total"`
+	PlatformAmount     float64           `json:"platform_amount"`
+	BrokerAmount       float64           `json:"broker_amount"`
+	Milestones         []MilestonePayout `json:"milestones"`
+	Balanced           bool              `json:"balanced"`
+	Issues             []string          `json:"issues"`
+}
+
+type MilestonePayout struct {
+	Index              int               `json:"index"`
+	MilestoneID        string            `json:"milestone_id"`
+	Title              string            `json:"title"`
+	Amount             float64           `json:"amount"`
+	ShippingFee        float64           `json:"shipping_fee"`
+	EscrowCharge       float64           `json:"escrow_charge"`
+	SellerEscrowCharge float64           `json:"seller_escrow_charge"`
+	BrokerCharge       float64           `json:"broker_charge"`
+	Unallocated        float64           `json:"unallocated"`
+	Recipients         []RecipientPayout `json:"recipients"`
+	Balanced           bool              `json:"balanced"`
+	Issues             []string          `json:"issues"`
+}
+
+type RecipientPayout struct {
+	AccountID    int     `json:"account_id"`
+	Amount       float64 `json:"amount"`
+	ShippingFee  float64 `json:"shipping_fee"`
+	EscrowCharge float64 `json:"escrow_charge"`
+	BrokerCharge float64 `json:"broker_charge"`
+	Payout       float64 `json:"payout"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"context"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+)
+
+type PrincipalKind string
+
+const (
+	PrincipalUser     PrincipalKind = "user"
+	PrincipalBusiness PrincipalKind = "business"
+	PrincipalAdmin    PrincipalKind = "admin"
+	PrincipalApp      PrincipalKind = "app"
+)
+
+// Principal is the caller a request was authorized as. It is resolved by middleware.Authorize and
+// carried on the request context, never shared between requests.
+type Principal struct {
+	Kind PrincipalKind
+	// AuthType is the authorization type that admitted the request, recorded as the audit actor.
+	AuthType  string
+	AccountID int
+	// BusinessID is the business an API key principal acts for.
+	BusinessID int
+	// Scopes are what an API key principal was granted. Principals without scopes are not limited
+	// by them.
+	Scopes []string
+	// KeyID is the public key of a principal authorized with an API key pair.
+	KeyID string
+	// Plan is the plan the caller is on, which sets its rate limits.
+	Plan string
+	// Token is the bearer token of a user principal, forwarded to services that act on the user's
+	// behalf.
+	Token string
+	User  *external_models.User
+}
+
+// HasScope reports whether the principal may use an endpoint requiring scope.
+func (p Principal) HasScope(scope string) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal of the request ctx belongs to. It reports false
+// outside of an authorized request, such as in cron jobs.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	if ctx == nil {
+		return Principal{}, false
+	}
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
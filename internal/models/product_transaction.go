@@ -8,16 +8,16 @@ import (
 )
 
 type ProductTransaction struct {
-	ID                   int64     `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`
-	TransactionID        string    `gorm:"column:transaction_id;not null" json:"transaction_id"`
-	ProductTransactionID string    `gorm:"column:product_transaction_id;not null" json:"product_transaction_id"`
-	Title                string    `gorm:"column:title" json:"title"`
-	Quantity             int64     `gorm:"column:quantity" json:"quantity"`
-	Photo                string    `gorm:"column:photo" json:"photo"`
-	Amount               float64   `gorm:"column:amount" json:"amount"`
-	DeletedAt            time.Time `gorm:"column:deleted_at" json:"deleted_at"`
-	CreatedAt            time.Time `gorm:"column:created_at; autoCreateTime" json:"created_at"`
-	UpdatedAt            time.Time `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+	ID                   int64          `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"`
+	TransactionID        string         `gorm:"column:transaction_id;not null" json:"transaction_id"`
+	ProductTransactionID string         `gorm:"column:product_transaction_id;not null" json:"product_transaction_id"`
+	Title                string         `gorm:"column:title" json:"title"`
+	Quantity             int64          `gorm:"column:quantity" json:"quantity"`
+	Photo                string         `gorm:"column:photo" json:"photo"`
+	Amount               float64        `gorm:"column:amount" json:"amount"`
+	DeletedAt            gorm.DeletedAt `gorm:"column:deleted_at" json:"deleted_at"`
+	CreatedAt            time.Time      `gorm:"column:created_at; autoCreateTime" json:"created_at"`
+	UpdatedAt            time.Time      `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
 }
 
 func (p *ProductTransaction) GetAllByTransactionID(db *gorm.DB) ([]ProductTransaction, error) {
@@ -28,3 +28,12 @@ func (p *ProductTransaction) GetAllByTransactionID(db *gorm.DB) ([]ProductTransa
 	}
 	return details, nil
 }
+
+func (p *ProductTransaction) GetAllByTransactionIDs(db *gorm.DB, transactionIDs []string) ([]ProductTransaction, error) {
+	details := []ProductTransaction{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_id IN ? ", transactionIDs)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
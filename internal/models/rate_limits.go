@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateLimitCounter counts the requests one caller made to one policy in a fixed window. Replicas
+// share the table, so a limit holds however many of them serve the caller.
+type RateLimitCounter struct {
+	Key         string    `gorm:"column:key;type:varchar(255);primaryKey" json:"key"`
+	WindowStart time.Time `gorm:"column:window_start;primaryKey" json:"window_start"`
+	Count       int       `gorm:"column:count;not null;default:0" json:"count"`
+	ExpiresAt   time.Time `gorm:"column:expires_at;index" json:"expires_at"`
+}
+
+// Increment adds one to the counter, creating it on the first request of the window, and reads
+// back the new count in the same statement.
+func (r *RateLimitCounter) Increment(db *gorm.DB) error {
+	r.Count = 1
+	return db.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}, {Name: "window_start"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("rate_limit_counters.count + 1")}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "count"}}},
+	).Create(r).Error
+}
+
+// Get reads the counter for r's key and window, leaving Count at 0 when nothing has been counted.
+func (r *RateLimitCounter) Get(db *gorm.DB) error {
+	err := db.Where(&RateLimitCounter{Key: r.Key, WindowStart: r.WindowStart}).Take(r).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		r.Count = 0
+		return nil
+	}
+	return err
+}
+
+func (r RateLimitCounter) DeleteExpired(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("expires_at < ?", before).Delete(&RateLimitCounter{})
+	return result.RowsAffected, result.Error
+}
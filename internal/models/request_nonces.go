@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RequestNonce is a nonce a signed request has used. It is kept until the request's timestamp falls
+// outside the allowed clock skew, after which the timestamp check alone rejects a replay.
+type RequestNonce struct {
+	Nonce     string    `gorm:"column:nonce;type:varchar(255);primaryKey" json:"nonce"`
+	ExpiresAt time.Time `gorm:"column:expires_at;index" json:"expires_at"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+// Claim records the nonce and reports false when it was already recorded.
+func (n *RequestNonce) Claim(db *gorm.DB) (bool, error) {
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(n)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (n RequestNonce) DeleteExpired(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("expires_at < ?", before).Delete(&RequestNonce{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// transactionTables are the tables holding a transaction's rows, soft deleted, restored and purged
+// together with it. The transactions table comes first.
+var transactionTables = []transactionTableRows{
+	transactionTable[Transaction]{},
+	transactionTable[TransactionParty]{},
+	transactionTable[TransactionBroker]{},
+	transactionTable[TransactionDispute]{},
+	transactionTable[TransactionFile]{},
+	transactionTable[TransactionDueDateExtensionRequest]{},
+	transactionTable[TransactionsRejected]{},
+	transactionTable[ProductTransaction]{},
+}
+
+type transactionTableRows interface {
+	softDelete(tx *gorm.DB, transactionID string) error
+	restore(tx *gorm.DB, transactionID string, deletedAt time.Time) error
+	purge(tx *gorm.DB, before time.Time) (int64, error)
+}
+
+// transactionTable works on the rows of the table T is stored in.
+type transactionTable[T any] struct{}
+
+// softDelete deletes the rows one at a time rather than in one statement, so the audit hooks see
+// each of them.
+func (transactionTable[T]) softDelete(tx *gorm.DB, transactionID string) error {
+	rows := []T{}
+	if err := tx.Where("transaction_id = ?", transactionID).Find(&rows).Error; err != nil {
+		return err
+	}
+	for i := range rows {
+		if err := tx.Delete(&rows[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restore and purge skip the hooks, which would try to audit an update and a delete of their own,
+// and audit the rows they bring back and remove themselves.
+func (transactionTable[T]) restore(tx *gorm.DB, transactionID string, deletedAt time.Time) error {
+	rows, err := auditedRows[T](tx, "transaction_id = ? AND deleted_at = ?", transactionID, deletedAt)
+	if err != nil {
+		return err
+	}
+	err = tx.Session(&gorm.Session{SkipHooks: true}).Unscoped().Model(new(T)).
+		Where("transaction_id = ? AND deleted_at = ?", transactionID, deletedAt).
+		Update("deleted_at", nil).Error
+	if err != nil {
+		return err
+	}
+	return auditRows(tx, rows, AuditActionRestore)
+}
+
+func (transactionTable[T]) purge(tx *gorm.DB, before time.Time) (int64, error) {
+	rows, err := auditedRows[T](tx, "deleted_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	result := tx.Session(&gorm.Session{SkipHooks: true}).Unscoped().Where("deleted_at < ?", before).Delete(new(T))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, auditRows(tx, rows, AuditActionPurge)
+}
+
+// auditedRow is a row the audit log keeps track of.
+type auditedRow interface {
+	auditEntity() (entityType, transactionID string, id interface{})
+}
+
+// auditedRows loads the deleted rows of T matching query, or none when T is not audited.
+func auditedRows[T any](tx *gorm.DB, query string, args ...interface{}) ([]T, error) {
+	rows := []T{}
+	if _, ok := any(new(T)).(auditedRow); !ok {
+		return rows, nil
+	}
+	err := tx.Unscoped().Where(query, args...).Find(&rows).Error
+	return rows, err
+}
+
+func auditRows[T any](tx *gorm.DB, rows []T, action string) error {
+	for i := range rows {
+		row, ok := any(&rows[i]).(auditedRow)
+		if !ok {
+			continue
+		}
+		entityType, transactionID, id := row.auditEntity()
+		if err := auditRecord(tx, entityType, transactionID, action, id, &rows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -10,27 +10,35 @@ import (
 )
 
 type TransactionBroker struct {
-	ID                  uint      `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
-	TransactionBrokerID string    `gorm:"column:transaction_broker_id; type:varchar(255); not null" json:"transaction_broker_id"`
-	TransactionID       string    `gorm:"column:transaction_id; type:varchar(255); not null; comment: 12 characters long string" json:"transaction_id"`
-	BrokerCharge        string    `gorm:"column:broker_charge; type:varchar(255); not null" json:"broker_charge"`
-	BrokerChargeBearer  string    `gorm:"column:broker_charge_bearer; type:varchar(255); not null" json:"broker_charge_bearer"`
-	CreatedAt           time.Time `gorm:"column:created_at; autoCreateTime" json:"created_at"`
-	UpdatedAt           time.Time `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
-	BrokerChargeType    string    `gorm:"column:broker_charge_type; type:varchar(255); not null;default:fixed; comment: fixed|percentage" json:"broker_charge_type"`
-	IsSellerAccepted    bool      `gorm:"column:is_seller_accepted; type:bool; default:false" json:"is_seller_accepted"`
-	IsBuyerAccepted     bool      `gorm:"column:is_buyer_accepted; type:bool; default:false" json:"is_buyer_accepted"`
+	ID                  uint           `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
+	TransactionBrokerID string         `gorm:"column:transaction_broker_id; type:varchar(255); not null" json:"transaction_broker_id"`
+	TransactionID       string         `gorm:"column:transaction_id; type:varchar(255); not null; comment: 12 characters long string" json:"transaction_id"`
+	BrokerCharge        string         `gorm:"column:broker_charge; type:varchar(255); not null" json:"broker_charge"`
+	BrokerChargeBearer  string         `gorm:"column:broker_charge_bearer; type:varchar(255); not null" json:"broker_charge_bearer"`
+	CreatedAt           time.Time      `gorm:"column:created_at; autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"column:deleted_at" json:"deleted_at"`
+	BrokerChargeType    string         `gorm:"column:broker_charge_type; type:varchar(255); not null;default:fixed; comment: fixed|percentage" json:"broker_charge_type"`
+	IsSellerAccepted    bool           `gorm:"column:is_seller_accepted; type:bool; default:false" json:"is_seller_accepted"`
+	IsBuyerAccepted     bool           `gorm:"column:is_buyer_accepted; type:bool; default:false" json:"is_buyer_accepted"`
+	Version             int            `gorm:"column:version; type:int; not null; default:1" json:"version"`
 }
 
 type UpdateTransactionBrokerRequest struct {
 	TransactionID      string `json:"transaction_id" validate:"required" pgvalidate:"exists=transaction$transactions$transaction_id"`
-	BrokerCharge       string `json:"broker_charge"`
+	BrokerCharge       string `json:"broker_charge" validate:"omitempty,numeric"`
 	BrokerChargeBearer string `json:"broker_charge_bearer"`
-	BrokerChargeType   string `json:"broker_charge_type"`
+	BrokerChargeType   string `json:"broker_charge_type" validate:"omitempty,oneof=fixed percentage"`
 	IsSellerAccepted   *bool  `json:"is_seller_accepted"`
 	IsBuyerAccepted    *bool  `json:"is_buyer_accepted"`
 }
 
+type BrokerTerms struct {
+	BrokerCharge       string `json:"broker_charge" validate:"required,numeric"`
+	BrokerChargeBearer string `json:"broker_charge_bearer" validate:"required,oneof=buyer seller"`
+	BrokerChargeType   string `json:"broker_charge_type" validate:"required,oneof=fixed percentage"`
+}
+
 func (t *TransactionBroker) GetTransactionBrokerByTransactionID(db *gorm.DB) (int, error) {
 	err, nilErr := postgresql.SelectOneFromDb(db, &t, "transaction_id = ?", t.TransactionID)
 	if nilErr != nil {
@@ -43,8 +51,26 @@ func (t *TransactionBroker) GetTransactionBrokerByTransactionID(db *gorm.DB) (in
 	return http.StatusOK, nil
 }
 
+func (t *TransactionBroker) GetAllByTransactionIDs(db *gorm.DB, transactionIDs []string) ([]TransactionBroker, error) {
+	details := []TransactionBroker{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_id IN ? ", transactionIDs)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
+// UpdateAllFields saves t unless its version moved on, like Transaction.UpdateAllFields.
 func (t *TransactionBroker) UpdateAllFields(db *gorm.DB) error {
-	_, err := postgresql.SaveAllFields(db, &t)
+	if t.ID == 0 {
+		_, err := postgresql.SaveAllFields(db, &t)
+		return err
+	}
+	t.Version++
+	err := postgresql.SaveVersioned(db, t, postgresql.RecordKey(AuditEntityTransactionBroker, t.ID), t.Version-1)
+	if err != nil {
+		t.Version--
+	}
 	return err
 }
 
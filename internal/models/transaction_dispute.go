@@ -10,16 +10,17 @@ import (
 )
 
 type TransactionDispute struct {
-	ID            int64     `gorm:"primary_key;AUTO_INCREMENT;column:id" json:"id"`
-	DisputeID     string    `gorm:"column:dispute_id" json:"dispute_id"`
-	TransactionID string    `gorm:"column:transaction_id" json:"transaction_id"`
-	Reason        string    `gorm:"column:reason" json:"reason"`
-	DisputeStatus string    `gorm:"column:dispute_status" json:"dispute_status"`
-	Decision      string    `gorm:"column:decision" json:"decision"`
-	MediatorID    string    `gorm:"column:mediator_id" json:"mediator_id"`
-	DeletedAt     time.Time `gorm:"column:deleted_at" json:"deleted_at"`
-	CreatedAt     time.Time `gorm:"column:created_at; autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+	ID            int64          `gorm:"primary_key;AUTO_INCREMENT;column:id" json:"id"`
+	DisputeID     string         `gorm:"column:dispute_id" json:"dispute_id"`
+	TransactionID string         `gorm:"column:transaction_id" json:"transaction_id"`
+	Reason        string         `gorm:"column:reason" json:"reason"`
+	DisputeStatus string         `gorm:"column:dispute_status" json:"dispute_status"`
+	Decision      string         `gorm:"column:decision" json:"decision"`
+	MediatorID    string         `gorm:"column:mediator_id" json:"mediator_id"`
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at" json:"deleted_at"`
+	CreatedAt     time.Time      `gorm:"column:created_at; autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+	Version       int            `gorm:"column:version; type:int; not null; default:1" json:"version"`
 }
 
 type CreateDisputeRequest struct {
@@ -49,6 +50,15 @@ func (t *TransactionDispute) IsDisputed(db *gorm.DB) (bool, error) {
 	return true, nil
 }
 
+func (t *TransactionDispute) GetAllByTransactionIDs(db *gorm.DB, transactionIDs []string) ([]TransactionDispute, error) {
+	details := []TransactionDispute{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_id IN ? ", transactionIDs)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
 func (t *TransactionDispute) GetTransactionDisputeByTransactionID(db *gorm.DB) (int, error) {
 	err, nilErr := postgresql.SelectOneFromDb(db, &t, "transaction_id = ?", t.TransactionID)
 	if nilErr != nil {
@@ -61,7 +71,23 @@ func (t *TransactionDispute) GetTransactionDisputeByTransactionID(db *gorm.DB) (
 	return http.StatusOK, nil
 }
 
+// CountUndecided returns how many disputes are yet to be given a decision.
+func (t *TransactionDispute) CountUndecided(db *gorm.DB) (int64, error) {
+	var count int64
+	err := db.Model(&TransactionDispute{}).Where("COALESCE(decision, '') = ''").Count(&count).Error
+	return count, err
+}
+
+// UpdateAllFields saves t unless its version moved on, like Transaction.UpdateAllFields.
 func (t *TransactionDispute) UpdateAllFields(db *gorm.DB) error {
-	_, err := postgresql.SaveAllFields(db, &t)
+	if t.ID == 0 {
+		_, err := postgresql.SaveAllFields(db, &t)
+		return err
+	}
+	t.Version++
+	err := postgresql.SaveVersioned(db, t, postgresql.RecordKey(AuditEntityTransactionDispute, t.ID), t.Version-1)
+	if err != nil {
+		t.Version--
+	}
 	return err
 }
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sections of a transaction response that are loaded separately and can be left out of a listing.
+const (
+	TransactionFieldProducts   = "products"
+	TransactionFieldParties    = "parties"
+	TransactionFieldFiles      = "files"
+	TransactionFieldMilestones = "milestones"
+	TransactionFieldBroker     = "broker"
+	TransactionFieldActivities = "activities"
+	TransactionFieldCountry    = "country"
+	TransactionFieldClosedAt   = "closed_at"
+	TransactionFieldDispute    = "dispute"
+	TransactionFieldPayment    = "payment"
+)
+
+var transactionFields = []string{
+	TransactionFieldProducts,
+	TransactionFieldParties,
+	TransactionFieldFiles,
+	TransactionFieldMilestones,
+	TransactionFieldBroker,
+	TransactionFieldActivities,
+	TransactionFieldCountry,
+	TransactionFieldClosedAt,
+	TransactionFieldDispute,
+	TransactionFieldPayment,
+}
+
+// TransactionFields is the set of sections to load for each transaction in a response.
+type TransactionFields map[string]bool
+
+// AllTransactionFields loads every section, which is what responses contained before sections
+// could be selected.
+func AllTransactionFields() TransactionFields {
+	fields := TransactionFields{}
+	for _, field := range transactionFields {
+		fields[field] = true
+	}
+	return fields
+}
+
+// ParseTransactionFields reads a comma separated list of sections. An empty list selects them all.
+func ParseTransactionFields(value string) (TransactionFields, error) {
+	if strings.TrimSpace(value) == "" {
+		return AllTransactionFields(), nil
+	}
+
+	all := AllTransactionFields()
+	fields := TransactionFields{}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if !all[field] {
+			return nil, fmt.Errorf("unknown field %v, expected any of %v", field, strings.Join(transactionFields, ", "))
+		}
+		fields[field] = true
+	}
+	return fields, nil
+}
+
+func (f TransactionFields) Has(field string) bool {
+	return f[field]
+}
@@ -9,13 +9,14 @@ import (
 )
 
 type TransactionFile struct {
-	ID            uint      `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
-	TransactionID string    `gorm:"column:transaction_id; type:varchar(255); not null; comment: 12 characters long string" json:"transaction_id"`
-	AccountID     int       `gorm:"column:account_id; type:int" json:"account_id"`
-	FileType      string    `gorm:"column:file_type; type:varchar(255)" json:"file_type"`
-	FileUrl       string    `gorm:"column:file_url; type:varchar(255); not null" json:"file_url"`
-	CreatedAt     time.Time `gorm:"column:created_at; autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+	ID            uint           `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
+	TransactionID string         `gorm:"column:transaction_id; type:varchar(255); not null; comment: 12 characters long string" json:"transaction_id"`
+	AccountID     int            `gorm:"column:account_id; type:int" json:"account_id"`
+	FileType      string         `gorm:"column:file_type; type:varchar(255)" json:"file_type"`
+	FileUrl       string         `gorm:"column:file_url; type:varchar(255); not null" json:"file_url"`
+	CreatedAt     time.Time      `gorm:"column:created_at; autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at" json:"deleted_at"`
 }
 
 func (t *TransactionFile) CreateTransactionFile(db *gorm.DB) error {
@@ -34,3 +35,12 @@ func (t *TransactionFile) GetAllByTransactionID(db *gorm.DB) ([]TransactionFile,
 	}
 	return details, nil
 }
+
+func (t *TransactionFile) GetAllByTransactionIDs(db *gorm.DB, transactionIDs []string) ([]TransactionFile, error) {
+	details := []TransactionFile{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_id IN ? ", transactionIDs)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
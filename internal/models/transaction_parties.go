@@ -17,12 +17,14 @@ type TransactionParty struct {
 	TransactionID        string           `gorm:"column:transaction_id; type:varchar(255); not null; comment: 12 characters long string" json:"transaction_id"`
 	AccountID            int              `gorm:"column:account_id; type:int" json:"account_id"`
 	Role                 string           `gorm:"column:role; type:varchar(255); not null" json:"role"`
-	DeletedAt            time.Time        `gorm:"column:deleted_at" json:"deleted_at"`
+	DeletedAt            gorm.DeletedAt   `gorm:"column:deleted_at" json:"deleted_at"`
 	CreatedAt            time.Time        `gorm:"column:created_at; autoCreateTime" json:"created_at"`
 	UpdatedAt            time.Time        `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
 	RoleCapabilities     roleCapabilities `gorm:"column:role_capabilities; type:varchar(250); default: '{\"can_view\":true,\"can_receive\":false,\"mark_as_done\":false,\"approve\":true}'; comment: view|manage" json:"role_capabilities"`
 	RoleDescription      string           `gorm:"column:role_description; type:text" json:"role_description"`
 	Status               string           `gorm:"column:status; type:varchar(255); not null;default:created" json:"status"`
+	Version              int              `gorm:"column:version; type:int; not null; default:1" json:"version"`
+	ArchivedAt           *time.Time       `gorm:"column:archived_at; comment: set while the party has the transaction archived" json:"archived_at"`
 }
 
 type UpdateTransactionPartiesRequest struct {
@@ -40,6 +42,25 @@ type AssignTransactionBuyerRequest struct {
 	PhoneNumber   string `json:"phone_number" validate:"required"`
 }
 
+// defaultRoleCapabilities mirrors the role_capabilities column default.
+var defaultRoleCapabilities = map[string]bool{"can_view": true, "can_receive": false, "mark_as_done": false, "approve": true}
+
+// HasCapability reports whether the party's role capabilities grant capability, using the column
+// default for capabilities that were never set.
+func (t TransactionParty) HasCapability(capability string) bool {
+	value, ok := t.RoleCapabilities[capability]
+	if !ok {
+		return defaultRoleCapabilities[capability]
+	}
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
 func (t *TransactionParty) CreateTransactionParty(db *gorm.DB) error {
 	err := postgresql.CreateOneRecord(db, &t)
 	if err != nil {
@@ -98,6 +119,16 @@ func (t *TransactionParty) GetAllByTransactionID(db *gorm.DB) ([]TransactionPart
 	return details, nil
 }
 
+// GetAllDeletedByTransactionID loads the parties soft deleted with their transaction at deletedAt.
+func (t *TransactionParty) GetAllDeletedByTransactionID(db *gorm.DB, deletedAt time.Time) ([]TransactionParty, error) {
+	details := []TransactionParty{}
+	err := postgresql.SelectAllFromDb(db.Unscoped(), "asc", &details, "transaction_id = ? AND deleted_at = ?", t.TransactionID, deletedAt)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
 func (t *TransactionParty) GetAllByTransactionPartiesID(db *gorm.DB) ([]TransactionParty, error) {
 	details := []TransactionParty{}
 	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_parties_id = ? ", t.TransactionPartiesID)
@@ -107,6 +138,15 @@ func (t *TransactionParty) GetAllByTransactionPartiesID(db *gorm.DB) ([]Transact
 	return details, nil
 }
 
+func (t *TransactionParty) GetAllByTransactionIDsOrPartiesIDs(db *gorm.DB, transactionIDs, transactionPartiesIDs []string) ([]TransactionParty, error) {
+	details := []TransactionParty{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_id IN ? OR transaction_parties_id IN ? ", transactionIDs, transactionPartiesIDs)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
 func (t *TransactionParty) GetAllByAndQueriesForUniqueValue(db *gorm.DB, CreatedAtInterval string, orderBy, order string, groupColumn string, paginator postgresql.Pagination) ([]TransactionParty, postgresql.PaginationResponse, error) {
 	var (
 		details = []TransactionParty{}
@@ -144,6 +184,8 @@ func (t *TransactionParty) GetAllByAndQueriesForUniqueValue(db *gorm.DB, Created
 	return details, totalPages, nil
 }
 
+// GetAllByAndQueriesForUniqueValueForTransactionStatus leaves out the parties that archived their
+// transaction, see GetAllArchivedByAccountID.
 func (t *TransactionParty) GetAllByAndQueriesForUniqueValueForTransactionStatus(db *gorm.DB, CreatedAtInterval string, orderBy, order string, groupColumn, transactionStatus string, paginator postgresql.Pagination) ([]TransactionParty, postgresql.PaginationResponse, error) {
 	var (
 		details = []TransactionParty{}
@@ -180,6 +222,7 @@ func (t *TransactionParty) GetAllByAndQueriesForUniqueValueForTransactionStatus(
 		query = addQuery(query, fmt.Sprintf("transaction_id IN (SELECT transactions.transaction_id FROM transactions WHERE CAST(transactions.transaction_id AS character varying)=transaction_parties.transaction_id AND LOWER(transactions.status) = '%v')", strings.ToLower(transactionStatus)), "AND")
 
 	}
+	query = addQuery(query, "archived_at IS NULL", "AND")
 
 	totalPages, err := postgresql.SelectAllFromByGroup(db, orderBy, order, &paginator, &details, query, groupColumn)
 	if err != nil {
@@ -188,6 +231,16 @@ func (t *TransactionParty) GetAllByAndQueriesForUniqueValueForTransactionStatus(
 	return details, totalPages, nil
 }
 
+// GetAllArchivedByAccountID pages through the parties t.AccountID archived, newest first.
+func (t *TransactionParty) GetAllArchivedByAccountID(db *gorm.DB, paginator postgresql.Pagination) ([]TransactionParty, postgresql.PaginationResponse, error) {
+	details := []TransactionParty{}
+	totalPages, err := postgresql.SelectAllFromByGroup(db, "id", "desc", &paginator, &details, "account_id = ? AND archived_at IS NOT NULL", "transaction_id", t.AccountID)
+	if err != nil {
+		return details, totalPages, err
+	}
+	return details, totalPages, nil
+}
+
 func (t *TransactionParty) GetAllByAndQueriesForUniqueValueForDispute(db *gorm.DB, CreatedAtInterval, orderBy, order, groupColumn string, paginator postgresql.Pagination) ([]TransactionParty, postgresql.PaginationResponse, error) {
 	var (
 		details = []TransactionParty{}
@@ -229,7 +282,16 @@ func (t *TransactionParty) GetAllByAndQueriesForUniqueValueForDispute(db *gorm.D
 	return details, totalPages, nil
 }
 
+// UpdateAllFields saves t unless its version moved on, like Transaction.UpdateAllFields.
 func (t *TransactionParty) UpdateAllFields(db *gorm.DB) error {
-	_, err := postgresql.SaveAllFields(db, &t)
+	if t.ID == 0 {
+		_, err := postgresql.SaveAllFields(db, &t)
+		return err
+	}
+	t.Version++
+	err := postgresql.SaveVersioned(db, t, postgresql.RecordKey(AuditEntityTransactionParty, t.ID), t.Version-1)
+	if err != nil {
+		t.Version--
+	}
 	return err
 }
@@ -13,13 +13,13 @@ func (TransactionsRejected) TableName() string {
 }
 
 type TransactionsRejected struct {
-	ID            uint      `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
-	AccountID     int64     `gorm:"column:account_id; not null" json:"account_id"`
-	TransactionID string    `gorm:"column:transaction_id; type:varchar(255); not null" json:"transaction_id"`
-	Reason        string    `gorm:"column:reason; type:varchar(255); not null" json:"reason"`
-	CreatedAt     time.Time `gorm:"column:created_at; autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
-	DeletedAt     time.Time `gorm:"column:deleted_at" json:"deleted_at"`
+	ID            uint           `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
+	AccountID     int64          `gorm:"column:account_id; not null" json:"account_id"`
+	TransactionID string         `gorm:"column:transaction_id; type:varchar(255); not null" json:"transaction_id"`
+	Reason        string         `gorm:"column:reason; type:varchar(255); not null" json:"reason"`
+	CreatedAt     time.Time      `gorm:"column:created_at; autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at" json:"deleted_at"`
 }
 
 func (t *TransactionsRejected) CreateTransactionsRejected(db *gorm.DB) error {
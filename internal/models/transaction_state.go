@@ -32,6 +32,24 @@ func (t *TransactionState) GetTransactionStateByTransactionIDAndStatus(db *gorm.
 	return http.StatusOK, nil
 }
 
+func (t *TransactionState) GetAllByTransactionID(db *gorm.DB) ([]TransactionState, error) {
+	details := []TransactionState{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_id = ? ", t.TransactionID)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
+func (t *TransactionState) GetAllByTransactionIDsAndStatus(db *gorm.DB, transactionIDs []string) ([]TransactionState, error) {
+	details := []TransactionState{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_id IN ? and LOWER(status)=?", transactionIDs, strings.ToLower(t.Status))
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
 func (t *TransactionState) CreateTransactionState(db *gorm.DB) error {
 	err := postgresql.CreateOneRecord(db, &t)
 	if err != nil {
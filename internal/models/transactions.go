@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,56 +13,61 @@ import (
 )
 
 type Transaction struct {
-	ID               uint      `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
-	TransactionID    string    `gorm:"column:transaction_id; type:varchar(255); not null; comment: 12 characters long string" json:"transaction_id"`
-	PartiesID        string    `gorm:"column:parties_id; type:varchar(255); not null; comment: " json:"parties_id"`
-	MilestoneID      string    `gorm:"column:milestone_id; type:varchar(255); comment: " json:"milestone_id"`
-	BrokerID         string    `gorm:"column:broker_id; type:varchar(255); comment: " json:"broker_id"`
-	Title            string    `gorm:"column:title; type:varchar(255); not null; comment: " json:"title"`
-	Type             string    `gorm:"column:type; type:varchar(255); comment: Transaction Type: product, service[oneoff], service[milestone]" json:"type"`
-	Description      string    `gorm:"column:description; type:text; not null; comment: " json:"description"`
-	Amount           float64   `gorm:"column:amount; type:decimal(20,2); comment:" json:"amount"`
-	Status           string    `gorm:"column:status; type:varchar(255); default: Draft; comment: Transaction Status" json:"status"`
-	Quantity         int       `gorm:"column:quantity; type:int" json:"quantity"`
-	InspectionPeriod string    `gorm:"column:inspection_period; type:varchar(255); comment: " json:"inspection_period"`
-	DueDate          string    `gorm:"column:due_date; type:varchar(255); comment: " json:"due_date"`
-	ShippingFee      float64   `gorm:"column:shipping_fee; type:decimal(20,2); comment:" json:"shipping_fee"`
-	GracePeriod      string    `gorm:"column:grace_period; type:varchar(255); comment: Grace Period 48 hours" json:"grace_period"`
-	Currency         string    `gorm:"column:currency; type:varchar(255); comment: Currency transaction made in" json:"currency"`
-	DeletedAt        time.Time `gorm:"column:deleted_at" json:"deleted_at"`
-	CreatedAt        time.Time `gorm:"column:created_at; autoCreateTime" json:"created_at"`
-	UpdatedAt        time.Time `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
-	BusinessID       int       `gorm:"column:business_id; type:int" json:"business_id"`
-	IsPaylinked      bool      `gorm:"column:is_paylinked; type:bool; default:false" json:"is_paylinked"`
-	Country          string    `gorm:"column:country; type:varchar(255)" json:"country"`
-	Source           string    `gorm:"column:source; type:varchar(255); default: api" json:"source"`
-	TransUssdCode    int       `gorm:"column:trans_ussd_code; type:int" json:"trans_ussd_code"`
-	Recipients       string    `gorm:"column:recipients; type:varchar(255)" json:"recipients"`
-	DisputeHandler   string    `gorm:"column:dispute_handler; type:varchar(255)" json:"dispute_handler"`
-	AmountPaid       float64   `gorm:"column:amount_paid; type:decimal(20,2); comment:" json:"amount_paid"`
-	EscrowCharge     float64   `gorm:"column:escrow_charge; type:decimal(20,2); comment:" json:"escrow_charge"`
-	EscrowWallet     string    `gorm:"column:escrow_wallet; type:varchar(255); default: no" json:"escrow_wallet"`
+	ID                 uint           `gorm:"column:id; type:uint; not null; primaryKey; unique; autoIncrement" json:"id"`
+	TransactionID      string         `gorm:"column:transaction_id; type:varchar(255); not null; comment: 12 characters long string" json:"transaction_id"`
+	PartiesID          string         `gorm:"column:parties_id; type:varchar(255); not null; comment: " json:"parties_id"`
+	MilestoneID        string         `gorm:"column:milestone_id; type:varchar(255); comment: " json:"milestone_id"`
+	BrokerID           string         `gorm:"column:broker_id; type:varchar(255); comment: " json:"broker_id"`
+	Title              string         `gorm:"column:title; type:varchar(255); not null; comment: " json:"title"`
+	Type               string         `gorm:"column:type; type:varchar(255); comment: Transaction Type: product, service[oneoff], service[milestone]" json:"type"`
+	Description        string         `gorm:"column:description; type:text; not null; comment: " json:"description"`
+	Amount             float64        `gorm:"column:amount; type:decimal(20,2); comment:" json:"amount"`
+	Status             string         `gorm:"column:status; type:varchar(255); default: Draft; comment: Transaction Status" json:"status"`
+	Quantity           int            `gorm:"column:quantity; type:int" json:"quantity"`
+	InspectionPeriod   string         `gorm:"column:inspection_period; type:varchar(255); comment: " json:"inspection_period"`
+	DueDate            string         `gorm:"column:due_date; type:varchar(255); comment: " json:"due_date"`
+	ShippingFee        float64        `gorm:"column:shipping_fee; type:decimal(20,2); comment:" json:"shipping_fee"`
+	GracePeriod        string         `gorm:"column:grace_period; type:varchar(255); comment: Grace Period 48 hours" json:"grace_period"`
+	Currency           string         `gorm:"column:currency; type:varchar(255); comment: Currency transaction made in" json:"currency"`
+	DeletedAt          gorm.DeletedAt `gorm:"column:deleted_at" json:"deleted_at"`
+	CreatedAt          time.Time      `gorm:"column:created_at; autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time      `gorm:"column:updated_at; autoUpdateTime" json:"updated_at"`
+	BusinessID         int            `gorm:"column:business_id; type:int" json:"business_id"`
+	IsPaylinked        bool           `gorm:"column:is_paylinked; type:bool; default:false" json:"is_paylinked"`
+	Country            string         `gorm:"column:country; type:varchar(255)" json:"country"`
+	Source             string         `gorm:"column:source; type:varchar(255); default: api" json:"source"`
+	TransUssdCode      int            `gorm:"column:trans_ussd_code; type:int" json:"trans_ussd_code"`
+	Recipients         string         `gorm:"column:recipients; type:varchar(255)" json:"recipients"`
+	DisputeHandler     string         `gorm:"column:dispute_handler; type:varchar(255)" json:"dispute_handler"`
+	AmountPaid         float64        `gorm:"column:amount_paid; type:decimal(20,2); comment:" json:"amount_paid"`
+	EscrowCharge       float64        `gorm:"column:escrow_charge; type:decimal(20,2); comment:" json:"escrow_charge"`
+	EscrowWallet       string         `gorm:"column:escrow_wallet; type:varchar(255); default: no" json:"escrow_wallet"`
+	SellerEscrowCharge float64        `gorm:"column:seller_escrow_charge; type:decimal(20,2); default:0; comment: part of escrow_charge taken from the seller's payout" json:"seller_escrow_charge"`
+	FeeScheduleID      string         `gorm:"column:fee_schedule_id; type:varchar(255); comment: fee schedule the escrow charge was worked out with" json:"fee_schedule_id"`
+	FeeScheduleVersion int            `gorm:"column:fee_schedule_version; type:int" json:"fee_schedule_version"`
+	Version            int            `gorm:"column:version; type:int; not null; default:1; comment: bumped on every update, see UpdateAllFields" json:"version"`
 }
 
 type CreateTransactionRequest struct {
-	BusinessID       int         `json:"business_id"  pgvalidate:"exists=auth$business_profiles$account_id"`
-	Parties          []Party     `json:"parties"  validate:"required"`
-	Title            string      `json:"title"  validate:"required"`
-	Type             string      `json:"type"  validate:"required,oneof=oneoff milestone"`
-	EscrowWallet     string      `json:"escrow_wallet"  validate:"required,oneof=yes no"`
-	Description      string      `json:"description"`
-	Files            []File      `json:"files"`
-	Milestones       []MileStone `json:"milestones"`
-	Quantity         int         `json:"quantity"`
-	Amount           float64     `json:"amount"`
-	InspectionPeriod int         `json:"inspection_period"`
-	GracePeriod      string      `json:"grace_period"`
-	DueDate          string      `json:"due_date"`
-	ShippingFee      float64     `json:"shipping_fee"`
-	Currency         string      `json:"currency"  validate:"required"`
-	Source           string      `json:"source" validate:"oneof=api instantescrow trizact transfer"`
-	DisputeHandler   string      `json:"dispute_handler"`
-	Paylinked        bool        `json:"paylinked"`
+	BusinessID       int          `json:"business_id"  pgvalidate:"exists=auth$business_profiles$account_id"`
+	Parties          []Party      `json:"parties"  validate:"required"`
+	Title            string       `json:"title"  validate:"required"`
+	Type             string       `json:"type"  validate:"required,oneof=oneoff milestone"`
+	EscrowWallet     string       `json:"escrow_wallet"  validate:"required,oneof=yes no"`
+	Description      string       `json:"description"`
+	Files            []File       `json:"files"`
+	Milestones       []MileStone  `json:"milestones"`
+	Quantity         int          `json:"quantity"`
+	Amount           float64      `json:"amount"`
+	InspectionPeriod int          `json:"inspection_period"`
+	GracePeriod      string       `json:"grace_period"`
+	DueDate          string       `json:"due_date"`
+	ShippingFee      float64      `json:"shipping_fee"`
+	Currency         string       `json:"currency"  validate:"required"`
+	Source           string       `json:"source" validate:"oneof=api instantescrow trizact transfer"`
+	DisputeHandler   string       `json:"dispute_handler"`
+	Paylinked        bool         `json:"paylinked"`
+	Broker           *BrokerTerms `json:"broker"`
 }
 type EditTransactionRequest struct {
 	TransactionID    string  `json:"transaction_id" validate:"required" pgvalidate:"exists=transaction$transactions$transaction_id"`
@@ -82,6 +88,21 @@ type UpdateTransactionAmountPaid struct {
 	Amount        float64 `json:"amount"`
 	Action        string  `json:"action" validate:"required,oneof=+ -"`
 }
+type FundMilestoneRequest struct {
+	TransactionID string  `json:"transaction_id" validate:"required" pgvalidate:"exists=transaction$transactions$transaction_id"`
+	MilestoneID   string  `json:"milestone_id" validate:"required" pgvalidate:"exists=transaction$transactions$milestone_id"`
+	Amount        float64 `json:"amount" validate:"required,gt=0"`
+	Action        string  `json:"action" validate:"required,oneof=+ -"`
+}
+type MilestoneRequest struct {
+	TransactionID string `json:"transaction_id" validate:"required" pgvalidate:"exists=transaction$transactions$transaction_id"`
+	MilestoneID   string `json:"milestone_id" validate:"required" pgvalidate:"exists=transaction$transactions$milestone_id"`
+}
+type RejectMilestoneRequest struct {
+	TransactionID string `json:"transaction_id" validate:"required" pgvalidate:"exists=transaction$transactions$transaction_id"`
+	MilestoneID   string `json:"milestone_id" validate:"required" pgvalidate:"exists=transaction$transactions$milestone_id"`
+	Reason        string `json:"reason"`
+}
 type RejectTransactionRequest struct {
 	TransactionID string `json:"transaction_id" validate:"required" pgvalidate:"exists=transaction$transactions$transaction_id"`
 	Reason        string `json:"reason"`
@@ -196,6 +217,66 @@ type ResolveTransactionObj struct {
 	BusinessID           int
 	DisputeHandler       string
 	EscrowWallet         string
+	EscrowFee            EscrowFee
+}
+
+// TransactionFilter picks out the transactions a background job works on. Statuses are compared
+// case-insensitively. Due dates are stored as unix seconds, so DueBefore compares them as numbers
+// and leaves out transactions without one. A Limit of 0 returns every match.
+type TransactionFilter struct {
+	Statuses        []string
+	ExcludeStatuses []string
+	DueBefore       time.Time
+	Limit           int
+}
+
+// Matches reports whether t would be returned for the filter, ignoring Limit.
+func (f TransactionFilter) Matches(t Transaction) bool {
+	status := strings.ToLower(t.Status)
+	if len(f.Statuses) > 0 && !containsString(lowerAll(f.Statuses), status) {
+		return false
+	}
+	if containsString(lowerAll(f.ExcludeStatuses), status) {
+		return false
+	}
+	if !f.DueBefore.IsZero() {
+		dueDate, err := strconv.ParseInt(t.DueDate, 10, 64)
+		if err != nil || dueDate >= f.DueBefore.Unix() {
+			return false
+		}
+	}
+	return true
+}
+
+// TransactionQuery picks out the transactions a listing pages through. Zero fields match any
+// transaction, Status is compared case-insensitively, IsPaylinked is only compared when UsePaylinked
+// is set and CreatedAtInterval is one of the intervals utility.GetStartAndEnd knows.
+type TransactionQuery struct {
+	BusinessID        int
+	Status            string
+	UsePaylinked      bool
+	IsPaylinked       bool
+	CreatedAtInterval string
+}
+
+// Matches reports whether t would be returned for the query.
+func (q TransactionQuery) Matches(t Transaction) bool {
+	if q.BusinessID != 0 && t.BusinessID != q.BusinessID {
+		return false
+	}
+	if q.Status != "" && !strings.EqualFold(t.Status, q.Status) {
+		return false
+	}
+	if q.UsePaylinked && t.IsPaylinked != q.IsPaylinked {
+		return false
+	}
+	if q.CreatedAtInterval != "" {
+		start, end := utility.GetStartAndEnd(q.CreatedAtInterval)
+		if t.CreatedAt.Before(start) || t.CreatedAt.After(end) {
+			return false
+		}
+	}
+	return true
 }
 
 func (t *Transaction) CreateTransaction(db *gorm.DB) error {
@@ -206,8 +287,18 @@ func (t *Transaction) CreateTransaction(db *gorm.DB) error {
 	return nil
 }
 
+// UpdateAllFields saves t if nobody else has since it was read, bumping its version, and returns
+// postgresql.ErrConflict otherwise. A transaction that was never created is inserted.
 func (t *Transaction) UpdateAllFields(db *gorm.DB) error {
-	_, err := postgresql.SaveAllFields(db, &t)
+	if t.ID == 0 {
+		_, err := postgresql.SaveAllFields(db, &t)
+		return err
+	}
+	t.Version++
+	err := postgresql.SaveVersioned(db, t, postgresql.RecordKey(AuditEntityTransaction, t.ID), t.Version-1)
+	if err != nil {
+		t.Version--
+	}
 	return err
 }
 
@@ -254,6 +345,24 @@ func (t *Transaction) GetAllByTransactionID(db *gorm.DB) ([]Transaction, error)
 	return details, nil
 }
 
+func (t *Transaction) GetAllByTransactionIDs(db *gorm.DB, transactionIDs []string) ([]Transaction, error) {
+	details := []Transaction{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "transaction_id IN ? ", transactionIDs)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
+func (t *Transaction) GetAllByPartiesID(db *gorm.DB) ([]Transaction, error) {
+	details := []Transaction{}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, "parties_id = ? ", t.PartiesID)
+	if err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
 func (t *Transaction) GetAllByQuery(db *gorm.DB, query string) ([]Transaction, error) {
 	details := []Transaction{}
 	err := postgresql.SelectAllFromDb(db, "asc", &details, query)
@@ -271,6 +380,51 @@ func (t *Transaction) GetAllByQueryWithLimit(db *gorm.DB, query string, limit in
 	return details, nil
 }
 
+// CountByStatus returns how many transactions have a milestone in each status.
+func (t *Transaction) CountByStatus(db *gorm.DB) (map[string]int64, error) {
+	rows := []struct {
+		Status string
+		Count  int64
+	}{}
+	err := db.Model(&Transaction{}).Select("status, COUNT(DISTINCT transaction_id) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (t *Transaction) GetAllByFilter(db *gorm.DB, filter TransactionFilter) ([]Transaction, error) {
+	var (
+		details = []Transaction{}
+		query   = ``
+		args    = []interface{}{}
+	)
+
+	if len(filter.Statuses) > 0 {
+		query = addQuery(query, "LOWER(status) IN ?", "AND")
+		args = append(args, lowerAll(filter.Statuses))
+	}
+	if len(filter.ExcludeStatuses) > 0 {
+		query = addQuery(query, "LOWER(status) NOT IN ?", "AND")
+		args = append(args, lowerAll(filter.ExcludeStatuses))
+	}
+	if !filter.DueBefore.IsZero() {
+		query = addQuery(query, "(CASE WHEN due_date ~ '^[0-9]+$' THEN due_date::bigint END) < ?", "AND")
+		args = append(args, filter.DueBefore.Unix())
+	}
+
+	if filter.Limit > 0 {
+		err := postgresql.SelectAllFromDbWithLimit(db, "asc", filter.Limit, &details, query, args...)
+		return details, err
+	}
+	err := postgresql.SelectAllFromDb(db, "asc", &details, query, args...)
+	return details, err
+}
+
 func (t *Transaction) GetAllOthersByIDAndPartiesID(db *gorm.DB) ([]Transaction, error) {
 	details := []Transaction{}
 	err := postgresql.SelectAllFromDb(db, "asc", &details, "id != ? and parties_id = ?", t.ID, t.PartiesID)
@@ -358,10 +512,69 @@ func (t *Transaction) GetLatestByAndQueries(db *gorm.DB, usePaylinked bool, Crea
 	return http.StatusOK, nil
 }
 
+// Delete soft deletes every milestone of t.TransactionID and the rows of its other records. They
+// all get the same deleted_at, so Restore brings back exactly what one Delete removed.
 func (t *Transaction) Delete(db *gorm.DB) error {
-	err := postgresql.DeleteRecordFromDb(db, &t)
+	deletedAt := time.Now().Truncate(time.Microsecond)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.Session(&gorm.Session{NowFunc: func() time.Time { return deletedAt }})
+		for _, table := range transactionTables {
+			if err := table.softDelete(tx, t.TransactionID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("transaction delete failed: %v", err.Error())
 	}
 	return nil
 }
+
+// GetDeletedTransactionByTransactionID loads the first milestone of a soft deleted transaction.
+func (t *Transaction) GetDeletedTransactionByTransactionID(db *gorm.DB) (int, error) {
+	err, nilErr := postgresql.SelectOneFromDb(db.Unscoped(), &t, "transaction_id = ? AND deleted_at IS NOT NULL", t.TransactionID)
+	if nilErr != nil {
+		return http.StatusBadRequest, nilErr
+	}
+
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// Restore undeletes the rows of t.TransactionID that Delete removed at deletedAt.
+func (t *Transaction) Restore(db *gorm.DB, deletedAt time.Time) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range transactionTables {
+			if err := table.restore(tx, t.TransactionID, deletedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("transaction restore failed: %v", err.Error())
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes the rows of transactions soft deleted before before and reports
+// how many milestones went.
+func (t Transaction) PurgeDeleted(db *gorm.DB, before time.Time) (int64, error) {
+	var purged int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for i, table := range transactionTables {
+			count, err := table.purge(tx, before)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				purged = count
+			}
+		}
+		return nil
+	})
+	return purged, err
+}
@@ -1,17 +1,17 @@
 package models
 
-import "github.com/vesicash/transactions-ms/external/external_models"
-
-var (
-	MyIdentity *external_models.User
-	Token      string
-)
-
 type GetEscrowChargeRequest struct {
-	BusinessID int     `json:"business_id" validate:"required" pgvalidate:"exists=auth$business_profiles$account_id"`
-	Amount     float64 `json:"amount" validate:"required"`
+	BusinessID      int     `json:"business_id" validate:"required" pgvalidate:"exists=auth$business_profiles$account_id"`
+	Amount          float64 `json:"amount" validate:"required"`
+	Currency        string  `json:"currency"`
+	TransactionType string  `json:"transaction_type" validate:"omitempty,oneof=oneoff milestone"`
 }
 type GetEscrowChargeResponse struct {
-	Amount float64 `json:"amount"`
-	Charge float64 `json:"charge"`
+	Amount        float64 `json:"amount"`
+	Charge        float64 `json:"charge"`
+	BuyerCharge   float64 `json:"buyer_charge"`
+	SellerCharge  float64 `json:"seller_charge"`
+	ChargeBearer  string  `json:"charge_bearer"`
+	FeeScheduleID string  `json:"fee_schedule_id"`
+	Version       int     `json:"version"`
 }
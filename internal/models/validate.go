@@ -1,8 +1,25 @@
 package models
 
-type ValidateOnDBReq struct {
-	Table string      `validate:"required" json:"table"`
-	Type  string      `validate:"required" json:"type"`
-	Query string      `validate:"required" json:"query"`
-	Value interface{} `json:"value"`
+import "encoding/json"
+
+// ValidationCheck names a registered check and carries its parameters, which are decoded into the
+// check's own parameter type.
+type ValidationCheck struct {
+	Check  string          `json:"check" validate:"required"`
+	Params json.RawMessage `json:"params"`
+}
+
+type ValidateChecksRequest struct {
+	Checks []ValidationCheck `json:"checks" validate:"required,min=1,max=50,dive"`
+}
+
+type ValidationCheckResult struct {
+	Check string `json:"check"`
+	Valid bool   `json:"valid"`
+}
+
+type ValidateChecksResponse struct {
+	// Valid is true when every check passed.
+	Valid   bool                    `json:"valid"`
+	Results []ValidationCheckResult `json:"results"`
 }
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GormPlugin starts a span for every statement gorm runs, as a child of the span in the statement's
+// context. Queries only join a request or job trace when they are made through db.WithContext.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	for _, err := range []error{
+		callback.Create().Before("gorm:create").Register("tracing:before_create", startGormSpan("create")),
+		callback.Create().After("gorm:create").Register("tracing:after_create", endGormSpan),
+		callback.Query().Before("gorm:query").Register("tracing:before_query", startGormSpan("query")),
+		callback.Query().After("gorm:query").Register("tracing:after_query", endGormSpan),
+		callback.Update().Before("gorm:update").Register("tracing:before_update", startGormSpan("update")),
+		callback.Update().After("gorm:update").Register("tracing:after_update", endGormSpan),
+		callback.Delete().Before("gorm:delete").Register("tracing:before_delete", startGormSpan("delete")),
+		callback.Delete().After("gorm:delete").Register("tracing:after_delete", endGormSpan),
+		callback.Row().Before("gorm:row").Register("tracing:before_row", startGormSpan("row")),
+		callback.Row().After("gorm:row").Register("tracing:after_row", endGormSpan),
+		callback.Raw().Before("gorm:raw").Register("tracing:before_raw", startGormSpan("raw")),
+		callback.Raw().After("gorm:raw").Register("tracing:after_raw", endGormSpan),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startGormSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		// the span is kept on the statement rather than its context so chained calls on the same
+		// session do not nest under each other
+		_, span := Tracer().Start(db.Statement.Context, "gorm."+operation, trace.WithSpanKind(trace.SpanKindClient))
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endGormSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	// the statement keeps its placeholders, the bound values are not recorded
+	span.SetAttributes(
+		semconv.DBSystemPostgreSQL,
+		semconv.DBSQLTableKey.String(db.Statement.Table),
+		semconv.DBStatementKey.String(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
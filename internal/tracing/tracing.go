@@ -0,0 +1,83 @@
+// Package tracing sets up OpenTelemetry for the service. Spans are started for incoming requests,
+// gorm queries, requests to other services and cron job runs, and W3C trace context is propagated
+// to the services we call so a request can be followed across them.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/utility"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/vesicash/transactions-ms"
+
+var (
+	ExporterOtlp   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+)
+
+// Tracer returns the tracer the service's spans are started with.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the W3C trace context propagator and, unless the exporter is none, a tracer
+// provider sending spans to it. The returned function flushes pending spans and should be called
+// before the process exits.
+func Setup(logger *utility.Logger, serviceName string, conf config.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, err := newExporter(conf)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		logger.Info("tracing disabled")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	ratio := conf.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	logger.Info("tracing enabled", conf.Exporter, ratio)
+	return provider.Shutdown, nil
+}
+
+func newExporter(conf config.Tracing) (sdktrace.SpanExporter, error) {
+	switch strings.ToLower(conf.Exporter) {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOtlp:
+		options := []otlptracehttp.Option{}
+		if conf.OtlpEndpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(conf.OtlpEndpoint))
+		}
+		if conf.OtlpInsecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), options...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %v", conf.Exporter)
+	}
+}
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/vesicash/transactions-ms/cronjobs"
 	"github.com/vesicash/transactions-ms/internal/config"
 	"github.com/vesicash/transactions-ms/internal/models/migrations"
+	"github.com/vesicash/transactions-ms/internal/tracing"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 
 	"github.com/go-playground/validator/v10"
@@ -18,25 +27,88 @@ func main() {
 
 	configuration := config.Setup(logger, "./app")
 
+	shutdownTracing, err := tracing.Setup(logger, config.ServiceName, configuration.Tracing)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	postgresql.ConnectToDatabases(logger, configuration.Databases)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(os.Stdout, db, os.Args[2:])
+		db.Close()
+		shutdownTracing(context.Background())
+		logger.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if configuration.Databases.Migrate {
-		migrations.RunAllMigrations(db)
+		if err := migrations.RunAllMigrations(db); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	r := router.Setup(logger, validatorRef, db, &configuration.App)
 	rM := router.SetupMetrics(&configuration.App)
 
-	go func(logger *utility.Logger, metricsPort string) {
-		utility.LogAndPrint(logger, fmt.Sprintf("Metric Server is starting at 127.0.0.1:%s", metricsPort))
-		err := rM.Run(":" + metricsPort)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}(logger, configuration.Server.MetricsPort)
+	srv := &http.Server{Addr: ":" + configuration.Server.Port, Handler: r}
+	metricsSrv := &http.Server{Addr: ":" + configuration.Server.MetricsPort, Handler: rM}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 2)
+	go serve(logger, "Metric Server", metricsSrv, serveErr)
+	go serve(logger, "Server", srv, serveErr)
 
-	utility.LogAndPrint(logger, fmt.Sprintf("Server is starting at 127.0.0.1:%s", configuration.Server.Port))
-	log.Fatal(r.Run(":" + configuration.Server.Port))
+	// A server that fails to start shuts the service down rather than leave it running without it.
+	var failed error
+	select {
+	case <-ctx.Done():
+	case failed = <-serveErr:
+	}
+	stop()
+	utility.LogAndPrint(logger, "shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(configuration.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+
+	// Stop taking new requests and let in-flight ones finish before stopping the jobs they may
+	// have started, then release what they were using.
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		utility.LogAndPrint(logger, fmt.Sprintf("server shutdown: %v", err))
+	}
+	if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+		utility.LogAndPrint(logger, fmt.Sprintf("metric server shutdown: %v", err))
+	}
+	if err := cronjobs.Shutdown(shutdownCtx); err != nil {
+		utility.LogAndPrint(logger, fmt.Sprintf("cronjobs shutdown: %v", err))
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		utility.LogAndPrint(logger, fmt.Sprintf("tracing shutdown: %v", err))
+	}
+	if err := db.Close(); err != nil {
+		utility.LogAndPrint(logger, fmt.Sprintf("closing databases: %v", err))
+	}
+
+	utility.LogAndPrint(logger, "shutdown complete")
+	logger.Close()
+	if failed != nil {
+		os.Exit(1)
+	}
+}
+
+// serve runs srv until it is shut down, sending any other error on errs.
+func serve(logger *utility.Logger, name string, srv *http.Server, errs chan<- error) {
+	utility.LogAndPrint(logger, fmt.Sprintf("%s is starting at 127.0.0.1%s", name, srv.Addr))
+	err := srv.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		utility.LogAndPrint(logger, fmt.Sprintf("%s failed: %v", name, err))
+		errs <- err
+	}
 }
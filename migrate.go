@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/vesicash/transactions-ms/internal/models/migrations"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+)
+
+const migrateUsage = `usage:
+  migrate up [version]   apply pending migrations, up to version if given
+  migrate down [steps]   roll back the last steps migrations, 1 if not given
+  migrate status         list migrations and whether they are applied`
+
+// runMigrate runs the migrate subcommand against the transaction database.
+func runMigrate(out io.Writer, db postgresql.Databases, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf(migrateUsage)
+	}
+	migrator, err := migrations.NewMigrator(db.Transaction)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		var target int64
+		if len(args) == 2 {
+			if target, err = strconv.ParseInt(args[1], 10, 64); err != nil || target <= 0 {
+				return fmt.Errorf("version must be a positive number\n%v", migrateUsage)
+			}
+		}
+		applied, err := migrator.Up(target)
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied %v_%v\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "no migrations to apply")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				return fmt.Errorf("steps must be a positive number\n%v", migrateUsage)
+			}
+		}
+		rolledBack, err := migrator.Down(steps)
+		for _, m := range rolledBack {
+			fmt.Fprintf(out, "rolled back %v_%v\n", m.Version, m.Name)
+		}
+		if err == nil && len(rolledBack) == 0 {
+			fmt.Fprintln(out, "no migrations to roll back")
+		}
+		return err
+	case "status":
+		if len(args) != 1 {
+			return fmt.Errorf(migrateUsage)
+		}
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	healthService "github.com/vesicash/transactions-ms/services/health"
 	"github.com/vesicash/transactions-ms/services/ping"
 	"github.com/vesicash/transactions-ms/utility"
 )
@@ -18,6 +19,7 @@ type Controller struct {
 	Validator *validator.Validate
 	Logger    *utility.Logger
 	ExtReq    request.ExternalRequest
+	Checkers  []healthService.Checker
 }
 
 func (base *Controller) Post(c *gin.Context) {
@@ -63,3 +65,25 @@ func (base *Controller) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, rd)
 
 }
+
+// Live reports that the process is up and serving requests. It checks no dependencies, so an
+// orchestrator only restarts the service when it is truly stuck.
+func (base *Controller) Live(c *gin.Context) {
+	rd := utility.BuildSuccessResponse(http.StatusOK, "alive", gin.H{"status": models.HealthStatusOk})
+	c.JSON(http.StatusOK, rd)
+}
+
+// Ready reports whether the service can handle traffic, with the status of each dependency. It
+// responds 503 when a critical dependency is down.
+func (base *Controller) Ready(c *gin.Context) {
+	report := healthService.Readiness(c.Request.Context(), base.Checkers)
+	if report.Status == models.HealthStatusDown {
+		base.Logger.Error("readiness check failed", report)
+		rd := utility.BuildErrorResponse(http.StatusServiceUnavailable, "error", "service not ready", "critical dependency down", report)
+		c.JSON(http.StatusServiceUnavailable, rd)
+		return
+	}
+
+	rd := utility.BuildSuccessResponse(http.StatusOK, "service ready", report)
+	c.JSON(http.StatusOK, rd)
+}
@@ -2,10 +2,12 @@ package transactions
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/services/transactions"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
@@ -28,7 +30,7 @@ func (base *Controller) CreateActivityLog(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -37,10 +39,17 @@ func (base *Controller) CreateActivityLog(c *gin.Context) {
 	}
 
 	activityLog := models.ActivityLog{
-		TransactionID: req.TransactionID,
-		Description:   req.Description,
+		TransactionID:  req.TransactionID,
+		MilestoneID:    req.MilestoneID,
+		EventType:      req.EventType,
+		ActorAccountID: req.ActorAccountID,
+		ActorRole:      req.ActorRole,
+		PreviousStatus: req.PreviousStatus,
+		NewStatus:      req.NewStatus,
+		Metadata:       req.Metadata,
+		Description:    req.Description,
 	}
-	err = activityLog.CreateActivityLog(base.Db.Transaction)
+	err = base.Repo.ActivityLogs.Create(&activityLog)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusInternalServerError, "error", err.Error(), err, nil)
 		c.JSON(http.StatusInternalServerError, rd)
@@ -51,3 +60,73 @@ func (base *Controller) CreateActivityLog(c *gin.Context) {
 	c.JSON(http.StatusOK, rd)
 
 }
+
+func (base *Controller) ListActivityLogs(c *gin.Context) {
+	var (
+		paginator = postgresql.GetPagination(c)
+		req       = getListActivityLogsRequest(c)
+	)
+
+	req.TransactionID = c.Param("transaction_id")
+	if req.TransactionID == "" {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "transaction id not provided", c.Params, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	if !base.authorizeBusinessTransaction(c, req.TransactionID) {
+		return
+	}
+
+	activities, pagination, code, err := transactions.ListActivityLogsService(base.ExtReq, base.Logger, base.Repo, req, paginator)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", activities, pagination)
+	c.JSON(http.StatusOK, rd)
+
+}
+
+func (base *Controller) ListBusinessActivityLogs(c *gin.Context) {
+	var (
+		paginator = postgresql.GetPagination(c)
+		req       = getListActivityLogsRequest(c)
+	)
+
+	businessID, err := strconv.Atoi(c.Param("business_id"))
+	if err != nil || businessID == 0 {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "invalid business id", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+	req.BusinessID = businessID
+
+	if !authorizeBusiness(c, businessID) {
+		return
+	}
+
+	activities, pagination, code, err := transactions.ListActivityLogsService(base.ExtReq, base.Logger, base.Repo, req, paginator)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", activities, pagination)
+	c.JSON(http.StatusOK, rd)
+
+}
+
+func getListActivityLogsRequest(c *gin.Context) models.ListActivityLogsRequest {
+	actorAccountID, _ := strconv.Atoi(c.Query("actor_account_id"))
+	return models.ListActivityLogsRequest{
+		MilestoneID:    c.Query("milestone_id"),
+		EventType:      c.Query("event_type"),
+		ActorAccountID: actorAccountID,
+		ActorRole:      c.Query("actor_role"),
+		Status:         c.Query("status"),
+	}
+}
@@ -0,0 +1,47 @@
+package transactions
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/services/transactions"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func (base *Controller) ArchiveTransaction(c *gin.Context) {
+	base.archiveTransaction(c, true, "Transaction Archived")
+}
+
+func (base *Controller) UnarchiveTransaction(c *gin.Context) {
+	base.archiveTransaction(c, false, "Transaction Unarchived")
+}
+
+func (base *Controller) archiveTransaction(c *gin.Context, archived bool, message string) {
+	var (
+		transactionID = c.Param("id")
+	)
+
+	user := currentUser(c)
+	if user == nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", fmt.Errorf("error retrieving authenticated user"), nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	if !base.checkIfMatch(c, transactionID) {
+		return
+	}
+
+	code, err := transactions.ArchiveTransactionService(base.ExtReq, base.Logger, base.Repo, transactionID, *user, archived)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	base.setETag(c, transactionID)
+	rd := utility.BuildSuccessResponse(http.StatusOK, message, nil)
+	c.JSON(http.StatusOK, rd)
+
+}
@@ -0,0 +1,47 @@
+package transactions
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/services/transactions"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func (base *Controller) ListAuditLogs(c *gin.Context) {
+	var (
+		paginator     = postgresql.GetPagination(c)
+		transactionID = c.Param("transaction_id")
+	)
+
+	if transactionID == "" {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "transaction id not provided", c.Params, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	logs, pagination, code, err := transactions.ListAuditLogsService(base.ExtReq, base.Logger, base.Repo, transactionID, paginator)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", logs, pagination)
+	c.JSON(http.StatusOK, rd)
+
+}
+
+func (base *Controller) VerifyAuditChain(c *gin.Context) {
+	result, code, err := transactions.VerifyAuditChainService(base.ExtReq, base.Logger, base.Repo)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", result)
+	c.JSON(http.StatusOK, rd)
+
+}
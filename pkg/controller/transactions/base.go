@@ -1,15 +1,133 @@
 package transactions
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/services/transactions"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
 type Controller struct {
 	Db        postgresql.Databases
+	Repo      repository.Repositories
 	Validator *validator.Validate
 	Logger    *utility.Logger
 	ExtReq    request.ExternalRequest
 }
+
+// WithContext returns a copy of the controller whose databases, repositories and clients run under
+// ctx, so the queries and requests a handler makes join the trace ctx carries.
+func (base *Controller) WithContext(ctx context.Context) *Controller {
+	scoped := *base
+	scoped.Db = base.Db.WithContext(ctx)
+	scoped.Repo = base.Repo.WithContext(ctx)
+	scoped.ExtReq = base.ExtReq.WithContext(ctx)
+	return &scoped
+}
+
+// Traced returns a handler running handler on a copy of the controller bound to the request context.
+// The context carries the postgresql.ExpectedVersions checkIfMatch pins the transaction's records in.
+func (base *Controller) Traced(handler func(*Controller, *gin.Context)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := postgresql.WithExpectedVersions(c.Request.Context(), postgresql.NewExpectedVersions())
+		c.Request = c.Request.WithContext(ctx)
+		handler(base.WithContext(ctx), c)
+	}
+}
+
+// currentUser is the user the request was authorized as, or nil when the caller is not a user.
+func currentUser(c *gin.Context) *external_models.User {
+	principal, _ := middleware.GetPrincipal(c)
+	return principal.User
+}
+
+// apiKeyBusiness is the business an API key caller acts for. It reports false for other callers,
+// who are not limited to one business.
+func apiKeyBusiness(c *gin.Context) (int, bool) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok || principal.Scopes == nil {
+		return 0, false
+	}
+	return principal.BusinessID, true
+}
+
+// authorizeBusiness writes a 403 and returns false when an API key caller asks for another
+// business's data.
+func authorizeBusiness(c *gin.Context, businessID int) bool {
+	keyBusinessID, ok := apiKeyBusiness(c)
+	if !ok || keyBusinessID == businessID {
+		return true
+	}
+	err := fmt.Errorf("api key does not belong to this business")
+	rd := utility.BuildErrorResponse(http.StatusForbidden, "error", err.Error(), err, nil)
+	c.JSON(http.StatusForbidden, rd)
+	return false
+}
+
+// authorizeBusinessTransaction is authorizeBusiness for the business owning a transaction.
+func (base *Controller) authorizeBusinessTransaction(c *gin.Context, transactionID string) bool {
+	businessID, ok := apiKeyBusiness(c)
+	if !ok {
+		return true
+	}
+	code, err := transactions.AuthorizeBusinessTransaction(base.Repo, transactionID, businessID)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return false
+	}
+	return true
+}
+
+// checkIfMatch writes a 412 and returns false when the request's If-Match no longer matches the
+// transaction's ETag, that is when the transaction changed since the caller read it. Requests
+// without If-Match are let through. When it matches, the records behind the ETag are pinned to the
+// versions it was checked against, so the request's saves fail with a 412 rather than overwrite a
+// change made after the check.
+func (base *Controller) checkIfMatch(c *gin.Context, transactionID string) bool {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		return true
+	}
+	versions, code, err := transactions.TransactionVersions(base.Repo, transactionID)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return false
+	}
+	etag := transactions.VersionsETag(versions)
+	if !transactions.ETagMatches(ifMatch, etag) {
+		err := fmt.Errorf("transaction was changed by another request, reload it and try again")
+		c.Header("ETag", etag)
+		rd := utility.BuildErrorResponse(http.StatusPreconditionFailed, "error", err.Error(), err, nil)
+		c.JSON(http.StatusPreconditionFailed, rd)
+		return false
+	}
+	if strings.TrimSpace(ifMatch) != "*" {
+		expected := postgresql.ExpectedVersionsFrom(c.Request.Context())
+		for _, version := range versions {
+			expected.Expect(version.Key, version.Version)
+		}
+	}
+	return true
+}
+
+// setETag sends the transaction's current ETag, for the caller to send back in If-Match.
+func (base *Controller) setETag(c *gin.Context, transactionID string) {
+	etag, _, err := transactions.TransactionETag(base.Repo, transactionID)
+	if err != nil {
+		base.Logger.Error("error getting transaction etag: ", err.Error())
+		return
+	}
+	c.Header("ETag", etag)
+}
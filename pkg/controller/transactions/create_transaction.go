@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	"github.com/vesicash/transactions-ms/services/transactions"
 	"github.com/vesicash/transactions-ms/utility"
@@ -29,7 +30,7 @@ func (base *Controller) CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -37,14 +38,14 @@ func (base *Controller) CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	user := models.MyIdentity
-	if user == nil {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok || principal.User == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	transaction, code, err := transactions.CreateTransactionService(base.ExtReq, base.Logger, base.Db, req, *user)
+	transaction, code, err := transactions.CreateTransactionService(base.ExtReq, base.Logger, base.Repo, req, principal)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -75,7 +76,7 @@ func (base *Controller) CheckTransactionAmount(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -83,7 +84,11 @@ func (base *Controller) CheckTransactionAmount(c *gin.Context) {
 		return
 	}
 
-	status, code, err := transactions.CheckTransactionAmountService(base.ExtReq, base.Logger, base.Db, req.TransactionID)
+	if !base.authorizeBusinessTransaction(c, req.TransactionID) {
+		return
+	}
+
+	status, code, err := transactions.CheckTransactionAmountService(base.ExtReq, base.Logger, base.Repo, req.TransactionID)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -113,7 +118,7 @@ func (base *Controller) UpdateTransactionAmountPaid(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -121,13 +126,18 @@ func (base *Controller) UpdateTransactionAmountPaid(c *gin.Context) {
 		return
 	}
 
-	transaction, code, err := transactions.UpdateTransactionAmountPaidService(base.ExtReq, base.Logger, base.Db, req)
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	transaction, code, err := transactions.UpdateTransactionAmountPaidService(base.ExtReq, base.Logger, base.Repo, req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", transaction)
 	c.JSON(http.StatusOK, rd)
 
@@ -28,7 +28,7 @@ func (base *Controller) StartCronJob(c *gin.Context) {
 	}
 
 	if req.IntervalNumber != 0 && req.IntervalBase != "" {
-		err := cronjobs.UpdateCronJobInterval(base.ExtReq, base.Db, req.Name, req.IntervalNumber, req.IntervalBase)
+		err := cronjobs.UpdateCronJobInterval(base.ExtReq, base.Repo, req.Name, req.IntervalNumber, req.IntervalBase)
 		if err != nil {
 			rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
 			c.JSON(http.StatusBadRequest, rd)
@@ -36,7 +36,7 @@ func (base *Controller) StartCronJob(c *gin.Context) {
 		}
 	}
 
-	cronjobs.StartCronJob(base.ExtReq, base.Db, req.Name)
+	cronjobs.StartCronJob(base.ExtReq, base.Repo, req.Name)
 
 	rd := utility.BuildSuccessResponse(http.StatusOK, "started cron job", nil)
 	c.JSON(http.StatusOK, rd)
@@ -65,7 +65,7 @@ func (base *Controller) StartCronJobsBulk(c *gin.Context) {
 
 	for _, req := range reqSlice.Jobs {
 		if req.IntervalNumber != 0 && req.IntervalBase != "" {
-			err := cronjobs.UpdateCronJobInterval(base.ExtReq, base.Db, req.Name, req.IntervalNumber, req.IntervalBase)
+			err := cronjobs.UpdateCronJobInterval(base.ExtReq, base.Repo, req.Name, req.IntervalNumber, req.IntervalBase)
 			if err != nil {
 				rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
 				c.JSON(http.StatusBadRequest, rd)
@@ -73,7 +73,7 @@ func (base *Controller) StartCronJobsBulk(c *gin.Context) {
 			}
 		}
 
-		cronjobs.StartCronJob(base.ExtReq, base.Db, req.Name)
+		cronjobs.StartCronJob(base.ExtReq, base.Repo, req.Name)
 	}
 
 	rd := utility.BuildSuccessResponse(http.StatusOK, "started cron jobs", nil)
@@ -128,14 +128,14 @@ func (base *Controller) UpdateCronJobInterval(c *gin.Context) {
 		return
 	}
 
-	err = cronjobs.UpdateCronJobInterval(base.ExtReq, base.Db, req.Name, req.IntervalNumber, req.IntervalBase)
+	err = cronjobs.UpdateCronJobInterval(base.ExtReq, base.Repo, req.Name, req.IntervalNumber, req.IntervalBase)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	go cronjobs.RestartCronJob(base.ExtReq, base.Db, req.Name)
+	go cronjobs.RestartCronJob(base.ExtReq, base.Repo, req.Name)
 
 	rd := utility.BuildSuccessResponse(http.StatusOK, "updated", nil)
 	c.JSON(http.StatusOK, rd)
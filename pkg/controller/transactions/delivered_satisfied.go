@@ -29,7 +29,7 @@ func (base *Controller) TransactionDelivered(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -37,20 +37,25 @@ func (base *Controller) TransactionDelivered(c *gin.Context) {
 		return
 	}
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	code, err := transactions.TransactionDeliveredService(base.ExtReq, base.Logger, base.Db, req, *user)
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.TransactionDeliveredService(base.ExtReq, base.Logger, base.Repo, req, *user)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "Transaction Delivered", nil)
 	c.JSON(http.StatusOK, rd)
 
@@ -75,7 +80,7 @@ func (base *Controller) Satisfied(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -83,20 +88,25 @@ func (base *Controller) Satisfied(c *gin.Context) {
 		return
 	}
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	code, err := transactions.SatisfiedService(base.ExtReq, base.Logger, base.Db, req.TransactionID, *user)
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.SatisfiedService(base.ExtReq, base.Logger, base.Repo, req.TransactionID, *user)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "Transaction Delivery Accepted", nil)
 	c.JSON(http.StatusOK, rd)
 
@@ -121,7 +131,7 @@ func (base *Controller) SatisfiedApi(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -129,13 +139,22 @@ func (base *Controller) SatisfiedApi(c *gin.Context) {
 		return
 	}
 
-	code, err := transactions.SatisfiedApiService(base.ExtReq, base.Logger, base.Db, req.TransactionID)
+	if !base.authorizeBusinessTransaction(c, req.TransactionID) {
+		return
+	}
+
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.SatisfiedApiService(base.ExtReq, base.Logger, base.Repo, req.TransactionID)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "Transaction Delivery Accepted", nil)
 	c.JSON(http.StatusOK, rd)
 
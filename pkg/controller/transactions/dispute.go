@@ -30,7 +30,7 @@ func (base *Controller) CreateDispute(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -38,20 +38,25 @@ func (base *Controller) CreateDispute(c *gin.Context) {
 		return
 	}
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	code, err := transactions.CreateDisputeService(base.ExtReq, base.Logger, base.Db, req, *user)
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.CreateDisputeService(base.ExtReq, base.Logger, base.Repo, req, *user)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusCreated, "Transaction Disputed", nil)
 	c.JSON(http.StatusCreated, rd)
 
@@ -62,14 +67,14 @@ func (base *Controller) GetDisputeByTransactionID(c *gin.Context) {
 		transactionID = c.Param("transaction_id")
 	)
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", fmt.Errorf("error retrieving authenticated user"), nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	dispute, code, err := transactions.GetDisputeByTransactionIDService(base.ExtReq, base.Logger, base.Db, transactionID, *user)
+	dispute, code, err := transactions.GetDisputeByTransactionIDService(base.ExtReq, base.Logger, base.Repo, transactionID, *user)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -100,7 +105,7 @@ func (base *Controller) UpdateDispute(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -108,20 +113,25 @@ func (base *Controller) UpdateDispute(c *gin.Context) {
 		return
 	}
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	code, err := transactions.UpdateDisputeService(base.ExtReq, base.Logger, base.Db, req, *user)
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.UpdateDisputeService(base.ExtReq, base.Logger, base.Repo, req, *user)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "Transaction Dispute Modified", nil)
 	c.JSON(http.StatusOK, rd)
 
@@ -131,14 +141,14 @@ func (base *Controller) GetDisputeByUser(c *gin.Context) {
 	var (
 		paginator = postgresql.GetPagination(c)
 	)
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", fmt.Errorf("error retrieving authenticated user"), nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	disputes, pagination, code, err := transactions.GetDisputeByUserService(base.ExtReq, base.Logger, base.Db, *user, paginator)
+	disputes, pagination, code, err := transactions.GetDisputeByUserService(base.ExtReq, base.Logger, base.Repo, *user, paginator)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -29,7 +29,7 @@ func (base *Controller) RequestDueDateExtension(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -37,14 +37,14 @@ func (base *Controller) RequestDueDateExtension(c *gin.Context) {
 		return
 	}
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	code, err := transactions.RequestDueDateExtensionService(base.ExtReq, base.Logger, base.Db, req, *user)
+	code, err := transactions.RequestDueDateExtensionService(base.ExtReq, base.Logger, base.Repo, req, *user)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -75,7 +75,7 @@ func (base *Controller) ApproveDueDateExtension(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -83,20 +83,25 @@ func (base *Controller) ApproveDueDateExtension(c *gin.Context) {
 		return
 	}
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	code, err := transactions.ApproveDueDateExtensionService(base.ExtReq, base.Logger, base.Db, req, *user)
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.ApproveDueDateExtensionService(base.ExtReq, base.Logger, base.Repo, req, *user)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "Transaction Due Date Extension Approved", nil)
 	c.JSON(http.StatusOK, rd)
 
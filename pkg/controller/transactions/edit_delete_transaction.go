@@ -30,7 +30,7 @@ func (base *Controller) EditTransaction(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -38,20 +38,25 @@ func (base *Controller) EditTransaction(c *gin.Context) {
 		return
 	}
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	transaction, code, err := transactions.EditTransactionService(base.ExtReq, base.Logger, base.Db, req, *user)
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	transaction, code, err := transactions.EditTransactionService(base.ExtReq, base.Logger, base.Repo, req, *user)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "Transaction Details Updated", transaction)
 	c.JSON(http.StatusOK, rd)
 
@@ -62,14 +67,18 @@ func (base *Controller) DeleteTransaction(c *gin.Context) {
 		transactionID = c.Param("id")
 	)
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", fmt.Errorf("error retrieving authenticated user"), nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	code, err := transactions.DeleteTransactionService(base.ExtReq, base.Logger, base.Db, transactionID, *user)
+	if !base.checkIfMatch(c, transactionID) {
+		return
+	}
+
+	code, err := transactions.DeleteTransactionService(base.ExtReq, base.Logger, base.Repo, transactionID, *user)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -80,3 +89,28 @@ func (base *Controller) DeleteTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, rd)
 
 }
+
+func (base *Controller) RestoreTransaction(c *gin.Context) {
+	var (
+		transactionID = c.Param("id")
+	)
+
+	user := currentUser(c)
+	if user == nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", fmt.Errorf("error retrieving authenticated user"), nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	code, err := transactions.RestoreTransactionService(base.ExtReq, base.Logger, base.Repo, transactionID, *user)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	base.setETag(c, transactionID)
+	rd := utility.BuildSuccessResponse(http.StatusOK, "Transaction Restored", nil)
+	c.JSON(http.StatusOK, rd)
+
+}
@@ -29,7 +29,7 @@ func (base *Controller) GetEscrowCharge(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -37,7 +37,11 @@ func (base *Controller) GetEscrowCharge(c *gin.Context) {
 		return
 	}
 
-	resp, code, err := transactions.GetEscrowChargeService(base.ExtReq, base.Logger, base.Db, req)
+	if !authorizeBusiness(c, req.BusinessID) {
+		return
+	}
+
+	resp, code, err := transactions.GetEscrowChargeService(base.ExtReq, base.Logger, base.Repo, req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -0,0 +1,120 @@
+package transactions
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/services/transactions"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func (base *Controller) CreateFeeSchedule(c *gin.Context) {
+	var (
+		req models.CreateFeeScheduleRequest
+	)
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Failed to parse request body", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	err = base.Validator.Struct(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Validation failed", utility.ValidationResponse(err, base.Validator), nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
+	err = vr.ValidateRequest(req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	schedule, code, err := transactions.CreateFeeScheduleService(base.ExtReq, base.Logger, base.Repo, req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	rd := utility.BuildSuccessResponse(http.StatusOK, "Fee schedule created", schedule)
+	c.JSON(http.StatusOK, rd)
+
+}
+
+func (base *Controller) UpdateFeeSchedule(c *gin.Context) {
+	var (
+		req           models.UpdateFeeScheduleRequest
+		feeScheduleID = c.Param("fee_schedule_id")
+	)
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Failed to parse request body", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	err = base.Validator.Struct(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Validation failed", utility.ValidationResponse(err, base.Validator), nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	schedule, code, err := transactions.UpdateFeeScheduleService(base.ExtReq, base.Logger, base.Repo, feeScheduleID, req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	rd := utility.BuildSuccessResponse(http.StatusOK, "Fee schedule updated", schedule)
+	c.JSON(http.StatusOK, rd)
+
+}
+
+func (base *Controller) ListFeeSchedules(c *gin.Context) {
+	var (
+		paginator = postgresql.GetPagination(c)
+	)
+
+	businessID, _ := strconv.Atoi(c.Query("business_id"))
+	req := models.ListFeeSchedulesRequest{
+		BusinessID:      businessID,
+		Currency:        c.Query("currency"),
+		TransactionType: c.Query("transaction_type"),
+	}
+
+	schedules, pagination, code, err := transactions.ListFeeSchedulesService(base.ExtReq, base.Logger, base.Repo, req, paginator)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", schedules, pagination)
+	c.JSON(http.StatusOK, rd)
+
+}
+
+func (base *Controller) GetFeeScheduleVersions(c *gin.Context) {
+	versions, code, err := transactions.GetFeeScheduleVersionsService(base.ExtReq, base.Logger, base.Repo, c.Param("fee_schedule_id"))
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", versions)
+	c.JSON(http.StatusOK, rd)
+
+}
@@ -21,13 +21,18 @@ func (base *Controller) ListTransactionsByID(c *gin.Context) {
 		return
 	}
 
-	transactions, code, err := transactions.ListTransactionsByIDService(base.ExtReq, base.Logger, base.Db, transactionID)
+	if !base.authorizeBusinessTransaction(c, transactionID) {
+		return
+	}
+
+	transactions, code, err := transactions.ListTransactionsByIDService(base.ExtReq, base.Logger, base.Repo, transactionID)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, transactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", transactions)
 	c.JSON(http.StatusOK, rd)
 
@@ -42,13 +47,17 @@ func (base *Controller) ListTransactionsByUSSDCode(c *gin.Context) {
 		return
 	}
 
-	transactions, code, err := transactions.ListTransactionsByUssdCodeService(base.ExtReq, base.Logger, base.Db, ussdCode)
+	transactions, code, err := transactions.ListTransactionsByUssdCodeService(base.ExtReq, base.Logger, base.Repo, ussdCode)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	if !authorizeBusiness(c, transactions.BusinessID) {
+		return
+	}
+
 	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", transactions)
 	c.JSON(http.StatusOK, rd)
 
@@ -74,7 +83,7 @@ func (base *Controller) ListTransactions(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -89,7 +98,18 @@ func (base *Controller) ListTransactions(c *gin.Context) {
 		return
 	}
 
-	transactions, pagination, code, err := transactions.ListTransactionsService(base.ExtReq, base.Logger, base.Db, req, paginator)
+	fields, err := getTransactionFields(c)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	if businessID, ok := apiKeyBusiness(c); ok {
+		req.BusinessID = businessID
+	}
+
+	transactions, pagination, code, err := transactions.ListTransactionsService(base.ExtReq, base.Logger, base.Repo, req, paginator, fields)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -121,7 +141,7 @@ func (base *Controller) ListTransactionsByBusiness(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -129,7 +149,18 @@ func (base *Controller) ListTransactionsByBusiness(c *gin.Context) {
 		return
 	}
 
-	transactions, pagination, code, err := transactions.ListTransactionsByBusinessService(base.ExtReq, base.Logger, base.Db, req, paginator)
+	fields, err := getTransactionFields(c)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	if !authorizeBusiness(c, req.BusinessID) {
+		return
+	}
+
+	transactions, pagination, code, err := transactions.ListTransactionsByBusinessService(base.ExtReq, base.Logger, base.Repo, req, paginator, fields)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -161,7 +192,7 @@ func (base *Controller) ListByBusinessFromMondayToThursday(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -169,7 +200,18 @@ func (base *Controller) ListByBusinessFromMondayToThursday(c *gin.Context) {
 		return
 	}
 
-	transactions, pagination, code, err := transactions.ListByBusinessFromMondayToThursdayService(base.ExtReq, base.Logger, base.Db, req, paginator)
+	fields, err := getTransactionFields(c)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	if !authorizeBusiness(c, req.BusinessID) {
+		return
+	}
+
+	transactions, pagination, code, err := transactions.ListByBusinessFromMondayToThursdayService(base.ExtReq, base.Logger, base.Repo, req, paginator, fields)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -201,21 +243,28 @@ func (base *Controller) ListTransactionsByUser(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	transactions, pagination, code, err := transactions.ListTransactionsByUserService(base.ExtReq, base.Logger, base.Db, req, paginator, *user)
+	fields, err := getTransactionFields(c)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	transactions, pagination, code, err := transactions.ListTransactionsByUserService(base.ExtReq, base.Logger, base.Repo, req, paginator, *user, fields)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -233,14 +282,21 @@ func (base *Controller) ListArchivedTransactions(c *gin.Context) {
 		paginator = postgresql.GetPagination(c)
 	)
 
-	user := models.MyIdentity
+	user := currentUser(c)
 	if user == nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", fmt.Errorf("error retrieving authenticated user"), nil)
 		c.JSON(http.StatusBadRequest, rd)
 		return
 	}
 
-	transactions, pagination, code, err := transactions.ListArchivedTransactionsService(base.ExtReq, base.Logger, base.Db, paginator, *user)
+	fields, err := getTransactionFields(c)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	transactions, pagination, code, err := transactions.ListArchivedTransactionsService(base.ExtReq, base.Logger, base.Repo, paginator, *user, fields)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -251,3 +307,13 @@ func (base *Controller) ListArchivedTransactions(c *gin.Context) {
 	c.JSON(http.StatusOK, rd)
 
 }
+
+// getTransactionFields reads the sections to return for each transaction from the fields query
+// parameter, or include when fields is not set.
+func getTransactionFields(c *gin.Context) (models.TransactionFields, error) {
+	value := c.Query("fields")
+	if value == "" {
+		value = c.Query("include")
+	}
+	return models.ParseTransactionFields(value)
+}
@@ -0,0 +1,208 @@
+package transactions
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/services/transactions"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func (base *Controller) FundMilestone(c *gin.Context) {
+	var (
+		req models.FundMilestoneRequest
+	)
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Failed to parse request body", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	err = base.Validator.Struct(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Validation failed", utility.ValidationResponse(err, base.Validator), nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
+	err = vr.ValidateRequest(req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	milestone, code, err := transactions.FundMilestoneService(base.ExtReq, base.Logger, base.Repo, req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	base.setETag(c, req.TransactionID)
+	rd := utility.BuildSuccessResponse(http.StatusOK, "Milestone funding updated", milestone)
+	c.JSON(http.StatusOK, rd)
+
+}
+
+func (base *Controller) MilestoneDelivered(c *gin.Context) {
+	var (
+		req models.MilestoneRequest
+	)
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Failed to parse request body", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	err = base.Validator.Struct(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Validation failed", utility.ValidationResponse(err, base.Validator), nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
+	err = vr.ValidateRequest(req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	user := currentUser(c)
+	if user == nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	milestone, code, err := transactions.MilestoneDeliveredService(base.ExtReq, base.Logger, base.Repo, req, *user)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	base.setETag(c, req.TransactionID)
+	rd := utility.BuildSuccessResponse(http.StatusOK, "Milestone Delivered", milestone)
+	c.JSON(http.StatusOK, rd)
+
+}
+
+func (base *Controller) AcceptMilestone(c *gin.Context) {
+	var (
+		req models.MilestoneRequest
+	)
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Failed to parse request body", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	err = base.Validator.Struct(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Validation failed", utility.ValidationResponse(err, base.Validator), nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
+	err = vr.ValidateRequest(req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	user := currentUser(c)
+	if user == nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	milestone, code, err := transactions.AcceptMilestoneService(base.ExtReq, base.Logger, base.Repo, req, *user)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	base.setETag(c, req.TransactionID)
+	rd := utility.BuildSuccessResponse(http.StatusOK, "Milestone Delivery Accepted", milestone)
+	c.JSON(http.StatusOK, rd)
+
+}
+
+func (base *Controller) RejectMilestone(c *gin.Context) {
+	var (
+		req models.RejectMilestoneRequest
+	)
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Failed to parse request body", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	err = base.Validator.Struct(&req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "Validation failed", utility.ValidationResponse(err, base.Validator), nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
+	err = vr.ValidateRequest(req)
+	if err != nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	user := currentUser(c)
+	if user == nil {
+		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", "error retrieving authenticated user", err, nil)
+		c.JSON(http.StatusBadRequest, rd)
+		return
+	}
+
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	milestone, code, err := transactions.RejectMilestoneService(base.ExtReq, base.Logger, base.Repo, req, *user)
+	if err != nil {
+		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
+		c.JSON(code, rd)
+		return
+	}
+
+	base.setETag(c, req.TransactionID)
+	rd := utility.BuildSuccessResponse(http.StatusOK, "Milestone Delivery Rejected", milestone)
+	c.JSON(http.StatusOK, rd)
+
+}
@@ -11,8 +11,7 @@ import (
 )
 
 func (base *Controller) ListRates(c *gin.Context) {
-	rate := models.Rate{}
-	rates, err := rate.GetAll(base.Db.Transaction)
+	rates, err := base.Repo.Rates.List()
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusInternalServerError, "error", err.Error(), err, nil)
 		c.JSON(http.StatusInternalServerError, rd)
@@ -29,6 +28,11 @@ func (base *Controller) ListExchangeTransactionByAccountID(c *gin.Context) {
 		paginator = postgresql.GetPagination(c)
 	)
 
+	businessID, _ := strconv.Atoi(accountID)
+	if !authorizeBusiness(c, businessID) {
+		return
+	}
+
 	exchangeTransaction := models.ExchangeTransaction{AccountID: accountID}
 	exchangeTransactions, pagination, err := exchangeTransaction.GetAllResolvedByAccountID(base.Db.Transaction, paginator)
 	if err != nil {
@@ -60,6 +64,11 @@ func (base *Controller) GetExchangeTransactionByID(c *gin.Context) {
 		c.JSON(code, rd)
 		return
 	}
+	businessID, _ := strconv.Atoi(rExchangeTransaction.AccountID)
+	if !authorizeBusiness(c, businessID) {
+		return
+	}
+
 	rd := utility.BuildSuccessResponse(http.StatusOK, "success", rExchangeTransaction)
 	c.JSON(http.StatusOK, rd)
 
@@ -83,7 +92,7 @@ func (base *Controller) CreateExchangeTransaction(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -123,8 +132,7 @@ func (base *Controller) GetRateByID(c *gin.Context) {
 		return
 	}
 
-	rate := models.Rate{ID: int64(id)}
-	code, err := rate.GetRateByID(base.Db.Transaction)
+	rate, code, err := base.Repo.Rates.GetByID(int64(id))
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
@@ -140,8 +148,7 @@ func (base *Controller) GetRateByFromAndToCurrencies(c *gin.Context) {
 		toCurrency   = c.Param("to")
 	)
 
-	rate := models.Rate{FromCurrency: fromCurrency, ToCurrency: toCurrency}
-	code, err := rate.GetRateByFromAndToCurrencies(base.Db.Transaction)
+	rate, code, err := base.Repo.Rates.GetByCurrencies(fromCurrency, toCurrency)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
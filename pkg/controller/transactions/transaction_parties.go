@@ -29,7 +29,7 @@ func (base *Controller) UpdateTransactionParties(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -37,13 +37,22 @@ func (base *Controller) UpdateTransactionParties(c *gin.Context) {
 		return
 	}
 
-	code, err := transactions.UpdateTransactionPartiesService(base.ExtReq, base.Logger, base.Db, req)
+	if !base.authorizeBusinessTransaction(c, req.TransactionID) {
+		return
+	}
+
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.UpdateTransactionPartiesService(base.ExtReq, base.Logger, base.Repo, req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "updated", nil)
 	c.JSON(http.StatusOK, rd)
 
@@ -68,7 +77,7 @@ func (base *Controller) UpdateTransactionPartyStatus(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -76,13 +85,22 @@ func (base *Controller) UpdateTransactionPartyStatus(c *gin.Context) {
 		return
 	}
 
-	code, err := transactions.UpdateTransactionPartyStatusService(base.ExtReq, base.Logger, base.Db, req)
+	if !base.authorizeBusinessTransaction(c, req.TransactionID) {
+		return
+	}
+
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.UpdateTransactionPartyStatusService(base.ExtReq, base.Logger, base.Repo, req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "updated", nil)
 	c.JSON(http.StatusOK, rd)
 
@@ -107,7 +125,7 @@ func (base *Controller) AssignTransactionBuyer(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -115,13 +133,27 @@ func (base *Controller) AssignTransactionBuyer(c *gin.Context) {
 		return
 	}
 
-	code, err := transactions.AssignTransactionBuyerService(base.ExtReq, base.Logger, base.Db, req)
+	if req.TransactionID == "" && req.UssdCode != 0 {
+		if transaction, _, err := base.Repo.Transactions.GetByUssdCode(req.UssdCode); err == nil {
+			req.TransactionID = transaction.TransactionID
+		}
+	}
+	if !base.authorizeBusinessTransaction(c, req.TransactionID) {
+		return
+	}
+
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.AssignTransactionBuyerService(base.ExtReq, base.Logger, base.Repo, req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", nil)
 	c.JSON(http.StatusOK, rd)
 
@@ -145,7 +177,7 @@ func (base *Controller) UpdateTransactionBroker(c *gin.Context) {
 		return
 	}
 
-	vr := postgresql.ValidateRequestM{Logger: base.Logger, Test: base.ExtReq.Test}
+	vr := postgresql.ValidateRequestM{Logger: base.Logger, Db: base.Db.Transaction, Auth: base.ExtReq.Auth}
 	err = vr.ValidateRequest(req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(http.StatusBadRequest, "error", err.Error(), err, nil)
@@ -153,13 +185,22 @@ func (base *Controller) UpdateTransactionBroker(c *gin.Context) {
 		return
 	}
 
-	code, err := transactions.UpdateTransactionBrokerService(base.ExtReq, base.Logger, base.Db, req)
+	if !base.authorizeBusinessTransaction(c, req.TransactionID) {
+		return
+	}
+
+	if !base.checkIfMatch(c, req.TransactionID) {
+		return
+	}
+
+	code, err := transactions.UpdateTransactionBrokerService(base.ExtReq, base.Logger, base.Repo, req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
+	base.setETag(c, req.TransactionID)
 	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", nil)
 	c.JSON(http.StatusOK, rd)
 
@@ -9,9 +9,10 @@ import (
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func (base *Controller) ValidateOnDB(c *gin.Context) {
+// ValidateChecks runs named checks for other services, see transactions.ValidationCheckNames.
+func (base *Controller) ValidateChecks(c *gin.Context) {
 	var (
-		req models.ValidateOnDBReq
+		req models.ValidateChecksRequest
 	)
 
 	err := c.ShouldBind(&req)
@@ -28,14 +29,14 @@ func (base *Controller) ValidateOnDB(c *gin.Context) {
 		return
 	}
 
-	status, code, err := transactions.ValidateOnDbService(req, base.Db)
+	response, code, err := transactions.ValidateChecksService(base.Repo, req)
 	if err != nil {
 		rd := utility.BuildErrorResponse(code, "error", err.Error(), err, nil)
 		c.JSON(code, rd)
 		return
 	}
 
-	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", status)
+	rd := utility.BuildSuccessResponse(http.StatusOK, "successful", response)
 	c.JSON(http.StatusOK, rd)
 
 }
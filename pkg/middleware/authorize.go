@@ -22,6 +22,8 @@ const (
 	Business      AuthorizationType = "business"
 )
 
+const principalKey = "principal"
+
 type (
 	AuthorizationType  string
 	AuthorizationTypes []AuthorizationType
@@ -34,8 +36,11 @@ func Authorize(db postgresql.Databases, extReq request.ExternalRequest, authType
 
 			msg := ""
 			for _, v := range authTypes {
-				ms, status := v.ValidateAuthorizationRequest(c, db, extReq)
+				ms, status := v.ValidateAuthorizationRequest(c, db.WithContext(c.Request.Context()), extReq.WithContext(c.Request.Context()))
 				if status {
+					if limitPrincipal(c) {
+						c.Next()
+					}
 					return
 				}
 				msg = ms
@@ -84,12 +89,11 @@ func (at AuthorizationType) ValidateAuthType(c *gin.Context, extReq request.Exte
 	}
 
 	bearerToken := bearerTokenArr[1]
-	models.Token = bearerToken
 	if bearerToken == "" {
 		return invalidToken, false
 	}
 
-	reqInf, err := extReq.SendExternalRequest(request.ValidateAuthorization, external_models.ValidateAuthorizationReq{
+	dataResponse, err := extReq.Auth.ValidateAuthorization(external_models.ValidateAuthorizationReq{
 		Type:               string(AuthType),
 		AuthorizationToken: bearerToken,
 	})
@@ -97,12 +101,11 @@ func (at AuthorizationType) ValidateAuthType(c *gin.Context, extReq request.Exte
 		return err.Error(), false
 	}
 
-	dataResponse := reqInf.(external_models.ValidateAuthorizationDataModel)
 	if !dataResponse.Status {
 		return dataResponse.Message, false
 	}
 
-	models.MyIdentity = &dataResponse.Data
+	setPrincipal(c, models.Principal{Kind: models.PrincipalUser, AuthType: string(at), AccountID: int(dataResponse.Data.AccountID), Token: bearerToken, Plan: dataResponse.Plan, User: &dataResponse.Data})
 	return "authorized", true
 }
 
@@ -111,7 +114,7 @@ func (at AuthorizationType) ValidateBusinessType(c *gin.Context, extReq request.
 	if !status {
 		return msg, false
 	}
-	reqInf, err := extReq.SendExternalRequest(request.ValidateAuthorization, external_models.ValidateAuthorizationReq{
+	dataResponse, err := extReq.Auth.ValidateAuthorization(external_models.ValidateAuthorizationReq{
 		Type:        string(Business),
 		VPrivateKey: privateKey,
 		VPublicKey:  publicKey,
@@ -120,10 +123,10 @@ func (at AuthorizationType) ValidateBusinessType(c *gin.Context, extReq request.
 		return err.Error(), false
 	}
 
-	dataResponse := reqInf.(external_models.ValidateAuthorizationDataModel)
 	if !dataResponse.Status {
 		return dataResponse.Message, false
 	}
+	setPrincipal(c, models.Principal{Kind: models.PrincipalBusiness, AuthType: string(at), AccountID: int(dataResponse.Data.AccountID), KeyID: publicKey, Plan: dataResponse.Plan, User: &dataResponse.Data})
 	return "authorized", true
 }
 
@@ -137,6 +140,7 @@ func (at AuthorizationType) ValidateAppType(c *gin.Context, extReq request.Exter
 	if appKey != config.Key {
 		return "invalid app key", false
 	}
+	setPrincipal(c, models.Principal{Kind: models.PrincipalApp, AuthType: string(at)})
 
 	return "authorized", true
 }
@@ -146,7 +150,7 @@ func (at AuthorizationType) ValidateBusinessAdminType(c *gin.Context, extReq req
 	if !status {
 		return msg, false
 	}
-	reqInf, err := extReq.SendExternalRequest(request.ValidateAuthorization, external_models.ValidateAuthorizationReq{
+	dataResponse, err := extReq.Auth.ValidateAuthorization(external_models.ValidateAuthorizationReq{
 		Type:        string(BusinessAdmin),
 		VPrivateKey: privateKey,
 		VPublicKey:  publicKey,
@@ -155,10 +159,10 @@ func (at AuthorizationType) ValidateBusinessAdminType(c *gin.Context, extReq req
 		return err.Error(), false
 	}
 
-	dataResponse := reqInf.(external_models.ValidateAuthorizationDataModel)
 	if !dataResponse.Status {
 		return dataResponse.Message, false
 	}
+	setPrincipal(c, models.Principal{Kind: models.PrincipalAdmin, AuthType: string(at), AccountID: int(dataResponse.Data.AccountID), KeyID: publicKey, Plan: dataResponse.Plan, User: &dataResponse.Data})
 	return "authorized", true
 }
 
@@ -167,7 +171,7 @@ func (at AuthorizationType) ValidateApiType(c *gin.Context, extReq request.Exter
 	if !status {
 		return msg, false
 	}
-	reqInf, err := extReq.SendExternalRequest(request.ValidateAuthorization, external_models.ValidateAuthorizationReq{
+	dataResponse, err := extReq.Auth.ValidateAuthorization(external_models.ValidateAuthorizationReq{
 		Type:        string(ApiType),
 		VPrivateKey: privateKey,
 		VPublicKey:  publicKey,
@@ -176,14 +180,43 @@ func (at AuthorizationType) ValidateApiType(c *gin.Context, extReq request.Exter
 		return err.Error(), false
 	}
 
-	dataResponse := reqInf.(external_models.ValidateAuthorizationDataModel)
 	if !dataResponse.Status {
 		return dataResponse.Message, false
 	}
+	scopes := dataResponse.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultApiKeyScopes
+	}
+	businessID := dataResponse.Data.BusinessId
+	if businessID == 0 {
+		businessID = int(dataResponse.Data.AccountID)
+	}
+	setPrincipal(c, models.Principal{Kind: models.PrincipalBusiness, AuthType: string(at), AccountID: int(dataResponse.Data.AccountID), BusinessID: businessID, Scopes: scopes, KeyID: publicKey, Plan: dataResponse.Plan, User: &dataResponse.Data})
 	return msg, status
 }
 
+// setPrincipal stores the principal on the gin context and on the request context, where the
+// databases and clients bound to the request can see it.
+func setPrincipal(c *gin.Context, principal models.Principal) {
+	c.Set(principalKey, principal)
+	c.Request = c.Request.WithContext(models.WithPrincipal(c.Request.Context(), principal))
+}
+
+// GetPrincipal returns the principal the request was authorized as.
+func GetPrincipal(c *gin.Context) (models.Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return models.Principal{}, false
+	}
+	principal, ok := value.(models.Principal)
+	return principal, ok
+}
+
 func (at AuthorizationType) getAccessTokens(c *gin.Context) (string, string, string, bool) {
+	if accessToken, ok := signedAccessToken(c); ok {
+		return accessToken.PrivateKey, accessToken.PublicKey, "authorized", true
+	}
+
 	privateKey := GetHeader(c, "v-private-key")
 	publicKey := GetHeader(c, "v-public-key")
 
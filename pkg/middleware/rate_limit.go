@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+const (
+	rateLimiterKey = "rate_limiter"
+
+	// DefaultRateLimitPlan is the plan used for callers whose plan has no limit of its own.
+	DefaultRateLimitPlan = "default"
+
+	defaultRateLimitWindow  = 60
+	defaultRequestPerSecond = 7
+	defaultPlanLimit        = 300
+)
+
+// RateLimiter counts requests in fixed windows kept in Store, so every replica sharing the store
+// enforces the same limits. Authenticated callers are limited by API key or account on their
+// plan's limit, anonymous ones by IP.
+type RateLimiter struct {
+	Store  repository.RateLimitRepository
+	Logger *utility.Logger
+	Window time.Duration
+	// IPLimit is the number of requests an anonymous caller may make per window.
+	IPLimit int
+	// Plans maps a plan to the number of requests its callers may make per window.
+	Plans map[string]int
+	// DailyQuotas maps an operation, see Quota, to the number of times a caller may run it a day.
+	DailyQuotas map[string]int
+	// Exempt lists the IPs that are never limited.
+	Exempt []string
+}
+
+// NewRateLimiter builds a limiter from the rate limit configuration. When no IP limit is set it
+// falls back to the older REQUEST_PER_SECOND setting.
+func NewRateLimiter(store repository.RateLimitRepository, logger *utility.Logger) *RateLimiter {
+	var (
+		serverConfig    = config.GetConfig().Server
+		rateLimitConfig = config.GetConfig().RateLimit
+		window          = rateLimitConfig.Window
+		ipLimit         = rateLimitConfig.IP
+		plans           = map[string]int{}
+	)
+
+	if window <= 0 {
+		window = defaultRateLimitWindow
+	}
+	if ipLimit <= 0 {
+		requestPerSecond := serverConfig.RequestPerSecond
+		if requestPerSecond <= 0 {
+			requestPerSecond = defaultRequestPerSecond
+		}
+		ipLimit = int(requestPerSecond * float64(window))
+	}
+	for plan, limit := range rateLimitConfig.Plans {
+		plans[plan] = limit
+	}
+	if _, ok := plans[DefaultRateLimitPlan]; !ok {
+		plans[DefaultRateLimitPlan] = defaultPlanLimit
+	}
+
+	return &RateLimiter{
+		Store:       store,
+		Logger:      logger,
+		Window:      time.Duration(window) * time.Second,
+		IPLimit:     ipLimit,
+		Plans:       plans,
+		DailyQuotas: rateLimitConfig.DailyQuotas,
+		Exempt:      serverConfig.ExemptFromThrottle,
+	}
+}
+
+// RateLimit makes limiter available to Authorize and Quota and limits callers by IP until
+// Authorize knows who they are. From then on Authorize limits them by key or account, so callers
+// sharing an IP do not share a limit. A request carrying credentials is turned away once its IP is
+// over the limit, and is counted against the IP when its credentials are wrong or the route never
+// checks them.
+func RateLimit(limiter *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(rateLimiterKey, limiter)
+		if limiter.exempt(c) {
+			c.Next()
+			return
+		}
+		subject := "ip:" + c.ClientIP()
+		if !hasCredentials(c) {
+			if limiter.allow(c, "ip", subject, limiter.IPLimit, limiter.Window) {
+				c.Next()
+			}
+			return
+		}
+
+		if !limiter.within(c, "ip", subject, limiter.IPLimit, limiter.Window) {
+			return
+		}
+		c.Next()
+		if _, ok := GetPrincipal(c); !ok {
+			limiter.count(c, "ip", subject, limiter.Window)
+		}
+	}
+}
+
+// Quota caps how many times a day each caller may run operation, for expensive endpoints like
+// imports. It goes after Authorize. Operations without a configured quota are not capped.
+func Quota(operation string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limiter := getRateLimiter(c)
+		if limiter == nil || limiter.exempt(c) {
+			c.Next()
+			return
+		}
+		limit, ok := limiter.DailyQuotas[operation]
+		if !ok || limit <= 0 {
+			c.Next()
+			return
+		}
+		principal, _ := GetPrincipal(c)
+		if limiter.allow(c, "quota:"+operation, rateLimitSubject(c, principal), limit, 24*time.Hour) {
+			c.Next()
+		}
+	}
+}
+
+// limitPrincipal applies the plan limit of the principal Authorize just admitted. It reports false,
+// having answered the request, when the limit is exceeded.
+func limitPrincipal(c *gin.Context) bool {
+	limiter := getRateLimiter(c)
+	if limiter == nil || limiter.exempt(c) {
+		return true
+	}
+	principal, ok := GetPrincipal(c)
+	if !ok || principal.Kind == models.PrincipalApp {
+		// Other services calling with the app key are not limited.
+		return true
+	}
+	plan := principal.Plan
+	limit, ok := limiter.Plans[plan]
+	if !ok {
+		plan, limit = DefaultRateLimitPlan, limiter.Plans[DefaultRateLimitPlan]
+	}
+	return limiter.allow(c, "plan:"+plan, rateLimitSubject(c, principal), limit, limiter.Window)
+}
+
+// rateLimitSubject is who a request is counted against: its API key, its account or its IP.
+func rateLimitSubject(c *gin.Context, principal models.Principal) string {
+	if principal.KeyID != "" {
+		return "key:" + principal.KeyID
+	}
+	if principal.AccountID != 0 {
+		return "account:" + strconv.Itoa(principal.AccountID)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// allow counts the request and sets the RateLimit headers. Over the limit it answers with 429 and
+// reports false. When the store fails the request is let through rather than failing the API.
+func (l *RateLimiter) allow(c *gin.Context, policy, subject string, limit int, window time.Duration) bool {
+	count, ok := l.count(c, policy, subject, window)
+	if !ok {
+		return true
+	}
+	return l.check(c, count, limit, window)
+}
+
+// within is allow for a request that is not counted yet: it answers with 429 and reports false when
+// the limit is already spent, and otherwise leaves the headers to whichever limit counts it.
+func (l *RateLimiter) within(c *gin.Context, policy, subject string, limit int, window time.Duration) bool {
+	count, err := l.store(c.Request.Context()).Count(policy+":"+subject, time.Now().UTC().Truncate(window))
+	if err != nil {
+		l.Logger.Error(fmt.Sprintf("error reading count for rate limit %v: %v", policy, err.Error()))
+		return true
+	}
+	if count < limit {
+		return true
+	}
+	return l.check(c, count+1, limit, window)
+}
+
+// count counts one request against subject and returns the count so far, reporting false when the
+// store fails.
+func (l *RateLimiter) count(c *gin.Context, policy, subject string, window time.Duration) (int, bool) {
+	windowStart := time.Now().UTC().Truncate(window)
+	count, err := l.store(c.Request.Context()).Increment(policy+":"+subject, windowStart, windowStart.Add(window))
+	if err != nil {
+		l.Logger.Error(fmt.Sprintf("error counting request for rate limit %v: %v", policy, err.Error()))
+		return 0, false
+	}
+	return count, true
+}
+
+// check sets the RateLimit headers for a request that is the count-th in its window, answering
+// with 429 and reporting false when that is over the limit.
+func (l *RateLimiter) check(c *gin.Context, count, limit int, window time.Duration) bool {
+	var (
+		now   = time.Now()
+		reset = now.UTC().Truncate(window).Add(window)
+	)
+
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	resetSeconds := int(reset.Sub(now).Seconds() + 0.5)
+	setRateLimitHeaders(c, limit, remaining, resetSeconds, window)
+
+	if count > limit {
+		c.Header("Retry-After", strconv.Itoa(resetSeconds))
+		msg := fmt.Sprintf("rate limit exceeded, try again in %v seconds", resetSeconds)
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, utility.BuildErrorResponse(http.StatusTooManyRequests, "error", msg, fmt.Errorf(msg), nil))
+		return false
+	}
+	return true
+}
+
+func (l *RateLimiter) store(ctx context.Context) repository.RateLimitRepository {
+	if s, ok := l.Store.(interface {
+		WithContext(context.Context) repository.RateLimitRepository
+	}); ok {
+		return s.WithContext(ctx)
+	}
+	return l.Store
+}
+
+func (l *RateLimiter) exempt(c *gin.Context) bool {
+	return isExemptIP(c.ClientIP(), l.Exempt)
+}
+
+// setRateLimitHeaders reports the most restrictive of the limits a request was counted against, so
+// a nearly spent daily quota is not hidden by a fresh per-minute window.
+func setRateLimitHeaders(c *gin.Context, limit, remaining, reset int, window time.Duration) {
+	if current := c.Writer.Header().Get("RateLimit-Remaining"); current != "" {
+		if currentRemaining, err := strconv.Atoi(current); err == nil && currentRemaining < remaining {
+			return
+		}
+	}
+	c.Header("RateLimit-Limit", strconv.Itoa(limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(reset))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%v;w=%v", limit, int(window.Seconds())))
+}
+
+func getRateLimiter(c *gin.Context) *RateLimiter {
+	value, ok := c.Get(rateLimiterKey)
+	if !ok {
+		return nil
+	}
+	limiter, _ := value.(*RateLimiter)
+	return limiter
+}
+
+func hasCredentials(c *gin.Context) bool {
+	return GetHeader(c, "Authorization") != "" || GetHeader(c, "v-private-key") != "" || GetHeader(c, SignatureHeader) != "" || GetHeader(c, "v-app") != ""
+}
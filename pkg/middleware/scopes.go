@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+const (
+	ScopeTransactionsRead  = "transactions:read"
+	ScopeTransactionsWrite = "transactions:write"
+	ScopePartiesWrite      = "parties:write"
+	ScopeFundsRelease      = "funds:release"
+	ScopeRatesRead         = "rates:read"
+)
+
+// defaultApiKeyScopes are granted to keys issued before scopes existed, which the auth service
+// returns without any. They keep read access only.
+var defaultApiKeyScopes = []string{ScopeTransactionsRead, ScopeRatesRead}
+
+// RequireScope stops API key callers whose key was not granted scope. Callers authorized any other
+// way are let through.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if ok && !principal.HasScope(scope) {
+			msg := fmt.Sprintf("api key is missing the %v scope", scope)
+			rd := utility.BuildErrorResponse(http.StatusForbidden, "error", msg, fmt.Errorf(msg), nil)
+			c.AbortWithStatusJSON(http.StatusForbidden, rd)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+const (
+	SignatureHeader = "v-signature"
+	TimestampHeader = "v-timestamp"
+	NonceHeader     = "v-nonce"
+
+	signedKeyKey = "signed_access_token"
+
+	defaultSignatureMaxSkew = 300
+)
+
+// SignatureVerifier checks signed requests, which carry v-public-key, v-timestamp, v-nonce and
+// v-signature in place of v-private-key. See SignRequest for what is signed.
+type SignatureVerifier struct {
+	Nonces repository.NonceRepository
+	ExtReq request.ExternalRequest
+	// MaxSkew is how far a request's timestamp may be from the server's clock. Nonces are kept for as
+	// long, past which the timestamp check alone rejects a replay.
+	MaxSkew time.Duration
+}
+
+func NewSignatureVerifier(nonces repository.NonceRepository, extReq request.ExternalRequest) *SignatureVerifier {
+	maxSkew := config.GetConfig().RequestSigning.MaxSkew
+	if maxSkew <= 0 {
+		maxSkew = defaultSignatureMaxSkew
+	}
+	return &SignatureVerifier{Nonces: nonces, ExtReq: extReq, MaxSkew: time.Duration(maxSkew) * time.Second}
+}
+
+// SignRequest returns the hex encoded HMAC-SHA256, keyed with the private key, of the method, the
+// path with its query string, the timestamp in unix seconds, the nonce and the hex encoded SHA-256
+// of the body, joined by newlines.
+func SignRequest(privateKey, method, requestURI, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	message := strings.Join([]string{strings.ToUpper(method), requestURI, timestamp, nonce, hex.EncodeToString(bodyHash[:])}, "\n")
+	mac := hmac.New(sha256.New, []byte(privateKey))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature lets API clients sign their requests instead of sending their private key.
+// Requests without a signature are passed on untouched. A verified request is authorized by the
+// key pair it was signed with, the same as if both keys had been sent.
+func VerifySignature(verifier *SignatureVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetHeader(c, SignatureHeader) == "" {
+			c.Next()
+			return
+		}
+		accessToken, code, err := verifier.verify(c)
+		if err != nil {
+			if code == http.StatusUnauthorized {
+				c.AbortWithStatusJSON(code, utility.UnauthorisedResponse(code, fmt.Sprint(code), "Unauthorized", err.Error()))
+			} else {
+				c.AbortWithStatusJSON(code, utility.BuildErrorResponse(code, "error", err.Error(), err, nil))
+			}
+			return
+		}
+		c.Set(signedKeyKey, accessToken)
+		c.Next()
+	}
+}
+
+func (v *SignatureVerifier) verify(c *gin.Context) (external_models.AccessToken, int, error) {
+	var (
+		publicKey = GetHeader(c, "v-public-key")
+		timestamp = GetHeader(c, TimestampHeader)
+		nonce     = GetHeader(c, NonceHeader)
+		signature = GetHeader(c, SignatureHeader)
+		ctx       = c.Request.Context()
+	)
+
+	if publicKey == "" || timestamp == "" || nonce == "" {
+		return external_models.AccessToken{}, http.StatusUnauthorized, fmt.Errorf("signed requests need the v-public-key, v-timestamp and v-nonce headers")
+	}
+	if GetHeader(c, "v-private-key") != "" {
+		return external_models.AccessToken{}, http.StatusUnauthorized, fmt.Errorf("signed requests must not send v-private-key")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return external_models.AccessToken{}, http.StatusUnauthorized, fmt.Errorf("v-timestamp must be a unix timestamp in seconds")
+	}
+	signedAt := time.Unix(unix, 0)
+	if skew := time.Since(signedAt); skew > v.MaxSkew || skew < -v.MaxSkew {
+		return external_models.AccessToken{}, http.StatusUnauthorized, fmt.Errorf("request timestamp is more than %v away from the server time", v.MaxSkew)
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return external_models.AccessToken{}, http.StatusBadRequest, fmt.Errorf("error reading request body: %v", err.Error())
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	invalidSignature := fmt.Errorf("invalid request signature")
+	accessToken, err := v.ExtReq.WithContext(ctx).Auth.GetAccessTokenByKey(publicKey)
+	if err != nil || accessToken.PrivateKey == "" || (accessToken.PublicKey != "" && accessToken.PublicKey != publicKey) {
+		return external_models.AccessToken{}, http.StatusUnauthorized, invalidSignature
+	}
+	expected := SignRequest(accessToken.PrivateKey, c.Request.Method, c.Request.URL.RequestURI(), timestamp, nonce, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return external_models.AccessToken{}, http.StatusUnauthorized, invalidSignature
+	}
+
+	// The nonce is only claimed once the signature holds, so nobody without the key can use up a
+	// client's nonces.
+	claimed, err := v.nonces(ctx).Claim(publicKey+":"+nonce, signedAt.Add(v.MaxSkew))
+	if err != nil {
+		v.ExtReq.Logger.Error("error claiming request nonce: ", err.Error())
+		return external_models.AccessToken{}, http.StatusInternalServerError, fmt.Errorf("error verifying request signature")
+	}
+	if !claimed {
+		return external_models.AccessToken{}, http.StatusUnauthorized, fmt.Errorf("request nonce has already been used")
+	}
+
+	accessToken.PublicKey = publicKey
+	return accessToken, http.StatusOK, nil
+}
+
+func (v *SignatureVerifier) nonces(ctx context.Context) repository.NonceRepository {
+	if n, ok := v.Nonces.(interface {
+		WithContext(context.Context) repository.NonceRepository
+	}); ok {
+		return n.WithContext(ctx)
+	}
+	return v.Nonces
+}
+
+// signedAccessToken returns the key pair VerifySignature verified the request with.
+func signedAccessToken(c *gin.Context) (external_models.AccessToken, bool) {
+	value, ok := c.Get(signedKeyKey)
+	if !ok {
+		return external_models.AccessToken{}, false
+	}
+	accessToken, ok := value.(external_models.AccessToken)
+	return accessToken, ok
+}
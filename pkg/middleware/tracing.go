@@ -0,0 +1,12 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Tracing starts a server span named after the matched route for every request, continuing the
+// caller's trace when the request carries W3C trace context headers.
+func Tracing(service string) gin.HandlerFunc {
+	return otelgin.Middleware(service)
+}
@@ -0,0 +1,65 @@
+package gormrepo
+
+import (
+	"context"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"gorm.io/gorm"
+)
+
+type activityLogRepository struct {
+	db *gorm.DB
+}
+
+func (r activityLogRepository) WithContext(ctx context.Context) repository.ActivityLogRepository {
+	return activityLogRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r activityLogRepository) Create(activityLog *models.ActivityLog) error {
+	return activityLog.CreateActivityLog(r.db)
+}
+
+func (r activityLogRepository) ListByTransactionID(transactionID string) ([]models.ActivityLog, error) {
+	activityLog := models.ActivityLog{TransactionID: transactionID}
+	return activityLog.GetAllByTransactionID(r.db)
+}
+
+func (r activityLogRepository) ListByTransactionIDs(transactionIDs []string) ([]models.ActivityLog, error) {
+	activityLog := models.ActivityLog{}
+	return activityLog.GetAllByTransactionIDs(r.db, transactionIDs)
+}
+
+func (r activityLogRepository) ListByFilters(req models.ListActivityLogsRequest, paginator postgresql.Pagination) ([]models.ActivityLog, postgresql.PaginationResponse, error) {
+	activityLog := models.ActivityLog{}
+	return activityLog.GetAllByFilters(r.db, req, paginator)
+}
+
+type stateRepository struct {
+	db *gorm.DB
+}
+
+func (r stateRepository) WithContext(ctx context.Context) repository.StateRepository {
+	return stateRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r stateRepository) Create(state *models.TransactionState) error {
+	return state.CreateTransactionState(r.db)
+}
+
+func (r stateRepository) GetByTransactionIDAndStatus(transactionID, status string) (models.TransactionState, int, error) {
+	state := models.TransactionState{TransactionID: transactionID, Status: status}
+	code, err := state.GetTransactionStateByTransactionIDAndStatus(r.db)
+	return state, code, err
+}
+
+func (r stateRepository) ListByTransactionID(transactionID string) ([]models.TransactionState, error) {
+	state := models.TransactionState{TransactionID: transactionID}
+	return state.GetAllByTransactionID(r.db)
+}
+
+func (r stateRepository) ListByTransactionIDsAndStatus(transactionIDs []string, status string) ([]models.TransactionState, error) {
+	state := models.TransactionState{Status: status}
+	return state.GetAllByTransactionIDsAndStatus(r.db, transactionIDs)
+}
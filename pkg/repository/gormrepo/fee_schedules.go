@@ -0,0 +1,63 @@
+package gormrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"gorm.io/gorm"
+)
+
+type feeScheduleRepository struct {
+	db *gorm.DB
+}
+
+func (r feeScheduleRepository) WithContext(ctx context.Context) repository.FeeScheduleRepository {
+	return feeScheduleRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r feeScheduleRepository) Create(schedule *models.FeeSchedule) error {
+	return schedule.CreateFeeSchedule(r.db)
+}
+
+func (r feeScheduleRepository) GetLatestVersion(feeScheduleID string) (models.FeeSchedule, int, error) {
+	schedule := models.FeeSchedule{FeeScheduleID: feeScheduleID}
+	code, err := schedule.GetLatestVersion(r.db)
+	return schedule, code, err
+}
+
+func (r feeScheduleRepository) ListVersions(feeScheduleID string) ([]models.FeeSchedule, error) {
+	schedule := models.FeeSchedule{FeeScheduleID: feeScheduleID}
+	return schedule.GetAllVersions(r.db)
+}
+
+func (r feeScheduleRepository) ListLatest(req models.ListFeeSchedulesRequest, paginator postgresql.Pagination) ([]models.FeeSchedule, postgresql.PaginationResponse, error) {
+	schedule := models.FeeSchedule{}
+	return schedule.GetAllLatest(r.db, req, paginator)
+}
+
+func (r feeScheduleRepository) GetEffective(businessID int, currency, transactionType string, at time.Time) (models.FeeSchedule, bool, error) {
+	schedule := models.FeeSchedule{}
+	found, err := schedule.GetEffective(r.db, businessID, currency, transactionType, at)
+	return schedule, found, err
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func (r auditLogRepository) WithContext(ctx context.Context) repository.AuditLogRepository {
+	return auditLogRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r auditLogRepository) ListByTransactionID(transactionID string, paginator postgresql.Pagination) ([]models.AuditLog, postgresql.PaginationResponse, error) {
+	auditLog := models.AuditLog{TransactionID: transactionID}
+	return auditLog.GetAllByTransactionID(r.db, paginator)
+}
+
+func (r auditLogRepository) VerifyChain() (models.AuditChainVerification, error) {
+	auditLog := models.AuditLog{}
+	return auditLog.VerifyChain(r.db)
+}
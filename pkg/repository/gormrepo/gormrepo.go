@@ -0,0 +1,28 @@
+// Package gormrepo implements the repository interfaces on top of the gorm model methods.
+package gormrepo
+
+import (
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"gorm.io/gorm"
+)
+
+// New returns repositories reading and writing through db, normally postgresql.Databases.Transaction.
+func New(db *gorm.DB) repository.Repositories {
+	return repository.Repositories{
+		Transactions:      transactionRepository{db: db},
+		Parties:           partyRepository{db: db},
+		Disputes:          disputeRepository{db: db},
+		Brokers:           brokerRepository{db: db},
+		Files:             fileRepository{db: db},
+		Products:          productRepository{db: db},
+		Rates:             rateRepository{db: db},
+		ActivityLogs:      activityLogRepository{db: db},
+		States:            stateRepository{db: db},
+		Rejections:        rejectionRepository{db: db},
+		DueDateExtensions: dueDateExtensionRepository{db: db},
+		FeeSchedules:      feeScheduleRepository{db: db},
+		AuditLogs:         auditLogRepository{db: db},
+		RateLimits:        rateLimitRepository{db: db},
+		Nonces:            nonceRepository{db: db},
+	}
+}
@@ -0,0 +1,27 @@
+package gormrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"gorm.io/gorm"
+)
+
+type nonceRepository struct {
+	db *gorm.DB
+}
+
+func (r nonceRepository) WithContext(ctx context.Context) repository.NonceRepository {
+	return nonceRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r nonceRepository) Claim(nonce string, expiresAt time.Time) (bool, error) {
+	requestNonce := models.RequestNonce{Nonce: nonce, ExpiresAt: expiresAt}
+	return requestNonce.Claim(r.db)
+}
+
+func (r nonceRepository) DeleteExpired(before time.Time) (int64, error) {
+	return models.RequestNonce{}.DeleteExpired(r.db, before)
+}
@@ -0,0 +1,80 @@
+package gormrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"gorm.io/gorm"
+)
+
+type partyRepository struct {
+	db *gorm.DB
+}
+
+func (r partyRepository) WithContext(ctx context.Context) repository.PartyRepository {
+	return partyRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r partyRepository) Create(party *models.TransactionParty) error {
+	return party.CreateTransactionParty(r.db)
+}
+
+func (r partyRepository) Update(party *models.TransactionParty) error {
+	return party.UpdateAllFields(r.db)
+}
+
+func (r partyRepository) GetByTransactionIDAndRole(transactionID, role string) (models.TransactionParty, int, error) {
+	party := models.TransactionParty{TransactionID: transactionID, Role: role}
+	code, err := party.GetTransactionPartyByTransactionIDAndRole(r.db)
+	return party, code, err
+}
+
+func (r partyRepository) GetByTransactionPartiesIDAndRole(transactionPartiesID, role string) (models.TransactionParty, int, error) {
+	party := models.TransactionParty{TransactionPartiesID: transactionPartiesID, Role: role}
+	code, err := party.GetTransactionPartyByTransactionPartiesIDAndRole(r.db)
+	return party, code, err
+}
+
+func (r partyRepository) GetByTransactionIDAndAccountID(transactionID string, accountID int) (models.TransactionParty, int, error) {
+	party := models.TransactionParty{TransactionID: transactionID, AccountID: accountID}
+	code, err := party.GetTransactionPartyByTransactionIDAndAccountID(r.db)
+	return party, code, err
+}
+
+func (r partyRepository) ListByTransactionID(transactionID string) ([]models.TransactionParty, error) {
+	party := models.TransactionParty{TransactionID: transactionID}
+	return party.GetAllByTransactionID(r.db)
+}
+
+func (r partyRepository) ListByTransactionPartiesID(transactionPartiesID string) ([]models.TransactionParty, error) {
+	party := models.TransactionParty{TransactionPartiesID: transactionPartiesID}
+	return party.GetAllByTransactionPartiesID(r.db)
+}
+
+func (r partyRepository) ListByTransactionIDsOrPartiesIDs(transactionIDs, transactionPartiesIDs []string) ([]models.TransactionParty, error) {
+	party := models.TransactionParty{}
+	return party.GetAllByTransactionIDsOrPartiesIDs(r.db, transactionIDs, transactionPartiesIDs)
+}
+
+func (r partyRepository) ListByAccountID(accountID int, role, transactionStatus string, paginator postgresql.Pagination) ([]models.TransactionParty, postgresql.PaginationResponse, error) {
+	party := models.TransactionParty{AccountID: accountID, Role: role}
+	return party.GetAllByAndQueriesForUniqueValueForTransactionStatus(r.db, "", "id", "desc", "transaction_id", transactionStatus, paginator)
+}
+
+func (r partyRepository) ListArchivedByAccountID(accountID int, paginator postgresql.Pagination) ([]models.TransactionParty, postgresql.PaginationResponse, error) {
+	party := models.TransactionParty{AccountID: accountID}
+	return party.GetAllArchivedByAccountID(r.db, paginator)
+}
+
+func (r partyRepository) ListDeletedByTransactionID(transactionID string, deletedAt time.Time) ([]models.TransactionParty, error) {
+	party := models.TransactionParty{TransactionID: transactionID}
+	return party.GetAllDeletedByTransactionID(r.db, deletedAt)
+}
+
+func (r partyRepository) ListDisputedByAccountID(accountID int, paginator postgresql.Pagination) ([]models.TransactionParty, postgresql.PaginationResponse, error) {
+	party := models.TransactionParty{AccountID: accountID}
+	return party.GetAllByAndQueriesForUniqueValueForDispute(r.db, "", "id", "desc", "transaction_id", paginator)
+}
@@ -0,0 +1,34 @@
+package gormrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"gorm.io/gorm"
+)
+
+type rateLimitRepository struct {
+	db *gorm.DB
+}
+
+func (r rateLimitRepository) WithContext(ctx context.Context) repository.RateLimitRepository {
+	return rateLimitRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r rateLimitRepository) Increment(key string, windowStart, expiresAt time.Time) (int, error) {
+	counter := models.RateLimitCounter{Key: key, WindowStart: windowStart, ExpiresAt: expiresAt}
+	err := counter.Increment(r.db)
+	return counter.Count, err
+}
+
+func (r rateLimitRepository) Count(key string, windowStart time.Time) (int, error) {
+	counter := models.RateLimitCounter{Key: key, WindowStart: windowStart}
+	err := counter.Get(r.db)
+	return counter.Count, err
+}
+
+func (r rateLimitRepository) DeleteExpired(before time.Time) (int64, error) {
+	return models.RateLimitCounter{}.DeleteExpired(r.db, before)
+}
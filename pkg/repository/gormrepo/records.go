@@ -0,0 +1,160 @@
+package gormrepo
+
+import (
+	"context"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"gorm.io/gorm"
+)
+
+type disputeRepository struct {
+	db *gorm.DB
+}
+
+func (r disputeRepository) WithContext(ctx context.Context) repository.DisputeRepository {
+	return disputeRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r disputeRepository) Create(dispute *models.TransactionDispute) error {
+	return dispute.CreateTransactionDispute(r.db)
+}
+
+func (r disputeRepository) Update(dispute *models.TransactionDispute) error {
+	return dispute.UpdateAllFields(r.db)
+}
+
+func (r disputeRepository) GetByTransactionID(transactionID string) (models.TransactionDispute, int, error) {
+	dispute := models.TransactionDispute{TransactionID: transactionID}
+	code, err := dispute.GetTransactionDisputeByTransactionID(r.db)
+	return dispute, code, err
+}
+
+func (r disputeRepository) ListByTransactionIDs(transactionIDs []string) ([]models.TransactionDispute, error) {
+	dispute := models.TransactionDispute{}
+	return dispute.GetAllByTransactionIDs(r.db, transactionIDs)
+}
+
+func (r disputeRepository) CountUndecided() (int64, error) {
+	dispute := models.TransactionDispute{}
+	return dispute.CountUndecided(r.db)
+}
+
+type brokerRepository struct {
+	db *gorm.DB
+}
+
+func (r brokerRepository) WithContext(ctx context.Context) repository.BrokerRepository {
+	return brokerRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r brokerRepository) Create(broker *models.TransactionBroker) error {
+	return broker.CreateTransactionBroker(r.db)
+}
+
+func (r brokerRepository) Update(broker *models.TransactionBroker) error {
+	return broker.UpdateAllFields(r.db)
+}
+
+func (r brokerRepository) GetByTransactionID(transactionID string) (models.TransactionBroker, int, error) {
+	broker := models.TransactionBroker{TransactionID: transactionID}
+	code, err := broker.GetTransactionBrokerByTransactionID(r.db)
+	return broker, code, err
+}
+
+func (r brokerRepository) ListByTransactionIDs(transactionIDs []string) ([]models.TransactionBroker, error) {
+	broker := models.TransactionBroker{}
+	return broker.GetAllByTransactionIDs(r.db, transactionIDs)
+}
+
+type fileRepository struct {
+	db *gorm.DB
+}
+
+func (r fileRepository) WithContext(ctx context.Context) repository.FileRepository {
+	return fileRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r fileRepository) Create(file *models.TransactionFile) error {
+	return file.CreateTransactionFile(r.db)
+}
+
+func (r fileRepository) ListByTransactionID(transactionID string) ([]models.TransactionFile, error) {
+	file := models.TransactionFile{TransactionID: transactionID}
+	return file.GetAllByTransactionID(r.db)
+}
+
+func (r fileRepository) ListByTransactionIDs(transactionIDs []string) ([]models.TransactionFile, error) {
+	file := models.TransactionFile{}
+	return file.GetAllByTransactionIDs(r.db, transactionIDs)
+}
+
+type productRepository struct {
+	db *gorm.DB
+}
+
+func (r productRepository) WithContext(ctx context.Context) repository.ProductRepository {
+	return productRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r productRepository) ListByTransactionID(transactionID string) ([]models.ProductTransaction, error) {
+	product := models.ProductTransaction{TransactionID: transactionID}
+	return product.GetAllByTransactionID(r.db)
+}
+
+func (r productRepository) ListByTransactionIDs(transactionIDs []string) ([]models.ProductTransaction, error) {
+	product := models.ProductTransaction{}
+	return product.GetAllByTransactionIDs(r.db, transactionIDs)
+}
+
+type rateRepository struct {
+	db *gorm.DB
+}
+
+func (r rateRepository) WithContext(ctx context.Context) repository.RateRepository {
+	return rateRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r rateRepository) Create(rate *models.Rate) error {
+	return rate.CreateRate(r.db)
+}
+
+func (r rateRepository) List() ([]models.Rate, error) {
+	return models.Rate{}.GetAll(r.db)
+}
+
+func (r rateRepository) GetByID(id int64) (models.Rate, int, error) {
+	rate := models.Rate{ID: id}
+	code, err := rate.GetRateByID(r.db)
+	return rate, code, err
+}
+
+func (r rateRepository) GetByCurrencies(fromCurrency, toCurrency string) (models.Rate, int, error) {
+	rate := models.Rate{FromCurrency: fromCurrency, ToCurrency: toCurrency}
+	code, err := rate.GetRateByFromAndToCurrencies(r.db)
+	return rate, code, err
+}
+
+type rejectionRepository struct {
+	db *gorm.DB
+}
+
+func (r rejectionRepository) WithContext(ctx context.Context) repository.RejectionRepository {
+	return rejectionRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r rejectionRepository) Create(rejection *models.TransactionsRejected) error {
+	return rejection.CreateTransactionsRejected(r.db)
+}
+
+type dueDateExtensionRepository struct {
+	db *gorm.DB
+}
+
+func (r dueDateExtensionRepository) WithContext(ctx context.Context) repository.DueDateExtensionRepository {
+	return dueDateExtensionRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r dueDateExtensionRepository) Create(request *models.TransactionDueDateExtensionRequest) error {
+	return request.CreateTransactionDueDateExtensionRequest(r.db)
+}
@@ -0,0 +1,93 @@
+package gormrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"gorm.io/gorm"
+)
+
+type transactionRepository struct {
+	db *gorm.DB
+}
+
+func (r transactionRepository) WithContext(ctx context.Context) repository.TransactionRepository {
+	return transactionRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r transactionRepository) Create(transaction *models.Transaction) error {
+	return transaction.CreateTransaction(r.db)
+}
+
+func (r transactionRepository) Update(transaction *models.Transaction) error {
+	return transaction.UpdateAllFields(r.db)
+}
+
+func (r transactionRepository) Delete(transaction *models.Transaction) error {
+	return transaction.Delete(r.db)
+}
+
+func (r transactionRepository) GetDeletedByTransactionID(transactionID string) (models.Transaction, int, error) {
+	transaction := models.Transaction{TransactionID: transactionID}
+	code, err := transaction.GetDeletedTransactionByTransactionID(r.db)
+	return transaction, code, err
+}
+
+func (r transactionRepository) Restore(transaction *models.Transaction) error {
+	return transaction.Restore(r.db, transaction.DeletedAt.Time)
+}
+
+func (r transactionRepository) PurgeDeleted(before time.Time) (int64, error) {
+	return models.Transaction{}.PurgeDeleted(r.db, before)
+}
+
+func (r transactionRepository) GetByTransactionID(transactionID string) (models.Transaction, int, error) {
+	transaction := models.Transaction{TransactionID: transactionID}
+	code, err := transaction.GetTransactionByTransactionID(r.db)
+	return transaction, code, err
+}
+
+func (r transactionRepository) GetByTransactionIDAndMilestoneID(transactionID, milestoneID string) (models.Transaction, int, error) {
+	transaction := models.Transaction{TransactionID: transactionID, MilestoneID: milestoneID}
+	code, err := transaction.GetTransactionByTransactionIDAndMilestoneID(r.db)
+	return transaction, code, err
+}
+
+func (r transactionRepository) GetByUssdCode(ussdCode int) (models.Transaction, int, error) {
+	transaction := models.Transaction{TransUssdCode: ussdCode}
+	code, err := transaction.GetTransactionByUssdCode(r.db)
+	return transaction, code, err
+}
+
+func (r transactionRepository) ListByTransactionID(transactionID string) ([]models.Transaction, error) {
+	transaction := models.Transaction{TransactionID: transactionID}
+	return transaction.GetAllByTransactionID(r.db)
+}
+
+func (r transactionRepository) ListByTransactionIDs(transactionIDs []string) ([]models.Transaction, error) {
+	transaction := models.Transaction{}
+	return transaction.GetAllByTransactionIDs(r.db, transactionIDs)
+}
+
+func (r transactionRepository) ListByPartiesID(partiesID string) ([]models.Transaction, error) {
+	transaction := models.Transaction{PartiesID: partiesID}
+	return transaction.GetAllByPartiesID(r.db)
+}
+
+func (r transactionRepository) ListByFilter(filter models.TransactionFilter) ([]models.Transaction, error) {
+	transaction := models.Transaction{}
+	return transaction.GetAllByFilter(r.db, filter)
+}
+
+func (r transactionRepository) ListByQuery(query models.TransactionQuery, paginator postgresql.Pagination) ([]models.Transaction, postgresql.PaginationResponse, error) {
+	transaction := models.Transaction{BusinessID: query.BusinessID, Status: query.Status, IsPaylinked: query.IsPaylinked}
+	return transaction.GetAllByAndQueries(r.db, query.UsePaylinked, query.CreatedAtInterval, "id", "asc", paginator)
+}
+
+func (r transactionRepository) CountByStatus() (map[string]int64, error) {
+	transaction := models.Transaction{}
+	return transaction.CountByStatus(r.db)
+}
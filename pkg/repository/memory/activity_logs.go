@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"strings"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+)
+
+type activityLogRepository struct {
+	s *Store
+}
+
+func (r activityLogRepository) Create(activityLog *models.ActivityLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	activityLog.ApplyDefaults()
+	activityLog.ID = r.s.nextID("activity_logs")
+	activityLog.CreatedAt, activityLog.UpdatedAt = time.Now(), time.Now()
+	r.s.activityLogs = append(r.s.activityLogs, *activityLog)
+	return nil
+}
+
+func (r activityLogRepository) ListByTransactionID(transactionID string) ([]models.ActivityLog, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.activityLogs, func(a models.ActivityLog) bool { return a.TransactionID == transactionID }), nil
+}
+
+func (r activityLogRepository) ListByTransactionIDs(transactionIDs []string) ([]models.ActivityLog, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.activityLogs, inStrings(transactionIDs, func(a models.ActivityLog) string { return a.TransactionID })), nil
+}
+
+func (r activityLogRepository) ListByFilters(req models.ListActivityLogsRequest, paginator postgresql.Pagination) ([]models.ActivityLog, postgresql.PaginationResponse, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	businessTransactions := map[string]bool{}
+	for _, t := range r.s.transactions {
+		if t.BusinessID == req.BusinessID {
+			businessTransactions[t.TransactionID] = true
+		}
+	}
+	activityLogs := findAll(r.s.activityLogs, func(a models.ActivityLog) bool {
+		switch {
+		case req.TransactionID != "" && a.TransactionID != req.TransactionID:
+			return false
+		case req.BusinessID != 0 && !businessTransactions[a.TransactionID]:
+			return false
+		case req.MilestoneID != "" && a.MilestoneID != req.MilestoneID:
+			return false
+		case req.EventType != "" && a.EventType != req.EventType:
+			return false
+		case req.ActorAccountID != 0 && a.ActorAccountID != req.ActorAccountID:
+			return false
+		case req.ActorRole != "" && a.ActorRole != req.ActorRole:
+			return false
+		case req.Status != "" && !strings.EqualFold(a.PreviousStatus, req.Status) && !strings.EqualFold(a.NewStatus, req.Status):
+			return false
+		}
+		return true
+	})
+	page, pagination := newestFirstPage(activityLogs, paginator)
+	return page, pagination, nil
+}
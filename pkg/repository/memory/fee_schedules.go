@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+)
+
+type feeScheduleRepository struct {
+	s *Store
+}
+
+func (r feeScheduleRepository) Create(schedule *models.FeeSchedule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	schedule.ID = r.s.nextID("fee_schedules")
+	schedule.CreatedAt, schedule.UpdatedAt = time.Now(), time.Now()
+	r.s.feeSchedules = append(r.s.feeSchedules, *schedule)
+	return nil
+}
+
+func (r feeScheduleRepository) GetLatestVersion(feeScheduleID string) (models.FeeSchedule, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, schedule := range r.latest() {
+		if schedule.FeeScheduleID == feeScheduleID {
+			return schedule, http.StatusOK, nil
+		}
+	}
+	return models.FeeSchedule{}, http.StatusBadRequest, repository.ErrNotFound
+}
+
+func (r feeScheduleRepository) ListVersions(feeScheduleID string) ([]models.FeeSchedule, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.feeSchedules, func(f models.FeeSchedule) bool { return f.FeeScheduleID == feeScheduleID }), nil
+}
+
+func (r feeScheduleRepository) ListLatest(req models.ListFeeSchedulesRequest, paginator postgresql.Pagination) ([]models.FeeSchedule, postgresql.PaginationResponse, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	schedules := findAll(r.latest(), func(f models.FeeSchedule) bool {
+		switch {
+		case req.BusinessID != 0 && f.BusinessID != req.BusinessID:
+			return false
+		case req.Currency != "" && f.Currency != req.Currency:
+			return false
+		case req.TransactionType != "" && f.TransactionType != req.TransactionType:
+			return false
+		}
+		return true
+	})
+	page, pagination := newestFirstPage(schedules, paginator)
+	return page, pagination, nil
+}
+
+func (r feeScheduleRepository) GetEffective(businessID int, currency, transactionType string, at time.Time) (models.FeeSchedule, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	candidates := findAll(r.latest(), func(f models.FeeSchedule) bool { return f.AppliesTo(businessID, currency, transactionType, at) })
+	schedule, found := models.MostSpecificFeeSchedule(candidates)
+	return schedule, found, nil
+}
+
+// latest keeps the highest version of every schedule, in id order. Callers hold r.s.mu.
+func (r feeScheduleRepository) latest() []models.FeeSchedule {
+	highest := map[string]int{}
+	for _, f := range r.s.feeSchedules {
+		if f.Version > highest[f.FeeScheduleID] {
+			highest[f.FeeScheduleID] = f.Version
+		}
+	}
+	return findAll(r.s.feeSchedules, func(f models.FeeSchedule) bool { return f.Version == highest[f.FeeScheduleID] })
+}
+
+// auditLogRepository reads an empty trail: the audit log is written by the gorm hooks of the
+// audited models, which the in-memory store never runs.
+type auditLogRepository struct{}
+
+func (r auditLogRepository) ListByTransactionID(transactionID string, paginator postgresql.Pagination) ([]models.AuditLog, postgresql.PaginationResponse, error) {
+	page, pagination := oldestFirstPage([]models.AuditLog{}, paginator)
+	return page, pagination, nil
+}
+
+func (r auditLogRepository) VerifyChain() (models.AuditChainVerification, error) {
+	return models.AuditChainVerification{Valid: true}, nil
+}
@@ -0,0 +1,30 @@
+package memory
+
+import "time"
+
+type nonceRepository struct {
+	s *Store
+}
+
+func (r nonceRepository) Claim(nonce string, expiresAt time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.nonces[nonce]; ok {
+		return false, nil
+	}
+	r.s.nonces[nonce] = expiresAt
+	return true, nil
+}
+
+func (r nonceRepository) DeleteExpired(before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var deleted int64
+	for nonce, expiresAt := range r.s.nonces {
+		if expiresAt.Before(before) {
+			delete(r.s.nonces, nonce)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
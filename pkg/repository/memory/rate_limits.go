@@ -0,0 +1,47 @@
+package memory
+
+import "time"
+
+type rateLimitWindow struct {
+	key         string
+	windowStart time.Time
+}
+
+type rateLimitCount struct {
+	count     int
+	expiresAt time.Time
+}
+
+type rateLimitRepository struct {
+	s *Store
+}
+
+func (r rateLimitRepository) Increment(key string, windowStart, expiresAt time.Time) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	window := rateLimitWindow{key: key, windowStart: windowStart.UTC()}
+	counter := r.s.rateLimits[window]
+	counter.count++
+	counter.expiresAt = expiresAt
+	r.s.rateLimits[window] = counter
+	return counter.count, nil
+}
+
+func (r rateLimitRepository) Count(key string, windowStart time.Time) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.rateLimits[rateLimitWindow{key: key, windowStart: windowStart.UTC()}].count, nil
+}
+
+func (r rateLimitRepository) DeleteExpired(before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var deleted int64
+	for window, counter := range r.s.rateLimits {
+		if counter.expiresAt.Before(before) {
+			delete(r.s.rateLimits, window)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
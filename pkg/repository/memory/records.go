@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+)
+
+type disputeRepository struct {
+	s   *Store
+	ctx context.Context
+}
+
+func (r disputeRepository) WithContext(ctx context.Context) repository.DisputeRepository {
+	return disputeRepository{s: r.s, ctx: ctx}
+}
+
+func (r disputeRepository) Create(dispute *models.TransactionDispute) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	dispute.ID = int64(r.s.nextID("transaction_disputes"))
+	dispute.CreatedAt, dispute.UpdatedAt = time.Now(), time.Now()
+	dispute.Version = 1
+	r.s.disputes = append(r.s.disputes, *dispute)
+	return nil
+}
+
+func (r disputeRepository) Update(dispute *models.TransactionDispute) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if dispute.ID == 0 {
+		dispute.ID = int64(r.s.nextID("transaction_disputes"))
+		dispute.CreatedAt = time.Now()
+	}
+	dispute.UpdatedAt = time.Now()
+	var err error
+	r.s.disputes, err = saveVersioned(r.s.disputes, dispute, func(d models.TransactionDispute) bool { return d.ID == dispute.ID }, func(d *models.TransactionDispute) *int { return &d.Version }, postgresql.ExpectedVersionsFrom(r.ctx), postgresql.RecordKey(models.AuditEntityTransactionDispute, dispute.ID))
+	return err
+}
+
+func (r disputeRepository) GetByTransactionID(transactionID string) (models.TransactionDispute, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.disputes, func(d models.TransactionDispute) bool { return d.TransactionID == transactionID })
+}
+
+func (r disputeRepository) ListByTransactionIDs(transactionIDs []string) ([]models.TransactionDispute, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.disputes, inStrings(transactionIDs, func(d models.TransactionDispute) string { return d.TransactionID })), nil
+}
+
+func (r disputeRepository) CountUndecided() (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(findAll(r.s.disputes, func(d models.TransactionDispute) bool { return d.Decision == "" }))), nil
+}
+
+type brokerRepository struct {
+	s   *Store
+	ctx context.Context
+}
+
+func (r brokerRepository) WithContext(ctx context.Context) repository.BrokerRepository {
+	return brokerRepository{s: r.s, ctx: ctx}
+}
+
+func (r brokerRepository) Create(broker *models.TransactionBroker) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	broker.ID = r.s.nextID("transaction_brokers")
+	broker.CreatedAt, broker.UpdatedAt = time.Now(), time.Now()
+	broker.Version = 1
+	if broker.BrokerChargeType == "" {
+		broker.BrokerChargeType = "fixed"
+	}
+	r.s.brokers = append(r.s.brokers, *broker)
+	return nil
+}
+
+func (r brokerRepository) Update(broker *models.TransactionBroker) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if broker.ID == 0 {
+		broker.ID = r.s.nextID("transaction_brokers")
+		broker.CreatedAt = time.Now()
+	}
+	broker.UpdatedAt = time.Now()
+	var err error
+	r.s.brokers, err = saveVersioned(r.s.brokers, broker, func(b models.TransactionBroker) bool { return b.ID == broker.ID }, func(b *models.TransactionBroker) *int { return &b.Version }, postgresql.ExpectedVersionsFrom(r.ctx), postgresql.RecordKey(models.AuditEntityTransactionBroker, broker.ID))
+	return err
+}
+
+func (r brokerRepository) GetByTransactionID(transactionID string) (models.TransactionBroker, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.brokers, func(b models.TransactionBroker) bool { return b.TransactionID == transactionID })
+}
+
+func (r brokerRepository) ListByTransactionIDs(transactionIDs []string) ([]models.TransactionBroker, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.brokers, inStrings(transactionIDs, func(b models.TransactionBroker) string { return b.TransactionID })), nil
+}
+
+type fileRepository struct {
+	s *Store
+}
+
+func (r fileRepository) Create(file *models.TransactionFile) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	file.ID = r.s.nextID("transaction_files")
+	file.CreatedAt, file.UpdatedAt = time.Now(), time.Now()
+	r.s.files = append(r.s.files, *file)
+	return nil
+}
+
+func (r fileRepository) ListByTransactionID(transactionID string) ([]models.TransactionFile, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.files, func(f models.TransactionFile) bool { return f.TransactionID == transactionID }), nil
+}
+
+func (r fileRepository) ListByTransactionIDs(transactionIDs []string) ([]models.TransactionFile, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.files, inStrings(transactionIDs, func(f models.TransactionFile) string { return f.TransactionID })), nil
+}
+
+type productRepository struct {
+	s *Store
+}
+
+func (r productRepository) ListByTransactionID(transactionID string) ([]models.ProductTransaction, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.products, func(p models.ProductTransaction) bool { return p.TransactionID == transactionID }), nil
+}
+
+func (r productRepository) ListByTransactionIDs(transactionIDs []string) ([]models.ProductTransaction, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.products, inStrings(transactionIDs, func(p models.ProductTransaction) string { return p.TransactionID })), nil
+}
+
+type rateRepository struct {
+	s *Store
+}
+
+func (r rateRepository) Create(rate *models.Rate) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rate.ID = int64(r.s.nextID("rates"))
+	rate.CreatedAt, rate.UpdatedAt = time.Now(), time.Now()
+	r.s.rates = append(r.s.rates, *rate)
+	return nil
+}
+
+func (r rateRepository) List() ([]models.Rate, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rates := make([]models.Rate, len(r.s.rates))
+	for i, rate := range r.s.rates {
+		rates[len(r.s.rates)-1-i] = rate
+	}
+	return rates, nil
+}
+
+func (r rateRepository) GetByID(id int64) (models.Rate, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.rates, func(rate models.Rate) bool { return rate.ID == id })
+}
+
+func (r rateRepository) GetByCurrencies(fromCurrency, toCurrency string) (models.Rate, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.rates, func(rate models.Rate) bool {
+		return strings.EqualFold(rate.FromCurrency, fromCurrency) && strings.EqualFold(rate.ToCurrency, toCurrency)
+	})
+}
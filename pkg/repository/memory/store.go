@@ -0,0 +1,215 @@
+// Package memory implements the repository interfaces in process so services and cron jobs can be
+// tested without postgres. Lists come back in insertion order, matching the "id asc" the gorm
+// backend uses, and single record lookups that find nothing return repository.ErrNotFound with
+// http.StatusBadRequest.
+package memory
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+)
+
+const defaultLimit = 20
+
+// Store holds every table. It is safe for concurrent use.
+type Store struct {
+	mu                sync.Mutex
+	ids               map[string]uint
+	transactions      []models.Transaction
+	parties           []models.TransactionParty
+	disputes          []models.TransactionDispute
+	brokers           []models.TransactionBroker
+	files             []models.TransactionFile
+	products          []models.ProductTransaction
+	rates             []models.Rate
+	activityLogs      []models.ActivityLog
+	states            []models.TransactionState
+	rejections        []models.TransactionsRejected
+	dueDateExtensions []models.TransactionDueDateExtensionRequest
+	feeSchedules      []models.FeeSchedule
+	rateLimits        map[rateLimitWindow]rateLimitCount
+	nonces            map[string]time.Time
+	deleted           []deletedTransaction
+}
+
+// deletedTransaction holds the rows a transaction's Delete took out of the store until they are
+// restored or purged, so lookups never see them.
+type deletedTransaction struct {
+	transactionID     string
+	deletedAt         time.Time
+	transactions      []models.Transaction
+	parties           []models.TransactionParty
+	disputes          []models.TransactionDispute
+	brokers           []models.TransactionBroker
+	files             []models.TransactionFile
+	products          []models.ProductTransaction
+	rejections        []models.TransactionsRejected
+	dueDateExtensions []models.TransactionDueDateExtensionRequest
+}
+
+func New() *Store {
+	return &Store{ids: map[string]uint{}, rateLimits: map[rateLimitWindow]rateLimitCount{}, nonces: map[string]time.Time{}}
+}
+
+// Repositories returns repositories sharing the store, so a transaction written through one is
+// seen by the filters of another.
+func (s *Store) Repositories() repository.Repositories {
+	return repository.Repositories{
+		Transactions:      transactionRepository{s: s},
+		Parties:           partyRepository{s: s},
+		Disputes:          disputeRepository{s: s},
+		Brokers:           brokerRepository{s: s},
+		Files:             fileRepository{s},
+		Products:          productRepository{s},
+		Rates:             rateRepository{s},
+		ActivityLogs:      activityLogRepository{s},
+		States:            stateRepository{s},
+		Rejections:        rejectionRepository{s},
+		DueDateExtensions: dueDateExtensionRepository{s},
+		FeeSchedules:      feeScheduleRepository{s},
+		AuditLogs:         auditLogRepository{},
+		RateLimits:        rateLimitRepository{s},
+		Nonces:            nonceRepository{s},
+	}
+}
+
+// Rejections returns the rejection reasons recorded so far.
+func (s *Store) Rejections() []models.TransactionsRejected {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.TransactionsRejected{}, s.rejections...)
+}
+
+// nextID hands out ids per table the way an auto increment column would. Callers hold s.mu.
+func (s *Store) nextID(table string) uint {
+	s.ids[table]++
+	return s.ids[table]
+}
+
+func findOne[T any](items []T, match func(T) bool) (T, int, error) {
+	for _, item := range items {
+		if match(item) {
+			return item, http.StatusOK, nil
+		}
+	}
+	var zero T
+	return zero, http.StatusBadRequest, repository.ErrNotFound
+}
+
+// inStrings returns a match for findAll picking out the items whose key is one of values.
+func inStrings[T any](values []string, key func(T) string) func(T) bool {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[v] = true
+	}
+	return func(item T) bool { return set[key(item)] }
+}
+
+func findAll[T any](items []T, match func(T) bool) []T {
+	found := []T{}
+	for _, item := range items {
+		if match(item) {
+			found = append(found, item)
+		}
+	}
+	return found
+}
+
+// save replaces the item with the same id, or appends it when there is none, like gorm's Save.
+func save[T any](items []T, item T, sameID func(T) bool) []T {
+	for i := range items {
+		if sameID(items[i]) {
+			items[i] = item
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+// saveVersioned is save for records with a version, failing with repository.ErrConflict when the
+// stored record is no longer at the version item was read at and bumping item's version otherwise.
+// A record pinned in expected under key must be at the pinned version instead, like
+// postgresql.SaveVersioned.
+func saveVersioned[T any](items []T, item *T, sameID func(T) bool, version func(*T) *int, expected *postgresql.ExpectedVersions, key string) ([]T, error) {
+	for i := range items {
+		if sameID(items[i]) {
+			want := expected.Version(key, *version(item))
+			if *version(&items[i]) != want {
+				if want != *version(item) {
+					return items, repository.ErrPreconditionFailed
+				}
+				return items, repository.ErrConflict
+			}
+			*version(item)++
+			items[i] = *item
+			expected.Saved(key, *version(item))
+			return items, nil
+		}
+	}
+	if *version(item) == 0 {
+		*version(item) = 1
+	}
+	return append(items, *item), nil
+}
+
+// take splits items into those that do not match and those that do.
+func take[T any](items []T, match func(T) bool) ([]T, []T) {
+	kept, taken := []T{}, []T{}
+	for _, item := range items {
+		if match(item) {
+			taken = append(taken, item)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+	return kept, taken
+}
+
+// putBack merges restored into items, keeping them in id order as they were before being taken.
+func putBack[T any](items, restored []T, id func(T) int64) []T {
+	items = append(items, restored...)
+	sort.SliceStable(items, func(i, j int) bool { return id(items[i]) < id(items[j]) })
+	return items
+}
+
+// newestFirstPage mirrors postgresql.SelectAllFromDbOrderByPaginated ordered by "id desc".
+func newestFirstPage[T any](items []T, paginator postgresql.Pagination) ([]T, postgresql.PaginationResponse) {
+	reversed := make([]T, len(items))
+	for i, item := range items {
+		reversed[len(items)-1-i] = item
+	}
+	return oldestFirstPage(reversed, paginator)
+}
+
+// oldestFirstPage mirrors postgresql.SelectAllFromDbOrderByPaginated ordered by "id asc".
+func oldestFirstPage[T any](items []T, paginator postgresql.Pagination) ([]T, postgresql.PaginationResponse) {
+	if paginator.Page <= 0 {
+		paginator.Page = 1
+	}
+	if paginator.Limit <= 0 {
+		paginator.Limit = defaultLimit
+	}
+
+	start := (paginator.Page - 1) * paginator.Limit
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + paginator.Limit
+	if end > len(items) {
+		end = len(items)
+	}
+	page := items[start:end]
+
+	return page, postgresql.PaginationResponse{
+		CurrentPage:     paginator.Page,
+		PageCount:       len(page),
+		TotalPagesCount: int(math.Ceil(float64(len(items)) / float64(paginator.Limit))),
+	}
+}
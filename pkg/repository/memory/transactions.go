@@ -0,0 +1,393 @@
+package memory
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"gorm.io/gorm"
+)
+
+type transactionRepository struct {
+	s   *Store
+	ctx context.Context
+}
+
+func (r transactionRepository) WithContext(ctx context.Context) repository.TransactionRepository {
+	return transactionRepository{s: r.s, ctx: ctx}
+}
+
+func (r transactionRepository) Create(transaction *models.Transaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	transaction.ID = r.s.nextID("transactions")
+	transaction.CreatedAt, transaction.UpdatedAt = time.Now(), time.Now()
+	transaction.Version = 1
+	r.s.transactions = append(r.s.transactions, *transaction)
+	return nil
+}
+
+func (r transactionRepository) Update(transaction *models.Transaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if transaction.ID == 0 {
+		transaction.ID = r.s.nextID("transactions")
+		transaction.CreatedAt = time.Now()
+	}
+	transaction.UpdatedAt = time.Now()
+	var err error
+	r.s.transactions, err = saveVersioned(r.s.transactions, transaction, func(t models.Transaction) bool { return t.ID == transaction.ID }, func(t *models.Transaction) *int { return &t.Version }, postgresql.ExpectedVersionsFrom(r.ctx), postgresql.RecordKey(models.AuditEntityTransaction, transaction.ID))
+	return err
+}
+
+func (r transactionRepository) Delete(transaction *models.Transaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var (
+		transactionID = transaction.TransactionID
+		deleted       = deletedTransaction{transactionID: transactionID, deletedAt: time.Now()}
+	)
+	r.s.transactions, deleted.transactions = take(r.s.transactions, func(t models.Transaction) bool { return t.TransactionID == transactionID })
+	r.s.parties, deleted.parties = take(r.s.parties, func(p models.TransactionParty) bool { return p.TransactionID == transactionID })
+	r.s.disputes, deleted.disputes = take(r.s.disputes, func(d models.TransactionDispute) bool { return d.TransactionID == transactionID })
+	r.s.brokers, deleted.brokers = take(r.s.brokers, func(b models.TransactionBroker) bool { return b.TransactionID == transactionID })
+	r.s.files, deleted.files = take(r.s.files, func(f models.TransactionFile) bool { return f.TransactionID == transactionID })
+	r.s.products, deleted.products = take(r.s.products, func(p models.ProductTransaction) bool { return p.TransactionID == transactionID })
+	r.s.rejections, deleted.rejections = take(r.s.rejections, func(rj models.TransactionsRejected) bool { return rj.TransactionID == transactionID })
+	r.s.dueDateExtensions, deleted.dueDateExtensions = take(r.s.dueDateExtensions, func(d models.TransactionDueDateExtensionRequest) bool { return d.TransactionID == transactionID })
+	if len(deleted.transactions) == 0 {
+		return nil
+	}
+	for i := range deleted.transactions {
+		deleted.transactions[i].DeletedAt = gorm.DeletedAt{Time: deleted.deletedAt, Valid: true}
+	}
+	r.s.deleted = append(r.s.deleted, deleted)
+	return nil
+}
+
+func (r transactionRepository) GetDeletedByTransactionID(transactionID string) (models.Transaction, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := len(r.s.deleted) - 1; i >= 0; i-- {
+		if r.s.deleted[i].transactionID == transactionID {
+			return r.s.deleted[i].transactions[0], http.StatusOK, nil
+		}
+	}
+	return models.Transaction{}, http.StatusBadRequest, repository.ErrNotFound
+}
+
+func (r transactionRepository) Restore(transaction *models.Transaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i, deleted := range r.s.deleted {
+		if deleted.transactionID != transaction.TransactionID || !deleted.deletedAt.Equal(transaction.DeletedAt.Time) {
+			continue
+		}
+		for j := range deleted.transactions {
+			deleted.transactions[j].DeletedAt = gorm.DeletedAt{}
+		}
+		r.s.transactions = putBack(r.s.transactions, deleted.transactions, func(t models.Transaction) int64 { return int64(t.ID) })
+		r.s.parties = putBack(r.s.parties, deleted.parties, func(p models.TransactionParty) int64 { return int64(p.ID) })
+		r.s.disputes = putBack(r.s.disputes, deleted.disputes, func(d models.TransactionDispute) int64 { return d.ID })
+		r.s.brokers = putBack(r.s.brokers, deleted.brokers, func(b models.TransactionBroker) int64 { return int64(b.ID) })
+		r.s.files = putBack(r.s.files, deleted.files, func(f models.TransactionFile) int64 { return int64(f.ID) })
+		r.s.products = putBack(r.s.products, deleted.products, func(p models.ProductTransaction) int64 { return p.ID })
+		r.s.rejections = putBack(r.s.rejections, deleted.rejections, func(rj models.TransactionsRejected) int64 { return int64(rj.ID) })
+		r.s.dueDateExtensions = putBack(r.s.dueDateExtensions, deleted.dueDateExtensions, func(d models.TransactionDueDateExtensionRequest) int64 { return int64(d.ID) })
+		r.s.deleted = append(r.s.deleted[:i], r.s.deleted[i+1:]...)
+		return nil
+	}
+	return nil
+}
+
+func (r transactionRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var purged int64
+	kept := []deletedTransaction{}
+	for _, deleted := range r.s.deleted {
+		if deleted.deletedAt.Before(before) {
+			purged += int64(len(deleted.transactions))
+		} else {
+			kept = append(kept, deleted)
+		}
+	}
+	r.s.deleted = kept
+	return purged, nil
+}
+
+func (r transactionRepository) GetByTransactionID(transactionID string) (models.Transaction, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.transactions, func(t models.Transaction) bool { return t.TransactionID == transactionID })
+}
+
+func (r transactionRepository) GetByTransactionIDAndMilestoneID(transactionID, milestoneID string) (models.Transaction, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.transactions, func(t models.Transaction) bool {
+		return t.TransactionID == transactionID && t.MilestoneID == milestoneID
+	})
+}
+
+func (r transactionRepository) GetByUssdCode(ussdCode int) (models.Transaction, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.transactions, func(t models.Transaction) bool { return t.TransUssdCode == ussdCode })
+}
+
+func (r transactionRepository) ListByTransactionID(transactionID string) ([]models.Transaction, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.transactions, func(t models.Transaction) bool { return t.TransactionID == transactionID }), nil
+}
+
+func (r transactionRepository) ListByTransactionIDs(transactionIDs []string) ([]models.Transaction, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.transactions, inStrings(transactionIDs, func(t models.Transaction) string { return t.TransactionID })), nil
+}
+
+func (r transactionRepository) ListByPartiesID(partiesID string) ([]models.Transaction, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.transactions, func(t models.Transaction) bool { return t.PartiesID == partiesID }), nil
+}
+
+func (r transactionRepository) ListByFilter(filter models.TransactionFilter) ([]models.Transaction, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	found := findAll(r.s.transactions, filter.Matches)
+	if filter.Limit > 0 && len(found) > filter.Limit {
+		found = found[:filter.Limit]
+	}
+	return found, nil
+}
+
+func (r transactionRepository) ListByQuery(query models.TransactionQuery, paginator postgresql.Pagination) ([]models.Transaction, postgresql.PaginationResponse, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	page, pagination := oldestFirstPage(findAll(r.s.transactions, query.Matches), paginator)
+	return page, pagination, nil
+}
+
+func (r transactionRepository) CountByStatus() (map[string]int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	seen := map[string]map[string]bool{}
+	for _, t := range r.s.transactions {
+		if seen[t.Status] == nil {
+			seen[t.Status] = map[string]bool{}
+		}
+		seen[t.Status][t.TransactionID] = true
+	}
+	counts := map[string]int64{}
+	for status, transactionIDs := range seen {
+		counts[status] = int64(len(transactionIDs))
+	}
+	return counts, nil
+}
+
+type partyRepository struct {
+	s   *Store
+	ctx context.Context
+}
+
+func (r partyRepository) WithContext(ctx context.Context) repository.PartyRepository {
+	return partyRepository{s: r.s, ctx: ctx}
+}
+
+func (r partyRepository) Create(party *models.TransactionParty) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	party.ID = r.s.nextID("transaction_parties")
+	party.CreatedAt, party.UpdatedAt = time.Now(), time.Now()
+	party.Version = 1
+	if party.Status == "" {
+		party.Status = "created"
+	}
+	r.s.parties = append(r.s.parties, *party)
+	return nil
+}
+
+func (r partyRepository) Update(party *models.TransactionParty) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if party.ID == 0 {
+		party.ID = r.s.nextID("transaction_parties")
+		party.CreatedAt = time.Now()
+	}
+	party.UpdatedAt = time.Now()
+	var err error
+	r.s.parties, err = saveVersioned(r.s.parties, party, func(p models.TransactionParty) bool { return p.ID == party.ID }, func(p *models.TransactionParty) *int { return &p.Version }, postgresql.ExpectedVersionsFrom(r.ctx), postgresql.RecordKey(models.AuditEntityTransactionParty, party.ID))
+	return err
+}
+
+func (r partyRepository) GetByTransactionIDAndRole(transactionID, role string) (models.TransactionParty, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.parties, func(p models.TransactionParty) bool {
+		return p.TransactionID == transactionID && p.Role == role
+	})
+}
+
+func (r partyRepository) GetByTransactionPartiesIDAndRole(transactionPartiesID, role string) (models.TransactionParty, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.parties, func(p models.TransactionParty) bool {
+		return p.TransactionPartiesID == transactionPartiesID && p.Role == role
+	})
+}
+
+func (r partyRepository) GetByTransactionIDAndAccountID(transactionID string, accountID int) (models.TransactionParty, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.parties, func(p models.TransactionParty) bool {
+		return p.TransactionID == transactionID && p.AccountID == accountID
+	})
+}
+
+func (r partyRepository) ListByTransactionID(transactionID string) ([]models.TransactionParty, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.parties, func(p models.TransactionParty) bool { return p.TransactionID == transactionID }), nil
+}
+
+func (r partyRepository) ListByTransactionPartiesID(transactionPartiesID string) ([]models.TransactionParty, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.parties, func(p models.TransactionParty) bool { return p.TransactionPartiesID == transactionPartiesID }), nil
+}
+
+func (r partyRepository) ListByTransactionIDsOrPartiesIDs(transactionIDs, transactionPartiesIDs []string) ([]models.TransactionParty, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var (
+		byTransactionID = inStrings(transactionIDs, func(p models.TransactionParty) string { return p.TransactionID })
+		byPartiesID     = inStrings(transactionPartiesIDs, func(p models.TransactionParty) string { return p.TransactionPartiesID })
+	)
+	return findAll(r.s.parties, func(p models.TransactionParty) bool { return byTransactionID(p) || byPartiesID(p) }), nil
+}
+
+func (r partyRepository) ListByAccountID(accountID int, role, transactionStatus string, paginator postgresql.Pagination) ([]models.TransactionParty, postgresql.PaginationResponse, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	inStatus := map[string]bool{}
+	for _, t := range r.s.transactions {
+		if strings.EqualFold(t.Status, transactionStatus) {
+			inStatus[t.TransactionID] = true
+		}
+	}
+	parties := findAll(r.s.parties, func(p models.TransactionParty) bool {
+		switch {
+		case p.AccountID != accountID || p.ArchivedAt != nil:
+			return false
+		case role != "" && p.Role != role:
+			return false
+		case transactionStatus != "" && !inStatus[p.TransactionID]:
+			return false
+		}
+		return true
+	})
+	page, pagination := newestFirstPage(parties, paginator)
+	return page, pagination, nil
+}
+
+func (r partyRepository) ListArchivedByAccountID(accountID int, paginator postgresql.Pagination) ([]models.TransactionParty, postgresql.PaginationResponse, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	parties := findAll(r.s.parties, func(p models.TransactionParty) bool { return p.AccountID == accountID && p.ArchivedAt != nil })
+	page, pagination := newestFirstPage(parties, paginator)
+	return page, pagination, nil
+}
+
+func (r partyRepository) ListDeletedByTransactionID(transactionID string, deletedAt time.Time) ([]models.TransactionParty, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, deleted := range r.s.deleted {
+		if deleted.transactionID == transactionID && deleted.deletedAt.Equal(deletedAt) {
+			return append([]models.TransactionParty{}, deleted.parties...), nil
+		}
+	}
+	return []models.TransactionParty{}, nil
+}
+
+func (r partyRepository) ListDisputedByAccountID(accountID int, paginator postgresql.Pagination) ([]models.TransactionParty, postgresql.PaginationResponse, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	disputed := map[string]bool{}
+	for _, d := range r.s.disputes {
+		disputed[d.TransactionID] = true
+	}
+	parties := findAll(r.s.parties, func(p models.TransactionParty) bool {
+		return p.AccountID == accountID && disputed[p.TransactionID]
+	})
+	page, pagination := newestFirstPage(parties, paginator)
+	return page, pagination, nil
+}
+
+type stateRepository struct {
+	s *Store
+}
+
+func (r stateRepository) Create(state *models.TransactionState) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	state.ID = r.s.nextID("transaction_states")
+	state.CreatedAt, state.UpdatedAt = time.Now(), time.Now()
+	r.s.states = append(r.s.states, *state)
+	return nil
+}
+
+func (r stateRepository) GetByTransactionIDAndStatus(transactionID, status string) (models.TransactionState, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findOne(r.s.states, func(st models.TransactionState) bool {
+		return st.TransactionID == transactionID && strings.EqualFold(st.Status, status)
+	})
+}
+
+func (r stateRepository) ListByTransactionID(transactionID string) ([]models.TransactionState, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return findAll(r.s.states, func(st models.TransactionState) bool { return st.TransactionID == transactionID }), nil
+}
+
+func (r stateRepository) ListByTransactionIDsAndStatus(transactionIDs []string, status string) ([]models.TransactionState, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	inTransactions := inStrings(transactionIDs, func(st models.TransactionState) string { return st.TransactionID })
+	return findAll(r.s.states, func(st models.TransactionState) bool {
+		return inTransactions(st) && strings.EqualFold(st.Status, status)
+	}), nil
+}
+
+type rejectionRepository struct {
+	s *Store
+}
+
+func (r rejectionRepository) Create(rejection *models.TransactionsRejected) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rejection.ID = r.s.nextID("transactions_rejected")
+	rejection.CreatedAt, rejection.UpdatedAt = time.Now(), time.Now()
+	r.s.rejections = append(r.s.rejections, *rejection)
+	return nil
+}
+
+type dueDateExtensionRepository struct {
+	s *Store
+}
+
+func (r dueDateExtensionRepository) Create(request *models.TransactionDueDateExtensionRequest) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	request.ID = r.s.nextID("transaction_due_date_extension_requests")
+	request.CreatedAt, request.UpdatedAt = time.Now(), time.Now()
+	r.s.dueDateExtensions = append(r.s.dueDateExtensions, *request)
+	return nil
+}
@@ -0,0 +1,229 @@
+// Package repository describes the storage the transaction services and cron jobs work against.
+// pkg/repository/gormrepo backs it with postgres and pkg/repository/memory keeps everything in
+// process, so code written against these interfaces can be exercised without a database.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned, together with http.StatusBadRequest, when a single record lookup finds
+// nothing. Both backends return the same value so callers can keep using errors.Is.
+var ErrNotFound = gorm.ErrRecordNotFound
+
+// ErrConflict is returned by the Update of versioned records, transactions, parties, brokers and
+// disputes, when the record changed after it was read. The caller should reload it and decide again.
+var ErrConflict = postgresql.ErrConflict
+
+// ErrPreconditionFailed is the ErrConflict of an Update whose record changed after the request's
+// If-Match was checked, see postgresql.ExpectedVersions.
+var ErrPreconditionFailed = postgresql.ErrPreconditionFailed
+
+// Repositories groups the repositories for the transactions database.
+type Repositories struct {
+	Transactions      TransactionRepository
+	Parties           PartyRepository
+	Disputes          DisputeRepository
+	Brokers           BrokerRepository
+	Files             FileRepository
+	Products          ProductRepository
+	Rates             RateRepository
+	ActivityLogs      ActivityLogRepository
+	States            StateRepository
+	Rejections        RejectionRepository
+	DueDateExtensions DueDateExtensionRepository
+	FeeSchedules      FeeScheduleRepository
+	AuditLogs         AuditLogRepository
+	RateLimits        RateLimitRepository
+	Nonces            NonceRepository
+}
+
+// WithContext returns repositories whose queries run under ctx, so they join the trace it carries.
+// Repositories that do not take a context, like the in-memory ones, are kept as they are.
+func (r Repositories) WithContext(ctx context.Context) Repositories {
+	r.Transactions = bindContext(r.Transactions, ctx)
+	r.Parties = bindContext(r.Parties, ctx)
+	r.Disputes = bindContext(r.Disputes, ctx)
+	r.Brokers = bindContext(r.Brokers, ctx)
+	r.Files = bindContext(r.Files, ctx)
+	r.Products = bindContext(r.Products, ctx)
+	r.Rates = bindContext(r.Rates, ctx)
+	r.ActivityLogs = bindContext(r.ActivityLogs, ctx)
+	r.States = bindContext(r.States, ctx)
+	r.Rejections = bindContext(r.Rejections, ctx)
+	r.DueDateExtensions = bindContext(r.DueDateExtensions, ctx)
+	r.FeeSchedules = bindContext(r.FeeSchedules, ctx)
+	r.AuditLogs = bindContext(r.AuditLogs, ctx)
+	r.RateLimits = bindContext(r.RateLimits, ctx)
+	r.Nonces = bindContext(r.Nonces, ctx)
+	return r
+}
+
+func bindContext[T any](repo T, ctx context.Context) T {
+	if r, ok := any(repo).(interface{ WithContext(context.Context) T }); ok {
+		return r.WithContext(ctx)
+	}
+	return repo
+}
+
+// Single record lookups return the status code a handler should answer with alongside the error:
+// http.StatusBadRequest when nothing matched and http.StatusInternalServerError otherwise.
+
+type TransactionRepository interface {
+	Create(transaction *models.Transaction) error
+	Update(transaction *models.Transaction) error
+	// Delete soft deletes every milestone of the transaction together with its parties, broker,
+	// dispute and other records. Deleted rows are left out of every lookup below.
+	Delete(transaction *models.Transaction) error
+	// GetDeletedByTransactionID finds a deleted transaction, for Restore.
+	GetDeletedByTransactionID(transactionID string) (models.Transaction, int, error)
+	// Restore brings back what the Delete of transaction, as returned by GetDeletedByTransactionID,
+	// removed.
+	Restore(transaction *models.Transaction) error
+	// PurgeDeleted permanently removes the transactions deleted before before, with their records,
+	// and reports how many milestones there were.
+	PurgeDeleted(before time.Time) (int64, error)
+	GetByTransactionID(transactionID string) (models.Transaction, int, error)
+	GetByTransactionIDAndMilestoneID(transactionID, milestoneID string) (models.Transaction, int, error)
+	GetByUssdCode(ussdCode int) (models.Transaction, int, error)
+	ListByTransactionID(transactionID string) ([]models.Transaction, error)
+	ListByTransactionIDs(transactionIDs []string) ([]models.Transaction, error)
+	ListByPartiesID(partiesID string) ([]models.Transaction, error)
+	ListByFilter(filter models.TransactionFilter) ([]models.Transaction, error)
+	// ListByQuery pages through the milestones matching query, oldest first.
+	ListByQuery(query models.TransactionQuery, paginator postgresql.Pagination) ([]models.Transaction, postgresql.PaginationResponse, error)
+	// CountByStatus returns how many transactions have a milestone in each status.
+	CountByStatus() (map[string]int64, error)
+}
+
+type PartyRepository interface {
+	Create(party *models.TransactionParty) error
+	Update(party *models.TransactionParty) error
+	GetByTransactionIDAndRole(transactionID, role string) (models.TransactionParty, int, error)
+	GetByTransactionPartiesIDAndRole(transactionPartiesID, role string) (models.TransactionParty, int, error)
+	GetByTransactionIDAndAccountID(transactionID string, accountID int) (models.TransactionParty, int, error)
+	ListByTransactionID(transactionID string) ([]models.TransactionParty, error)
+	ListByTransactionPartiesID(transactionPartiesID string) ([]models.TransactionParty, error)
+	ListByTransactionIDsOrPartiesIDs(transactionIDs, transactionPartiesIDs []string) ([]models.TransactionParty, error)
+	// ListByAccountID pages through the parties an account holds, newest first, leaving out the
+	// transactions it archived. role and transactionStatus narrow the list down when they are set.
+	ListByAccountID(accountID int, role, transactionStatus string, paginator postgresql.Pagination) ([]models.TransactionParty, postgresql.PaginationResponse, error)
+	// ListArchivedByAccountID pages through the parties an account holds on the transactions it
+	// archived, newest first.
+	ListArchivedByAccountID(accountID int, paginator postgresql.Pagination) ([]models.TransactionParty, postgresql.PaginationResponse, error)
+	// ListDeletedByTransactionID returns the parties the Delete of a transaction removed at
+	// deletedAt, for authorizing its Restore.
+	ListDeletedByTransactionID(transactionID string, deletedAt time.Time) ([]models.TransactionParty, error)
+	// ListDisputedByAccountID pages through the parties an account holds on disputed transactions,
+	// newest first.
+	ListDisputedByAccountID(accountID int, paginator postgresql.Pagination) ([]models.TransactionParty, postgresql.PaginationResponse, error)
+}
+
+type DisputeRepository interface {
+	Create(dispute *models.TransactionDispute) error
+	Update(dispute *models.TransactionDispute) error
+	GetByTransactionID(transactionID string) (models.TransactionDispute, int, error)
+	ListByTransactionIDs(transactionIDs []string) ([]models.TransactionDispute, error)
+	// CountUndecided returns how many disputes are yet to be given a decision.
+	CountUndecided() (int64, error)
+}
+
+type BrokerRepository interface {
+	Create(broker *models.TransactionBroker) error
+	Update(broker *models.TransactionBroker) error
+	GetByTransactionID(transactionID string) (models.TransactionBroker, int, error)
+	ListByTransactionIDs(transactionIDs []string) ([]models.TransactionBroker, error)
+}
+
+type FileRepository interface {
+	Create(file *models.TransactionFile) error
+	ListByTransactionID(transactionID string) ([]models.TransactionFile, error)
+	ListByTransactionIDs(transactionIDs []string) ([]models.TransactionFile, error)
+}
+
+// ProductRepository reads the products of product transactions. They are written by the older api
+// and never by this service.
+type ProductRepository interface {
+	ListByTransactionID(transactionID string) ([]models.ProductTransaction, error)
+	ListByTransactionIDs(transactionIDs []string) ([]models.ProductTransaction, error)
+}
+
+type RateRepository interface {
+	Create(rate *models.Rate) error
+	// List returns every rate, newest first.
+	List() ([]models.Rate, error)
+	GetByID(id int64) (models.Rate, int, error)
+	GetByCurrencies(fromCurrency, toCurrency string) (models.Rate, int, error)
+}
+
+type ActivityLogRepository interface {
+	// Create fills in the event type and description when they are missing, see
+	// models.ActivityLog.ApplyDefaults.
+	Create(activityLog *models.ActivityLog) error
+	ListByTransactionID(transactionID string) ([]models.ActivityLog, error)
+	ListByTransactionIDs(transactionIDs []string) ([]models.ActivityLog, error)
+	// ListByFilters pages through the entries matching req, newest first.
+	ListByFilters(req models.ListActivityLogsRequest, paginator postgresql.Pagination) ([]models.ActivityLog, postgresql.PaginationResponse, error)
+}
+
+type StateRepository interface {
+	Create(state *models.TransactionState) error
+	// GetByTransactionIDAndStatus finds the first state of a transaction with status, compared
+	// case-insensitively.
+	GetByTransactionIDAndStatus(transactionID, status string) (models.TransactionState, int, error)
+	ListByTransactionID(transactionID string) ([]models.TransactionState, error)
+	ListByTransactionIDsAndStatus(transactionIDs []string, status string) ([]models.TransactionState, error)
+}
+
+type RejectionRepository interface {
+	Create(rejection *models.TransactionsRejected) error
+}
+
+type DueDateExtensionRepository interface {
+	Create(request *models.TransactionDueDateExtensionRequest) error
+}
+
+// FeeScheduleRepository stores every version of a fee schedule as its own row, see
+// models.FeeSchedule.
+type FeeScheduleRepository interface {
+	Create(schedule *models.FeeSchedule) error
+	GetLatestVersion(feeScheduleID string) (models.FeeSchedule, int, error)
+	// ListVersions returns every version of a schedule, oldest first.
+	ListVersions(feeScheduleID string) ([]models.FeeSchedule, error)
+	// ListLatest pages through the latest version of every schedule matching req, newest first.
+	ListLatest(req models.ListFeeSchedulesRequest, paginator postgresql.Pagination) ([]models.FeeSchedule, postgresql.PaginationResponse, error)
+	// GetEffective finds the schedule in force for a business, currency and transaction type at
+	// at, see models.FeeSchedule.GetEffective, and reports false when there is none.
+	GetEffective(businessID int, currency, transactionType string, at time.Time) (models.FeeSchedule, bool, error)
+}
+
+type AuditLogRepository interface {
+	// ListByTransactionID pages through the entries for a transaction, oldest first.
+	ListByTransactionID(transactionID string, paginator postgresql.Pagination) ([]models.AuditLog, postgresql.PaginationResponse, error)
+	// VerifyChain checks every entry against its hash and the hash of the entry before it.
+	VerifyChain() (models.AuditChainVerification, error)
+}
+
+type RateLimitRepository interface {
+	// Increment counts one request against key in the window starting at windowStart and returns
+	// the count so far. The counter can be dropped once expiresAt has passed.
+	Increment(key string, windowStart, expiresAt time.Time) (int, error)
+	// Count returns the count so far for key in the window starting at windowStart, without
+	// counting a request.
+	Count(key string, windowStart time.Time) (int, error)
+	// DeleteExpired drops the counters that expired before before and reports how many there were.
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+type NonceRepository interface {
+	// Claim records a nonce until expiresAt and reports false when it was already recorded, that is
+	// when the request carrying it is a replay.
+	Claim(nonce string, expiresAt time.Time) (bool, error)
+	// DeleteExpired drops the nonces that expired before before and reports how many there were.
+	DeleteExpired(before time.Time) (int64, error)
+}
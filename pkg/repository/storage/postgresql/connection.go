@@ -1,12 +1,14 @@
 package postgresql
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"log"
 
 	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/tracing"
 	"github.com/vesicash/transactions-ms/utility"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -27,6 +29,17 @@ type Databases struct {
 
 var DB Databases
 
+// WithContext returns the connections with ctx set, so the queries made through them join the trace
+// it carries.
+func (d Databases) WithContext(ctx context.Context) Databases {
+	for _, db := range []**gorm.DB{&d.Admin, &d.Auth, &d.Notifications, &d.Payment, &d.Reminder, &d.Subscription, &d.Transaction, &d.Verification, &d.Cron} {
+		if *db != nil {
+			*db = (*db).WithContext(ctx)
+		}
+	}
+	return d
+}
+
 // Connection gets connection of mysqlDB database
 func Connection() Databases {
 	return DB
@@ -75,10 +88,33 @@ func connectToDb(host, user, password, dbname, port, sslmode, timezone string, l
 
 	}
 
+	err = db.Use(tracing.GormPlugin{})
+	if err != nil {
+		utility.LogAndPrint(logger, fmt.Sprintf("tracing %v db queries failed with: %v", dbname, err))
+	}
+
 	utility.LogAndPrint(logger, fmt.Sprintf("connected to %v db", dbname))
 	return db
 }
 
+// Close closes the connection pool of every connected database.
+func (d Databases) Close() error {
+	var closeErr error
+	for _, db := range []*gorm.DB{d.Admin, d.Auth, d.Notifications, d.Payment, d.Reminder, d.Subscription, d.Transaction, d.Verification, d.Cron} {
+		if db == nil {
+			continue
+		}
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil {
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
 func ReturnDatabase(name string) *gorm.DB {
 	databases := DB
 	switch name {
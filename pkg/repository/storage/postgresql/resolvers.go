@@ -0,0 +1,151 @@
+package postgresql
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vesicash/transactions-ms/external"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/request"
+	"gorm.io/gorm"
+)
+
+// ValidationTarget is what an exists or notexists tag names, source$table$column.
+type ValidationTarget struct {
+	Source string
+	Table  string
+	Column string
+}
+
+var identifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+func ParseValidationTarget(target string) (ValidationTarget, error) {
+	parts := strings.Split(target, "$")
+	if len(parts) != 3 {
+		return ValidationTarget{}, fmt.Errorf("invalid validation target %v, expected source$table$column", target)
+	}
+	for _, part := range parts {
+		if !identifier.MatchString(part) {
+			return ValidationTarget{}, fmt.Errorf("invalid validation target %v", target)
+		}
+	}
+	return ValidationTarget{Source: parts[0], Table: parts[1], Column: parts[2]}, nil
+}
+
+// ExistenceResolver answers whether a row with the value in the target's column exists. An error
+// means the answer is unknown, not that the row is missing.
+type ExistenceResolver interface {
+	Exists(target ValidationTarget, value interface{}) (bool, error)
+}
+
+// DBResolver looks targets up in a database this service is connected to. Soft deleted rows, in
+// tables with a deleted_at column, do not count.
+type DBResolver struct {
+	Db *gorm.DB
+}
+
+// softDeleteTables remembers which tables have a deleted_at column.
+var softDeleteTables sync.Map
+
+func (r DBResolver) Exists(target ValidationTarget, value interface{}) (bool, error) {
+	if r.Db == nil {
+		return false, fmt.Errorf("%v database is not connected", target.Source)
+	}
+	var result map[string]interface{}
+	query := r.Db.Table(target.Table).Where(fmt.Sprintf("%v = ?", target.Column), value)
+	if r.softDeletes(target.Table) {
+		query = query.Where("deleted_at IS NULL")
+	}
+	tx := query.Take(&result)
+	if tx.Error != nil && tx.Error != gorm.ErrRecordNotFound {
+		return false, tx.Error
+	}
+	return tx.RowsAffected != 0, nil
+}
+
+func (r DBResolver) softDeletes(table string) bool {
+	if known, ok := softDeleteTables.Load(table); ok {
+		return known.(bool)
+	}
+	has := r.Db.Migrator().HasColumn(table, "deleted_at")
+	softDeleteTables.Store(table, has)
+	return has
+}
+
+// AuthResolver looks targets up through the auth service, which owns users and business profiles.
+type AuthResolver struct {
+	Auth request.AuthClient
+}
+
+func (r AuthResolver) Exists(target ValidationTarget, value interface{}) (bool, error) {
+	if r.Auth == nil {
+		return false, fmt.Errorf("auth client not provided")
+	}
+
+	var (
+		id  uint
+		err error
+	)
+	switch target.Table {
+	case "users":
+		req := external_models.GetUserRequestModel{}
+		switch target.Column {
+		case "account_id":
+			req.AccountID, err = uintValue(value)
+		case "id":
+			req.ID, err = uintValue(value)
+		case "email_address":
+			req.EmailAddress = fmt.Sprint(value)
+		case "phone_number":
+			req.PhoneNumber = fmt.Sprint(value)
+		case "username":
+			req.Username = fmt.Sprint(value)
+		default:
+			return false, fmt.Errorf("users can not be looked up by %v", target.Column)
+		}
+		if err != nil {
+			return false, err
+		}
+		var user external_models.User
+		user, err = r.Auth.GetUser(req)
+		id = user.ID
+	case "business_profiles":
+		req := external_models.GetBusinessProfileModel{}
+		switch target.Column {
+		case "account_id":
+			req.AccountID, err = uintValue(value)
+		case "id":
+			req.ID, err = uintValue(value)
+		default:
+			return false, fmt.Errorf("business profiles can not be looked up by %v", target.Column)
+		}
+		if err != nil {
+			return false, err
+		}
+		var profile external_models.BusinessProfile
+		profile, err = r.Auth.GetBusinessProfile(req)
+		id = profile.ID
+	default:
+		return false, fmt.Errorf("%v can not be looked up on auth", target.Table)
+	}
+
+	if err != nil {
+		if code := external.GetResponseCode(err); code >= http.StatusBadRequest && code < http.StatusInternalServerError {
+			return false, nil
+		}
+		return false, err
+	}
+	return id != 0, nil
+}
+
+func uintValue(value interface{}) (uint, error) {
+	n, err := strconv.ParseUint(fmt.Sprint(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%v is not a valid id", value)
+	}
+	return uint(n), nil
+}
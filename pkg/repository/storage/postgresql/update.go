@@ -1,9 +1,21 @@
 package postgresql
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
 	"gorm.io/gorm"
 )
 
+// ErrConflict is returned by SaveVersioned when the row changed after it was read.
+var ErrConflict = errors.New("record was changed by another request, reload it and try again")
+
+// ErrPreconditionFailed is the ErrConflict of a save whose row is no longer at the version pinned
+// by ExpectedVersions, that is a row changed after the request's If-Match was checked.
+var ErrPreconditionFailed = fmt.Errorf("if-match no longer holds: %w", ErrConflict)
+
 func SaveAllFields(db *gorm.DB, model interface{}) (*gorm.DB, error) {
 	result := db.Save(model)
 	if result.Error != nil {
@@ -11,3 +23,94 @@ func SaveAllFields(db *gorm.DB, model interface{}) (*gorm.DB, error) {
 	}
 	return result, nil
 }
+
+// SaveVersioned saves every field of model, which already carries its next version, as long as its
+// row is still at version. A row that has moved on, or gone, gives ErrConflict. key names the row
+// for the ExpectedVersions of db's context, see RecordKey: when the row is pinned there, it must
+// be at the pinned version instead, and gives ErrPreconditionFailed when it is not.
+func SaveVersioned(db *gorm.DB, model interface{}, key string, version int) error {
+	expected := ExpectedVersionsFrom(db.Statement.Context)
+	want := expected.Version(key, version)
+	result := db.Model(model).Where("version = ?", want).Select("*").Updates(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if want != version {
+			return ErrPreconditionFailed
+		}
+		return ErrConflict
+	}
+	expected.Saved(key, version+1)
+	return nil
+}
+
+// RecordKey names a versioned row, such as "party 12", for SaveVersioned and ExpectedVersions.
+func RecordKey(kind string, id interface{}) string {
+	return fmt.Sprintf("%v %v", kind, id)
+}
+
+// ExpectedVersions pins rows to the versions a request checked its If-Match against, so that the
+// request's saves fail instead of overwriting a change made between the check and the save. It is
+// safe for concurrent use and its methods do nothing on a nil ExpectedVersions.
+type ExpectedVersions struct {
+	mu       sync.Mutex
+	versions map[string]int
+}
+
+func NewExpectedVersions() *ExpectedVersions {
+	return &ExpectedVersions{versions: map[string]int{}}
+}
+
+// Expect pins the row named key to version.
+func (e *ExpectedVersions) Expect(key string, version int) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.versions[key] = version
+}
+
+// Version returns the version the row named key must be at to be saved over: the pinned one, or
+// read, the version it was read at, when it is not pinned.
+func (e *ExpectedVersions) Version(key string, read int) int {
+	if e == nil {
+		return read
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if version, ok := e.versions[key]; ok {
+		return version
+	}
+	return read
+}
+
+// Saved moves a pinned row on to version once the request itself saved it, so its next save
+// expects the version it left the row at.
+func (e *ExpectedVersions) Saved(key string, version int) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.versions[key]; ok {
+		e.versions[key] = version
+	}
+}
+
+type expectedVersionsKey struct{}
+
+// WithExpectedVersions returns ctx carrying expected, for the saves made under it.
+func WithExpectedVersions(ctx context.Context, expected *ExpectedVersions) context.Context {
+	return context.WithValue(ctx, expectedVersionsKey{}, expected)
+}
+
+// ExpectedVersionsFrom returns the ExpectedVersions ctx carries, or nil when it has none.
+func ExpectedVersionsFrom(ctx context.Context) *ExpectedVersions {
+	if ctx == nil {
+		return nil
+	}
+	expected, _ := ctx.Value(expectedVersionsKey{}).(*ExpectedVersions)
+	return expected
+}
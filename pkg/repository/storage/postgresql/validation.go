@@ -7,7 +7,6 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/utility"
 	"gorm.io/gorm"
@@ -18,8 +17,20 @@ type Validation interface {
 }
 
 type ValidationError struct {
-	Field string
-	Error string
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// ValidationErrors holds every field that failed validation. It marshals as the list of fields, so
+// handlers passing it as the error of a response show each failure.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	errString := ""
+	for _, v := range e {
+		errString += v.Field + ": " + v.Error + " ;"
+	}
+	return errString
 }
 
 var (
@@ -31,145 +42,160 @@ var (
 	ValidationNeeded = "Input validation failed on some fields"
 )
 
+// ValidateRequestM checks the pgvalidate tags of a request struct. exists and notexists tags name
+// their target as source$table$column, and each source is answered by a resolver: the transaction
+// database for transaction and the auth service for auth, unless Resolvers says otherwise.
 type ValidateRequestM struct {
 	Logger *utility.Logger
-	Test   bool
+	// Db is the transaction database, bound to the request context.
+	Db   *gorm.DB
+	Auth request.AuthClient
+	// Resolvers replaces or adds resolvers by source.
+	Resolvers map[string]ExistenceResolver
 }
 
+// ValidateRequest returns ValidationErrors listing every failing field, looking into nested structs
+// and slices of structs. A target is looked up once per call however many fields name it.
 func (vr ValidateRequestM) ValidateRequest(V interface{}) error {
+	var (
+		errs    ValidationErrors
+		checked = map[existenceKey]existenceResult{}
+	)
 
-	var err []ValidationError
-	if reflect.ValueOf(V).Kind() == reflect.Struct {
-		t := reflect.TypeOf(V)
-		v := reflect.ValueOf(V)
+	vr.validateStruct(reflect.ValueOf(V), "", checked, &errs)
 
-		for i := 0; i < t.NumField(); i++ {
-			FieldT := t.Field(i)
-			FieldV := v.Field(i)
-			// reflect.ValueOf(V).Field(i).Type()
+	if len(errs) < 1 {
+		return nil
+	}
+	return errs
+}
 
-			validateFields := FieldT.Tag.Get("pgvalidate")
-			splitFields := strings.Split(validateFields, ",")
-			if validateFields == "_" || validateFields == "" {
-				continue
-			}
+type existenceKey struct {
+	target ValidationTarget
+	value  string
+}
 
-			for j := 0; j < len(splitFields); j++ {
-				splitFieldsStr := strings.ToLower(splitFields[j])
-				if strings.Contains(splitFieldsStr, "notexists") {
-					value, status := ValidateNext(FieldV)
-					if status {
-						firstSplit := strings.Split(splitFieldsStr, "=")
-						if len(firstSplit) == 2 {
-							secondSplit := strings.Split(firstSplit[1], "$")
-							if len(secondSplit) == 3 {
-								dbName := secondSplit[0]
-								tableName := secondSplit[1]
-								columnName := secondSplit[2]
-								if !vr.ValidationCheck(dbName, tableName, "notexists", fmt.Sprintf("%v = ?", columnName), value) {
-									err = append(err, ValidationError{
-										Field: FieldT.Name,
-										Error: fmt.Sprintf("%v exists in %v table", columnName, tableName),
-									})
-								}
-
-							}
-
-						}
-					}
-				} else if strings.Contains(splitFieldsStr, "exists") {
-					value, status := ValidateNext(FieldV)
-					if status {
-						firstSplit := strings.Split(splitFieldsStr, "=")
-						if len(firstSplit) == 2 {
-							secondSplit := strings.Split(firstSplit[1], "$")
-							if len(secondSplit) == 3 {
-								dbName := secondSplit[0]
-								tableName := secondSplit[1]
-								columnName := secondSplit[2]
-								if !vr.ValidationCheck(dbName, tableName, "exists", fmt.Sprintf("%v = ?", columnName), value) {
-									err = append(err, ValidationError{
-										Field: FieldT.Name,
-										Error: fmt.Sprintf("%v does not exist in %v table", columnName, tableName),
-									})
-								}
-
-							}
-
-						}
-					}
-				} else if strings.Contains(splitFieldsStr, "email") {
-					if FieldV.String() != "" {
-						if !regexpEmail.Match([]byte(FieldV.String())) {
-							err = append(err, ValidationError{
-								Field: FieldT.Name,
-								Error: ErrInvalidEmail.Error(),
-							})
-						}
-					}
-				}
-			}
+type existenceResult struct {
+	exists bool
+	err    error
+}
+
+func (vr ValidateRequestM) validateStruct(v reflect.Value, prefix string, checked map[existenceKey]existenceResult, errs *ValidationErrors) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
 		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
 	}
 
-	errString := ""
-	if len(err) < 1 {
-		return nil
-	} else {
-		for _, v := range err {
-			errString += v.Field + ": " + v.Error + " ;"
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		FieldT := t.Field(i)
+		FieldV := v.Field(i)
+		if !FieldT.IsExported() {
+			continue
+		}
+		name := prefix + FieldT.Name
+
+		vr.validateNested(FieldV, name, checked, errs)
+
+		validateFields := FieldT.Tag.Get("pgvalidate")
+		if validateFields == "_" || validateFields == "" {
+			continue
+		}
+
+		for _, rule := range strings.Split(validateFields, ",") {
+			rule = strings.ToLower(strings.TrimSpace(rule))
+			switch {
+			case strings.HasPrefix(rule, "notexists="), strings.HasPrefix(rule, "exists="):
+				value, status := ValidateNext(FieldV)
+				if !status {
+					continue
+				}
+				checkType, targetStr, _ := strings.Cut(rule, "=")
+				target, err := ParseValidationTarget(targetStr)
+				if err != nil {
+					*errs = append(*errs, ValidationError{Field: name, Error: err.Error()})
+					continue
+				}
+				exists, err := vr.exists(target, value, checked)
+				if err != nil {
+					vr.Logger.Error(fmt.Sprintf("error checking %v for %v: %v", targetStr, name, err.Error()))
+					*errs = append(*errs, ValidationError{Field: name, Error: fmt.Sprintf("could not check %v in %v", target.Column, target.Table)})
+				} else if checkType == "exists" && !exists {
+					*errs = append(*errs, ValidationError{Field: name, Error: fmt.Sprintf("%v does not exist in %v table", target.Column, target.Table)})
+				} else if checkType == "notexists" && exists {
+					*errs = append(*errs, ValidationError{Field: name, Error: fmt.Sprintf("%v exists in %v table", target.Column, target.Table)})
+				}
+			case rule == "email":
+				if FieldV.Kind() == reflect.String && FieldV.String() != "" && !regexpEmail.MatchString(FieldV.String()) {
+					*errs = append(*errs, ValidationError{Field: name, Error: ErrInvalidEmail.Error()})
+				}
+			}
 		}
 	}
-	return fmt.Errorf(errString)
 }
 
-func (vr ValidateRequestM) ValidationCheck(dbName string, table, checkType string, query interface{}, args ...interface{}) bool {
-	db := ReturnDatabase(dbName)
-	switch dbName {
-	case "admin":
-		return checkForConnectedDB(db, table, checkType, query, args...)
-	case "auth":
-		er := request.ExternalRequest{
-			Logger: vr.Logger,
-			Test:   vr.Test,
+// validateNested descends into struct fields and slices of structs, naming their fields after the
+// path to them, like Parties[0].AccountID.
+func (vr ValidateRequestM) validateNested(v reflect.Value, name string, checked map[existenceKey]existenceResult, errs *ValidationErrors) {
+	elem := v
+	for elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			return
 		}
-		status, err := er.SendExternalRequest(request.ValidateOnAuth, external_models.ValidateOnDBReq{
-			Table: table,
-			Type:  checkType,
-			Query: fmt.Sprintf("%v", query),
-			Value: args[0],
-		})
-		if err != nil {
-			vr.Logger.Error("error occurred in validation", err.Error())
-			return false
+		elem = elem.Elem()
+	}
+	switch elem.Kind() {
+	case reflect.Struct:
+		if elem.Type().PkgPath() == "time" {
+			return
+		}
+		vr.validateStruct(elem, name+".", checked, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < elem.Len(); i++ {
+			item := elem.Index(i)
+			for item.Kind() == reflect.Ptr && !item.IsNil() {
+				item = item.Elem()
+			}
+			if item.Kind() == reflect.Struct {
+				vr.validateStruct(item, fmt.Sprintf("%v[%v].", name, i), checked, errs)
+			}
 		}
-		return status.(bool)
-	case "notifications":
-		return checkForConnectedDB(db, table, checkType, query, args...)
-	case "payment":
-		return checkForConnectedDB(db, table, checkType, query, args...)
-	case "reminder":
-		return checkForConnectedDB(db, table, checkType, query, args...)
-	case "subscription":
-		return checkForConnectedDB(db, table, checkType, query, args...)
-	case "transaction":
-		return checkForConnectedDB(db, table, checkType, query, args...)
-	case "verification":
-		return checkForConnectedDB(db, table, checkType, query, args...)
-	case "cron":
-		return checkForConnectedDB(db, table, checkType, query, args...)
-	default:
-		return false
 	}
 }
 
-func checkForConnectedDB(db *gorm.DB, table, checkType string, query interface{}, args ...interface{}) bool {
-	if checkType == "notexists" {
-		return !CheckExistsInTable(db, table, query, args...)
-	} else if checkType == "exists" {
-		return CheckExistsInTable(db, table, query, args...)
+func (vr ValidateRequestM) exists(target ValidationTarget, value interface{}, checked map[existenceKey]existenceResult) (bool, error) {
+	key := existenceKey{target: target, value: fmt.Sprint(value)}
+	if result, ok := checked[key]; ok {
+		return result.exists, result.err
+	}
+
+	resolver, ok := vr.Resolvers[target.Source]
+	if !ok {
+		resolver, ok = vr.defaultResolvers()[target.Source]
+	}
+	var result existenceResult
+	if !ok {
+		result.err = fmt.Errorf("no resolver for %v", target.Source)
 	} else {
-		return false
+		result.exists, result.err = resolver.Exists(target, value)
+	}
+	checked[key] = result
+	return result.exists, result.err
+}
+
+func (vr ValidateRequestM) defaultResolvers() map[string]ExistenceResolver {
+	db := vr.Db
+	if db == nil {
+		db = ReturnDatabase("transaction")
+	}
+	return map[string]ExistenceResolver{
+		"transaction": DBResolver{Db: db},
+		"auth":        AuthResolver{Auth: vr.Auth},
 	}
 }
 
@@ -187,15 +213,15 @@ func ValidateNext(value reflect.Value) (interface{}, bool) {
 	} else if value.Type().Kind() == reflect.Uint {
 		return value.Uint(), value.Uint() != 0
 	} else if value.Type().Kind() == reflect.Uint8 {
-		return value.Int(), value.Uint() != 0
+		return value.Uint(), value.Uint() != 0
 	} else if value.Type().Kind() == reflect.Uint16 {
-		return value.Int(), value.Uint() != 0
+		return value.Uint(), value.Uint() != 0
 	} else if value.Type().Kind() == reflect.Uint32 {
-		return value.Int(), value.Uint() != 0
+		return value.Uint(), value.Uint() != 0
 	} else if value.Type().Kind() == reflect.Uint64 {
-		return value.Int(), value.Uint() != 0
+		return value.Uint(), value.Uint() != 0
 	} else if value.Type().Kind() == reflect.Uintptr {
-		return value.Int(), value.Uint() != 0
+		return value.Uint(), value.Uint() != 0
 	} else if value.Type().Kind() == reflect.Float32 {
 		return value.Float(), value.Float() != 0
 	} else if value.Type().Kind() == reflect.Float64 {
@@ -8,17 +8,20 @@ import (
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/pkg/controller/health"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	healthService "github.com/vesicash/transactions-ms/services/health"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
 func Health(r *gin.Engine, ApiVersion string, validator *validator.Validate, db postgresql.Databases, logger *utility.Logger) *gin.Engine {
-	extReq := request.ExternalRequest{Logger: logger, Test: false}
-	health := health.Controller{Db: db, Validator: validator, Logger: logger, ExtReq: extReq}
+	extReq := request.NewExternalRequest(logger)
+	health := health.Controller{Db: db, Validator: validator, Logger: logger, ExtReq: extReq, Checkers: healthService.DefaultCheckers(db)}
 
 	healthUrl := r.Group(fmt.Sprintf("%v", ApiVersion))
 	{
 		healthUrl.POST("/health", health.Post)
 		healthUrl.GET("/health", health.Get)
+		healthUrl.GET("/health/live", health.Live)
+		healthUrl.GET("/health/ready", health.Ready)
 	}
 	return r
 }
@@ -6,8 +6,12 @@ import (
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/config"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	"github.com/vesicash/transactions-ms/utility"
 )
@@ -22,9 +26,11 @@ func Setup(logger *utility.Logger, validator *validator.Validate, db postgresql.
 	// r.Use(gin.Logger())
 	r.ForwardedByClientIP = true
 	r.SetTrustedProxies(config.GetConfig().Server.TrustedProxies)
-	r.Use(middleware.PrometheusMiddleware())
+	r.Use(middleware.Tracing(config.ServiceName))
+	r.Use(middleware.PrometheusMiddleware(config.ServiceName))
 	r.Use(middleware.Security())
-	r.Use(middleware.Throttle())
+	r.Use(middleware.RateLimit(middleware.NewRateLimiter(rateLimitStore(db), logger)))
+	r.Use(middleware.VerifySignature(middleware.NewSignatureVerifier(nonceStore(db), request.NewExternalRequest(logger))))
 	r.Use(middleware.Logger())
 	r.Use(gin.Recovery())
 	r.Use(middleware.CORS())
@@ -54,3 +60,21 @@ func Setup(logger *utility.Logger, validator *validator.Validate, db postgresql.
 
 	return r
 }
+
+// rateLimitStore is where request counts are kept. Postgres is shared by every replica; the memory
+// store is only for running a single instance, such as locally.
+func rateLimitStore(db postgresql.Databases) repository.RateLimitRepository {
+	if config.GetConfig().RateLimit.Store == "memory" {
+		return memory.New().Repositories().RateLimits
+	}
+	return gormrepo.New(db.Transaction).RateLimits
+}
+
+// nonceStore is where the nonces of signed requests are kept. Like rateLimitStore, the memory store
+// only holds for a single instance.
+func nonceStore(db postgresql.Databases) repository.NonceRepository {
+	if config.GetConfig().RequestSigning.NonceStore == "memory" {
+		return memory.New().Repositories().Nonces
+	}
+	return gormrepo.New(db.Transaction).Nonces
+}
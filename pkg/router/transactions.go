@@ -8,13 +8,15 @@ import (
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
 func Transaction(r *gin.Engine, ApiVersion string, validator *validator.Validate, db postgresql.Databases, logger *utility.Logger) *gin.Engine {
-	extReq := request.ExternalRequest{Logger: logger, Test: false}
-	transaction := transactions.Controller{Db: db, Validator: validator, Logger: logger, ExtReq: extReq}
+	extReq := request.NewExternalRequest(logger)
+	transaction := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validator, Logger: logger, ExtReq: extReq}
+	traced := transaction.Traced
 
 	// transactionsUrl := r.Group(fmt.Sprintf("%v", ApiVersion))
 	// {
@@ -23,65 +25,81 @@ func Transaction(r *gin.Engine, ApiVersion string, validator *validator.Validate
 
 	transactionsAuthUrl := r.Group(fmt.Sprintf("%v", ApiVersion), middleware.Authorize(db, extReq, middleware.AuthType))
 	{
-		transactionsAuthUrl.POST("/create", transaction.CreateTransaction)
-		transactionsAuthUrl.PATCH("/edit", transaction.EditTransaction)
-		transactionsAuthUrl.DELETE("/delete/:id", transaction.DeleteTransaction)
-		transactionsAuthUrl.POST("/listByUser", transaction.ListTransactionsByUser)
-		transactionsAuthUrl.GET("/list/archived", transaction.ListArchivedTransactions)
-		transactionsAuthUrl.POST("/send", transaction.SendTransaction)
-		transactionsAuthUrl.POST("/dispute", transaction.CreateDispute)
-		transactionsAuthUrl.GET("/dispute/fetch/:transaction_id", transaction.GetDisputeByTransactionID)
-		transactionsAuthUrl.PATCH("/dispute/update", transaction.UpdateDispute)
-		transactionsAuthUrl.GET("/list/user_disputes", transaction.GetDisputeByUser)
-		transactionsAuthUrl.POST("/accept", transaction.AcceptTransaction)
-		transactionsAuthUrl.POST("/delivered", transaction.TransactionDelivered)
-		transactionsAuthUrl.POST("/reject", transaction.RejectTransaction)
-		transactionsAuthUrl.POST("/reject_delivery", transaction.RejectTransactionDelivery)
-		transactionsAuthUrl.POST("/request/due_date_extension", transaction.RequestDueDateExtension)
-		transactionsAuthUrl.POST("/approve/due_date_extension", transaction.ApproveDueDateExtension)
-		transactionsAuthUrl.POST("/satisfied", transaction.Satisfied)
-		transactionsAuthUrl.PATCH("/updateStatus", transaction.UpdateTransactionStatus)
-		transactionsAuthUrl.POST("/import", transaction.ImportTransactions)
+		transactionsAuthUrl.POST("/create", traced((*transactions.Controller).CreateTransaction))
+		transactionsAuthUrl.PATCH("/edit", traced((*transactions.Controller).EditTransaction))
+		transactionsAuthUrl.DELETE("/delete/:id", traced((*transactions.Controller).DeleteTransaction))
+		transactionsAuthUrl.PATCH("/restore/:id", traced((*transactions.Controller).RestoreTransaction))
+		transactionsAuthUrl.POST("/listByUser", traced((*transactions.Controller).ListTransactionsByUser))
+		transactionsAuthUrl.GET("/list/archived", traced((*transactions.Controller).ListArchivedTransactions))
+		transactionsAuthUrl.PATCH("/archive/:id", traced((*transactions.Controller).ArchiveTransaction))
+		transactionsAuthUrl.PATCH("/unarchive/:id", traced((*transactions.Controller).UnarchiveTransaction))
+		transactionsAuthUrl.POST("/send", traced((*transactions.Controller).SendTransaction))
+		transactionsAuthUrl.POST("/dispute", traced((*transactions.Controller).CreateDispute))
+		transactionsAuthUrl.GET("/dispute/fetch/:transaction_id", traced((*transactions.Controller).GetDisputeByTransactionID))
+		transactionsAuthUrl.PATCH("/dispute/update", traced((*transactions.Controller).UpdateDispute))
+		transactionsAuthUrl.GET("/list/user_disputes", traced((*transactions.Controller).GetDisputeByUser))
+		transactionsAuthUrl.POST("/accept", traced((*transactions.Controller).AcceptTransaction))
+		transactionsAuthUrl.POST("/delivered", traced((*transactions.Controller).TransactionDelivered))
+		transactionsAuthUrl.POST("/reject", traced((*transactions.Controller).RejectTransaction))
+		transactionsAuthUrl.POST("/reject_delivery", traced((*transactions.Controller).RejectTransactionDelivery))
+		transactionsAuthUrl.POST("/request/due_date_extension", traced((*transactions.Controller).RequestDueDateExtension))
+		transactionsAuthUrl.POST("/approve/due_date_extension", traced((*transactions.Controller).ApproveDueDateExtension))
+		transactionsAuthUrl.POST("/satisfied", traced((*transactions.Controller).Satisfied))
+		transactionsAuthUrl.PATCH("/updateStatus", traced((*transactions.Controller).UpdateTransactionStatus))
+		transactionsAuthUrl.POST("/import", middleware.Quota("import"), traced((*transactions.Controller).ImportTransactions))
+		transactionsAuthUrl.POST("/milestone/delivered", traced((*transactions.Controller).MilestoneDelivered))
+		transactionsAuthUrl.POST("/milestone/accept", traced((*transactions.Controller).AcceptMilestone))
+		transactionsAuthUrl.POST("/milestone/reject", traced((*transactions.Controller).RejectMilestone))
+		transactionsAuthUrl.POST("/payout/preview", traced((*transactions.Controller).PayoutPreview))
 
 	}
 
 	transactionsApiUrl := r.Group(fmt.Sprintf("%v", ApiVersion), middleware.Authorize(db, extReq, middleware.ApiType))
 	{
-		transactionsApiUrl.POST("/list", transaction.ListTransactions)
-		transactionsApiUrl.GET("/listById/:id", transaction.ListTransactionsByID)
-		transactionsApiUrl.GET("/list-transactions-by-ussd-code/:code", transaction.ListTransactionsByUSSDCode)
-		transactionsApiUrl.POST("/listByBusiness", transaction.ListTransactionsByBusiness)
-		transactionsApiUrl.POST("/listByBusinessFromMondayToThursday", transaction.ListByBusinessFromMondayToThursday)
-		transactionsApiUrl.PATCH("/parties/update", transaction.UpdateTransactionParties)
-		transactionsApiUrl.PATCH("/parties/update-status", transaction.UpdateTransactionPartyStatus)
-		transactionsApiUrl.POST("/assign/buyer", transaction.AssignTransactionBuyer)
-		transactionsApiUrl.PATCH("/broker/update", transaction.UpdateTransactionBroker)
-		transactionsApiUrl.POST("/check-amount", transaction.CheckTransactionAmount)
-		transactionsApiUrl.POST("/escrowcharge", transaction.GetEscrowCharge)
-		transactionsApiUrl.GET("/rates", transaction.ListRates)
-		transactionsApiUrl.GET("/exchange-transaction/:account_id", transaction.ListExchangeTransactionByAccountID)
-		transactionsApiUrl.GET("exchange-transaction/show/:exchange_id", transaction.GetExchangeTransactionByID)
-		transactionsApiUrl.PATCH("/api/updateStatus", transaction.UpdateTransactionStatusApi)
-		transactionsApiUrl.POST("/api/satisfied", transaction.SatisfiedApi)
+		transactionsApiUrl.POST("/list", middleware.RequireScope(middleware.ScopeTransactionsRead), traced((*transactions.Controller).ListTransactions))
+		transactionsApiUrl.GET("/listById/:id", middleware.RequireScope(middleware.ScopeTransactionsRead), traced((*transactions.Controller).ListTransactionsByID))
+		transactionsApiUrl.GET("/list-transactions-by-ussd-code/:code", middleware.RequireScope(middleware.ScopeTransactionsRead), traced((*transactions.Controller).ListTransactionsByUSSDCode))
+		transactionsApiUrl.POST("/listByBusiness", middleware.RequireScope(middleware.ScopeTransactionsRead), traced((*transactions.Controller).ListTransactionsByBusiness))
+		transactionsApiUrl.POST("/listByBusinessFromMondayToThursday", middleware.RequireScope(middleware.ScopeTransactionsRead), traced((*transactions.Controller).ListByBusinessFromMondayToThursday))
+		transactionsApiUrl.PATCH("/parties/update", middleware.RequireScope(middleware.ScopePartiesWrite), traced((*transactions.Controller).UpdateTransactionParties))
+		transactionsApiUrl.PATCH("/parties/update-status", middleware.RequireScope(middleware.ScopePartiesWrite), traced((*transactions.Controller).UpdateTransactionPartyStatus))
+		transactionsApiUrl.POST("/assign/buyer", middleware.RequireScope(middleware.ScopePartiesWrite), traced((*transactions.Controller).AssignTransactionBuyer))
+		transactionsApiUrl.PATCH("/broker/update", middleware.RequireScope(middleware.ScopeTransactionsWrite), traced((*transactions.Controller).UpdateTransactionBroker))
+		transactionsApiUrl.POST("/check-amount", middleware.RequireScope(middleware.ScopeTransactionsRead), traced((*transactions.Controller).CheckTransactionAmount))
+		transactionsApiUrl.POST("/escrowcharge", middleware.RequireScope(middleware.ScopeTransactionsRead), traced((*transactions.Controller).GetEscrowCharge))
+		transactionsApiUrl.GET("/rates", middleware.RequireScope(middleware.ScopeRatesRead), traced((*transactions.Controller).ListRates))
+		transactionsApiUrl.GET("/exchange-transaction/:account_id", middleware.RequireScope(middleware.ScopeRatesRead), traced((*transactions.Controller).ListExchangeTransactionByAccountID))
+		transactionsApiUrl.GET("exchange-transaction/show/:exchange_id", middleware.RequireScope(middleware.ScopeRatesRead), traced((*transactions.Controller).GetExchangeTransactionByID))
+		transactionsApiUrl.PATCH("/api/updateStatus", middleware.RequireScope(middleware.ScopeTransactionsWrite), traced((*transactions.Controller).UpdateTransactionStatusApi))
+		transactionsApiUrl.POST("/api/satisfied", middleware.RequireScope(middleware.ScopeFundsRelease), traced((*transactions.Controller).SatisfiedApi))
+		transactionsApiUrl.GET("/activities/:transaction_id", middleware.RequireScope(middleware.ScopeTransactionsRead), traced((*transactions.Controller).ListActivityLogs))
+		transactionsApiUrl.GET("/activities/business/:business_id", middleware.RequireScope(middleware.ScopeTransactionsRead), traced((*transactions.Controller).ListBusinessActivityLogs))
 
 	}
 
 	transactionsAppUrl := r.Group(fmt.Sprintf("%v", ApiVersion), middleware.Authorize(db, extReq, middleware.AppType))
 	{
-		transactionsAppUrl.POST("/validate_on_db", transaction.ValidateOnDB)
-		transactionsAppUrl.PATCH("/update_transaction_amount_paid", transaction.UpdateTransactionAmountPaid)
-		transactionsAppUrl.POST("/create_activity_log", transaction.CreateActivityLog)
-		transactionsAppUrl.POST("/create_exchange_transaction", transaction.CreateExchangeTransaction)
-		transactionsAppUrl.GET("/get_rate_by_currency/:from/:to", transaction.GetRateByFromAndToCurrencies)
-		transactionsAppUrl.GET("/get_rate/:id", transaction.GetRateByID)
+		transactionsAppUrl.POST("/validate", traced((*transactions.Controller).ValidateChecks))
+		transactionsAppUrl.PATCH("/update_transaction_amount_paid", traced((*transactions.Controller).UpdateTransactionAmountPaid))
+		transactionsAppUrl.PATCH("/milestone/fund", traced((*transactions.Controller).FundMilestone))
+		transactionsAppUrl.POST("/create_activity_log", traced((*transactions.Controller).CreateActivityLog))
+		transactionsAppUrl.POST("/create_exchange_transaction", traced((*transactions.Controller).CreateExchangeTransaction))
+		transactionsAppUrl.GET("/get_rate_by_currency/:from/:to", traced((*transactions.Controller).GetRateByFromAndToCurrencies))
+		transactionsAppUrl.GET("/get_rate/:id", traced((*transactions.Controller).GetRateByID))
+		transactionsAppUrl.GET("/audit/transaction/:transaction_id", traced((*transactions.Controller).ListAuditLogs))
+		transactionsAppUrl.GET("/audit/verify", traced((*transactions.Controller).VerifyAuditChain))
+		transactionsAppUrl.POST("/fees/schedules", traced((*transactions.Controller).CreateFeeSchedule))
+		transactionsAppUrl.GET("/fees/schedules", traced((*transactions.Controller).ListFeeSchedules))
+		transactionsAppUrl.PATCH("/fees/schedules/:fee_schedule_id", traced((*transactions.Controller).UpdateFeeSchedule))
+		transactionsAppUrl.GET("/fees/schedules/:fee_schedule_id/versions", traced((*transactions.Controller).GetFeeScheduleVersions))
 	}
 
 	transactionsjobsUrl := r.Group(fmt.Sprintf("%v/jobs", ApiVersion))
 	{
-		transactionsjobsUrl.POST("/start", transaction.StartCronJob)
-		transactionsjobsUrl.POST("/start-bulk", transaction.StartCronJobsBulk)
-		transactionsjobsUrl.POST("/stop", transaction.StopCronJob)
-		transactionsjobsUrl.PATCH("/update_interval", transaction.UpdateCronJobInterval)
+		transactionsjobsUrl.POST("/start", traced((*transactions.Controller).StartCronJob))
+		transactionsjobsUrl.POST("/start-bulk", traced((*transactions.Controller).StartCronJobsBulk))
+		transactionsjobsUrl.POST("/stop", traced((*transactions.Controller).StopCronJob))
+		transactionsjobsUrl.PATCH("/update_interval", traced((*transactions.Controller).UpdateCronJobInterval))
 	}
 	return r
 }
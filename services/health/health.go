@@ -0,0 +1,148 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vesicash/transactions-ms/cronjobs"
+	"github.com/vesicash/transactions-ms/external"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/internal/models/migrations"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+)
+
+// checkTimeout bounds each check so one hanging dependency cannot stall the probe.
+var checkTimeout = 2 * time.Second
+
+// Checker is one dependency the service needs. Readiness fails when a critical checker fails; a
+// failing non-critical checker only degrades it.
+type Checker struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) (details interface{}, err error)
+}
+
+// Readiness runs every checker concurrently and collects their results.
+func Readiness(ctx context.Context, checkers []Checker) models.HealthReport {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		report = models.HealthReport{Status: models.HealthStatusOk, Checks: map[string]models.HealthCheck{}}
+	)
+
+	for _, checker := range checkers {
+		wg.Add(1)
+		go func(checker Checker) {
+			defer wg.Done()
+			result := runCheck(ctx, checker)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[checker.Name] = result
+			if result.Status == models.HealthStatusOk {
+				return
+			}
+			if checker.Critical {
+				report.Status = models.HealthStatusDown
+			} else if report.Status == models.HealthStatusOk {
+				report.Status = models.HealthStatusDegraded
+			}
+		}(checker)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func runCheck(ctx context.Context, checker Checker) (result models.HealthCheck) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	started := time.Now()
+	result = models.HealthCheck{Status: models.HealthStatusOk, Critical: checker.Critical}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Status, result.Error = models.HealthStatusDown, fmt.Sprint(r)
+		}
+		result.LatencyMs = time.Since(started).Milliseconds()
+	}()
+
+	details, err := checker.Check(ctx)
+	result.Details = details
+	if err != nil {
+		result.Status, result.Error = models.HealthStatusDown, err.Error()
+	}
+	return result
+}
+
+// DefaultCheckers are the dependencies checked by the readiness endpoint: the transactions
+// database and its schema, the auth, payment and notification services, and the cron scheduler.
+func DefaultCheckers(db postgresql.Databases) []Checker {
+	ms := config.GetConfig().Microservices
+	return []Checker{
+		{Name: "database", Critical: true, Check: DatabaseCheck(db)},
+		{Name: "migrations", Critical: true, Check: MigrationsCheck(db)},
+		{Name: "auth", Critical: true, Check: ServiceCheck(ms.Auth)},
+		{Name: "payment", Check: ServiceCheck(ms.Payment)},
+		{Name: "notification", Check: ServiceCheck(ms.Notification)},
+		{Name: "scheduler", Check: SchedulerCheck},
+	}
+}
+
+func DatabaseCheck(db postgresql.Databases) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		if db.Transaction == nil {
+			return nil, fmt.Errorf("database not connected")
+		}
+		sqlDB, err := db.Transaction.DB()
+		if err != nil {
+			return nil, err
+		}
+		return nil, sqlDB.PingContext(ctx)
+	}
+}
+
+// MigrationsCheck reports the migrations that have not been applied to the database.
+func MigrationsCheck(db postgresql.Databases) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		if db.Transaction == nil {
+			return nil, fmt.Errorf("database not connected")
+		}
+		migrator, err := migrations.NewMigrator(db.Transaction.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		pending, err := migrator.Pending()
+		if err != nil {
+			return nil, err
+		}
+		if len(pending) > 0 {
+			return map[string]interface{}{"pending_migrations": pending}, fmt.Errorf("%v migrations not applied", len(pending))
+		}
+		return nil, nil
+	}
+}
+
+func ServiceCheck(baseURL string) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		return nil, external.Ping(ctx, baseURL)
+	}
+}
+
+// SchedulerCheck fails when a running cron job has panicked or stopped completing its passes.
+func SchedulerCheck(ctx context.Context) (interface{}, error) {
+	statuses := cronjobs.Statuses()
+	unhealthy := []string{}
+	for _, status := range statuses {
+		if !status.Healthy {
+			unhealthy = append(unhealthy, status.Name)
+		}
+	}
+	if len(unhealthy) > 0 {
+		return statuses, fmt.Errorf("unhealthy cronjobs: %v", unhealthy)
+	}
+	return statuses, nil
+}
@@ -7,17 +7,21 @@ import (
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func AcceptTransactionService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string, user external_models.User) (int, error) {
+func AcceptTransactionService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string, user external_models.User) (int, error) {
 	var (
-		transaction = models.Transaction{TransactionID: transactionID}
-		statusCode  = ""
+		statusCode = ""
 	)
 
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+	transaction, code, err := repo.Transactions.GetByTransactionID(transactionID)
+	if err != nil {
+		return code, err
+	}
+
+	code, err = AuthorizePartyAction(repo, transactionID, int(user.AccountID), ActionAccept)
 	if err != nil {
 		return code, err
 	}
@@ -38,28 +42,32 @@ func AcceptTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 	}
 
 	transaction.Status = GetTransactionStatus(statusCode)
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
 
-	_, err = CreateTransactionState(db, statusCode, transactionID, transaction.MilestoneID, int(user.AccountID))
+	_, err = CreateTransactionState(repo, statusCode, transactionID, transaction.MilestoneID, int(user.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
-	extReq.SendExternalRequest(request.SendTransactionAcceptedNotification, external_models.TransactionIDRequestModel{
+	extReq.Notification.SendTransactionAcceptedNotification(external_models.TransactionIDRequestModel{
 		TransactionId: transactionID,
 	})
 	return http.StatusOK, nil
 }
-func RejectTransactionService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.RejectTransactionRequest, user external_models.User) (int, error) {
+func RejectTransactionService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.RejectTransactionRequest, user external_models.User) (int, error) {
 	var (
-		transaction = models.Transaction{TransactionID: req.TransactionID}
-		statusCode  = ""
+		statusCode = ""
 	)
 
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+	transaction, code, err := repo.Transactions.GetByTransactionID(req.TransactionID)
+	if err != nil {
+		return code, err
+	}
+
+	code, err = AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), ActionAccept)
 	if err != nil {
 		return code, err
 	}
@@ -76,12 +84,12 @@ func RejectTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 	}
 
 	transaction.Status = GetTransactionStatus(statusCode)
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
 
-	_, err = CreateTransactionState(db, statusCode, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
+	_, err = CreateTransactionState(repo, statusCode, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -93,7 +101,7 @@ func RejectTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 			Reason:        req.Reason,
 		}
 
-		err = transactionRejected.CreateTransactionsRejected(db.Transaction)
+		err = repo.Rejections.Create(&transactionRejected)
 		if err != nil {
 			return http.StatusInternalServerError, err
 		}
@@ -102,50 +110,56 @@ func RejectTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 	// Send Refund If The Transaction Has Been PAid For
 	if payment.IsPaid {
 		// Send Refund
-		_, err := extReq.SendExternalRequest(request.RequestManualRefund, req.TransactionID)
+		_, err := extReq.Payment.RequestManualRefund(req.TransactionID)
 		if err != nil {
 			return http.StatusInternalServerError, fmt.Errorf("refund failed")
 		}
+		RecordRefund(transaction.Currency, transaction.AmountPaid)
 		transaction.Status = GetTransactionStatus("cr")
 	} else {
 		transaction.Status = GetTransactionStatus("closed")
 	}
 
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
+	RecordStatusChange(transaction, GetTransactionStatus(statusCode))
 
-	_, err = CreateTransactionState(db, transaction.Status, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
+	_, err = CreateTransactionState(repo, transaction.Status, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
-	extReq.SendExternalRequest(request.SendTransactionRejectedNotification, external_models.TransactionIDRequestModel{
+	extReq.Notification.SendTransactionRejectedNotification(external_models.TransactionIDRequestModel{
 		TransactionId: req.TransactionID,
 	})
 
 	return http.StatusOK, nil
 }
 
-func RejectTransactionDeliveryService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.RejectTransactionRequest, user external_models.User) (int, error) {
+func RejectTransactionDeliveryService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.RejectTransactionRequest, user external_models.User) (int, error) {
 	var (
-		transaction = models.Transaction{TransactionID: req.TransactionID}
-		statusCode  = "dr"
+		statusCode = "dr"
 	)
 
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+	transaction, code, err := repo.Transactions.GetByTransactionID(req.TransactionID)
+	if err != nil {
+		return code, err
+	}
+
+	code, err = AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), ActionApproveRelease)
 	if err != nil {
 		return code, err
 	}
 
 	transaction.Status = GetTransactionStatus(statusCode)
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
 
-	_, err = CreateTransactionState(db, statusCode, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
+	_, err = CreateTransactionState(repo, statusCode, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -153,17 +167,17 @@ func RejectTransactionDeliveryService(extReq request.ExternalRequest, logger *ut
 	statusCode = "closed"
 
 	transaction.Status = GetTransactionStatus(statusCode)
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
 
-	_, err = CreateTransactionState(db, statusCode, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
+	_, err = CreateTransactionState(repo, statusCode, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
-	extReq.SendExternalRequest(request.SendTransactionDeliveredRejectedNotification, external_models.TransactionIDRequestModel{
+	extReq.Notification.SendTransactionDeliveredRejectedNotification(external_models.TransactionIDRequestModel{
 		TransactionId: req.TransactionID,
 	})
 
@@ -0,0 +1,31 @@
+package transactions
+
+import (
+	"net/http"
+
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func ListActivityLogsService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.ListActivityLogsRequest, paginator postgresql.Pagination) ([]models.ActivityLog, postgresql.PaginationResponse, int, error) {
+	activities, pagination, err := repo.ActivityLogs.ListByFilters(req, paginator)
+	if err != nil {
+		return activities, pagination, http.StatusInternalServerError, err
+	}
+	return activities, pagination, http.StatusOK, nil
+}
+
+func resolveActorRole(repo repository.Repositories, transactionID string, accountID int) string {
+	if accountID == 0 {
+		return "system"
+	}
+
+	party, _, err := repo.Parties.GetByTransactionIDAndAccountID(transactionID, accountID)
+	if err != nil {
+		return ""
+	}
+	return party.Role
+}
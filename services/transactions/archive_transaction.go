@@ -0,0 +1,42 @@
+package transactions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+// ArchiveTransactionService archives, or with archived false unarchives, a transaction for the
+// user's parties on it only. Archived transactions move from the user's list to the archived one.
+func ArchiveTransactionService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string, user external_models.User, archived bool) (int, error) {
+	code, err := AuthorizePartyAction(repo, transactionID, int(user.AccountID), ActionArchive)
+	if err != nil {
+		return code, err
+	}
+
+	parties, err := repo.Parties.ListByTransactionID(transactionID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	now := time.Now()
+	for _, party := range parties {
+		if party.AccountID != int(user.AccountID) || (party.ArchivedAt != nil) == archived {
+			continue
+		}
+		party.ArchivedAt = nil
+		if archived {
+			party.ArchivedAt = &now
+		}
+		err = repo.Parties.Update(&party)
+		if err != nil {
+			return updateErrorCode(err), err
+		}
+	}
+
+	return http.StatusOK, nil
+}
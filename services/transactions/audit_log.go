@@ -0,0 +1,30 @@
+package transactions
+
+import (
+	"net/http"
+
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func ListAuditLogsService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string, paginator postgresql.Pagination) ([]models.AuditLog, postgresql.PaginationResponse, int, error) {
+	logs, pagination, err := repo.AuditLogs.ListByTransactionID(transactionID, paginator)
+	if err != nil {
+		return logs, pagination, http.StatusInternalServerError, err
+	}
+	return logs, pagination, http.StatusOK, nil
+}
+
+func VerifyAuditChainService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories) (models.AuditChainVerification, int, error) {
+	result, err := repo.AuditLogs.VerifyChain()
+	if err != nil {
+		return result, http.StatusInternalServerError, err
+	}
+	if !result.Valid {
+		logger.Error("audit chain broken", result.BrokenLinkID, result.Reason)
+	}
+	return result, http.StatusOK, nil
+}
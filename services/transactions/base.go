@@ -3,16 +3,16 @@ package transactions
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
 func GetBusinessProfileByAccountID(extReq request.ExternalRequest, logger *utility.Logger, accountID int) (external_models.BusinessProfile, error) {
-	businessProfileInterface, err := extReq.SendExternalRequest(request.GetBusinessProfile, external_models.GetBusinessProfileModel{
+	businessProfile, err := extReq.Auth.GetBusinessProfile(external_models.GetBusinessProfileModel{
 		AccountID: uint(accountID),
 	})
 	if err != nil {
@@ -20,11 +20,6 @@ func GetBusinessProfileByAccountID(extReq request.ExternalRequest, logger *utili
 		return external_models.BusinessProfile{}, fmt.Errorf("Business lacks a profile.")
 	}
 
-	businessProfile, ok := businessProfileInterface.(external_models.BusinessProfile)
-	if !ok {
-		return external_models.BusinessProfile{}, fmt.Errorf("response data format error")
-	}
-
 	if businessProfile.ID == 0 {
 		return external_models.BusinessProfile{}, fmt.Errorf("Business lacks a profile.")
 	}
@@ -32,16 +27,11 @@ func GetBusinessProfileByAccountID(extReq request.ExternalRequest, logger *utili
 }
 
 func CreatePayment(extReq request.ExternalRequest, data external_models.CreatePaymentRequestWithToken) (external_models.Payment, error) {
-	paymentInterface, err := extReq.SendExternalRequest(request.CreatePayment, data)
+	payment, err := extReq.Payment.CreatePayment(data)
 	if err != nil {
 		return external_models.Payment{}, err
 	}
 
-	payment, ok := paymentInterface.(external_models.Payment)
-	if !ok {
-		return external_models.Payment{}, fmt.Errorf("response data format error")
-	}
-
 	if payment.ID == 0 {
 		return external_models.Payment{}, fmt.Errorf("payment creation failed")
 	}
@@ -49,48 +39,62 @@ func CreatePayment(extReq request.ExternalRequest, data external_models.CreatePa
 }
 
 func GetUserWithAccountID(extReq request.ExternalRequest, accountID int) (external_models.User, error) {
-	usItf, err := extReq.SendExternalRequest(request.GetUserReq, external_models.GetUserRequestModel{AccountID: uint(accountID)})
+	us, err := extReq.Auth.GetUser(external_models.GetUserRequestModel{AccountID: uint(accountID)})
 	if err != nil {
 		return external_models.User{}, err
 	}
 
-	us, ok := usItf.(external_models.User)
-	if !ok {
-		return external_models.User{}, fmt.Errorf("response data format error")
-	}
-
 	if us.ID == 0 {
 		return external_models.User{}, fmt.Errorf("user not found")
 	}
 	return us, nil
 }
+
+// GetUsersWithAccountIDs looks up each distinct account once, a few at a time, since the auth service
+// has no batch endpoint. Accounts that cannot be found are left out of the result.
+func GetUsersWithAccountIDs(extReq request.ExternalRequest, accountIDs []int) map[int]external_models.User {
+	var (
+		users  = map[int]external_models.User{}
+		seen   = map[int]bool{}
+		unique = []int{}
+		mu     sync.Mutex
+	)
+	for _, accountID := range accountIDs {
+		if !seen[accountID] {
+			seen[accountID] = true
+			unique = append(unique, accountID)
+		}
+	}
+
+	forEachBounded(len(unique), func(i int) {
+		user, err := GetUserWithAccountID(extReq, unique[i])
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		users[unique[i]] = user
+		mu.Unlock()
+	})
+	return users
+}
+
 func GetUserWithEmail(extReq request.ExternalRequest, email string) (external_models.User, error) {
-	usItf, err := extReq.SendExternalRequest(request.GetUserReq, external_models.GetUserRequestModel{EmailAddress: email})
+	us, err := extReq.Auth.GetUser(external_models.GetUserRequestModel{EmailAddress: email})
 	if err != nil {
 		return external_models.User{}, err
 	}
 
-	us, ok := usItf.(external_models.User)
-	if !ok {
-		return external_models.User{}, fmt.Errorf("response data format error")
-	}
-
 	if us.ID == 0 {
 		return external_models.User{}, fmt.Errorf("user not found")
 	}
 	return us, nil
 }
 func GetUserWithPhone(extReq request.ExternalRequest, phone string) (external_models.User, error) {
-	usItf, err := extReq.SendExternalRequest(request.GetUserReq, external_models.GetUserRequestModel{PhoneNumber: phone})
+	us, err := extReq.Auth.GetUser(external_models.GetUserRequestModel{PhoneNumber: phone})
 	if err != nil {
 		return external_models.User{}, err
 	}
 
-	us, ok := usItf.(external_models.User)
-	if !ok {
-		return external_models.User{}, fmt.Errorf("response data format error")
-	}
-
 	if us.ID == 0 {
 		return external_models.User{}, fmt.Errorf("user not found")
 	}
@@ -98,7 +102,7 @@ func GetUserWithPhone(extReq request.ExternalRequest, phone string) (external_mo
 }
 
 func getBusinessChargeWithBusinessIDAndCurrency(extReq request.ExternalRequest, businessID int, currency string) (external_models.BusinessCharge, error) {
-	dataInterface, err := extReq.SendExternalRequest(request.GetBusinessCharge, external_models.GetBusinessChargeModel{
+	businessCharge, err := extReq.Auth.GetBusinessCharge(external_models.GetBusinessChargeModel{
 		BusinessID: uint(businessID),
 		Currency:   strings.ToUpper(currency),
 	})
@@ -108,11 +112,6 @@ func getBusinessChargeWithBusinessIDAndCurrency(extReq request.ExternalRequest,
 		return external_models.BusinessCharge{}, err
 	}
 
-	businessCharge, ok := dataInterface.(external_models.BusinessCharge)
-	if !ok {
-		return external_models.BusinessCharge{}, fmt.Errorf("response data format error")
-	}
-
 	if businessCharge.ID == 0 {
 		return external_models.BusinessCharge{}, fmt.Errorf("business charge not found")
 	}
@@ -120,7 +119,7 @@ func getBusinessChargeWithBusinessIDAndCurrency(extReq request.ExternalRequest,
 	return businessCharge, nil
 }
 func initBusinessCharge(extReq request.ExternalRequest, businessID int, currency string) (external_models.BusinessCharge, error) {
-	dataInterface, err := extReq.SendExternalRequest(request.InitBusinessCharge, external_models.InitBusinessChargeModel{
+	businessCharge, err := extReq.Auth.InitBusinessCharge(external_models.InitBusinessChargeModel{
 		BusinessID: uint(businessID),
 		Currency:   strings.ToUpper(currency),
 	})
@@ -130,11 +129,6 @@ func initBusinessCharge(extReq request.ExternalRequest, businessID int, currency
 		return external_models.BusinessCharge{}, err
 	}
 
-	businessCharge, ok := dataInterface.(external_models.BusinessCharge)
-	if !ok {
-		return external_models.BusinessCharge{}, fmt.Errorf("response data format error")
-	}
-
 	if businessCharge.ID == 0 {
 		return external_models.BusinessCharge{}, fmt.Errorf("business charge init failed")
 	}
@@ -143,7 +137,7 @@ func initBusinessCharge(extReq request.ExternalRequest, businessID int, currency
 }
 func GetCountryByNameOrCode(extReq request.ExternalRequest, logger *utility.Logger, NameOrCode string) (external_models.Country, error) {
 
-	countryInterface, err := extReq.SendExternalRequest(request.GetCountry, external_models.GetCountryModel{
+	country, err := extReq.Auth.GetCountry(external_models.GetCountryModel{
 		Name: NameOrCode,
 	})
 
@@ -151,10 +145,6 @@ func GetCountryByNameOrCode(extReq request.ExternalRequest, logger *utility.Logg
 		logger.Error(err.Error())
 		return external_models.Country{}, fmt.Errorf("Your country could not be resolved, please update your profile.")
 	}
-	country, ok := countryInterface.(external_models.Country)
-	if !ok {
-		return external_models.Country{}, fmt.Errorf("response data format error")
-	}
 	if country.ID == 0 {
 		return external_models.Country{}, fmt.Errorf("Your country could not be resolved, please update your profile")
 	}
@@ -163,7 +153,7 @@ func GetCountryByNameOrCode(extReq request.ExternalRequest, logger *utility.Logg
 }
 func getCountryByCurrency(extReq request.ExternalRequest, logger *utility.Logger, currencyCode string) (external_models.Country, error) {
 
-	countryInterface, err := extReq.SendExternalRequest(request.GetCountry, external_models.GetCountryModel{
+	country, err := extReq.Auth.GetCountry(external_models.GetCountryModel{
 		CurrencyCode: currencyCode,
 	})
 
@@ -171,10 +161,6 @@ func getCountryByCurrency(extReq request.ExternalRequest, logger *utility.Logger
 		logger.Error(err.Error())
 		return external_models.Country{}, fmt.Errorf("Your country could not be resolved, please update your profile.")
 	}
-	country, ok := countryInterface.(external_models.Country)
-	if !ok {
-		return external_models.Country{}, fmt.Errorf("response data format error")
-	}
 	if country.ID == 0 {
 		return external_models.Country{}, fmt.Errorf("Your country could not be resolved, please update your profile")
 	}
@@ -183,16 +169,11 @@ func getCountryByCurrency(extReq request.ExternalRequest, logger *utility.Logger
 }
 
 func ListPayment(extReq request.ExternalRequest, transactionID string) (external_models.ListPayment, error) {
-	paymentInterface, err := extReq.SendExternalRequest(request.ListPayment, transactionID)
+	payment, err := extReq.Payment.ListPayment(transactionID)
 	if err != nil {
 		return external_models.ListPayment{}, err
 	}
 
-	payment, ok := paymentInterface.(external_models.ListPayment)
-	if !ok {
-		return external_models.ListPayment{}, fmt.Errorf("response data format error")
-	}
-
 	if payment.ID == 0 {
 		return external_models.ListPayment{}, fmt.Errorf("payment listing failed")
 	}
@@ -200,15 +181,11 @@ func ListPayment(extReq request.ExternalRequest, transactionID string) (external
 }
 
 func SignupUserWithPhone(extReq request.ExternalRequest, phone, accountType string) (external_models.User, error) {
-	usItf, err := extReq.SendExternalRequest(request.SignupUser, external_models.CreateUserRequestModel{PhoneNumber: phone, AccountType: accountType})
+	us, err := extReq.Auth.SignupUser(external_models.CreateUserRequestModel{PhoneNumber: phone, AccountType: accountType})
 	if err != nil {
 		return external_models.User{}, err
 	}
 
-	us, ok := usItf.(external_models.User)
-	if !ok {
-		return external_models.User{}, fmt.Errorf("response data format error")
-	}
 	fmt.Println("user", us)
 	fmt.Println("user.id", us.ID)
 
@@ -218,32 +195,34 @@ func SignupUserWithPhone(extReq request.ExternalRequest, phone, accountType stri
 	return us, nil
 }
 
+// transactionStatuses maps status codes onto the statuses stored on transactions.
+var transactionStatuses = map[string]string{
+	"":        "Draft",
+	"sac":     "Sent - Awaiting Confirmation",
+	"sr":      "Sent - Rejected",
+	"af":      "Accepted - Funded",
+	"anf":     "Accepted - Not Funded",
+	"fr":      "Funded - Rejected",
+	"ip":      "In Progress",
+	"d":       "Delivered",
+	"da":      "Delivered - Accepted",
+	"dr":      "Delivered - Rejected",
+	"cdp":     "Closed - Disbursement Pending",
+	"cmdp":    "Closed - Manual Disbursement Pending",
+	"cdc":     "Closed - Disbursement Complete",
+	"cd":      "Closed - Disputed",
+	"cnf":     "Closed - Not Funded",
+	"closed":  "Closed",
+	"draft":   "Draft",
+	"active":  "Active",
+	"cr":      "Closed - Refunded",
+	"deleted": "Deleted",
+}
+
 func GetTransactionStatus(index string) string {
-	dataMap := map[string]string{
-		"":        "Draft",
-		"sac":     "Sent - Awaiting Confirmation",
-		"sr":      "Sent - Rejected",
-		"af":      "Accepted - Funded",
-		"anf":     "Accepted - Not Funded",
-		"fr":      "Funded - Rejected",
-		"ip":      "In Progress",
-		"d":       "Delivered",
-		"da":      "Delivered - Accepted",
-		"dr":      "Delivered - Rejected",
-		"cdp":     "Closed - Disbursement Pending",
-		"cmdp":    "Closed - Manual Disbursement Pending",
-		"cdc":     "Closed - Disbursement Complete",
-		"cd":      "Closed - Disputed",
-		"cnf":     "Closed - Not Funded",
-		"closed":  "Closed",
-		"draft":   "Draft",
-		"active":  "Active",
-		"cr":      "Closed - Refunded",
-		"deleted": "Deleted",
-	}
-	status := dataMap[strings.ToLower(index)]
+	status := transactionStatuses[strings.ToLower(index)]
 	if status == "" {
-		status = dataMap[""]
+		status = transactionStatuses[""]
 	}
 	return status
 }
@@ -280,21 +259,16 @@ func GetAccessTokenByKeyFromRequest(extReq request.ExternalRequest, c *gin.Conte
 	if key == "" {
 		key = publicKey
 	}
-	acItf, err := extReq.SendExternalRequest(request.GetAccessTokenByKey, key)
+	accessToken, err := extReq.Auth.GetAccessTokenByKey(key)
 	if err != nil {
 		return external_models.AccessToken{}, err
 	}
 
-	accessToken, ok := acItf.(external_models.AccessToken)
-	if !ok {
-		return external_models.AccessToken{}, fmt.Errorf("response data format error")
-	}
-
 	return accessToken, nil
 }
 
-func DebitWallet(extReq request.ExternalRequest, db postgresql.Databases, amount float64, currency string, businessID int, creditEscrow string, creditMor string, transactionID string) (external_models.WalletBalance, error) {
-	walletItf, err := extReq.SendExternalRequest(request.DebitWallet, external_models.DebitWalletRequest{
+func DebitWallet(extReq request.ExternalRequest, amount float64, currency string, businessID int, creditEscrow string, creditMor string, transactionID string) (external_models.WalletBalance, error) {
+	walletBalance, err := extReq.Payment.DebitWallet(external_models.DebitWalletRequest{
 		Amount:        amount,
 		Currency:      currency,
 		BusinessID:    businessID,
@@ -306,16 +280,11 @@ func DebitWallet(extReq request.ExternalRequest, db postgresql.Databases, amount
 		return external_models.WalletBalance{}, err
 	}
 
-	walletBalance, ok := walletItf.(external_models.WalletBalance)
-	if !ok {
-		return external_models.WalletBalance{}, fmt.Errorf("response data format error")
-	}
-
 	return walletBalance, nil
 }
 
-func CreditWallet(extReq request.ExternalRequest, db postgresql.Databases, amount float64, currency string, businessID int, isRefund bool, creditEscrow string, creditMor string, transactionID string) (external_models.WalletBalance, error) {
-	walletItf, err := extReq.SendExternalRequest(request.CreditWallet, external_models.CreditWalletRequest{
+func CreditWallet(extReq request.ExternalRequest, amount float64, currency string, businessID int, isRefund bool, creditEscrow string, creditMor string, transactionID string) (external_models.WalletBalance, error) {
+	walletBalance, err := extReq.Payment.CreditWallet(external_models.CreditWalletRequest{
 		Amount:        amount,
 		Currency:      currency,
 		BusinessID:    businessID,
@@ -328,10 +297,5 @@ func CreditWallet(extReq request.ExternalRequest, db postgresql.Databases, amoun
 		return external_models.WalletBalance{}, err
 	}
 
-	walletBalance, ok := walletItf.(external_models.WalletBalance)
-	if !ok {
-		return external_models.WalletBalance{}, fmt.Errorf("response data format error")
-	}
-
 	return walletBalance, nil
 }
@@ -10,12 +10,13 @@ import (
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func CreateTransactionService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.CreateTransactionRequest, user external_models.User) (models.TransactionCreateResponse, int, error) {
+func CreateTransactionService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.CreateTransactionRequest, principal models.Principal) (models.TransactionCreateResponse, int, error) {
 	var (
+		user                      = *principal.User
 		transaction               = models.Transaction{}
 		businessID                = req.BusinessID
 		businessCharge            = external_models.BusinessCharge{}
@@ -62,6 +63,9 @@ func CreateTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 	if err := validatePartiesAndMilestones(transactionType, req.Parties, req.Milestones); err != nil {
 		return models.TransactionCreateResponse{}, http.StatusBadRequest, err
 	}
+	if req.Broker != nil && !hasPartyWithRole(req.Parties, "broker") {
+		return models.TransactionCreateResponse{}, http.StatusBadRequest, fmt.Errorf("a party with the broker role is required to set a broker charge")
+	}
 
 	gracePeriod, err := validateDueDate(req.GracePeriod)
 	if err != nil {
@@ -81,12 +85,12 @@ func CreateTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 	transactionCountry := businessCharge.Country
 	transactionStatus := GetTransactionStatus("draft")
 
-	transactionFiles, err := resolveTransactionFiles(req.Files, transactionID, businessID, db)
+	transactionFiles, err := resolveTransactionFiles(req.Files, transactionID, businessID, repo)
 	if err != nil {
 		return models.TransactionCreateResponse{}, http.StatusInternalServerError, err
 	}
 
-	_, partiesResponse, err := resolveParties(extReq, req.Parties, transactionPartiesID, transactionID, db)
+	_, partiesResponse, err := resolveParties(extReq, req.Parties, transactionPartiesID, transactionID, repo)
 	if err != nil {
 		return models.TransactionCreateResponse{}, http.StatusInternalServerError, err
 	}
@@ -108,19 +112,25 @@ func CreateTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 		DisputeHandler:       transactionDisputeHandler,
 		EscrowWallet:         req.EscrowWallet,
 	}
-	escrowCharge := getEscrowCharge(businessCharge, totalMilestonesAmount)
+	escrowFee, err := getEscrowFee(repo, businessCharge, businessID, transactionCurrency, transactionType, totalMilestonesAmount)
+	if err != nil {
+		return models.TransactionCreateResponse{}, http.StatusInternalServerError, err
+	}
 	if transactionSource == "transfer" {
-		escrowCharge = 2
+		escrowFee = models.EscrowFee{Charge: 2, ChargeBearer: models.ChargeBearerBuyer}
+		escrowFee.Split(0)
 	}
+	escrowCharge := escrowFee.Charge
+	transactionObj.EscrowFee = escrowFee
 
 	switch transactionType {
 	case "oneoff":
-		transaction, mileStoneResponse, err = resolveCreateOneOffTransaction(extReq, req.Milestones, transactionAmount, escrowCharge, transactionObj, db)
+		transaction, mileStoneResponse, err = resolveCreateOneOffTransaction(extReq, req.Milestones, transactionAmount, escrowCharge, transactionObj, repo)
 		if err != nil {
 			return models.TransactionCreateResponse{}, http.StatusInternalServerError, err
 		}
 	case "milestone":
-		transaction, mileStoneResponse, err = resolveCreateMilestoneTransaction(extReq, req.Milestones, transactionAmount, escrowCharge, transactionObj, db)
+		transaction, mileStoneResponse, err = resolveCreateMilestoneTransaction(extReq, req.Milestones, transactionAmount, escrowCharge, transactionObj, repo)
 		if err != nil {
 			return models.TransactionCreateResponse{}, http.StatusInternalServerError, err
 		}
@@ -129,21 +139,43 @@ func CreateTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 
 	}
 
+	transactionBroker := models.TransactionBroker{}
+	if req.Broker != nil {
+		transactionBroker = models.TransactionBroker{
+			TransactionBrokerID: utility.RandomString(20),
+			TransactionID:       transactionID,
+			BrokerCharge:        req.Broker.BrokerCharge,
+			BrokerChargeBearer:  req.Broker.BrokerChargeBearer,
+			BrokerChargeType:    req.Broker.BrokerChargeType,
+		}
+		err = repo.Brokers.Create(&transactionBroker)
+		if err != nil {
+			return models.TransactionCreateResponse{}, http.StatusInternalServerError, err
+		}
+		transaction.BrokerID = transactionBroker.TransactionBrokerID
+	}
+
 	transaction.IsPaylinked = transactionPaylinked
 	transaction.Source = transactionSource
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return models.TransactionCreateResponse{}, http.StatusInternalServerError, err
+		return models.TransactionCreateResponse{}, updateErrorCode(err), err
+	}
+
+	// the buyer is only charged for a broker both parties have accepted
+	var buyerBrokerCharge float64
+	if brokerChargeAccepted(transactionBroker) {
+		buyerBrokerCharge = getBuyerBrokerCharge(transactionBroker, totalMilestonesAmount)
 	}
 
 	createPaymentPayload := external_models.CreatePaymentRequestWithToken{
 		TransactionID: transactionID,
 		TotalAmount:   transactionAmount,
 		ShippingFee:   transactionShippingFee,
-		BrokerCharge:  0,
-		EscrowCharge:  escrowCharge,
+		BrokerCharge:  buyerBrokerCharge,
+		EscrowCharge:  escrowFee.BuyerCharge,
 		Currency:      transactionCurrency,
-		Token:         models.Token,
+		Token:         principal.Token,
 	}
 
 	_, err = CreatePayment(extReq, createPaymentPayload)
@@ -152,13 +184,25 @@ func CreateTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 	}
 
 	activityLog := models.ActivityLog{
-		TransactionID: transactionID,
-		Description:   "Transaction details have been sent to all invited parties",
-	}
-	err = activityLog.CreateActivityLog(db.Transaction)
+		TransactionID:  transactionID,
+		MilestoneID:    transaction.MilestoneID,
+		EventType:      models.ActivityTransactionCreated,
+		ActorAccountID: int(user.AccountID),
+		ActorRole:      resolveActorRole(repo, transactionID, int(user.AccountID)),
+		NewStatus:      transactionStatus,
+		Metadata: map[string]interface{}{
+			"amount":   transactionAmount,
+			"currency": transactionCurrency,
+			"type":     transactionType,
+			"source":   transactionSource,
+		},
+		Description: "Transaction details have been sent to all invited parties",
+	}
+	err = repo.ActivityLogs.Create(&activityLog)
 	if err != nil {
 		return models.TransactionCreateResponse{}, http.StatusInternalServerError, err
 	}
+	recordTransactionCreated(transaction)
 
 	var rRrecipients []models.MileStoneRecipient
 	json.Unmarshal([]byte(transaction.Recipients), &rRrecipients)
@@ -193,16 +237,13 @@ func CreateTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 	}, http.StatusOK, nil
 }
 
-func CheckTransactionAmountService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string) (string, int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: transactionID}
-	)
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+func CheckTransactionAmountService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string) (string, int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionID(transactionID)
 	if err != nil {
 		return "", code, err
 	}
 
-	transactionResponse, code, err := ListTransactionsByIDService(extReq, logger, db, transactionID)
+	transactionResponse, code, err := ListTransactionsByIDService(extReq, logger, repo, transactionID)
 	if err != nil {
 		return "", code, err
 	}
@@ -221,22 +262,24 @@ func CheckTransactionAmountService(extReq request.ExternalRequest, logger *utili
 
 }
 
-func getEscrowCharge(businessCharge external_models.BusinessCharge, totalAmountForMilestones float64) float64 {
+// getEscrowCharge works out the escrow charge from the charge configured for the business on the auth
+// service. It is used for businesses without a local fee schedule.
+func getEscrowCharge(businessCharge external_models.BusinessCharge, totalAmountForMilestones float64) (float64, error) {
 	var (
 		charge float64 = 0
 	)
 
-	bCharge, err := strconv.ParseFloat(businessCharge.BusinessCharge, 64)
+	bCharge, err := parseChargeString(businessCharge.BusinessCharge)
 	if err != nil {
-		bCharge = 0
+		return 0, fmt.Errorf("invalid business charge %q for business %v", businessCharge.BusinessCharge, businessCharge.BusinessId)
 	}
-	vCharge, err := strconv.ParseFloat(businessCharge.VesicashCharge, 64)
+	vCharge, err := parseChargeString(businessCharge.VesicashCharge)
 	if err != nil {
-		vCharge = 0
+		return 0, fmt.Errorf("invalid vesicash charge %q for business %v", businessCharge.VesicashCharge, businessCharge.BusinessId)
 	}
-	processingFee, err := strconv.ParseFloat(businessCharge.ProcessingFee, 64)
+	processingFee, err := parseChargeString(businessCharge.ProcessingFee)
 	if err != nil {
-		processingFee = 0
+		return 0, fmt.Errorf("invalid processing fee %q for business %v", businessCharge.ProcessingFee, businessCharge.BusinessId)
 	}
 
 	if businessCharge.ChargeMin != nil && businessCharge.ChargeMid != nil && businessCharge.ChargeMax != nil {
@@ -266,10 +309,19 @@ func getEscrowCharge(businessCharge external_models.BusinessCharge, totalAmountF
 	} else {
 		charge = utility.PercentageOf(totalAmountForMilestones, bCharge+vCharge) + processingFee
 	}
-	return charge
+	return charge, nil
+}
+
+// parseChargeString treats an unset charge as zero but rejects values that are set and not a number.
+func parseChargeString(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
 }
 
-func resolveCreateOneOffTransaction(extReq request.ExternalRequest, milestones []models.MileStone, transactionAmount, escrowCharge float64, transactionObj models.ResolveTransactionObj, db postgresql.Databases) (models.Transaction, []models.MilestonesResponse, error) {
+func resolveCreateOneOffTransaction(extReq request.ExternalRequest, milestones []models.MileStone, transactionAmount, escrowCharge float64, transactionObj models.ResolveTransactionObj, repo repository.Repositories) (models.Transaction, []models.MilestonesResponse, error) {
 	var (
 		// escrowCharge       = transactionAmount - getTotalAmoutForMilestones(milestones)
 		milestonesResponse = []models.MilestonesResponse{}
@@ -286,30 +338,33 @@ func resolveCreateOneOffTransaction(extReq request.ExternalRequest, milestones [
 			return transactionM, milestonesResponse, err
 		}
 		transaction := models.Transaction{
-			TransactionID:    transactionObj.TransactionID,
-			PartiesID:        transactionObj.TransactionPartiesID,
-			Title:            transactionObj.Title + ";" + m.Title + ";" + strconv.Itoa(int(transactionObj.Amount)) + ";" + strconv.Itoa(i+1),
-			Type:             transactionObj.Type,
-			Description:      transactionObj.Description,
-			MilestoneID:      milestoneID,
-			Amount:           transactionObj.Amount,
-			Status:           m.Status,
-			Quantity:         transactionObj.Quantity,
-			InspectionPeriod: strconv.Itoa(m.InspectionPeriod),
-			DueDate:          dueDate,
-			ShippingFee:      transactionObj.ShippingFee,
-			GracePeriod:      transactionObj.GracePeriod,
-			Currency:         transactionObj.Currency,
-			Country:          transactionObj.Country,
-			BusinessID:       transactionObj.BusinessID,
-			EscrowCharge:     escrowCharge,
-			TransUssdCode:    transUssdCode,
-			Recipients:       string(recipientsJson),
-			DisputeHandler:   transactionObj.DisputeHandler,
-			EscrowWallet:     transactionObj.EscrowWallet,
+			TransactionID:      transactionObj.TransactionID,
+			PartiesID:          transactionObj.TransactionPartiesID,
+			Title:              transactionObj.Title + ";" + m.Title + ";" + strconv.Itoa(int(transactionObj.Amount)) + ";" + strconv.Itoa(i+1),
+			Type:               transactionObj.Type,
+			Description:        transactionObj.Description,
+			MilestoneID:        milestoneID,
+			Amount:             transactionObj.Amount,
+			Status:             m.Status,
+			Quantity:           transactionObj.Quantity,
+			InspectionPeriod:   strconv.Itoa(m.InspectionPeriod),
+			DueDate:            dueDate,
+			ShippingFee:        transactionObj.ShippingFee,
+			GracePeriod:        transactionObj.GracePeriod,
+			Currency:           transactionObj.Currency,
+			Country:            transactionObj.Country,
+			BusinessID:         transactionObj.BusinessID,
+			EscrowCharge:       escrowCharge,
+			SellerEscrowCharge: transactionObj.EscrowFee.SellerCharge,
+			FeeScheduleID:      transactionObj.EscrowFee.FeeScheduleID,
+			FeeScheduleVersion: transactionObj.EscrowFee.Version,
+			TransUssdCode:      transUssdCode,
+			Recipients:         string(recipientsJson),
+			DisputeHandler:     transactionObj.DisputeHandler,
+			EscrowWallet:       transactionObj.EscrowWallet,
 		}
 
-		err = transaction.CreateTransaction(db.Transaction)
+		err = repo.Transactions.Create(&transaction)
 		if err != nil {
 			return transactionM, milestonesResponse, err
 		} else {
@@ -351,7 +406,7 @@ func resolveCreateOneOffTransaction(extReq request.ExternalRequest, milestones [
 	return transactionM, milestonesResponse, nil
 }
 
-func resolveCreateMilestoneTransaction(extReq request.ExternalRequest, milestones []models.MileStone, transactionAmount, escrowCharge float64, transactionObj models.ResolveTransactionObj, db postgresql.Databases) (models.Transaction, []models.MilestonesResponse, error) {
+func resolveCreateMilestoneTransaction(extReq request.ExternalRequest, milestones []models.MileStone, transactionAmount, escrowCharge float64, transactionObj models.ResolveTransactionObj, repo repository.Repositories) (models.Transaction, []models.MilestonesResponse, error) {
 	var (
 		// escrowCharge       = transactionAmount - getTotalAmoutForMilestones(milestones)
 		milestonesResponse = []models.MilestonesResponse{}
@@ -382,30 +437,33 @@ func resolveCreateMilestoneTransaction(extReq request.ExternalRequest, milestone
 		}
 
 		transaction := models.Transaction{
-			TransactionID:    transactionObj.TransactionID,
-			PartiesID:        transactionObj.TransactionPartiesID,
-			Title:            transactionObj.Title + ";" + m.Title + ";" + strconv.Itoa(int(transactionObj.Amount)) + ";" + strconv.Itoa(i+1),
-			Type:             transactionObj.Type,
-			Description:      description,
-			MilestoneID:      milestoneID,
-			Amount:           m.Amount,
-			Status:           m.Status,
-			Quantity:         quantity,
-			InspectionPeriod: strconv.Itoa(m.InspectionPeriod),
-			DueDate:          dueDate,
-			ShippingFee:      m.ShippingFee,
-			GracePeriod:      gracePeriod,
-			Currency:         transactionObj.Currency,
-			Country:          transactionObj.Country,
-			BusinessID:       transactionObj.BusinessID,
-			EscrowCharge:     escrowCharge,
-			TransUssdCode:    transUssdCode,
-			Recipients:       string(recipientsJson),
-			DisputeHandler:   transactionObj.DisputeHandler,
-			EscrowWallet:     transactionObj.EscrowWallet,
+			TransactionID:      transactionObj.TransactionID,
+			PartiesID:          transactionObj.TransactionPartiesID,
+			Title:              transactionObj.Title + ";" + m.Title + ";" + strconv.Itoa(int(transactionObj.Amount)) + ";" + strconv.Itoa(i+1),
+			Type:               transactionObj.Type,
+			Description:        description,
+			MilestoneID:        milestoneID,
+			Amount:             m.Amount,
+			Status:             m.Status,
+			Quantity:           quantity,
+			InspectionPeriod:   strconv.Itoa(m.InspectionPeriod),
+			DueDate:            dueDate,
+			ShippingFee:        m.ShippingFee,
+			GracePeriod:        gracePeriod,
+			Currency:           transactionObj.Currency,
+			Country:            transactionObj.Country,
+			BusinessID:         transactionObj.BusinessID,
+			EscrowCharge:       escrowCharge,
+			SellerEscrowCharge: transactionObj.EscrowFee.SellerCharge,
+			FeeScheduleID:      transactionObj.EscrowFee.FeeScheduleID,
+			FeeScheduleVersion: transactionObj.EscrowFee.Version,
+			TransUssdCode:      transUssdCode,
+			Recipients:         string(recipientsJson),
+			DisputeHandler:     transactionObj.DisputeHandler,
+			EscrowWallet:       transactionObj.EscrowWallet,
 		}
 
-		err = transaction.CreateTransaction(db.Transaction)
+		err = repo.Transactions.Create(&transaction)
 		if err != nil {
 			return transactionM, milestonesResponse, err
 		} else {
@@ -461,7 +519,7 @@ func getTotalAmoutForMilestones(milestones []models.MileStone) float64 {
 	return totalAmount
 }
 
-func resolveParties(extReq request.ExternalRequest, parties []models.Party, transactionPartiesID, transactionID string, db postgresql.Databases) ([]models.TransactionParty, []models.PartyResponse, error) {
+func resolveParties(extReq request.ExternalRequest, parties []models.Party, transactionPartiesID, transactionID string, repo repository.Repositories) ([]models.TransactionParty, []models.PartyResponse, error) {
 	var (
 		transactionParties = []models.TransactionParty{}
 		partiesResponse    = []models.PartyResponse{}
@@ -492,7 +550,7 @@ func resolveParties(extReq request.ExternalRequest, parties []models.Party, tran
 			RoleCapabilities:     roleCapabilities,
 		}
 
-		err = transactionParty.CreateTransactionParty(db.Transaction)
+		err = repo.Parties.Create(&transactionParty)
 		if err != nil {
 			return transactionParties, partiesResponse, err
 		} else {
@@ -513,7 +571,7 @@ func resolveParties(extReq request.ExternalRequest, parties []models.Party, tran
 	return transactionParties, partiesResponse, nil
 }
 
-func resolveTransactionFiles(files []models.File, transactionID string, accountID int, db postgresql.Databases) ([]models.TransactionFile, error) {
+func resolveTransactionFiles(files []models.File, transactionID string, accountID int, repo repository.Repositories) ([]models.TransactionFile, error) {
 	var (
 		transactionFiles = []models.TransactionFile{}
 	)
@@ -523,7 +581,7 @@ func resolveTransactionFiles(files []models.File, transactionID string, accountI
 			AccountID:     accountID,
 			FileUrl:       f.URL,
 		}
-		err := transactionFile.CreateTransactionFile(db.Transaction)
+		err := repo.Files.Create(&transactionFile)
 		if err != nil {
 			return transactionFiles, err
 		} else {
@@ -588,6 +646,15 @@ func validatePartiesAndMilestones(tType string, parties []models.Party, mileston
 	return nil
 }
 
+func hasPartyWithRole(parties []models.Party, role string) bool {
+	for _, p := range parties {
+		if strings.EqualFold(p.Role, role) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateDueDate(dateString string) (string, error) {
 	if dateString != "" {
 		dateString, err := utility.FormatDate(dateString, "2006-01-02", "2006-01-02")
@@ -599,16 +666,13 @@ func validateDueDate(dateString string) (string, error) {
 	return dateString, nil
 }
 
-func UpdateTransactionAmountPaidService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.UpdateTransactionAmountPaid) (models.Transaction, int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: req.TransactionID}
-	)
-
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+func UpdateTransactionAmountPaidService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.UpdateTransactionAmountPaid) (models.Transaction, int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionID(req.TransactionID)
 	if err != nil {
 		return models.Transaction{}, code, err
 	}
 
+	previousAmountPaid := transaction.AmountPaid
 	if req.Action == "+" {
 		transaction.AmountPaid += req.Amount
 	} else if req.Action == "-" {
@@ -619,9 +683,12 @@ func UpdateTransactionAmountPaidService(extReq request.ExternalRequest, logger *
 		}
 	}
 
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return transaction, http.StatusInternalServerError, err
+		return transaction, updateErrorCode(err), err
+	}
+	if req.Action == "+" {
+		recordPayment(transaction, previousAmountPaid, req.Amount)
 	}
 
 	return transaction, http.StatusOK, nil
@@ -2,10 +2,10 @@ package transactions
 
 import (
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 )
 
-func CreateTransactionState(db postgresql.Databases, status, transactionID, mileStoneID string, AccountID int) (models.TransactionState, error) {
+func CreateTransactionState(repo repository.Repositories, status, transactionID, mileStoneID string, AccountID int) (models.TransactionState, error) {
 	var (
 		transactionState = models.TransactionState{
 			AccountID:     int64(AccountID),
@@ -15,7 +15,7 @@ func CreateTransactionState(db postgresql.Databases, status, transactionID, mile
 		}
 	)
 
-	err := transactionState.CreateTransactionState(db.Transaction)
+	err := repo.States.Create(&transactionState)
 	if err != nil {
 		return transactionState, err
 	}
@@ -7,80 +7,70 @@ import (
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func TransactionDeliveredService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.TransactionDeliveredRequest, user external_models.User) (int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: req.TransactionID, MilestoneID: req.MilestoneID}
-	)
+func TransactionDeliveredService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.TransactionDeliveredRequest, user external_models.User) (int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionIDAndMilestoneID(req.TransactionID, req.MilestoneID)
+	if err != nil {
+		return code, err
+	}
 
-	code, err := transaction.GetTransactionByTransactionIDAndMilestoneID(db.Transaction)
+	code, err = AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), ActionDeliver)
 	if err != nil {
 		return code, err
 	}
 
-	transaction.Status = GetTransactionStatus("d")
-	err = transaction.UpdateAllFields(db.Transaction)
+	code, err = moveTransactionStatus(repo, &transaction, "d")
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return code, err
 	}
 
-	_, err = CreateTransactionState(db, "d", req.TransactionID, transaction.MilestoneID, int(user.AccountID))
+	_, err = CreateTransactionState(repo, "d", req.TransactionID, transaction.MilestoneID, int(user.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
-	extReq.SendExternalRequest(request.SendTransactionDeliveredNotification, external_models.TransactionIDRequestModel{
+	extReq.Notification.SendTransactionDeliveredNotification(external_models.TransactionIDRequestModel{
 		TransactionId: req.TransactionID,
 	})
 
 	return http.StatusOK, nil
 }
 
-func SatisfiedService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string, user external_models.User) (int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: transactionID}
-	)
-
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+func SatisfiedService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string, user external_models.User) (int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionID(transactionID)
 	if err != nil {
 		return code, err
 	}
 
-	buyerParty := models.TransactionParty{TransactionID: transactionID, Role: "buyer"}
-	code, err = buyerParty.GetTransactionPartyByTransactionIDAndRole(db.Transaction)
+	code, err = AuthorizePartyAction(repo, transactionID, int(user.AccountID), ActionApproveRelease)
 	if err != nil {
-		return code, fmt.Errorf("buyer not found: %v", err.Error())
-	}
-
-	if user.AccountID != uint(buyerParty.AccountID) {
-		return http.StatusBadRequest, fmt.Errorf("you cannot make this request as you are not the buyer")
+		return code, err
 	}
 
-	transaction.Status = GetTransactionStatus("da")
-	err = transaction.UpdateAllFields(db.Transaction)
+	code, err = moveTransactionStatus(repo, &transaction, "da")
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return code, err
 	}
 
-	_, err = CreateTransactionState(db, "da", transactionID, transaction.MilestoneID, int(user.AccountID))
+	_, err = CreateTransactionState(repo, "da", transactionID, transaction.MilestoneID, int(user.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
-	extReq.SendExternalRequest(request.SendTransactionDeliveredAcceptedNotification, external_models.TransactionIDRequestModel{
+	extReq.Notification.SendTransactionDeliveredAcceptedNotification(external_models.TransactionIDRequestModel{
 		TransactionId: transactionID,
 	})
 
-	transaction.Status = GetTransactionStatus("cdp")
-	err = transaction.UpdateAllFields(db.Transaction)
+	code, err = moveTransactionStatus(repo, &transaction, "cdp")
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return code, err
 	}
+	RecordStatusChange(transaction, GetTransactionStatus("da"))
 
-	_, err = CreateTransactionState(db, "cdp", transactionID, transaction.MilestoneID, int(user.AccountID))
+	_, err = CreateTransactionState(repo, "cdp", transactionID, transaction.MilestoneID, int(user.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -88,44 +78,38 @@ func SatisfiedService(extReq request.ExternalRequest, logger *utility.Logger, db
 	return http.StatusOK, nil
 }
 
-func SatisfiedApiService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string) (int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: transactionID}
-	)
-
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+func SatisfiedApiService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string) (int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionID(transactionID)
 	if err != nil {
 		return code, err
 	}
 
-	buyerParty := models.TransactionParty{TransactionID: transactionID, Role: "buyer"}
-	code, err = buyerParty.GetTransactionPartyByTransactionIDAndRole(db.Transaction)
+	buyerParty, code, err := repo.Parties.GetByTransactionIDAndRole(transactionID, "buyer")
 	if err != nil {
 		return code, fmt.Errorf("buyer not found: %v", err.Error())
 	}
 
-	transaction.Status = GetTransactionStatus("da")
-	err = transaction.UpdateAllFields(db.Transaction)
+	code, err = moveTransactionStatus(repo, &transaction, "da")
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return code, err
 	}
 
-	_, err = CreateTransactionState(db, "da", transactionID, transaction.MilestoneID, int(buyerParty.AccountID))
+	_, err = CreateTransactionState(repo, "da", transactionID, transaction.MilestoneID, int(buyerParty.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
-	extReq.SendExternalRequest(request.SendTransactionDeliveredAcceptedNotification, external_models.TransactionIDRequestModel{
+	extReq.Notification.SendTransactionDeliveredAcceptedNotification(external_models.TransactionIDRequestModel{
 		TransactionId: transactionID,
 	})
 
-	transaction.Status = GetTransactionStatus("cdp")
-	err = transaction.UpdateAllFields(db.Transaction)
+	code, err = moveTransactionStatus(repo, &transaction, "cdp")
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return code, err
 	}
+	RecordStatusChange(transaction, GetTransactionStatus("da"))
 
-	_, err = CreateTransactionState(db, "cdp", transactionID, transaction.MilestoneID, int(buyerParty.AccountID))
+	_, err = CreateTransactionState(repo, "cdp", transactionID, transaction.MilestoneID, int(buyerParty.AccountID))
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -7,16 +7,18 @@ import (
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func CreateDisputeService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.CreateDisputeRequest, user external_models.User) (int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: req.TransactionID}
-	)
+func CreateDisputeService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.CreateDisputeRequest, user external_models.User) (int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionID(req.TransactionID)
+	if err != nil {
+		return code, err
+	}
 
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+	code, err = AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), ActionDispute)
 	if err != nil {
 		return code, err
 	}
@@ -27,30 +29,34 @@ func CreateDisputeService(extReq request.ExternalRequest, logger *utility.Logger
 		DisputeStatus: req.DisputeStatus,
 		Decision:      req.Decision,
 	}
-	err = transactionDispute.CreateTransactionDispute(db.Transaction)
+	err = repo.Disputes.Create(&transactionDispute)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
+	previousStatus := transaction.Status
 	transaction.Status = GetTransactionStatus("cd")
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
+	disputesOpened.Inc()
+	RecordStatusChange(transaction, previousStatus)
 
-	extReq.SendExternalRequest(request.SendDisputeOpenedNotification, external_models.TransactionIDAccountIDRequestModel{
+	extReq.Notification.SendDisputeOpenedNotification(external_models.TransactionIDAccountIDRequestModel{
 		TransactionId: req.TransactionID,
 		AccountId:     user.AccountID,
 	})
 	return http.StatusOK, nil
 }
 
-func UpdateDisputeService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.CreateDisputeRequest, user external_models.User) (int, error) {
-	var (
-		transactionDispute = models.TransactionDispute{TransactionID: req.TransactionID}
-	)
+func UpdateDisputeService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.CreateDisputeRequest, user external_models.User) (int, error) {
+	code, err := AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), ActionDispute)
+	if err != nil {
+		return code, err
+	}
 
-	code, err := transactionDispute.GetTransactionDisputeByTransactionID(db.Transaction)
+	transactionDispute, code, err := repo.Disputes.GetByTransactionID(req.TransactionID)
 	if err != nil {
 		return code, err
 	}
@@ -61,28 +67,32 @@ func UpdateDisputeService(extReq request.ExternalRequest, logger *utility.Logger
 	if req.DisputeStatus != "" {
 		transactionDispute.DisputeStatus = req.DisputeStatus
 	}
+	resolved := transactionDispute.Decision == "" && req.Decision != ""
 	if req.Decision != "" {
 		transactionDispute.Decision = req.Decision
 	}
-	err = transactionDispute.UpdateAllFields(db.Transaction)
+	err = repo.Disputes.Update(&transactionDispute)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
+	}
+	if resolved {
+		disputesResolved.Inc()
 	}
 	return http.StatusOK, nil
 }
 
-func GetDisputeByTransactionIDService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string, user external_models.User) (*models.TransactionDispute, int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: transactionID}
-	)
+func GetDisputeByTransactionIDService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string, user external_models.User) (*models.TransactionDispute, int, error) {
+	_, code, err := repo.Transactions.GetByTransactionID(transactionID)
+	if err != nil {
+		return nil, code, err
+	}
 
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+	code, err = AuthorizePartyAction(repo, transactionID, int(user.AccountID), ActionView)
 	if err != nil {
 		return nil, code, err
 	}
 
-	transactionDispute := models.TransactionDispute{TransactionID: transactionID}
-	code, err = transactionDispute.GetTransactionDisputeByTransactionID(db.Transaction)
+	transactionDispute, code, err := repo.Disputes.GetByTransactionID(transactionID)
 	if err != nil {
 		if code == http.StatusInternalServerError {
 			return nil, code, err
@@ -93,20 +103,18 @@ func GetDisputeByTransactionIDService(extReq request.ExternalRequest, logger *ut
 	return &transactionDispute, http.StatusOK, nil
 }
 
-func GetDisputeByUserService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, user external_models.User, paginator postgresql.Pagination) ([]models.TransactionDispute, postgresql.PaginationResponse, int, error) {
+func GetDisputeByUserService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, user external_models.User, paginator postgresql.Pagination) ([]models.TransactionDispute, postgresql.PaginationResponse, int, error) {
 	var (
-		disputes         = []models.TransactionDispute{}
-		transactionParty = models.TransactionParty{AccountID: int(user.AccountID)}
+		disputes = []models.TransactionDispute{}
 	)
 
-	transactionParties, pagination, err := transactionParty.GetAllByAndQueriesForUniqueValueForDispute(db.Transaction, "", "id", "desc", "transaction_id", paginator)
+	transactionParties, pagination, err := repo.Parties.ListDisputedByAccountID(int(user.AccountID), paginator)
 	if err != nil {
 		return []models.TransactionDispute{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
 	}
 
 	for _, t := range transactionParties {
-		transactionDispute := models.TransactionDispute{TransactionID: t.TransactionID}
-		code, err := transactionDispute.GetTransactionDisputeByTransactionID(db.Transaction)
+		transactionDispute, code, err := repo.Disputes.GetByTransactionID(t.TransactionID)
 		if err != nil {
 			if code == http.StatusInternalServerError {
 				return []models.TransactionDispute{}, pagination, code, err
@@ -8,41 +8,32 @@ import (
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func RequestDueDateExtensionService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.DueDateExtensionRequest, user external_models.User) (int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: req.TransactionID}
-	)
-
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+func RequestDueDateExtensionService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.DueDateExtensionRequest, user external_models.User) (int, error) {
+	_, code, err := repo.Transactions.GetByTransactionID(req.TransactionID)
 	if err != nil {
 		return code, err
 	}
 
-	sellerParty := models.TransactionParty{TransactionID: req.TransactionID, Role: "seller"}
-	code, err = sellerParty.GetTransactionPartyByTransactionIDAndRole(db.Transaction)
+	code, err = AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), ActionRequestExtension)
 	if err != nil {
-		return code, fmt.Errorf("seller not found: %v", err.Error())
-	}
-
-	if user.AccountID != uint(sellerParty.AccountID) {
-		return http.StatusBadRequest, fmt.Errorf("you cannot make this request as you are not the seller")
+		return code, err
 	}
 
 	dueDateExtension := models.TransactionDueDateExtensionRequest{
-		AccountID:     int64(sellerParty.AccountID),
+		AccountID:     int64(user.AccountID),
 		TransactionID: req.TransactionID,
 		Note:          req.Note,
 	}
-	err = dueDateExtension.CreateTransactionDueDateExtensionRequest(db.Transaction)
+	err = repo.DueDateExtensions.Create(&dueDateExtension)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
-	extReq.SendExternalRequest(request.SendDueDateProposalNotification, external_models.DueDateExtensionProposalRequestModel{
+	extReq.Notification.SendDueDateProposalNotification(external_models.DueDateExtensionProposalRequestModel{
 		TransactionId: req.TransactionID,
 		Note:          req.Note,
 	})
@@ -50,24 +41,15 @@ func RequestDueDateExtensionService(extReq request.ExternalRequest, logger *util
 	return http.StatusOK, nil
 }
 
-func ApproveDueDateExtensionService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.ApproveDueDateExtensionRequest, user external_models.User) (int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: req.TransactionID, MilestoneID: req.MilestoneID}
-	)
-
-	code, err := transaction.GetTransactionByTransactionIDAndMilestoneID(db.Transaction)
+func ApproveDueDateExtensionService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.ApproveDueDateExtensionRequest, user external_models.User) (int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionIDAndMilestoneID(req.TransactionID, req.MilestoneID)
 	if err != nil {
 		return code, err
 	}
 
-	buyerParty := models.TransactionParty{TransactionID: req.TransactionID, Role: "buyer"}
-	code, err = buyerParty.GetTransactionPartyByTransactionIDAndRole(db.Transaction)
+	code, err = AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), ActionApproveExtension)
 	if err != nil {
-		return code, fmt.Errorf("buyer not found: %v", err.Error())
-	}
-
-	if user.AccountID != uint(buyerParty.AccountID) {
-		return http.StatusBadRequest, fmt.Errorf("you cannot make this request as you are not the buyer")
+		return code, err
 	}
 
 	dueDate, err := validateDueDate(req.DueDate)
@@ -78,12 +60,12 @@ func ApproveDueDateExtensionService(extReq request.ExternalRequest, logger *util
 
 	transaction.DueDate = newDueDate
 	transaction.InspectionPeriod = strconv.Itoa(req.InspectionPeriod)
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
 
-	extReq.SendExternalRequest(request.SendDueDateExtendedNotification, external_models.TransactionIDRequestModel{
+	extReq.Notification.SendDueDateExtendedNotification(external_models.TransactionIDRequestModel{
 		TransactionId: req.TransactionID,
 	})
 
@@ -5,24 +5,27 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/internal/config"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func EditTransactionService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.EditTransactionRequest, user external_models.User) (models.Transaction, int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: req.TransactionID}
-	)
-
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+func EditTransactionService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.EditTransactionRequest, user external_models.User) (models.Transaction, int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionID(req.TransactionID)
 	if err != nil {
 		return transaction, code, err
 	}
 
+	code, err = AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), ActionEdit)
+	if err != nil {
+		return models.Transaction{}, code, err
+	}
+
 	if req.Title != "" {
 		titleSlice := strings.Split(transaction.Title, ";")
 		if len(titleSlice) > 0 {
@@ -72,20 +75,16 @@ func EditTransactionService(extReq request.ExternalRequest, logger *utility.Logg
 		transaction.GracePeriod = transactionGracePeriod
 	}
 
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return transaction, http.StatusInternalServerError, err
+		return transaction, updateErrorCode(err), err
 	}
 
 	return transaction, http.StatusOK, nil
 }
 
-func DeleteTransactionService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string, user external_models.User) (int, error) {
-	var (
-		transaction = models.Transaction{TransactionID: transactionID}
-	)
-
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+func DeleteTransactionService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string, user external_models.User) (int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionID(transactionID)
 	if err != nil {
 		if code == http.StatusBadRequest {
 			return http.StatusOK, fmt.Errorf("Transaction has been deleted")
@@ -93,14 +92,71 @@ func DeleteTransactionService(extReq request.ExternalRequest, logger *utility.Lo
 		return code, err
 	}
 
-	err = transaction.Delete(db.Transaction)
+	code, err = AuthorizePartyAction(repo, transactionID, int(user.AccountID), ActionDelete)
+	if err != nil {
+		return code, err
+	}
+
+	err = repo.Transactions.Delete(&transaction)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
 	status := GetTransactionStatus("deleted")
 
-	_, err = CreateTransactionState(db, status, transactionID, transaction.MilestoneID, int(user.AccountID))
+	_, err = CreateTransactionState(repo, status, transactionID, transaction.MilestoneID, int(user.AccountID))
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+const defaultDeletedTransactionRetentionDays = 30
+
+// DeletedTransactionRetention is how long a deleted transaction can be restored before the purge
+// job removes it for good.
+func DeletedTransactionRetention() time.Duration {
+	days := config.GetConfig().Retention.DeletedTransactionDays
+	if days <= 0 {
+		days = defaultDeletedTransactionRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// RestoreTransactionService undeletes a transaction deleted less than DeletedTransactionRetention
+// ago, after which it is due to be purged.
+func RestoreTransactionService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string, user external_models.User) (int, error) {
+	transaction, code, err := repo.Transactions.GetDeletedByTransactionID(transactionID)
+	if err != nil {
+		if code == http.StatusBadRequest {
+			return code, fmt.Errorf("no deleted transaction with this id")
+		}
+		return code, err
+	}
+
+	code, err = AuthorizeDeletedPartyAction(repo, transaction, int(user.AccountID), ActionRestore)
+	if err != nil {
+		return code, err
+	}
+
+	retention := DeletedTransactionRetention()
+	if time.Since(transaction.DeletedAt.Time) > retention {
+		return http.StatusBadRequest, fmt.Errorf("transaction was deleted more than %v days ago and can no longer be restored", int(retention.Hours()/24))
+	}
+
+	err = repo.Transactions.Restore(&transaction)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	// CreateTransactionState takes a status code, while the transaction holds the status itself.
+	err = repo.States.Create(&models.TransactionState{
+		AccountID:     int64(user.AccountID),
+		TransactionID: transactionID,
+		MilestoneID:   transaction.MilestoneID,
+		Status:        transaction.Status,
+	})
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
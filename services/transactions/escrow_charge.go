@@ -1,21 +1,23 @@
 package transactions
 
 import (
-	"math"
 	"net/http"
 
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func GetEscrowChargeService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.GetEscrowChargeRequest) (models.GetEscrowChargeResponse, int, error) {
+func GetEscrowChargeService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.GetEscrowChargeRequest) (models.GetEscrowChargeResponse, int, error) {
 	businessProfile, err := GetBusinessProfileByAccountID(extReq, logger, req.BusinessID)
 	if err != nil {
 		return models.GetEscrowChargeResponse{}, http.StatusInternalServerError, err
 	}
 	currency := businessProfile.Currency
+	if req.Currency != "" {
+		currency = req.Currency
+	}
 
 	businessCharge, err := getBusinessChargeWithBusinessIDAndCurrency(extReq, req.BusinessID, currency)
 	if err != nil {
@@ -25,9 +27,17 @@ func GetEscrowChargeService(extReq request.ExternalRequest, logger *utility.Logg
 		}
 	}
 
-	charge := getEscrowCharge(businessCharge, req.Amount)
+	fee, err := getEscrowFee(repo, businessCharge, req.BusinessID, currency, req.TransactionType, req.Amount)
+	if err != nil {
+		return models.GetEscrowChargeResponse{}, http.StatusInternalServerError, err
+	}
 	return models.GetEscrowChargeResponse{
-		Amount: req.Amount,
-		Charge: math.Round(charge*10000) / 10000,
+		Amount:        req.Amount,
+		Charge:        fee.Charge,
+		BuyerCharge:   fee.BuyerCharge,
+		SellerCharge:  fee.SellerCharge,
+		ChargeBearer:  fee.ChargeBearer,
+		FeeScheduleID: fee.FeeScheduleID,
+		Version:       fee.Version,
 	}, http.StatusOK, nil
 }
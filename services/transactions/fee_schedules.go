@@ -0,0 +1,205 @@
+package transactions
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func CreateFeeScheduleService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.CreateFeeScheduleRequest) (models.FeeSchedule, int, error) {
+	effectiveFrom, effectiveTo, err := parseFeeScheduleDates(req.EffectiveFrom, req.EffectiveTo, time.Now())
+	if err != nil {
+		return models.FeeSchedule{}, http.StatusBadRequest, err
+	}
+
+	schedule := models.FeeSchedule{
+		FeeScheduleID:   utility.RandomString(20),
+		Version:         1,
+		BusinessID:      req.BusinessID,
+		Currency:        strings.ToUpper(req.Currency),
+		TransactionType: req.TransactionType,
+		Tiers:           req.Tiers,
+		MinCharge:       req.MinCharge,
+		MaxCharge:       req.MaxCharge,
+		ChargeBearer:    req.ChargeBearer,
+		BuyerSplit:      req.BuyerSplit,
+		EffectiveFrom:   effectiveFrom,
+		EffectiveTo:     effectiveTo,
+		IsActive:        true,
+	}
+	if err := validateFeeSchedule(schedule); err != nil {
+		return models.FeeSchedule{}, http.StatusBadRequest, err
+	}
+
+	err = repo.FeeSchedules.Create(&schedule)
+	if err != nil {
+		return models.FeeSchedule{}, http.StatusInternalServerError, err
+	}
+	return schedule, http.StatusOK, nil
+}
+
+// UpdateFeeScheduleService stores the changes as the next version of the schedule, leaving earlier
+// versions untouched.
+func UpdateFeeScheduleService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, feeScheduleID string, req models.UpdateFeeScheduleRequest) (models.FeeSchedule, int, error) {
+	schedule, code, err := repo.FeeSchedules.GetLatestVersion(feeScheduleID)
+	if err != nil {
+		if code == http.StatusBadRequest {
+			return models.FeeSchedule{}, code, fmt.Errorf("fee schedule not found")
+		}
+		return models.FeeSchedule{}, code, err
+	}
+
+	next := schedule
+	next.ID = 0
+	next.Version = schedule.Version + 1
+	next.CreatedAt, next.UpdatedAt = time.Time{}, time.Time{}
+
+	if len(req.Tiers) > 0 {
+		next.Tiers = req.Tiers
+	}
+	if req.MinCharge != nil {
+		next.MinCharge = *req.MinCharge
+	}
+	if req.MaxCharge != nil {
+		next.MaxCharge = *req.MaxCharge
+	}
+	if req.ChargeBearer != "" {
+		next.ChargeBearer = req.ChargeBearer
+	}
+	if req.BuyerSplit != nil {
+		next.BuyerSplit = *req.BuyerSplit
+	}
+	if req.IsActive != nil {
+		next.IsActive = *req.IsActive
+	}
+	if req.EffectiveFrom != "" || req.EffectiveTo != "" {
+		effectiveFrom, effectiveTo, err := parseFeeScheduleDates(req.EffectiveFrom, req.EffectiveTo, schedule.EffectiveFrom)
+		if err != nil {
+			return models.FeeSchedule{}, http.StatusBadRequest, err
+		}
+		next.EffectiveFrom = effectiveFrom
+		if req.EffectiveTo != "" {
+			next.EffectiveTo = effectiveTo
+		}
+	}
+	if err := validateFeeSchedule(next); err != nil {
+		return models.FeeSchedule{}, http.StatusBadRequest, err
+	}
+
+	err = repo.FeeSchedules.Create(&next)
+	if err != nil {
+		return models.FeeSchedule{}, http.StatusInternalServerError, err
+	}
+	return next, http.StatusOK, nil
+}
+
+func ListFeeSchedulesService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.ListFeeSchedulesRequest, paginator postgresql.Pagination) ([]models.FeeSchedule, postgresql.PaginationResponse, int, error) {
+	req.Currency = strings.ToUpper(req.Currency)
+	schedules, pagination, err := repo.FeeSchedules.ListLatest(req, paginator)
+	if err != nil {
+		return schedules, pagination, http.StatusInternalServerError, err
+	}
+	return schedules, pagination, http.StatusOK, nil
+}
+
+func GetFeeScheduleVersionsService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, feeScheduleID string) ([]models.FeeSchedule, int, error) {
+	versions, err := repo.FeeSchedules.ListVersions(feeScheduleID)
+	if err != nil {
+		return versions, http.StatusInternalServerError, err
+	}
+	if len(versions) == 0 {
+		return versions, http.StatusBadRequest, fmt.Errorf("fee schedule not found")
+	}
+	return versions, http.StatusOK, nil
+}
+
+// getEscrowFee works out the escrow charge on amount and who pays it. The fee schedule in force for the
+// business, currency and transaction type is used when there is one; otherwise the charge configured for
+// the business on the auth service applies and the buyer pays all of it.
+func getEscrowFee(repo repository.Repositories, businessCharge external_models.BusinessCharge, businessID int, currency, transactionType string, amount float64) (models.EscrowFee, error) {
+	schedule, found, err := repo.FeeSchedules.GetEffective(businessID, strings.ToUpper(currency), transactionType, time.Now())
+	if err != nil {
+		return models.EscrowFee{}, err
+	}
+
+	fee := models.EscrowFee{ChargeBearer: models.ChargeBearerBuyer}
+	if found {
+		fee = schedule.Charge(amount)
+	} else {
+		fee.Charge, err = getEscrowCharge(businessCharge, amount)
+		if err != nil {
+			return models.EscrowFee{}, err
+		}
+		fee.Split(0)
+	}
+
+	fee.Charge = utility.RoundFloat(fee.Charge, 2)
+	fee.BuyerCharge = utility.RoundFloat(fee.BuyerCharge, 2)
+	fee.SellerCharge = utility.RoundFloat(fee.Charge-fee.BuyerCharge, 2)
+	return fee, nil
+}
+
+func parseFeeScheduleDates(from, to string, defaultFrom time.Time) (time.Time, *time.Time, error) {
+	effectiveFrom := defaultFrom
+	if from != "" {
+		parsed, err := parseFeeScheduleDate(from)
+		if err != nil {
+			return effectiveFrom, nil, fmt.Errorf("incorrect effective_from format, try 2006-01-02 or RFC3339")
+		}
+		effectiveFrom = parsed
+	}
+
+	if to == "" {
+		return effectiveFrom, nil, nil
+	}
+	effectiveTo, err := parseFeeScheduleDate(to)
+	if err != nil {
+		return effectiveFrom, nil, fmt.Errorf("incorrect effective_to format, try 2006-01-02 or RFC3339")
+	}
+	return effectiveFrom, &effectiveTo, nil
+}
+
+func parseFeeScheduleDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func validateFeeSchedule(schedule models.FeeSchedule) error {
+	if schedule.MaxCharge > 0 && schedule.MinCharge > schedule.MaxCharge {
+		return fmt.Errorf("min_charge cannot be greater than max_charge")
+	}
+	if schedule.EffectiveTo != nil && !schedule.EffectiveTo.After(schedule.EffectiveFrom) {
+		return fmt.Errorf("effective_to must be after effective_from")
+	}
+
+	bounds := []float64{}
+	unbounded := 0
+	for _, tier := range schedule.Tiers {
+		if tier.UpTo == 0 {
+			unbounded++
+			continue
+		}
+		bounds = append(bounds, tier.UpTo)
+	}
+	if unbounded > 1 {
+		return fmt.Errorf("only one tier can be without an up_to amount")
+	}
+	sort.Float64s(bounds)
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] == bounds[i-1] {
+			return fmt.Errorf("tiers cannot share the same up_to amount")
+		}
+	}
+	return nil
+}
@@ -14,11 +14,11 @@ import (
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func ImportTransactions(c *gin.Context, extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, user external_models.User) ([]models.Transaction, int, error) {
+func ImportTransactions(c *gin.Context, extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, user external_models.User) ([]models.Transaction, int, error) {
 	var (
 		columnLength = 11
 		transactions = []models.Transaction{}
@@ -157,10 +157,10 @@ func ImportTransactions(c *gin.Context, extReq request.ExternalRequest, logger *
 					})
 				}
 
-				transactionParty := models.TransactionParty{}
-				_, err = transactionParty.CreateTransactionsParties(db.Transaction, parties)
-				if err != nil {
-					logger.Error("error bulk creating transaction parties", err.Error())
+				for i := range parties {
+					if err := repo.Parties.Create(&parties[i]); err != nil {
+						logger.Error("error creating transaction party", err.Error())
+					}
 				}
 
 				amount, _ := strconv.ParseFloat(amount, 64)
@@ -188,10 +188,11 @@ func ImportTransactions(c *gin.Context, extReq request.ExternalRequest, logger *
 					BusinessID:       user.BusinessId,
 					TransUssdCode:    utility.GetRandomNumbersInRange(10000, 99999),
 				}
-				err := transaction.CreateTransaction(db.Transaction)
+				err := repo.Transactions.Create(&transaction)
 				if err != nil {
 					return transactions, http.StatusInternalServerError, err
 				}
+				recordTransactionCreated(transaction)
 				transactions = append(transactions, transaction)
 
 			}
@@ -12,207 +12,60 @@ import (
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func ListTransactionsByIDService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string) (models.TransactionByIDResponse, int, error) {
-	var (
-		transaction        = models.Transaction{TransactionID: transactionID}
-		productTransaction = models.ProductTransaction{TransactionID: transactionID}
-		parties            = []models.TransactionParty{}
-		transactionFile    = models.TransactionFile{TransactionID: transactionID}
-	)
-
-	transactions, err := transaction.GetAllByTransactionID(db.Transaction)
-	if err != nil {
-		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-	}
-
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+func ListTransactionsByIDService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string) (models.TransactionByIDResponse, int, error) {
+	_, code, err := repo.Transactions.GetByTransactionID(transactionID)
 	if err != nil {
 		return models.TransactionByIDResponse{}, code, fmt.Errorf("transaction not found: %v", err.Error())
 	}
 
-	productTransactions, err := productTransaction.GetAllByTransactionID(db.Transaction)
-	if err != nil {
-		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-	}
-
-	if transaction.Type == "milestone" {
-		transactionParty := models.TransactionParty{TransactionPartiesID: transactions[0].PartiesID}
-		parties, err = transactionParty.GetAllByTransactionPartiesID(db.Transaction)
-		if err != nil {
-			return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-		}
-	} else {
-		transactionParty := models.TransactionParty{TransactionID: transaction.TransactionID}
-		parties, err = transactionParty.GetAllByTransactionID(db.Transaction)
-		if err != nil {
-			return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-		}
-	}
-
-	transactionFiles, err := transactionFile.GetAllByTransactionID(db.Transaction)
-	if err != nil {
-		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-	}
-
-	transactionReponse := resolveTransactionAndListTransactionResponse(transaction)
-	transactionReponse.Products = productTransactions
-
-	partiess, members, err := getPartiesAndMembersFromParties(extReq, parties)
-	if err != nil {
-		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-	}
-
-	if len(parties) > 0 {
-		transactionReponse.Parties = partiess
-		transactionReponse.Members = members
-	} else {
-		transactionParty := models.TransactionParty{TransactionPartiesID: transactions[0].PartiesID}
-		tParties, err := transactionParty.GetAllByTransactionPartiesID(db.Transaction)
-		if err != nil {
-			return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-		}
-		partiess, members, err = getPartiesAndMembersFromParties(extReq, tParties)
-		if err != nil {
-			return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-		}
-
-		transactionReponse.Parties = partiess
-		transactionReponse.Members = members
-	}
-
-	transactionReponse.Files = transactionFiles
-	milestones := []models.MilestonesResponse{}
-
-	type chanData struct {
-		MileStoneSlice models.MilestonesResponse
-		TotalAmount    float64
-		Err            error
-	}
-
-	var wg sync.WaitGroup
-	ch := make(chan chanData, len(transactions))
-
-	for i, t := range transactions {
-		wg.Add(1)
-		go func(extReq request.ExternalRequest, db postgresql.Databases, index int, transaction models.Transaction, ch chan chanData, wg *sync.WaitGroup) {
-			defer wg.Done()
-
-			totalAmount, mileSresponse := resolveTransactionForAmountAndMilestoneResponse(extReq, index, transaction)
-			response := chanData{MileStoneSlice: mileSresponse, TotalAmount: totalAmount}
-
-			ch <- response
-		}(extReq, db, i, t, ch, &wg)
-
-	}
-	go func() {
-		defer close(ch)
-		wg.Wait()
-	}()
-
-	for data := range ch {
-		if data.Err != nil {
-			logger.Error("error getting transactions", data.Err.Error())
-		} else {
-			if len(milestones) == 0 {
-				transactionReponse.TotalAmount = data.TotalAmount
-			}
-			milestones = append(milestones, data.MileStoneSlice)
-		}
-
-	}
-
-	sort.SliceStable(milestones, func(i, j int) bool {
-		return milestones[i].Index < milestones[j].Index
-	})
-
-	transactionBroker := models.TransactionBroker{TransactionID: transactionID}
-	activity := models.ActivityLog{TransactionID: transactionID}
-
-	code, err = transactionBroker.GetTransactionBrokerByTransactionID(db.Transaction)
-	if err != nil && code == http.StatusInternalServerError {
-		logger.Error("error getting transaction broker", err.Error())
-	}
-
-	activities, err := activity.GetAllByTransactionID(db.Transaction)
+	responses, err := loadTransactionResponses(extReq, logger, repo, []string{transactionID}, models.AllTransactionFields())
 	if err != nil {
 		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 	}
-
-	country, err := GetCountryByNameOrCode(extReq, logger, transaction.Country)
-	if err != nil && code == http.StatusInternalServerError {
-		logger.Error("error getting country", err.Error())
-	}
-
-	dDateFormatted, err := utility.FormatDate(transaction.DueDate, "2006-01-02", "2006-01-02 15:04:05")
-	if err != nil {
-		dDateFormatted = ""
+	if len(responses) == 0 {
+		return models.TransactionByIDResponse{}, http.StatusBadRequest, fmt.Errorf("transaction not found")
 	}
-
-	transactionState := models.TransactionState{TransactionID: transactionID, Status: "Closed"}
-	code, err = transactionState.GetTransactionStateByTransactionIDAndStatus(db.Transaction)
-	if err != nil && code == http.StatusInternalServerError {
-		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-	}
-
-	transactionDispute := models.TransactionDispute{TransactionID: transactionID}
-	isDisputed, err := transactionDispute.IsDisputed(db.Transaction)
-	if err != nil {
-		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
-	}
-
-	titleSlice := strings.Split(transactionReponse.Title, ";")
-	transactionReponse.Title = titleSlice[0]
-	transactionReponse.Milestones = milestones
-	transactionReponse.Broker = transactionBroker
-	transactionReponse.Activities = activities
-	transactionReponse.Country = country
-	transactionReponse.DueDateFormatted = dDateFormatted
-	transactionReponse.TransactionClosedAt = transactionState.CreatedAt
-	transactionReponse.IsDisputed = isDisputed
-	return transactionReponse, http.StatusOK, nil
+	return responses[0], http.StatusOK, nil
 }
-func ListTransactionsByIDLegacyService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string) (models.TransactionByIDResponse, int, error) {
+
+func ListTransactionsByIDLegacyService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionID string) (models.TransactionByIDResponse, int, error) {
 	var (
-		transaction        = models.Transaction{TransactionID: transactionID}
-		productTransaction = models.ProductTransaction{TransactionID: transactionID}
-		parties            = []models.TransactionParty{}
-		transactionFile    = models.TransactionFile{TransactionID: transactionID}
+		parties = []models.TransactionParty{}
 	)
 
-	transactions, err := transaction.GetAllByTransactionID(db.Transaction)
+	transactions, err := repo.Transactions.ListByTransactionID(transactionID)
 	if err != nil {
 		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 	}
 
-	code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+	transaction, code, err := repo.Transactions.GetByTransactionID(transactionID)
 	if err != nil {
 		return models.TransactionByIDResponse{}, code, fmt.Errorf("transaction not found: %v", err.Error())
 	}
 
-	productTransactions, err := productTransaction.GetAllByTransactionID(db.Transaction)
+	productTransactions, err := repo.Products.ListByTransactionID(transactionID)
 	if err != nil {
 		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 	}
 
 	if transaction.Type == "milestone" {
-		transactionParty := models.TransactionParty{TransactionPartiesID: transactions[0].PartiesID}
-		parties, err = transactionParty.GetAllByTransactionPartiesID(db.Transaction)
+		parties, err = repo.Parties.ListByTransactionPartiesID(transactions[0].PartiesID)
 		if err != nil {
 			return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 		}
 	} else {
-		transactionParty := models.TransactionParty{TransactionID: transaction.TransactionID}
-		parties, err = transactionParty.GetAllByTransactionID(db.Transaction)
+		parties, err = repo.Parties.ListByTransactionID(transaction.TransactionID)
 		if err != nil {
 			return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 		}
 	}
 
-	transactionFiles, err := transactionFile.GetAllByTransactionID(db.Transaction)
+	transactionFiles, err := repo.Files.ListByTransactionID(transactionID)
 	if err != nil {
 		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 	}
@@ -229,8 +82,7 @@ func ListTransactionsByIDLegacyService(extReq request.ExternalRequest, logger *u
 		transactionReponse.Parties = partiess
 		transactionReponse.Members = members
 	} else {
-		transactionParty := models.TransactionParty{TransactionPartiesID: transactions[0].PartiesID}
-		tParties, err := transactionParty.GetAllByTransactionPartiesID(db.Transaction)
+		tParties, err := repo.Parties.ListByTransactionPartiesID(transactions[0].PartiesID)
 		if err != nil {
 			return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 		}
@@ -256,18 +108,24 @@ func ListTransactionsByIDLegacyService(extReq request.ExternalRequest, logger *u
 	ch := make(chan chanData, len(transactions))
 	wg.Add(len(transactions))
 	for i, t := range transactions {
-		go func(extReq request.ExternalRequest, db postgresql.Databases, index int, transaction models.Transaction, currentMileStoneSlice []models.MilestonesResponse, ch chan chanData, wg *sync.WaitGroup) {
+		go func(extReq request.ExternalRequest, repo repository.Repositories, index int, transaction models.Transaction, currentMileStoneSlice []models.MilestonesResponse, ch chan chanData, wg *sync.WaitGroup) {
 			defer wg.Done()
 			response := chanData{MileStoneSlice: currentMileStoneSlice, Index: index}
 			totalAmount, mileSresponse := resolveTransactionForAmountAndMilestoneResponse(extReq, index, transaction)
 			transactionReponse.TotalAmount = totalAmount
 			currentMileStoneSlice = append(currentMileStoneSlice, mileSresponse)
 
-			otherTransactions, err := transaction.GetAllOthersByIDAndPartiesID(db.Transaction)
+			partiesTransactions, err := repo.Transactions.ListByPartiesID(transaction.PartiesID)
 			if err != nil {
 				response.Err = err
 			}
 
+			otherTransactions := []models.Transaction{}
+			for _, pt := range partiesTransactions {
+				if pt.ID != transaction.ID {
+					otherTransactions = append(otherTransactions, pt)
+				}
+			}
 			for oi, ot := range otherTransactions {
 				_, otherMileSresponse := resolveTransactionForAmountAndMilestoneResponse(extReq, oi, ot)
 				currentMileStoneSlice = append(currentMileStoneSlice, otherMileSresponse)
@@ -275,7 +133,7 @@ func ListTransactionsByIDLegacyService(extReq request.ExternalRequest, logger *u
 			response.MileStoneSlice = currentMileStoneSlice
 
 			ch <- response
-		}(extReq, db, i, t, milestones[i], ch, &wg)
+		}(extReq, repo, i, t, milestones[i], ch, &wg)
 
 	}
 	go func() {
@@ -290,15 +148,12 @@ func ListTransactionsByIDLegacyService(extReq request.ExternalRequest, logger *u
 		milestones[data.Index] = data.MileStoneSlice
 	}
 
-	transactionBroker := models.TransactionBroker{TransactionID: transactionID}
-	activity := models.ActivityLog{TransactionID: transactionID}
-
-	code, err = transactionBroker.GetTransactionBrokerByTransactionID(db.Transaction)
+	transactionBroker, code, err := repo.Brokers.GetByTransactionID(transactionID)
 	if err != nil && code == http.StatusInternalServerError {
 		logger.Error("error getting transaction broker", err.Error())
 	}
 
-	activities, err := activity.GetAllByTransactionID(db.Transaction)
+	activities, err := repo.ActivityLogs.ListByTransactionID(transactionID)
 	if err != nil {
 		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 	}
@@ -313,17 +168,16 @@ func ListTransactionsByIDLegacyService(extReq request.ExternalRequest, logger *u
 		dDateFormatted = ""
 	}
 
-	transactionState := models.TransactionState{TransactionID: transactionID, Status: "Closed"}
-	code, err = transactionState.GetTransactionStateByTransactionIDAndStatus(db.Transaction)
+	transactionState, code, err := repo.States.GetByTransactionIDAndStatus(transactionID, "Closed")
 	if err != nil && code == http.StatusInternalServerError {
 		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 	}
 
-	transactionDispute := models.TransactionDispute{TransactionID: transactionID}
-	isDisputed, err := transactionDispute.IsDisputed(db.Transaction)
-	if err != nil {
+	_, code, err = repo.Disputes.GetByTransactionID(transactionID)
+	if err != nil && code == http.StatusInternalServerError {
 		return models.TransactionByIDResponse{}, http.StatusInternalServerError, err
 	}
+	isDisputed := err == nil
 
 	titleSlice := strings.Split(transactionReponse.Title, ";")
 	transactionReponse.Title = titleSlice[0]
@@ -337,73 +191,37 @@ func ListTransactionsByIDLegacyService(extReq request.ExternalRequest, logger *u
 	return transactionReponse, http.StatusOK, nil
 }
 
-func ListTransactionsByUssdCodeService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, ussdCode int) (models.TransactionByIDResponse, int, error) {
-	var (
-		transaction = models.Transaction{TransUssdCode: ussdCode}
-	)
-
-	code, err := transaction.GetTransactionByUssdCode(db.Transaction)
+func ListTransactionsByUssdCodeService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, ussdCode int) (models.TransactionByIDResponse, int, error) {
+	transaction, code, err := repo.Transactions.GetByUssdCode(ussdCode)
 	if err != nil {
 		return models.TransactionByIDResponse{}, code, err
 	}
 
-	return ListTransactionsByIDService(extReq, logger, db, transaction.TransactionID)
+	return ListTransactionsByIDService(extReq, logger, repo, transaction.TransactionID)
 }
 
-func ListTransactionsService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.ListTransactionsRequest, paginator postgresql.Pagination) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
-	var (
-		transaction  = models.Transaction{}
-		transactions = []models.Transaction{}
-	)
-
+func ListTransactionsService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.ListTransactionsRequest, paginator postgresql.Pagination, fields models.TransactionFields) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
+	query := models.TransactionQuery{BusinessID: req.BusinessID, CreatedAtInterval: req.Filter}
 	if req.Status != "" {
-		transaction.Status = req.Status
+		query.Status = req.Status
 	} else if req.StatusCode != "" {
-		transaction.Status = GetTransactionStatus(req.StatusCode)
+		query.Status = GetTransactionStatus(req.StatusCode)
 	}
 
-	transactions, pagination, err := transaction.GetAllByAndQueries(db.Transaction, false, req.Filter, "id", "asc", paginator)
+	transactions, pagination, err := repo.Transactions.ListByQuery(query, paginator)
 	if err != nil {
 		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
 	}
 
-	var transactionsResponses []models.TransactionByIDResponse
-
-	type chanData struct {
-		TransactionResponse models.TransactionByIDResponse
-		Err                 error
-	}
-
-	var wg sync.WaitGroup
-	ch := make(chan chanData, len(transactions))
-
+	transactionIDs := []string{}
 	for _, t := range transactions {
-		wg.Add(1)
-		go func(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string, ch chan chanData, wg *sync.WaitGroup) {
-			defer wg.Done()
-			response := chanData{}
-			transactionResponse, _, err := ListTransactionsByIDService(extReq, logger, db, transactionID)
-			if err != nil {
-				response.Err = err
-			} else {
-				response.TransactionResponse = transactionResponse
-			}
-			ch <- response
-		}(extReq, logger, db, t.TransactionID, ch, &wg)
+		transactionIDs = append(transactionIDs, t.TransactionID)
 	}
-
-	go func() {
-		defer close(ch)
-		wg.Wait()
-	}()
-
-	for data := range ch {
-		if data.Err != nil {
-			logger.Error("list transaction by id error", data.Err.Error())
-		} else {
-			transactionsResponses = append(transactionsResponses, data.TransactionResponse)
-		}
+	transactionsResponses, err := loadTransactionResponses(extReq, logger, repo, transactionIDs, fields)
+	if err != nil {
+		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
 	}
+
 	sort.SliceStable(transactionsResponses, func(i, j int) bool {
 		return transactionsResponses[i].ID > transactionsResponses[j].ID
 	})
@@ -412,68 +230,29 @@ func ListTransactionsService(extReq request.ExternalRequest, logger *utility.Log
 
 }
 
-func ListTransactionsByBusinessService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.ListTransactionByBusinessRequest, paginator postgresql.Pagination) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
-	var (
-		transaction  = models.Transaction{BusinessID: req.BusinessID, IsPaylinked: req.Paylinked}
-		transactions = []models.Transaction{}
-	)
-
+func ListTransactionsByBusinessService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.ListTransactionByBusinessRequest, paginator postgresql.Pagination, fields models.TransactionFields) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
+	query := models.TransactionQuery{BusinessID: req.BusinessID, UsePaylinked: req.Paylinked, IsPaylinked: req.Paylinked, CreatedAtInterval: req.Filter}
 	if req.Status != "" {
-		transaction.Status = req.Status
+		query.Status = req.Status
 	} else if req.StatusCode != "" {
-		transaction.Status = GetTransactionStatus(req.StatusCode)
+		query.Status = GetTransactionStatus(req.StatusCode)
 	}
 
-	transactions, pagination, err := transaction.GetAllByAndQueries(db.Transaction, req.Paylinked, req.Filter, "id", "asc", paginator)
+	transactions, pagination, err := repo.Transactions.ListByQuery(query, paginator)
 	if err != nil {
 		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
 	}
 
-	var transactionsResponses []models.TransactionByIDResponse
-
-	type chanData struct {
-		TransactionResponse models.TransactionByIDResponse
-		Err                 error
-	}
-	var wg sync.WaitGroup
-	ch := make(chan chanData, len(transactions))
-
+	transactionIDs := []string{}
 	for _, t := range transactions {
-		wg.Add(1)
-		go func(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string, ch chan chanData, wg *sync.WaitGroup) {
-			defer wg.Done()
-			response := chanData{}
-			transactionResponse, _, err := ListTransactionsByIDService(extReq, logger, db, transactionID)
-			if err != nil {
-				response.Err = err
-			} else {
-				payment, err := ListPayment(extReq, t.TransactionID)
-				if err != nil {
-					transactionResponse.TotalAmount = 0
-					transactionResponse.EscrowCharge = 0
-					logger.Error("list payment by transaction id error", err.Error())
-				} else {
-					transactionResponse.TotalAmount = payment.TotalAmount
-					transactionResponse.EscrowCharge = payment.EscrowCharge
-				}
-				response.TransactionResponse = transactionResponse
-			}
-			ch <- response
-		}(extReq, logger, db, t.TransactionID, ch, &wg)
-
+		transactionIDs = append(transactionIDs, t.TransactionID)
 	}
-
-	go func() {
-		defer close(ch)
-		wg.Wait()
-	}()
-
-	for data := range ch {
-		if data.Err != nil {
-			logger.Error("list transaction by id error or list payment by transaction id error", data.Err.Error())
-		} else {
-			transactionsResponses = append(transactionsResponses, data.TransactionResponse)
-		}
+	transactionsResponses, err := loadTransactionResponses(extReq, logger, repo, transactionIDs, fields)
+	if err != nil {
+		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
+	}
+	if fields.Has(models.TransactionFieldPayment) {
+		addPaymentTotals(extReq, logger, transactionsResponses)
 	}
 
 	sort.SliceStable(transactionsResponses, func(i, j int) bool {
@@ -483,67 +262,29 @@ func ListTransactionsByBusinessService(extReq request.ExternalRequest, logger *u
 	return transactionsResponses, pagination, http.StatusOK, nil
 
 }
-func ListByBusinessFromMondayToThursdayService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.ListByBusinessFromMondayToThursdayRequest, paginator postgresql.Pagination) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
-	var (
-		transaction  = models.Transaction{BusinessID: req.BusinessID, IsPaylinked: req.Paylinked}
-		transactions = []models.Transaction{}
-	)
-
+func ListByBusinessFromMondayToThursdayService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.ListByBusinessFromMondayToThursdayRequest, paginator postgresql.Pagination, fields models.TransactionFields) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
+	query := models.TransactionQuery{BusinessID: req.BusinessID, UsePaylinked: req.Paylinked, IsPaylinked: req.Paylinked, CreatedAtInterval: "monday_to_thursday"}
 	if req.Status != "" {
-		transaction.Status = req.Status
+		query.Status = req.Status
 	} else if req.StatusCode != "" {
-		transaction.Status = GetTransactionStatus(req.StatusCode)
+		query.Status = GetTransactionStatus(req.StatusCode)
 	}
 
-	transactions, pagination, err := transaction.GetAllByAndQueries(db.Transaction, req.Paylinked, "monday_to_thursday", "id", "asc", paginator)
+	transactions, pagination, err := repo.Transactions.ListByQuery(query, paginator)
 	if err != nil {
 		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
 	}
 
-	var transactionsResponses []models.TransactionByIDResponse
-
-	type chanData struct {
-		TransactionResponse models.TransactionByIDResponse
-		Err                 error
-	}
-	var wg sync.WaitGroup
-	ch := make(chan chanData, len(transactions))
+	transactionIDs := []string{}
 	for _, t := range transactions {
-		wg.Add(1)
-		go func(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string, ch chan chanData, wg *sync.WaitGroup) {
-			defer wg.Done()
-			response := chanData{}
-			transactionResponse, _, err := ListTransactionsByIDService(extReq, logger, db, transactionID)
-			if err != nil {
-				response.Err = err
-			} else {
-				payment, err := ListPayment(extReq, t.TransactionID)
-				if err != nil {
-					transactionResponse.TotalAmount = 0
-					transactionResponse.EscrowCharge = 0
-					logger.Error("list payment by transaction id error", err.Error())
-				} else {
-					transactionResponse.TotalAmount = payment.TotalAmount
-					transactionResponse.EscrowCharge = payment.EscrowCharge
-				}
-				response.TransactionResponse = transactionResponse
-			}
-			ch <- response
-		}(extReq, logger, db, t.TransactionID, ch, &wg)
-
+		transactionIDs = append(transactionIDs, t.TransactionID)
 	}
-
-	go func() {
-		defer close(ch)
-		wg.Wait()
-	}()
-
-	for data := range ch {
-		if data.Err != nil {
-			logger.Error("list transaction by id error or list payment by transaction id error", data.Err.Error())
-		} else {
-			transactionsResponses = append(transactionsResponses, data.TransactionResponse)
-		}
+	transactionsResponses, err := loadTransactionResponses(extReq, logger, repo, transactionIDs, fields)
+	if err != nil {
+		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
+	}
+	if fields.Has(models.TransactionFieldPayment) {
+		addPaymentTotals(extReq, logger, transactionsResponses)
 	}
 
 	sort.SliceStable(transactionsResponses, func(i, j int) bool {
@@ -554,11 +295,10 @@ func ListByBusinessFromMondayToThursdayService(extReq request.ExternalRequest, l
 
 }
 
-func ListTransactionsByUserService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.ListTransactionByUserRequest, paginator postgresql.Pagination, user external_models.User) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
+func ListTransactionsByUserService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.ListTransactionByUserRequest, paginator postgresql.Pagination, user external_models.User, fields models.TransactionFields) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
 	var (
 		// transactions          = []models.Transaction{}
 		transactionsResponses = []models.TransactionByIDResponse{}
-		transactionParty      = models.TransactionParty{AccountID: int(user.AccountID), Role: req.Role}
 	)
 
 	statusC := ""
@@ -566,7 +306,7 @@ func ListTransactionsByUserService(extReq request.ExternalRequest, logger *utili
 		statusC = GetTransactionStatus(req.StatusCode)
 	}
 
-	transactionParties, pagination, err := transactionParty.GetAllByAndQueriesForUniqueValueForTransactionStatus(db.Transaction, "", "id", "desc", "transaction_id", statusC, paginator)
+	transactionParties, pagination, err := repo.Parties.ListByAccountID(int(user.AccountID), req.Role, statusC, paginator)
 	if err != nil {
 		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
 	}
@@ -584,35 +324,13 @@ func ListTransactionsByUserService(extReq request.ExternalRequest, logger *utili
 	// 	}
 	// }
 
-	type chanData struct {
-		TransactionResponse models.TransactionByIDResponse
-		Err                 error
-	}
-
-	var wg sync.WaitGroup
-	ch := make(chan chanData, len(transactionParties))
-
+	transactionIDs := []string{}
 	for _, t := range transactionParties {
-		wg.Add(1)
-		go func(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string, ch chan chanData, wg *sync.WaitGroup) {
-			defer wg.Done()
-			transactionResponse, _, err := ListTransactionsByIDService(extReq, logger, db, transactionID)
-			response := chanData{Err: err, TransactionResponse: transactionResponse}
-			ch <- response
-		}(extReq, logger, db, t.TransactionID, ch, &wg)
+		transactionIDs = append(transactionIDs, t.TransactionID)
 	}
-
-	go func() {
-		defer close(ch)
-		wg.Wait()
-	}()
-
-	for data := range ch {
-		if data.Err != nil {
-			logger.Error("list transaction by id error", data.Err.Error())
-		} else {
-			transactionsResponses = append(transactionsResponses, data.TransactionResponse)
-		}
+	transactionsResponses, err = loadTransactionResponses(extReq, logger, repo, transactionIDs, fields)
+	if err != nil {
+		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
 	}
 
 	sort.SliceStable(transactionsResponses, func(i, j int) bool {
@@ -623,64 +341,24 @@ func ListTransactionsByUserService(extReq request.ExternalRequest, logger *utili
 
 }
 
-func ListArchivedTransactionsService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, paginator postgresql.Pagination, user external_models.User) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
+func ListArchivedTransactionsService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, paginator postgresql.Pagination, user external_models.User, fields models.TransactionFields) ([]models.TransactionByIDResponse, postgresql.PaginationResponse, int, error) {
 	var (
 		// transactions          = []models.Transaction{}
 		transactionsResponses = []models.TransactionByIDResponse{}
-		transactionParty      = models.TransactionParty{AccountID: int(user.AccountID), Role: "sender"}
 	)
 
-	transactionParties, pagination, err := transactionParty.GetAllByAndQueriesForUniqueValueForTransactionStatus(db.Transaction, "", "id", "desc", "transaction_id", "Deleted", paginator)
+	transactionParties, pagination, err := repo.Parties.ListArchivedByAccountID(int(user.AccountID), paginator)
 	if err != nil {
 		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
 	}
 
-	// for _, tp := range transactionParties {
-	// 	lTransaction := models.Transaction{TransactionID: tp.TransactionID, Status: "Deleted"}
-	// 	code, err := lTransaction.GetLatestByAndQueries(db.Transaction, false, "")
-	// 	if err != nil {
-	// 		if code == http.StatusInternalServerError {
-	// 			return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, code, err
-	// 		}
-	// 		logger.Error("error getting lastest transaction byy transaction id", err.Error())
-	// 	} else {
-	// 		transactions = append(transactions, lTransaction)
-	// 	}
-	// }
-
-	type chanData struct {
-		TransactionResponse models.TransactionByIDResponse
-		Err                 error
-	}
-
-	var wg sync.WaitGroup
-	ch := make(chan chanData, len(transactionParties))
+	transactionIDs := []string{}
 	for _, t := range transactionParties {
-		wg.Add(1)
-		go func(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, transactionID string, ch chan chanData, wg *sync.WaitGroup) {
-			defer wg.Done()
-			response := chanData{}
-			transactionResponse, _, err := ListTransactionsByIDService(extReq, logger, db, transactionID)
-			if err != nil {
-				response.Err = err
-			} else {
-				response.TransactionResponse = transactionResponse
-			}
-			ch <- response
-		}(extReq, logger, db, t.TransactionID, ch, &wg)
+		transactionIDs = append(transactionIDs, t.TransactionID)
 	}
-
-	go func() {
-		defer close(ch)
-		wg.Wait()
-	}()
-
-	for data := range ch {
-		if data.Err != nil {
-			logger.Error("list transaction by id error", data.Err.Error())
-		} else {
-			transactionsResponses = append(transactionsResponses, data.TransactionResponse)
-		}
+	transactionsResponses, err = loadTransactionResponses(extReq, logger, repo, transactionIDs, fields)
+	if err != nil {
+		return []models.TransactionByIDResponse{}, postgresql.PaginationResponse{}, http.StatusInternalServerError, err
 	}
 
 	sort.SliceStable(transactionsResponses, func(i, j int) bool {
@@ -692,6 +370,25 @@ func ListArchivedTransactionsService(extReq request.ExternalRequest, logger *uti
 }
 
 func resolveTransactionForAmountAndMilestoneResponse(extReq request.ExternalRequest, i int, t models.Transaction) (float64, models.MilestonesResponse) {
+	recipients := getMilestoneRecipients(extReq, t)
+	accountIDs := []int{}
+	for _, r := range recipients {
+		accountIDs = append(accountIDs, r.AccountID)
+	}
+	return buildMilestoneResponse(t, recipients, GetUsersWithAccountIDs(extReq, accountIDs))
+}
+
+func getMilestoneRecipients(extReq request.ExternalRequest, t models.Transaction) []models.MileStoneRecipient {
+	var recipients []models.MileStoneRecipient
+	err := json.Unmarshal([]byte(t.Recipients), &recipients)
+	if err != nil {
+		extReq.Logger.Error("error unmarshaling recipient json string to struct", t.Recipients, err.Error())
+	}
+	return recipients
+}
+
+// buildMilestoneResponse describes one milestone row, naming its recipients from users.
+func buildMilestoneResponse(t models.Transaction, recipients []models.MileStoneRecipient, users map[int]external_models.User) (float64, models.MilestonesResponse) {
 	var (
 		totalAmount float64 = 0
 	)
@@ -716,17 +413,9 @@ func resolveTransactionForAmountAndMilestoneResponse(extReq request.ExternalRequ
 		}
 	}
 
-	var recipients []models.MileStoneRecipient
 	var recipientsResponse []models.MilestonesRecipientResponse
-
-	fmt.Println("recipients string", t.Recipients)
-	err := json.Unmarshal([]byte(t.Recipients), &recipients)
-	if err != nil {
-		extReq.Logger.Error("error unmarshaling recipient json string to struct", t.Recipients, err.Error())
-	}
-
 	for _, r := range recipients {
-		user, _ := GetUserWithAccountID(extReq, r.AccountID)
+		user := users[r.AccountID]
 		accountName := ""
 		if user.ID != 0 {
 			accountName = user.Lastname + " " + user.Firstname
@@ -755,75 +444,50 @@ func resolveTransactionForAmountAndMilestoneResponse(extReq request.ExternalRequ
 }
 
 func getPartiesAndMembersFromParties(extReq request.ExternalRequest, parties []models.TransactionParty) (map[string]models.TransactionParty, []models.PartyResponse, error) {
+	accountIDs := []int{}
+	for _, p := range parties {
+		accountIDs = append(accountIDs, p.AccountID)
+	}
+	return buildPartiesAndMembers(parties, GetUsersWithAccountIDs(extReq, accountIDs))
+}
+
+// buildPartiesAndMembers keys parties by role and describes each of them as a member, naming them
+// from users.
+func buildPartiesAndMembers(parties []models.TransactionParty, users map[int]external_models.User) (map[string]models.TransactionParty, []models.PartyResponse, error) {
 	var (
 		partiess = map[string]models.TransactionParty{}
 		members  = []models.PartyResponse{}
-		wg       sync.WaitGroup
 	)
-	type chanData struct {
-		Member models.PartyResponse
-		Err    error
-	}
-
-	ch := make(chan chanData, len(parties))
-	wg.Add(len(parties))
-	for _, p := range parties {
-		partiess[p.Role] = p
-		go func(party models.TransactionParty, ch chan chanData, wg *sync.WaitGroup) {
-			defer wg.Done()
-			user, _ := GetUserWithAccountID(extReq, party.AccountID)
-			accountName := ""
-			if user.ID != 0 {
-				accountName = user.Lastname + " " + user.Firstname
-			}
-			var roleCapabilities models.PartyAccessLevel
-
-			inrec, err := json.Marshal(party.RoleCapabilities)
-			if err != nil {
-				ch <- chanData{
-					Member: models.PartyResponse{},
-					Err:    err,
-				}
-				return
-			}
-
-			err = json.Unmarshal(inrec, &roleCapabilities)
-			if err != nil {
-				ch <- chanData{
-					Member: models.PartyResponse{},
-					Err:    err,
-				}
-				return
-			}
-
-			ch <- chanData{
-				Member: models.PartyResponse{
-					PartyID:     int(party.ID),
-					AccountID:   party.AccountID,
-					AccountName: accountName,
-					PhoneNumber: user.PhoneNumber,
-					Email:       user.EmailAddress,
-					Role:        party.Role,
-					Status:      party.Status,
-					AccessLevel: roleCapabilities,
-				},
-				Err: nil,
-			}
 
-		}(p, ch, &wg)
+	for _, party := range parties {
+		partiess[party.Role] = party
 
-	}
-	go func() {
-		defer close(ch)
-		wg.Wait()
-	}()
+		user := users[party.AccountID]
+		accountName := ""
+		if user.ID != 0 {
+			accountName = user.Lastname + " " + user.Firstname
+		}
 
-	for data := range ch {
-		if data.Err != nil {
-			return partiess, members, data.Err
-		} else {
-			members = append(members, data.Member)
+		var roleCapabilities models.PartyAccessLevel
+		inrec, err := json.Marshal(party.RoleCapabilities)
+		if err != nil {
+			return partiess, members, err
 		}
+		err = json.Unmarshal(inrec, &roleCapabilities)
+		if err != nil {
+			return partiess, members, err
+		}
+
+		members = append(members, models.PartyResponse{
+			PartyID:     int(party.ID),
+			AccountID:   party.AccountID,
+			AccountName: accountName,
+			PhoneNumber: user.PhoneNumber,
+			Email:       user.EmailAddress,
+			Role:        party.Role,
+			Status:      party.Status,
+			AccessLevel: roleCapabilities,
+		})
 	}
 	return partiess, members, nil
 }
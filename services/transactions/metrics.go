@@ -0,0 +1,177 @@
+package transactions
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vesicash/transactions-ms/internal/models"
+)
+
+var (
+	transactionsCreated = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "transactions_created_total",
+			Help: "Transactions created, by currency and source.",
+		},
+		[]string{"currency", "source"},
+	)
+
+	transactionsFunded = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "transactions_funded_total",
+			Help: "Transactions and milestones whose amount paid reached what is owed, by currency and source.",
+		},
+		[]string{"currency", "source"},
+	)
+
+	transactionsClosed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "transactions_closed_total",
+			Help: "Transactions moved into one of the closed statuses, by currency, source and status.",
+		},
+		[]string{"currency", "source", "status"},
+	)
+
+	escrowVolume = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "escrow_volume_total",
+			Help: "Amount paid into escrow, by currency.",
+		},
+		[]string{"currency"},
+	)
+
+	disputesOpened = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "disputes_opened_total",
+			Help: "Disputes opened.",
+		},
+	)
+
+	disputesResolved = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "disputes_resolved_total",
+			Help: "Disputes given a decision.",
+		},
+	)
+
+	refunds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "refunds_total",
+			Help: "Refunds sent back to buyers, by currency.",
+		},
+		[]string{"currency"},
+	)
+
+	refundAmount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "refund_amount_total",
+			Help: "Amount refunded to buyers, by currency.",
+		},
+		[]string{"currency"},
+	)
+
+	transactionsByStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "transactions",
+			Help: "Transactions with a milestone in each status, as of the last domain-metrics cron job run.",
+		},
+		[]string{"status"},
+	)
+
+	disputesUndecided = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "disputes_open",
+			Help: "Disputes yet to be given a decision, as of the last domain-metrics cron job run.",
+		},
+	)
+
+	// metricCurrencies and metricSources bound the currency and source labels, which come from
+	// requests. Anything else is counted as "other".
+	metricCurrencies = []string{"NGN", "USD", "GBP", "EUR", "KES", "GHS", "ZAR", "CAD"}
+	metricSources    = []string{"api", "instantescrow", "trizact", "transfer"}
+)
+
+func init() {
+	prometheus.MustRegister(transactionsCreated, transactionsFunded, transactionsClosed, escrowVolume, disputesOpened, disputesResolved, refunds, refundAmount, transactionsByStatus, disputesUndecided)
+}
+
+func recordTransactionCreated(transaction models.Transaction) {
+	transactionsCreated.WithLabelValues(metricCurrency(transaction.Currency), metricSource(transaction.Source)).Inc()
+}
+
+// recordPayment counts amount paid into escrow for transaction, and the transaction as funded when
+// the payment takes what has been paid from short of what is owed to all of it.
+func recordPayment(transaction models.Transaction, previousAmountPaid, amount float64) {
+	currency := metricCurrency(transaction.Currency)
+	escrowVolume.WithLabelValues(currency).Add(amount)
+
+	owed := transaction.Amount + transaction.ShippingFee
+	if previousAmountPaid < owed && transaction.AmountPaid >= owed {
+		transactionsFunded.WithLabelValues(currency, metricSource(transaction.Source)).Inc()
+	}
+}
+
+// RecordStatusChange counts transaction as closed when it moves from previousStatus into one of the
+// closed statuses. Moves between closed statuses are not counted again.
+func RecordStatusChange(transaction models.Transaction, previousStatus string) {
+	if isClosedStatus(previousStatus) || !isClosedStatus(transaction.Status) {
+		return
+	}
+	transactionsClosed.WithLabelValues(metricCurrency(transaction.Currency), metricSource(transaction.Source), transaction.Status).Inc()
+}
+
+// RecordRefund counts a refund of amount back to a buyer.
+func RecordRefund(currency string, amount float64) {
+	currency = metricCurrency(currency)
+	refunds.WithLabelValues(currency).Inc()
+	refundAmount.WithLabelValues(currency).Add(amount)
+}
+
+// RecordDomainCounts sets the transaction and dispute gauges from counts taken by the
+// domain-metrics cron job. Statuses that are not one of the known transaction statuses are
+// reported as "other".
+func RecordDomainCounts(transactionCounts map[string]int64, undecidedDisputes int64) {
+	byStatus := map[string]int64{}
+	for _, status := range transactionStatuses {
+		byStatus[status] = 0
+	}
+	for status, count := range transactionCounts {
+		if _, ok := byStatus[status]; !ok {
+			status = "other"
+		}
+		byStatus[status] += count
+	}
+
+	transactionsByStatus.Reset()
+	for status, count := range byStatus {
+		transactionsByStatus.WithLabelValues(status).Set(float64(count))
+	}
+	disputesUndecided.Set(float64(undecidedDisputes))
+}
+
+func isClosedStatus(status string) bool {
+	return strings.HasPrefix(strings.ToLower(status), "closed")
+}
+
+func metricCurrency(currency string) string {
+	if currency == "" {
+		return "unknown"
+	}
+	return metricLabel(strings.ToUpper(currency), metricCurrencies)
+}
+
+func metricSource(source string) string {
+	if source == "" {
+		return "api"
+	}
+	return metricLabel(strings.ToLower(source), metricSources)
+}
+
+func metricLabel(value string, allowed []string) string {
+	for _, a := range allowed {
+		if value == a {
+			return value
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,338 @@
+package transactions
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func FundMilestoneService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.FundMilestoneRequest) (models.Transaction, int, error) {
+	milestone, code, err := repo.Transactions.GetByTransactionIDAndMilestoneID(req.TransactionID, req.MilestoneID)
+	if err != nil {
+		return milestone, code, err
+	}
+
+	previousAmountPaid := milestone.AmountPaid
+	if req.Action == "+" {
+		milestone.AmountPaid += req.Amount
+	} else if milestone.AmountPaid < req.Amount {
+		milestone.AmountPaid = 0
+	} else {
+		milestone.AmountPaid -= req.Amount
+	}
+
+	previousStatus := milestone.Status
+	funded := milestone.AmountPaid >= milestone.Amount+milestone.ShippingFee
+	if funded && statusIn(milestone.Status, "", "draft", "sac", "anf") {
+		milestone.Status = GetTransactionStatus("af")
+	}
+
+	err = repo.Transactions.Update(&milestone)
+	if err != nil {
+		return milestone, updateErrorCode(err), err
+	}
+	if req.Action == "+" {
+		recordPayment(milestone, previousAmountPaid, req.Amount)
+	}
+
+	if previousStatus != milestone.Status {
+		_, err = CreateTransactionState(repo, "af", milestone.TransactionID, milestone.MilestoneID, 0)
+		if err != nil {
+			return milestone, http.StatusInternalServerError, err
+		}
+		createMilestoneActivityLog(repo, milestone, previousStatus, 0)
+	}
+
+	return milestone, http.StatusOK, nil
+}
+
+func MilestoneDeliveredService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.MilestoneRequest, user external_models.User) (models.Transaction, int, error) {
+	var (
+		statusCode = "d"
+	)
+
+	milestone, code, err := repo.Transactions.GetByTransactionIDAndMilestoneID(req.TransactionID, req.MilestoneID)
+	if err != nil {
+		return milestone, code, err
+	}
+
+	code, err = AuthorizePartyAction(repo, milestone.TransactionID, int(user.AccountID), ActionDeliver)
+	if err != nil {
+		return milestone, code, err
+	}
+
+	if !statusIn(milestone.Status, "af", "ip", "dr") {
+		return milestone, http.StatusBadRequest, fmt.Errorf("milestone cannot be marked as delivered while it is %v", milestone.Status)
+	}
+
+	code, err = updateMilestoneStatus(repo, &milestone, statusCode, int(user.AccountID))
+	if err != nil {
+		return milestone, code, err
+	}
+
+	extReq.Notification.SendTransactionDeliveredNotification(external_models.TransactionIDRequestModel{
+		TransactionId: req.TransactionID,
+	})
+
+	return milestone, http.StatusOK, nil
+}
+
+func AcceptMilestoneService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.MilestoneRequest, user external_models.User) (models.Transaction, int, error) {
+	milestone, code, err := repo.Transactions.GetByTransactionIDAndMilestoneID(req.TransactionID, req.MilestoneID)
+	if err != nil {
+		return milestone, code, err
+	}
+
+	code, err = AuthorizePartyAction(repo, milestone.TransactionID, int(user.AccountID), ActionApproveRelease)
+	if err != nil {
+		return milestone, code, err
+	}
+
+	if !statusIn(milestone.Status, "d") {
+		return milestone, http.StatusBadRequest, fmt.Errorf("milestone cannot be accepted while it is %v", milestone.Status)
+	}
+
+	// the milestone is claimed for disbursement before any money moves, and never passes through
+	// "da", which the update-status job releases funds for, so it can only be paid out once.
+	code, err = updateMilestoneStatus(repo, &milestone, "cdp", int(user.AccountID))
+	if err != nil {
+		return milestone, code, err
+	}
+
+	extReq.Notification.SendTransactionDeliveredAcceptedNotification(external_models.TransactionIDRequestModel{
+		TransactionId: req.TransactionID,
+	})
+
+	// a failed transfer leaves the milestone pending manual disbursement rather than
+	// retrying automatically, since some recipients may already have been paid.
+	statusCode := "cdc"
+	err = ReleaseMilestoneFunds(extReq, repo, milestone)
+	if err != nil {
+		logger.Error(fmt.Sprintf("error releasing funds for milestone %v of transaction %v: %v", milestone.MilestoneID, milestone.TransactionID, err.Error()))
+		statusCode = "cmdp"
+	}
+
+	code, err = updateMilestoneStatus(repo, &milestone, statusCode, int(user.AccountID))
+	if err != nil {
+		return milestone, code, err
+	}
+
+	return milestone, http.StatusOK, nil
+}
+
+func RejectMilestoneService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.RejectMilestoneRequest, user external_models.User) (models.Transaction, int, error) {
+	milestone, code, err := repo.Transactions.GetByTransactionIDAndMilestoneID(req.TransactionID, req.MilestoneID)
+	if err != nil {
+		return milestone, code, err
+	}
+
+	code, err = AuthorizePartyAction(repo, milestone.TransactionID, int(user.AccountID), ActionApproveRelease)
+	if err != nil {
+		return milestone, code, err
+	}
+
+	if !statusIn(milestone.Status, "d") {
+		return milestone, http.StatusBadRequest, fmt.Errorf("milestone cannot be rejected while it is %v", milestone.Status)
+	}
+
+	if req.Reason != "" {
+		rejected := models.TransactionsRejected{
+			TransactionID: req.TransactionID,
+			AccountID:     int64(user.AccountID),
+			Reason:        req.Reason,
+		}
+		err = repo.Rejections.Create(&rejected)
+		if err != nil {
+			return milestone, http.StatusInternalServerError, err
+		}
+	}
+
+	code, err = updateMilestoneStatus(repo, &milestone, "dr", int(user.AccountID))
+	if err != nil {
+		return milestone, code, err
+	}
+
+	extReq.Notification.SendTransactionDeliveredRejectedNotification(external_models.TransactionIDRequestModel{
+		TransactionId: req.TransactionID,
+	})
+
+	return milestone, http.StatusOK, nil
+}
+
+// ReleaseMilestoneFunds pays out a milestone from the buyer's escrow wallet using the amounts from
+// CalculatePayout, so disbursement always matches what the payout preview showed.
+func ReleaseMilestoneFunds(extReq request.ExternalRequest, repo repository.Repositories, milestone models.Transaction) error {
+	preview, _, err := GetTransactionPayout(repo, milestone.TransactionID)
+	if err != nil {
+		return fmt.Errorf("error calculating payout: %v", err.Error())
+	}
+
+	var payout *models.MilestonePayout
+	for i := range preview.Milestones {
+		if preview.Milestones[i].MilestoneID == milestone.MilestoneID {
+			payout = &preview.Milestones[i]
+		}
+	}
+	if payout == nil {
+		return fmt.Errorf("milestone %v not found in payout", milestone.MilestoneID)
+	}
+	// recipients that were allocated less than the milestone amount are still paid what they were
+	// allocated, but a milestone that would pay out more than it holds is never released.
+	if len(payout.Recipients) == 0 || payout.Unallocated <= -0.01 {
+		return fmt.Errorf("payout does not balance: %v", strings.Join(payout.Issues, "; "))
+	}
+	for _, recipient := range payout.Recipients {
+		if recipient.Payout < 0 {
+			return fmt.Errorf("payout does not balance: %v", strings.Join(payout.Issues, "; "))
+		}
+	}
+
+	buyer, _, err := repo.Parties.GetByTransactionIDAndRole(milestone.TransactionID, "buyer")
+	if err != nil {
+		return fmt.Errorf("buyer not found: %v", err.Error())
+	}
+
+	broker := models.TransactionParty{}
+	if payout.BrokerCharge > 0 {
+		if !preview.BrokerAccepted {
+			return fmt.Errorf("broker charge has not been accepted by both buyer and seller")
+		}
+		broker, _, err = repo.Parties.GetByTransactionIDAndRole(milestone.TransactionID, "broker")
+		if err != nil {
+			return fmt.Errorf("broker not found: %v", err.Error())
+		}
+	}
+
+	var (
+		currency = strings.ToUpper(milestone.Currency)
+		failed   = []string{}
+	)
+	transfer := func(recipientAccountID int, amount float64) {
+		if amount <= 0 {
+			return
+		}
+		_, err := extReq.Payment.WalletTransfer(external_models.WalletTransferRequest{
+			SenderAccountID:    buyer.AccountID,
+			RecipientAccountID: recipientAccountID,
+			FinalAmount:        amount,
+			SenderCurrency:     "ESCROW_" + currency,
+			RecipientCurrency:  currency,
+			TransactionID:      milestone.TransactionID,
+		})
+		if err != nil {
+			extReq.Logger.Error(fmt.Sprintf("wallet transfer error for recipient %v, transaction %v, error: %v", recipientAccountID, milestone.TransactionID, err.Error()))
+			failed = append(failed, fmt.Sprintf("%v", recipientAccountID))
+		}
+	}
+
+	for _, recipient := range payout.Recipients {
+		transfer(recipient.AccountID, recipient.Payout)
+	}
+	if payout.BrokerCharge > 0 {
+		transfer(broker.AccountID, payout.BrokerCharge)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("wallet transfer failed for recipients %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// AggregateMilestoneStatus derives the status shown for a milestone transaction as a whole.
+// Identical milestones share their status, a dispute on any milestone marks the whole transaction
+// as disputed, fully closed milestones close it, and any progress past funding puts it in progress.
+func AggregateMilestoneStatus(milestones []models.Transaction) string {
+	if len(milestones) == 0 {
+		return ""
+	}
+
+	var (
+		same, closed, disbursed, progressed = true, true, true, false
+	)
+	for _, m := range milestones {
+		if !strings.EqualFold(m.Status, milestones[0].Status) {
+			same = false
+		}
+		if statusIn(m.Status, "cd") {
+			return GetTransactionStatus("cd")
+		}
+		if !statusIn(m.Status, "cdc", "cr", "cnf", "closed") {
+			closed = false
+		}
+		if !statusIn(m.Status, "cdc") {
+			disbursed = false
+		}
+		if statusIn(m.Status, "af", "ip", "d", "da", "dr", "cdp", "cmdp", "cdc") {
+			progressed = true
+		}
+	}
+
+	switch {
+	case same:
+		return milestones[0].Status
+	case disbursed:
+		return GetTransactionStatus("cdc")
+	case closed:
+		return GetTransactionStatus("closed")
+	case progressed:
+		return GetTransactionStatus("ip")
+	}
+	return milestones[0].Status
+}
+
+// updateMilestoneStatus moves milestone on from the status it was read at with
+// moveTransactionStatus, so a milestone someone else moved first gives http.StatusConflict.
+func updateMilestoneStatus(repo repository.Repositories, milestone *models.Transaction, statusCode string, accountID int) (int, error) {
+	previousStatus := milestone.Status
+	code, err := moveTransactionStatus(repo, milestone, statusCode)
+	if err != nil {
+		return code, err
+	}
+	RecordStatusChange(*milestone, previousStatus)
+
+	_, err = CreateTransactionState(repo, statusCode, milestone.TransactionID, milestone.MilestoneID, accountID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	createMilestoneActivityLog(repo, *milestone, previousStatus, accountID)
+	return http.StatusOK, nil
+}
+
+func createMilestoneActivityLog(repo repository.Repositories, milestone models.Transaction, previousStatus string, accountID int) {
+	var milestoneTitle string
+	titleSlice := strings.Split(milestone.Title, ";")
+	if len(titleSlice) > 1 {
+		milestoneTitle = titleSlice[1]
+	}
+
+	activityLog := models.ActivityLog{
+		TransactionID:  milestone.TransactionID,
+		MilestoneID:    milestone.MilestoneID,
+		EventType:      models.ActivityTransactionStatusChange,
+		ActorAccountID: accountID,
+		ActorRole:      resolveActorRole(repo, milestone.TransactionID, accountID),
+		PreviousStatus: previousStatus,
+		NewStatus:      milestone.Status,
+		Description:    fmt.Sprintf("Milestone %v status changed to %v", milestoneTitle, milestone.Status),
+	}
+	repo.ActivityLogs.Create(&activityLog)
+}
+
+func statusIn(status string, statusCodes ...string) bool {
+	for _, statusCode := range statusCodes {
+		if statusCode == "" && status == "" {
+			return true
+		}
+		if statusCode != "" && strings.EqualFold(status, GetTransactionStatus(statusCode)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+package transactions
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func PayoutPreviewService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.PayoutPreviewRequest, user external_models.User) (models.PayoutPreview, int, error) {
+	if req.TransactionID != "" {
+		code, err := AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), ActionView)
+		if err != nil {
+			return models.PayoutPreview{}, code, err
+		}
+		return GetTransactionPayout(repo, req.TransactionID)
+	}
+
+	if req.Draft == nil {
+		return models.PayoutPreview{}, http.StatusBadRequest, fmt.Errorf("either transaction_id or draft is required")
+	}
+
+	draft := *req.Draft
+	if err := validatePartiesAndMilestones(draft.Type, draft.Parties, draft.Milestones); err != nil {
+		return models.PayoutPreview{}, http.StatusBadRequest, err
+	}
+
+	businessID := draft.BusinessID
+	if businessID == 0 {
+		businessID = int(user.BusinessId)
+	}
+	if businessID == 0 {
+		businessID = int(user.AccountID)
+	}
+
+	businessCharge, err := getBusinessChargeWithBusinessIDAndCurrency(extReq, businessID, draft.Currency)
+	if err != nil {
+		businessCharge, err = initBusinessCharge(extReq, businessID, draft.Currency)
+		if err != nil {
+			return models.PayoutPreview{}, http.StatusInternalServerError, err
+		}
+	}
+
+	escrowFee, err := getEscrowFee(repo, businessCharge, businessID, draft.Currency, draft.Type, getTotalAmoutForMilestones(draft.Milestones))
+	if err != nil {
+		return models.PayoutPreview{}, http.StatusInternalServerError, err
+	}
+	if draft.Source == "transfer" {
+		escrowFee = models.EscrowFee{Charge: 2, ChargeBearer: models.ChargeBearerBuyer}
+		escrowFee.Split(0)
+	}
+
+	milestones := []models.Transaction{}
+	for i, m := range draft.Milestones {
+		recipientsJson, err := json.Marshal(m.Recipients)
+		if err != nil {
+			return models.PayoutPreview{}, http.StatusBadRequest, err
+		}
+		milestones = append(milestones, models.Transaction{
+			Title:              draft.Title + ";" + m.Title + ";" + strconv.Itoa(int(draft.Amount)) + ";" + strconv.Itoa(i+1),
+			Amount:             m.Amount,
+			ShippingFee:        m.ShippingFee,
+			Currency:           strings.ToUpper(draft.Currency),
+			EscrowCharge:       escrowFee.Charge,
+			SellerEscrowCharge: escrowFee.SellerCharge,
+			Recipients:         string(recipientsJson),
+		})
+	}
+
+	broker := models.TransactionBroker{
+		BrokerCharge:       req.BrokerCharge,
+		BrokerChargeBearer: req.BrokerChargeBearer,
+		BrokerChargeType:   req.BrokerChargeType,
+	}
+	if broker.BrokerCharge == "" && draft.Broker != nil {
+		broker.BrokerCharge = draft.Broker.BrokerCharge
+		broker.BrokerChargeBearer = draft.Broker.BrokerChargeBearer
+		broker.BrokerChargeType = draft.Broker.BrokerChargeType
+	}
+
+	preview := CalculatePayout(milestones, broker)
+	if draft.Amount < preview.MilestonesAmount {
+		preview.Balanced = false
+		preview.Issues = append(preview.Issues, "transaction amount cannot be less than the sum of amounts for milestones")
+	}
+
+	return preview, http.StatusOK, nil
+}
+
+// GetTransactionPayout loads a stored transaction and its broker and runs them through CalculatePayout.
+func GetTransactionPayout(repo repository.Repositories, transactionID string) (models.PayoutPreview, int, error) {
+	milestones, err := repo.Transactions.ListByTransactionID(transactionID)
+	if err != nil {
+		return models.PayoutPreview{}, http.StatusInternalServerError, err
+	}
+	if len(milestones) == 0 {
+		return models.PayoutPreview{}, http.StatusBadRequest, fmt.Errorf("transaction not found")
+	}
+
+	broker, code, err := repo.Brokers.GetByTransactionID(transactionID)
+	if err != nil && code == http.StatusInternalServerError {
+		return models.PayoutPreview{}, code, err
+	}
+
+	broker.BrokerChargeBearer = resolveBrokerChargeBearer(repo, broker)
+	return CalculatePayout(milestones, broker), http.StatusOK, nil
+}
+
+// CalculatePayout works out what each milestone recipient, the broker and the platform receive.
+// Recipients share their milestone's shipping fee in proportion to their amounts and, when the seller
+// bears them, the milestone's broker charge and its part of the escrow charge. The escrow charge is
+// stored on every milestone row as the charge for the whole transaction, so it is counted once and
+// spread over milestones by amount.
+func CalculatePayout(milestones []models.Transaction, broker models.TransactionBroker) models.PayoutPreview {
+	var (
+		preview = models.PayoutPreview{Balanced: true, Issues: []string{}, Milestones: []models.MilestonePayout{}}
+		amounts = []float64{}
+	)
+	if len(milestones) == 0 {
+		return preview
+	}
+
+	for _, m := range milestones {
+		amounts = append(amounts, m.Amount)
+		preview.MilestonesAmount += m.Amount
+		preview.ShippingFee += m.ShippingFee
+	}
+
+	preview.TransactionID = milestones[0].TransactionID
+	preview.Currency = milestones[0].Currency
+	preview.EscrowCharge = utility.RoundFloat(milestones[0].EscrowCharge, 2)
+	preview.SellerEscrowCharge = utility.RoundFloat(milestones[0].SellerEscrowCharge, 2)
+	preview.BrokerCharge = utility.RoundFloat(getBrokerCharge(broker, preview.MilestonesAmount, preview.MilestonesAmount), 2)
+	preview.BrokerChargeBearer = getBrokerChargeBearer(broker)
+	preview.BrokerAccepted = brokerChargeAccepted(broker)
+
+	escrowShares := splitByWeight(preview.EscrowCharge, amounts)
+	sellerEscrowShares := splitByWeight(preview.SellerEscrowCharge, amounts)
+	brokerShares := splitByWeight(preview.BrokerCharge, amounts)
+
+	for i, m := range milestones {
+		mp := calculateMilestonePayout(i, m, escrowShares[i], sellerEscrowShares[i], brokerShares[i], preview.BrokerChargeBearer)
+		if !mp.Balanced {
+			preview.Balanced = false
+			for _, issue := range mp.Issues {
+				preview.Issues = append(preview.Issues, fmt.Sprintf("milestone %v: %v", mp.Index, issue))
+			}
+		}
+		preview.Milestones = append(preview.Milestones, mp)
+	}
+
+	preview.MilestonesAmount = utility.RoundFloat(preview.MilestonesAmount, 2)
+	preview.ShippingFee = utility.RoundFloat(preview.ShippingFee, 2)
+	preview.PlatformAmount = preview.EscrowCharge
+	preview.BrokerAmount = preview.BrokerCharge
+	preview.BuyerTotal = preview.MilestonesAmount + preview.ShippingFee + preview.EscrowCharge - preview.SellerEscrowCharge
+	if preview.BrokerChargeBearer == "buyer" {
+		preview.BuyerTotal += preview.BrokerCharge
+	}
+	preview.BuyerTotal = utility.RoundFloat(preview.BuyerTotal, 2)
+
+	return preview
+}
+
+func calculateMilestonePayout(position int, milestone models.Transaction, escrowCharge, sellerEscrowCharge, brokerCharge float64, bearer string) models.MilestonePayout {
+	var (
+		recipients      []models.MileStoneRecipient
+		recipientAmount = []float64{}
+		recipientsTotal float64
+		titleSlice      = strings.Split(milestone.Title, ";")
+		mp              = models.MilestonePayout{
+			Index:              position + 1,
+			MilestoneID:        milestone.MilestoneID,
+			Title:              milestone.Title,
+			Amount:             milestone.Amount,
+			ShippingFee:        milestone.ShippingFee,
+			EscrowCharge:       escrowCharge,
+			SellerEscrowCharge: sellerEscrowCharge,
+			BrokerCharge:       brokerCharge,
+			Recipients:         []models.RecipientPayout{},
+			Balanced:           true,
+			Issues:             []string{},
+		}
+	)
+
+	if len(titleSlice) > 1 {
+		mp.Title = titleSlice[1]
+	}
+	if len(titleSlice) > 3 {
+		if index, err := strconv.Atoi(titleSlice[3]); err == nil {
+			mp.Index = index
+		}
+	}
+
+	err := json.Unmarshal([]byte(milestone.Recipients), &recipients)
+	if err != nil || len(recipients) == 0 {
+		mp.Balanced = false
+		mp.Issues = append(mp.Issues, "no recipients")
+		return mp
+	}
+
+	for _, r := range recipients {
+		recipientAmount = append(recipientAmount, r.Amount)
+		recipientsTotal += r.Amount
+	}
+
+	mp.Unallocated = utility.RoundFloat(milestone.Amount-recipientsTotal, 2)
+	if math.Abs(mp.Unallocated) >= 0.01 {
+		mp.Balanced = false
+		mp.Issues = append(mp.Issues, fmt.Sprintf("recipient amounts (%v) do not add up to the milestone amount (%v)", utility.RoundFloat(recipientsTotal, 2), milestone.Amount))
+	}
+
+	sellerBrokerCharge := 0.0
+	if bearer == "seller" {
+		sellerBrokerCharge = brokerCharge
+	}
+	shippingShares := splitByWeight(milestone.ShippingFee, recipientAmount)
+	brokerShares := splitByWeight(sellerBrokerCharge, recipientAmount)
+	escrowShares := splitByWeight(sellerEscrowCharge, recipientAmount)
+
+	for i, r := range recipients {
+		payout := models.RecipientPayout{
+			AccountID:    r.AccountID,
+			Amount:       r.Amount,
+			ShippingFee:  shippingShares[i],
+			EscrowCharge: escrowShares[i],
+			BrokerCharge: brokerShares[i],
+			Payout:       utility.RoundFloat(r.Amount+shippingShares[i]-escrowShares[i]-brokerShares[i], 2),
+		}
+		if payout.Payout < 0 {
+			mp.Balanced = false
+			mp.Issues = append(mp.Issues, fmt.Sprintf("payout for recipient %v is negative", r.AccountID))
+		}
+		mp.Recipients = append(mp.Recipients, payout)
+	}
+
+	return mp
+}
+
+// getBrokerCharge returns the broker's charge on amount. Fixed charges are spread over the transaction
+// in proportion to amount's share of total.
+func getBrokerCharge(broker models.TransactionBroker, amount, total float64) float64 {
+	charge, err := strconv.ParseFloat(strings.TrimSpace(broker.BrokerCharge), 64)
+	if err != nil || charge <= 0 || total <= 0 {
+		return 0
+	}
+	if strings.EqualFold(broker.BrokerChargeType, "percentage") {
+		return utility.PercentageOf(amount, charge)
+	}
+	return charge * amount / total
+}
+
+// getBuyerBrokerCharge is the part of the broker charge added to what the buyer pays.
+func getBuyerBrokerCharge(broker models.TransactionBroker, total float64) float64 {
+	if getBrokerChargeBearer(broker) != "buyer" {
+		return 0
+	}
+	return utility.RoundFloat(getBrokerCharge(broker, total, total), 2)
+}
+
+// brokerChargeAccepted reports whether a transaction's broker charge can be collected and paid out,
+// which needs both the buyer and the seller to have accepted it.
+func brokerChargeAccepted(broker models.TransactionBroker) bool {
+	if getBrokerCharge(broker, 1, 1) == 0 {
+		return true
+	}
+	return broker.IsBuyerAccepted && broker.IsSellerAccepted
+}
+
+// resolveBrokerChargeBearer maps older broker records, which hold the bearer's account id rather
+// than a role, onto the role that account has on the transaction.
+func resolveBrokerChargeBearer(repo repository.Repositories, broker models.TransactionBroker) string {
+	accountID, err := strconv.Atoi(strings.TrimSpace(broker.BrokerChargeBearer))
+	if err != nil {
+		return broker.BrokerChargeBearer
+	}
+
+	party, _, err := repo.Parties.GetByTransactionIDAndAccountID(broker.TransactionID, accountID)
+	if err != nil {
+		return broker.BrokerChargeBearer
+	}
+	return party.Role
+}
+
+func getBrokerChargeBearer(broker models.TransactionBroker) string {
+	if strings.EqualFold(broker.BrokerChargeBearer, "seller") {
+		return "seller"
+	}
+	return "buyer"
+}
+
+// splitByWeight divides total in proportion to weights, rounded to two decimal places, with the
+// last share absorbing the rounding difference so the shares always add back up to total.
+func splitByWeight(total float64, weights []float64) []float64 {
+	var (
+		shares      = make([]float64, len(weights))
+		weightTotal float64
+		allocated   float64
+	)
+	for _, w := range weights {
+		weightTotal += w
+	}
+	if len(weights) == 0 || total == 0 {
+		return shares
+	}
+
+	for i, w := range weights {
+		if i == len(weights)-1 {
+			shares[i] = utility.RoundFloat(total-allocated, 2)
+			break
+		}
+		share := total / float64(len(weights))
+		if weightTotal > 0 {
+			share = total * w / weightTotal
+		}
+		shares[i] = utility.RoundFloat(share, 2)
+		allocated += shares[i]
+	}
+	return shares
+}
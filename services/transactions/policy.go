@@ -0,0 +1,126 @@
+package transactions
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+)
+
+// Action is an operation a party performs on a transaction.
+type Action string
+
+const (
+	ActionView             Action = "view"
+	ActionDeliver          Action = "deliver"
+	ActionAccept           Action = "accept"
+	ActionApproveRelease   Action = "approve_release"
+	ActionDispute          Action = "dispute"
+	ActionRequestExtension Action = "request_extension"
+	ActionApproveExtension Action = "approve_extension"
+	ActionArchive          Action = "archive"
+	ActionEdit             Action = "edit"
+	ActionDelete           Action = "delete"
+	ActionRestore          Action = "restore"
+)
+
+// partyPermission is what a party needs to perform an action: one of Roles, when any are listed,
+// and Capability in its role capabilities, when one is named.
+type partyPermission struct {
+	Roles       []string
+	Capability  string
+	Description string
+}
+
+var partyPermissions = map[Action]partyPermission{
+	ActionView:             {Capability: "can_view", Description: "view"},
+	ActionDeliver:          {Roles: []string{"seller", "recipient"}, Capability: "mark_as_done", Description: "mark as delivered"},
+	ActionAccept:           {Roles: []string{"buyer", "seller", "sender", "recipient"}, Description: "accept or reject"},
+	ActionApproveRelease:   {Roles: []string{"buyer", "sender"}, Capability: "approve", Description: "approve or reject the delivery of"},
+	ActionDispute:          {Roles: []string{"buyer", "seller", "sender", "recipient"}, Description: "dispute"},
+	ActionRequestExtension: {Roles: []string{"seller", "recipient"}, Description: "request a due date extension on"},
+	ActionApproveExtension: {Roles: []string{"buyer", "sender"}, Capability: "approve", Description: "approve a due date extension on"},
+	ActionArchive:          {Description: "archive"},
+	ActionEdit:             {Roles: []string{"buyer", "seller", "sender"}, Description: "edit"},
+	ActionDelete:           {Roles: []string{"buyer", "seller", "sender"}, Description: "delete"},
+	ActionRestore:          {Roles: []string{"buyer", "seller", "sender"}, Description: "restore"},
+}
+
+// AuthorizePartyAction checks that accountID holds a party record on the transaction that permits
+// action, returning 403 when none does.
+func AuthorizePartyAction(repo repository.Repositories, transactionID string, accountID int, action Action) (int, error) {
+	parties, err := repo.Parties.ListByTransactionID(transactionID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return authorizeParties(parties, accountID, action)
+}
+
+// AuthorizeDeletedPartyAction is AuthorizePartyAction for a soft deleted transaction, as returned
+// by GetDeletedByTransactionID, checked against the parties deleted with it.
+func AuthorizeDeletedPartyAction(repo repository.Repositories, transaction models.Transaction, accountID int, action Action) (int, error) {
+	parties, err := repo.Parties.ListDeletedByTransactionID(transaction.TransactionID, transaction.DeletedAt.Time)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return authorizeParties(parties, accountID, action)
+}
+
+func authorizeParties(parties []models.TransactionParty, accountID int, action Action) (int, error) {
+	permission, ok := partyPermissions[action]
+	if !ok {
+		return http.StatusInternalServerError, fmt.Errorf("unknown action %v", action)
+	}
+
+	for _, party := range parties {
+		if party.AccountID == accountID && permission.allows(party) {
+			return http.StatusOK, nil
+		}
+	}
+	return http.StatusForbidden, fmt.Errorf("you are not permitted to %v this transaction", permission.Description)
+}
+
+func (p partyPermission) allows(party models.TransactionParty) bool {
+	if p.Capability != "" && !party.HasCapability(p.Capability) {
+		return false
+	}
+	if len(p.Roles) == 0 {
+		return true
+	}
+	for _, role := range p.Roles {
+		if strings.EqualFold(party.Role, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusAction is the action a party performs by moving a transaction to statusCode.
+func statusAction(statusCode string) Action {
+	switch statusCode {
+	case "d":
+		return ActionDeliver
+	case "da", "dr", "cdp", "cdc":
+		return ActionApproveRelease
+	case "cd":
+		return ActionDispute
+	case "af", "anf", "sr", "fr":
+		return ActionAccept
+	}
+	return ActionView
+}
+
+// AuthorizeBusinessTransaction checks that the transaction belongs to businessID, returning 403
+// when it belongs to another business.
+func AuthorizeBusinessTransaction(repo repository.Repositories, transactionID string, businessID int) (int, error) {
+	transaction, code, err := repo.Transactions.GetByTransactionID(transactionID)
+	if err != nil {
+		return code, err
+	}
+	if transaction.BusinessID != businessID {
+		return http.StatusForbidden, fmt.Errorf("transaction does not belong to your business")
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,294 @@
+package transactions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+// listWorkers bounds how many calls to other services a listing makes at once.
+var listWorkers = 10
+
+// loadTransactionResponses builds the responses for a page of transactions. Related rows for the whole
+// page are read with one query per table and lookups on other services go through a bounded worker pool,
+// so the cost of a page no longer grows with a query per transaction. Only the sections in fields are
+// loaded. Transactions that no longer exist are left out and the rest keep the order of transactionIDs.
+func loadTransactionResponses(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, transactionIDs []string, fields models.TransactionFields) ([]models.TransactionByIDResponse, error) {
+	var (
+		ids       = uniqueStrings(transactionIDs)
+		responses = []models.TransactionByIDResponse{}
+	)
+	if len(ids) == 0 {
+		return responses, nil
+	}
+
+	rows, err := repo.Transactions.ListByTransactionIDs(ids)
+	if err != nil {
+		return responses, err
+	}
+	transactionRows := map[string][]models.Transaction{}
+	partiesIDs := []string{}
+	for _, row := range rows {
+		if len(transactionRows[row.TransactionID]) == 0 {
+			partiesIDs = append(partiesIDs, row.PartiesID)
+		}
+		transactionRows[row.TransactionID] = append(transactionRows[row.TransactionID], row)
+	}
+
+	products := map[string][]models.ProductTransaction{}
+	if fields.Has(models.TransactionFieldProducts) {
+		productRows, err := repo.Products.ListByTransactionIDs(ids)
+		if err != nil {
+			return responses, err
+		}
+		for _, p := range productRows {
+			products[p.TransactionID] = append(products[p.TransactionID], p)
+		}
+	}
+
+	parties := map[string][]models.TransactionParty{}
+	if fields.Has(models.TransactionFieldParties) {
+		partyRows, err := repo.Parties.ListByTransactionIDsOrPartiesIDs(ids, uniqueStrings(partiesIDs))
+		if err != nil {
+			return responses, err
+		}
+		byTransactionID := map[string][]models.TransactionParty{}
+		byPartiesID := map[string][]models.TransactionParty{}
+		for _, p := range partyRows {
+			byTransactionID[p.TransactionID] = append(byTransactionID[p.TransactionID], p)
+			byPartiesID[p.TransactionPartiesID] = append(byPartiesID[p.TransactionPartiesID], p)
+		}
+		for id, tRows := range transactionRows {
+			if tRows[0].Type == "milestone" || len(byTransactionID[id]) == 0 {
+				parties[id] = byPartiesID[tRows[0].PartiesID]
+			} else {
+				parties[id] = byTransactionID[id]
+			}
+		}
+	}
+
+	files := map[string][]models.TransactionFile{}
+	if fields.Has(models.TransactionFieldFiles) {
+		fileRows, err := repo.Files.ListByTransactionIDs(ids)
+		if err != nil {
+			return responses, err
+		}
+		for _, f := range fileRows {
+			files[f.TransactionID] = append(files[f.TransactionID], f)
+		}
+	}
+
+	brokers := map[string]models.TransactionBroker{}
+	if fields.Has(models.TransactionFieldBroker) {
+		brokerRows, err := repo.Brokers.ListByTransactionIDs(ids)
+		if err != nil {
+			logger.Error("error getting transaction brokers", err.Error())
+		}
+		for _, b := range brokerRows {
+			if _, ok := brokers[b.TransactionID]; !ok {
+				brokers[b.TransactionID] = b
+			}
+		}
+	}
+
+	activities := map[string][]models.ActivityLog{}
+	if fields.Has(models.TransactionFieldActivities) {
+		activityRows, err := repo.ActivityLogs.ListByTransactionIDs(ids)
+		if err != nil {
+			return responses, err
+		}
+		for _, a := range activityRows {
+			activities[a.TransactionID] = append(activities[a.TransactionID], a)
+		}
+	}
+
+	closedStates := map[string]models.TransactionState{}
+	if fields.Has(models.TransactionFieldClosedAt) {
+		stateRows, err := repo.States.ListByTransactionIDsAndStatus(ids, "Closed")
+		if err != nil {
+			return responses, err
+		}
+		for _, st := range stateRows {
+			if _, ok := closedStates[st.TransactionID]; !ok {
+				closedStates[st.TransactionID] = st
+			}
+		}
+	}
+
+	disputed := map[string]bool{}
+	if fields.Has(models.TransactionFieldDispute) {
+		disputeRows, err := repo.Disputes.ListByTransactionIDs(ids)
+		if err != nil {
+			return responses, err
+		}
+		for _, d := range disputeRows {
+			disputed[d.TransactionID] = true
+		}
+	}
+
+	recipients := map[string][][]models.MileStoneRecipient{}
+	accountIDs := []int{}
+	for id, tRows := range transactionRows {
+		for _, p := range parties[id] {
+			accountIDs = append(accountIDs, p.AccountID)
+		}
+		if fields.Has(models.TransactionFieldMilestones) {
+			for _, row := range tRows {
+				rowRecipients := getMilestoneRecipients(extReq, row)
+				recipients[id] = append(recipients[id], rowRecipients)
+				for _, r := range rowRecipients {
+					accountIDs = append(accountIDs, r.AccountID)
+				}
+			}
+		}
+	}
+	users := GetUsersWithAccountIDs(extReq, accountIDs)
+
+	countries := map[string]external_models.Country{}
+	if fields.Has(models.TransactionFieldCountry) {
+		countryCodes := []string{}
+		for _, tRows := range transactionRows {
+			countryCodes = append(countryCodes, tRows[0].Country)
+		}
+		countries = getCountriesByNameOrCode(extReq, logger, uniqueStrings(countryCodes))
+	}
+
+	for _, id := range ids {
+		tRows, ok := transactionRows[id]
+		if !ok {
+			logger.Error("list transaction by id error", fmt.Sprintf("transaction %v not found", id))
+			continue
+		}
+		transaction := tRows[0]
+
+		transactionReponse := resolveTransactionAndListTransactionResponse(transaction)
+		if transaction.Type == "milestone" {
+			transactionReponse.Status = AggregateMilestoneStatus(tRows)
+		}
+		if fields.Has(models.TransactionFieldProducts) {
+			transactionReponse.Products = products[id]
+		}
+		if fields.Has(models.TransactionFieldParties) {
+			partiess, members, err := buildPartiesAndMembers(parties[id], users)
+			if err != nil {
+				return responses, err
+			}
+			transactionReponse.Parties = partiess
+			transactionReponse.Members = members
+		}
+		if fields.Has(models.TransactionFieldFiles) {
+			transactionReponse.Files = files[id]
+		}
+		if fields.Has(models.TransactionFieldMilestones) {
+			milestones := []models.MilestonesResponse{}
+			for i, row := range tRows {
+				totalAmount, milestone := buildMilestoneResponse(row, recipients[id][i], users)
+				if i == 0 {
+					transactionReponse.TotalAmount = totalAmount
+				}
+				milestones = append(milestones, milestone)
+			}
+			sort.SliceStable(milestones, func(i, j int) bool {
+				return milestones[i].Index < milestones[j].Index
+			})
+			transactionReponse.Milestones = milestones
+		}
+		if fields.Has(models.TransactionFieldBroker) {
+			transactionReponse.Broker = brokers[id]
+		}
+		if fields.Has(models.TransactionFieldActivities) {
+			transactionReponse.Activities = activities[id]
+		}
+		if fields.Has(models.TransactionFieldCountry) {
+			transactionReponse.Country = countries[transaction.Country]
+		}
+		if fields.Has(models.TransactionFieldClosedAt) {
+			transactionReponse.TransactionClosedAt = closedStates[id].CreatedAt
+		}
+		transactionReponse.IsDisputed = disputed[id]
+
+		dDateFormatted, err := utility.FormatDate(transaction.DueDate, "2006-01-02", "2006-01-02 15:04:05")
+		if err != nil {
+			dDateFormatted = ""
+		}
+		transactionReponse.DueDateFormatted = dDateFormatted
+		transactionReponse.Title = strings.Split(transactionReponse.Title, ";")[0]
+
+		responses = append(responses, transactionReponse)
+	}
+	return responses, nil
+}
+
+// addPaymentTotals replaces the total amount and escrow charge of each response with those of its
+// payment, or zero when the payment cannot be found.
+func addPaymentTotals(extReq request.ExternalRequest, logger *utility.Logger, responses []models.TransactionByIDResponse) {
+	forEachBounded(len(responses), func(i int) {
+		payment, err := ListPayment(extReq, responses[i].TransactionID)
+		if err != nil {
+			responses[i].TotalAmount = 0
+			responses[i].EscrowCharge = 0
+			logger.Error("list payment by transaction id error", err.Error())
+			return
+		}
+		responses[i].TotalAmount = payment.TotalAmount
+		responses[i].EscrowCharge = payment.EscrowCharge
+	})
+}
+
+func getCountriesByNameOrCode(extReq request.ExternalRequest, logger *utility.Logger, namesOrCodes []string) map[string]external_models.Country {
+	var (
+		countries = map[string]external_models.Country{}
+		mu        sync.Mutex
+	)
+	forEachBounded(len(namesOrCodes), func(i int) {
+		country, err := GetCountryByNameOrCode(extReq, logger, namesOrCodes[i])
+		if err != nil {
+			logger.Error("error getting country", err.Error())
+			return
+		}
+		mu.Lock()
+		countries[namesOrCodes[i]] = country
+		mu.Unlock()
+	})
+	return countries
+}
+
+// forEachBounded calls fn for every index below n, running at most listWorkers calls at a time.
+func forEachBounded(n int, fn func(i int)) {
+	var (
+		wg    sync.WaitGroup
+		slots = make(chan struct{}, listWorkers)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		unique = append(unique, v)
+	}
+	return unique
+}
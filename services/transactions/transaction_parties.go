@@ -8,15 +8,14 @@ import (
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func UpdateTransactionPartiesService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.UpdateTransactionPartiesRequest) (int, error) {
+func UpdateTransactionPartiesService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.UpdateTransactionPartiesRequest) (int, error) {
 
 	var (
-		transactionID = models.Transaction{TransactionID: req.TransactionID}
-		users         = map[string]external_models.User{}
+		users = map[string]external_models.User{}
 	)
 
 	if len(req.Parties) < 1 {
@@ -35,14 +34,13 @@ func UpdateTransactionPartiesService(extReq request.ExternalRequest, logger *uti
 		users[name] = user
 	}
 
-	code, err := transactionID.GetTransactionByTransactionID(db.Transaction)
+	transaction, code, err := repo.Transactions.GetByTransactionID(req.TransactionID)
 	if err != nil {
 		return code, err
 	}
 
 	for name, party := range req.Parties {
-		transactionParty := models.TransactionParty{TransactionPartiesID: transactionID.PartiesID, Role: name}
-		code, err := transactionParty.GetTransactionPartyByTransactionPartiesIDAndRole(db.Transaction)
+		transactionParty, code, err := repo.Parties.GetByTransactionPartiesIDAndRole(transaction.PartiesID, name)
 		if err != nil {
 			if code == http.StatusInternalServerError {
 				return http.StatusInternalServerError, err
@@ -59,9 +57,9 @@ func UpdateTransactionPartiesService(extReq request.ExternalRequest, logger *uti
 			}
 			transactionParty.AccountID = party.AccountID
 			transactionParty.RoleCapabilities = roleCapabilities
-			err = transactionParty.UpdateAllFields(db.Transaction)
+			err = repo.Parties.Update(&transactionParty)
 			if err != nil {
-				return http.StatusInternalServerError, err
+				return updateErrorCode(err), err
 			}
 		}
 
@@ -69,17 +67,14 @@ func UpdateTransactionPartiesService(extReq request.ExternalRequest, logger *uti
 	return http.StatusOK, nil
 }
 
-func UpdateTransactionPartyStatusService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.UpdateTransactionPartyStatusRequest) (int, error) {
+func UpdateTransactionPartyStatusService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.UpdateTransactionPartyStatusRequest) (int, error) {
 
-	var (
-		transactionParty = models.TransactionParty{TransactionID: req.TransactionID, AccountID: req.AccountID}
-	)
 	user, err := GetUserWithAccountID(extReq, req.AccountID)
 	if err != nil {
 		return http.StatusBadRequest, err
 	}
 
-	code, err := transactionParty.GetTransactionPartyByTransactionIDAndAccountID(db.Transaction)
+	transactionParty, code, err := repo.Parties.GetByTransactionIDAndAccountID(req.TransactionID, req.AccountID)
 	if err != nil {
 		if code == http.StatusInternalServerError {
 			return code, err
@@ -88,17 +83,26 @@ func UpdateTransactionPartyStatusService(extReq request.ExternalRequest, logger
 		return code, fmt.Errorf("this transaction has no party with this account id")
 	}
 
+	previousStatus := transactionParty.Status
 	transactionParty.Status = req.Status
-	err = transactionParty.UpdateAllFields(db.Transaction)
+	err = repo.Parties.Update(&transactionParty)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
 
 	activityLog := models.ActivityLog{
-		TransactionID: req.TransactionID,
-		Description:   fmt.Sprintf("%v has %v transaction invitation", user.EmailAddress, req.Status),
-	}
-	err = activityLog.CreateActivityLog(db.Transaction)
+		TransactionID:  req.TransactionID,
+		EventType:      models.ActivityPartyStatusChange,
+		ActorAccountID: req.AccountID,
+		ActorRole:      transactionParty.Role,
+		PreviousStatus: previousStatus,
+		NewStatus:      req.Status,
+		Metadata: map[string]interface{}{
+			"party_id": transactionParty.ID,
+		},
+		Description: fmt.Sprintf("%v has %v transaction invitation", user.EmailAddress, req.Status),
+	}
+	err = repo.ActivityLogs.Create(&activityLog)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -106,10 +110,10 @@ func UpdateTransactionPartyStatusService(extReq request.ExternalRequest, logger
 	return http.StatusOK, nil
 }
 
-func AssignTransactionBuyerService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.AssignTransactionBuyerRequest) (int, error) {
+func AssignTransactionBuyerService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.AssignTransactionBuyerRequest) (int, error) {
 	var (
 		phoneNumber, _ = utility.PhoneValid(req.PhoneNumber)
-		transaction    = models.Transaction{TransactionID: req.TransactionID, TransUssdCode: req.UssdCode}
+		transaction    models.Transaction
 		roles          = []string{"buyer", "recipient", "charge_bearer"}
 	)
 
@@ -123,12 +127,14 @@ func AssignTransactionBuyerService(extReq request.ExternalRequest, logger *utili
 	}
 
 	if req.TransactionID != "" {
-		code, err := transaction.GetTransactionByTransactionID(db.Transaction)
+		var code int
+		transaction, code, err = repo.Transactions.GetByTransactionID(req.TransactionID)
 		if err != nil {
 			return code, err
 		}
 	} else if req.UssdCode != 0 {
-		code, err := transaction.GetTransactionByUssdCode(db.Transaction)
+		var code int
+		transaction, code, err = repo.Transactions.GetByUssdCode(req.UssdCode)
 		if err != nil {
 			return code, err
 		}
@@ -137,8 +143,7 @@ func AssignTransactionBuyerService(extReq request.ExternalRequest, logger *utili
 	}
 
 	for _, role := range roles {
-		party := models.TransactionParty{TransactionPartiesID: transaction.PartiesID, Role: role}
-		code, err := party.GetTransactionPartyByTransactionPartiesIDAndRole(db.Transaction)
+		party, code, err := repo.Parties.GetByTransactionPartiesIDAndRole(transaction.PartiesID, role)
 		if err != nil {
 			if code == http.StatusInternalServerError {
 				return code, err
@@ -147,15 +152,15 @@ func AssignTransactionBuyerService(extReq request.ExternalRequest, logger *utili
 			party.TransactionID = transaction.TransactionID
 			party.TransactionPartiesID = transaction.PartiesID
 			party.Role = "buyer"
-			err = party.CreateTransactionParty(db.Transaction)
+			err = repo.Parties.Create(&party)
 			if err != nil {
 				return http.StatusInternalServerError, err
 			}
 		} else {
 			party.AccountID = int(user.AccountID)
-			err = party.UpdateAllFields(db.Transaction)
+			err = repo.Parties.Update(&party)
 			if err != nil {
-				return http.StatusInternalServerError, err
+				return updateErrorCode(err), err
 			}
 		}
 	}
@@ -163,12 +168,8 @@ func AssignTransactionBuyerService(extReq request.ExternalRequest, logger *utili
 	return http.StatusOK, nil
 }
 
-func UpdateTransactionBrokerService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.UpdateTransactionBrokerRequest) (int, error) {
-	var (
-		broker = models.TransactionBroker{TransactionID: req.TransactionID}
-	)
-
-	code, err := broker.GetTransactionBrokerByTransactionID(db.Transaction)
+func UpdateTransactionBrokerService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.UpdateTransactionBrokerRequest) (int, error) {
+	broker, code, err := repo.Brokers.GetByTransactionID(req.TransactionID)
 	if err != nil {
 		if code == http.StatusInternalServerError {
 			return code, err
@@ -177,6 +178,19 @@ func UpdateTransactionBrokerService(extReq request.ExternalRequest, logger *util
 
 	}
 
+	termsChanged := (req.BrokerCharge != "" && req.BrokerCharge != broker.BrokerCharge) ||
+		(req.BrokerChargeBearer != "" && req.BrokerChargeBearer != broker.BrokerChargeBearer) ||
+		(req.BrokerChargeType != "" && req.BrokerChargeType != broker.BrokerChargeType)
+	if termsChanged {
+		// the payment was created with the previous charge, so it would no longer match the new terms
+		if _, err := ListPayment(extReq, broker.TransactionID); err == nil {
+			return http.StatusBadRequest, fmt.Errorf("broker terms cannot be changed once the transaction payment has been created")
+		}
+		// acceptance was given for the previous terms
+		broker.IsBuyerAccepted = false
+		broker.IsSellerAccepted = false
+	}
+
 	if req.BrokerCharge != "" {
 		broker.BrokerCharge = req.BrokerCharge
 	}
@@ -197,9 +211,9 @@ func UpdateTransactionBrokerService(extReq request.ExternalRequest, logger *util
 		broker.IsSellerAccepted = *req.IsSellerAccepted
 	}
 
-	err = broker.UpdateAllFields(db.Transaction)
+	err = repo.Brokers.Update(&broker)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
 	return http.StatusOK, nil
 }
@@ -8,13 +8,12 @@ import (
 	"github.com/vesicash/transactions-ms/external/external_models"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func UpdateTransactionStatusService(extReq request.ExternalRequest, logger *utility.Logger, db postgresql.Databases, req models.UpdateTransactionStatusRequest, user external_models.User) (int, error) {
+func UpdateTransactionStatusService(extReq request.ExternalRequest, logger *utility.Logger, repo repository.Repositories, req models.UpdateTransactionStatusRequest, user external_models.User) (int, error) {
 	var (
-		transaction               = models.Transaction{TransactionID: req.TransactionID, MilestoneID: req.MilestoneID}
 		localStatus               = ""
 		transactionMessage        = ""
 		closedTransactionMessage  = ""
@@ -23,7 +22,13 @@ func UpdateTransactionStatusService(extReq request.ExternalRequest, logger *util
 		message                   = ""
 	)
 
-	code, err := transaction.GetTransactionByTransactionIDAndMilestoneID(db.Transaction)
+	transaction, code, err := repo.Transactions.GetByTransactionIDAndMilestoneID(req.TransactionID, req.MilestoneID)
+	if err != nil {
+		return code, err
+	}
+	previousStatus := transaction.Status
+
+	code, err = AuthorizePartyAction(repo, req.TransactionID, int(user.AccountID), statusAction(req.Status))
 	if err != nil {
 		return code, err
 	}
@@ -33,8 +38,7 @@ func UpdateTransactionStatusService(extReq request.ExternalRequest, logger *util
 	}
 
 	if req.Status == "sr" {
-		mainTransaction := models.Transaction{TransactionID: req.TransactionID}
-		code, err := mainTransaction.GetTransactionByTransactionID(db.Transaction)
+		mainTransaction, code, err := repo.Transactions.GetByTransactionID(req.TransactionID)
 		if err != nil {
 			return code, err
 		}
@@ -44,10 +48,11 @@ func UpdateTransactionStatusService(extReq request.ExternalRequest, logger *util
 			localStatus = GetTransactionStatus("closed")
 			closedTransactionMessage = "Transaction has been closed."
 		} else {
-			_, err := extReq.SendExternalRequest(request.RequestManualRefund, req.TransactionID)
+			_, err := extReq.Payment.RequestManualRefund(req.TransactionID)
 			if err != nil {
 				return http.StatusInternalServerError, fmt.Errorf("refund failed")
 			}
+			RecordRefund(mainTransaction.Currency, amountPaid)
 			localStatus = GetTransactionStatus("cr")
 			closedTransactionMessage = "Transaction has closed and payment refunded back to buyer."
 		}
@@ -84,16 +89,16 @@ func UpdateTransactionStatusService(extReq request.ExternalRequest, logger *util
 	} else {
 		transaction.Status = status
 	}
-	err = transaction.UpdateAllFields(db.Transaction)
+	err = repo.Transactions.Update(&transaction)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return updateErrorCode(err), err
 	}
 
 	if req.Status == "da" {
 		transaction.Status = GetTransactionStatus("cdp")
-		err = transaction.UpdateAllFields(db.Transaction)
+		err = repo.Transactions.Update(&transaction)
 		if err != nil {
-			return http.StatusInternalServerError, err
+			return updateErrorCode(err), err
 		}
 		transactionTitleSlice := strings.Split(transaction.Title, ";")
 		transactionTitle := ""
@@ -107,18 +112,20 @@ func UpdateTransactionStatusService(extReq request.ExternalRequest, logger *util
 			tType = transactionTitle
 		}
 		transactionMessage = fmt.Sprintf("has approved delivered %s for payment.", tType)
-		_, err = CreateTransactionState(db, "cdp", req.TransactionID, transaction.MilestoneID, int(user.AccountID))
+		_, err = CreateTransactionState(repo, "cdp", req.TransactionID, transaction.MilestoneID, int(user.AccountID))
 		if err != nil {
 			return http.StatusInternalServerError, err
 		}
 	} else {
-		_, err = CreateTransactionState(db, req.Status, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
+		_, err = CreateTransactionState(repo, req.Status, req.TransactionID, transaction.MilestoneID, int(user.AccountID))
 		if err != nil {
 			return http.StatusInternalServerError, err
 
 		}
 	}
 
+	RecordStatusChange(transaction, previousStatus)
+
 	if closedTransactionMessage != "" {
 		message = closedTransactionMessage
 	} else if transactionPartiesMessage != "" {
@@ -128,10 +135,19 @@ func UpdateTransactionStatusService(extReq request.ExternalRequest, logger *util
 	}
 
 	activityLog := models.ActivityLog{
-		TransactionID: req.TransactionID,
-		Description:   message,
+		TransactionID:  req.TransactionID,
+		MilestoneID:    transaction.MilestoneID,
+		EventType:      models.ActivityTransactionStatusChange,
+		ActorAccountID: int(user.AccountID),
+		ActorRole:      resolveActorRole(repo, req.TransactionID, int(user.AccountID)),
+		PreviousStatus: previousStatus,
+		NewStatus:      transaction.Status,
+		Metadata: map[string]interface{}{
+			"status_code": req.Status,
+		},
+		Description: message,
 	}
-	err = activityLog.CreateActivityLog(db.Transaction)
+	err = repo.ActivityLogs.Create(&activityLog)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -1,28 +1,142 @@
 package transactions
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/vesicash/transactions-ms/internal/models"
-	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/pkg/repository"
 )
 
-func ValidateOnDbService(req models.ValidateOnDBReq, db postgresql.Databases) (bool, int, error) {
-	if req.Type == "notexists" {
-		if req.Value != nil {
-			return !postgresql.CheckExistsInTable(db.Transaction, req.Table, req.Query, req.Value), http.StatusOK, nil
-		} else {
-			return !postgresql.CheckExistsInTable(db.Transaction, req.Table, req.Query), http.StatusOK, nil
-		}
+// validationCheck runs one named check against its decoded parameters.
+type validationCheck func(repo repository.Repositories, params json.RawMessage) (bool, error)
+
+// errInvalidParams marks parameter errors, which are the caller's to fix, apart from lookup errors.
+var errInvalidParams = errors.New("invalid params")
+
+var paramsValidator = validator.New()
+
+// validationChecks is the registry of checks other services may run through the validate endpoint.
+// Each takes typed parameters, so nothing the caller sends reaches a query as anything but a value.
+var validationChecks = map[string]validationCheck{
+	"transaction_exists": newValidationCheck(func(repo repository.Repositories, p struct {
+		TransactionID string `json:"transaction_id" validate:"required"`
+	}) (bool, error) {
+		_, _, err := repo.Transactions.GetByTransactionID(p.TransactionID)
+		return found(err)
+	}),
+	"milestone_exists": newValidationCheck(func(repo repository.Repositories, p struct {
+		TransactionID string `json:"transaction_id" validate:"required"`
+		MilestoneID   string `json:"milestone_id" validate:"required"`
+	}) (bool, error) {
+		_, _, err := repo.Transactions.GetByTransactionIDAndMilestoneID(p.TransactionID, p.MilestoneID)
+		return found(err)
+	}),
+	"party_exists_for_account": newValidationCheck(func(repo repository.Repositories, p struct {
+		TransactionID string `json:"transaction_id" validate:"required"`
+		AccountID     int    `json:"account_id" validate:"required"`
+	}) (bool, error) {
+		_, _, err := repo.Parties.GetByTransactionIDAndAccountID(p.TransactionID, p.AccountID)
+		return found(err)
+	}),
+	"party_exists_for_role": newValidationCheck(func(repo repository.Repositories, p struct {
+		TransactionID string `json:"transaction_id" validate:"required"`
+		Role          string `json:"role" validate:"required"`
+	}) (bool, error) {
+		_, _, err := repo.Parties.GetByTransactionIDAndRole(p.TransactionID, p.Role)
+		return found(err)
+	}),
+	"ussd_code_unused": newValidationCheck(func(repo repository.Repositories, p struct {
+		UssdCode int `json:"ussd_code" validate:"required"`
+	}) (bool, error) {
+		_, _, err := repo.Transactions.GetByUssdCode(p.UssdCode)
+		exists, err := found(err)
+		return !exists, err
+	}),
+	"rate_exists": newValidationCheck(func(repo repository.Repositories, p struct {
+		FromCurrency string `json:"from_currency" validate:"required"`
+		ToCurrency   string `json:"to_currency" validate:"required"`
+	}) (bool, error) {
+		_, _, err := repo.Rates.GetByCurrencies(p.FromCurrency, p.ToCurrency)
+		return found(err)
+	}),
+	"rate_id_exists": newValidationCheck(func(repo repository.Repositories, p struct {
+		ID int64 `json:"id" validate:"required"`
+	}) (bool, error) {
+		_, _, err := repo.Rates.GetByID(p.ID)
+		return found(err)
+	}),
+	"dispute_exists": newValidationCheck(func(repo repository.Repositories, p struct {
+		TransactionID string `json:"transaction_id" validate:"required"`
+	}) (bool, error) {
+		_, _, err := repo.Disputes.GetByTransactionID(p.TransactionID)
+		return found(err)
+	}),
+}
 
-	} else if req.Type == "exists" {
-		if req.Value != nil {
-			return postgresql.CheckExistsInTable(db.Transaction, req.Table, req.Query, req.Value), http.StatusOK, nil
-		} else {
-			return postgresql.CheckExistsInTable(db.Transaction, req.Table, req.Query), http.StatusOK, nil
+// newValidationCheck wraps run with the decoding and validation of its parameter type P. Unknown
+// fields are rejected so a misspelt parameter is not silently ignored.
+func newValidationCheck[P any](run func(repo repository.Repositories, params P) (bool, error)) validationCheck {
+	return func(repo repository.Repositories, raw json.RawMessage) (bool, error) {
+		var params P
+		if len(raw) == 0 {
+			raw = json.RawMessage("{}")
 		}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&params); err != nil {
+			return false, fmt.Errorf("%w: %v", errInvalidParams, err.Error())
+		}
+		if err := paramsValidator.Struct(params); err != nil {
+			return false, fmt.Errorf("%w: %v", errInvalidParams, err.Error())
+		}
+		return run(repo, params)
+	}
+}
+
+func found(err error) (bool, error) {
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ValidationCheckNames lists the registered checks.
+func ValidationCheckNames() []string {
+	names := make([]string, 0, len(validationChecks))
+	for name := range validationChecks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
 
-	} else {
-		return false, http.StatusOK, nil
+// ValidateChecksService runs every check in req. A check that is not registered or is given bad
+// parameters fails the whole call, as that is a bug in the caller rather than a failed validation.
+func ValidateChecksService(repo repository.Repositories, req models.ValidateChecksRequest) (models.ValidateChecksResponse, int, error) {
+	response := models.ValidateChecksResponse{Valid: true, Results: []models.ValidationCheckResult{}}
+
+	for i, c := range req.Checks {
+		check, ok := validationChecks[c.Check]
+		if !ok {
+			return response, http.StatusBadRequest, fmt.Errorf("checks[%v]: unknown check %q, expected one of %v", i, c.Check, strings.Join(ValidationCheckNames(), ", "))
+		}
+		valid, err := check(repo, c.Params)
+		if errors.Is(err, errInvalidParams) {
+			return response, http.StatusBadRequest, fmt.Errorf("checks[%v]: %v", i, err.Error())
+		}
+		if err != nil {
+			return response, http.StatusInternalServerError, err
+		}
+		response.Results = append(response.Results, models.ValidationCheckResult{Check: c.Check, Valid: valid})
+		response.Valid = response.Valid && valid
 	}
+
+	return response, http.StatusOK, nil
 }
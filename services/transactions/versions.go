@@ -0,0 +1,148 @@
+package transactions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+)
+
+// maxConflictRetries bounds how often UpdateTransactionWithRetry reloads a row that keeps changing
+// under it.
+const maxConflictRetries = 3
+
+// updateErrorCode is the status code for a failed update: http.StatusConflict when the record
+// changed after it was read, so the caller can reload and try again, and
+// http.StatusPreconditionFailed when it changed after the request's If-Match was checked.
+func updateErrorCode(err error) int {
+	if errors.Is(err, repository.ErrPreconditionFailed) {
+		return http.StatusPreconditionFailed
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// UpdateTransactionWithRetry applies change to transaction and saves it. When someone else saved
+// the row first, the row is reloaded and change applied again. change returns false when the row
+// as it now is needs no update, such as when another writer already moved it on, and
+// UpdateTransactionWithRetry then reports false.
+func UpdateTransactionWithRetry(repo repository.Repositories, transaction *models.Transaction, change func(*models.Transaction) bool) (bool, error) {
+	for attempt := 0; ; attempt++ {
+		if !change(transaction) {
+			return false, nil
+		}
+		err := repo.Transactions.Update(transaction)
+		if !errors.Is(err, repository.ErrConflict) || errors.Is(err, repository.ErrPreconditionFailed) || attempt == maxConflictRetries {
+			return err == nil, err
+		}
+
+		reloaded, _, err := repo.Transactions.GetByTransactionIDAndMilestoneID(transaction.TransactionID, transaction.MilestoneID)
+		if err != nil {
+			return false, err
+		}
+		*transaction = reloaded
+	}
+}
+
+// RecordVersion is the version of one of a transaction's records, named by
+// postgresql.RecordKey.
+type RecordVersion struct {
+	Key     string
+	Version int
+}
+
+// TransactionVersions returns the versions of a transaction's milestones, parties, broker and
+// dispute.
+func TransactionVersions(repo repository.Repositories, transactionID string) ([]RecordVersion, int, error) {
+	milestones, err := repo.Transactions.ListByTransactionID(transactionID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if len(milestones) == 0 {
+		return nil, http.StatusBadRequest, repository.ErrNotFound
+	}
+	parties, err := repo.Parties.ListByTransactionID(transactionID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	versions := []RecordVersion{}
+	for _, milestone := range milestones {
+		versions = append(versions, RecordVersion{postgresql.RecordKey(models.AuditEntityTransaction, milestone.ID), milestone.Version})
+	}
+	for _, party := range parties {
+		versions = append(versions, RecordVersion{postgresql.RecordKey(models.AuditEntityTransactionParty, party.ID), party.Version})
+	}
+
+	broker, _, err := repo.Brokers.GetByTransactionID(transactionID)
+	if err == nil {
+		versions = append(versions, RecordVersion{postgresql.RecordKey(models.AuditEntityTransactionBroker, broker.ID), broker.Version})
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	dispute, _, err := repo.Disputes.GetByTransactionID(transactionID)
+	if err == nil {
+		versions = append(versions, RecordVersion{postgresql.RecordKey(models.AuditEntityTransactionDispute, dispute.ID), dispute.Version})
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, http.StatusInternalServerError, err
+	}
+	return versions, http.StatusOK, nil
+}
+
+// VersionsETag fingerprints versions, so it changes whenever any of the records is updated.
+func VersionsETag(versions []RecordVersion) string {
+	hash := sha256.New()
+	for _, version := range versions {
+		fmt.Fprintf(hash, "%v %v\n", version.Key, version.Version)
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+}
+
+// moveTransactionStatus moves transaction on to the status statusCode stands for with
+// UpdateTransactionWithRetry, as long as it is still at the status it was read at. A transaction
+// someone else moved on first is left as they left it and gives http.StatusConflict.
+func moveTransactionStatus(repo repository.Repositories, transaction *models.Transaction, statusCode string) (int, error) {
+	from, to := transaction.Status, GetTransactionStatus(statusCode)
+	updated, err := UpdateTransactionWithRetry(repo, transaction, func(t *models.Transaction) bool {
+		if t.Status != from {
+			return false
+		}
+		t.Status = to
+		return true
+	})
+	if err != nil {
+		return updateErrorCode(err), err
+	}
+	if !updated {
+		return http.StatusConflict, fmt.Errorf("transaction moved to %v before it could be set to %v, reload it and try again", transaction.Status, to)
+	}
+	return http.StatusOK, nil
+}
+
+// TransactionETag is the VersionsETag of a transaction's TransactionVersions.
+func TransactionETag(repo repository.Repositories, transactionID string) (string, int, error) {
+	versions, code, err := TransactionVersions(repo, transactionID)
+	if err != nil {
+		return "", code, err
+	}
+	return VersionsETag(versions), http.StatusOK, nil
+}
+
+// ETagMatches reports whether an If-Match header names etag, or is * which matches any.
+func ETagMatches(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -13,13 +13,14 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 	"github.com/vesicash/transactions-ms/external/external_models"
-	"github.com/vesicash/transactions-ms/external/mocks/payment_mocks"
+	"github.com/vesicash/transactions-ms/external/fakes"
 	"github.com/vesicash/transactions-ms/external/request"
 	"github.com/vesicash/transactions-ms/internal/config"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/internal/models/migrations"
 	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	"github.com/vesicash/transactions-ms/utility"
 )
@@ -29,7 +30,9 @@ func Setup() *utility.Logger {
 	config := config.Setup(logger, "../../app")
 	db := postgresql.ConnectToDatabases(logger, config.TestDatabases)
 	if config.TestDatabases.Migrate {
-		migrations.RunAllMigrations(db)
+		if err := migrations.RunAllMigrations(db); err != nil {
+			logger.Error("running migrations", err.Error())
+		}
 	}
 	return logger
 }
@@ -66,7 +69,7 @@ type ListTransactionsByIDResponse struct {
 
 func CreateTransactionUser(t *testing.T, db postgresql.Databases, validator *validator.Validate, extReq request.ExternalRequest, accountID int, isArchived bool) models.TransactionByIDResponse {
 	var (
-		trans                 = transactions.Controller{Db: db, Validator: validator, Logger: extReq.Logger, ExtReq: extReq}
+		trans                 = transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validator, Logger: extReq.Logger, ExtReq: extReq}
 		createTransactionPath = "/v2/create"
 		createTransactionURI  = url.URL{Path: createTransactionPath}
 		token, _              = uuid.NewV4()
@@ -81,7 +84,11 @@ func CreateTransactionUser(t *testing.T, db postgresql.Databases, validator *val
 		transactionsAuthUrl.POST("/create", trans.CreateTransaction)
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	payment, ok := extReq.Payment.(*fakes.PaymentClient)
+	if !ok {
+		t.Fatal("CreateTransactionUser needs the fake payment client")
+	}
+	payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    utility.RandomString(20),
@@ -93,6 +100,18 @@ func CreateTransactionUser(t *testing.T, db postgresql.Databases, validator *val
 		ShippingFee:      20,
 		DisburseCurrency: "NGN",
 	}
+	auth, ok := extReq.Auth.(*fakes.AuthClient)
+	if !ok {
+		t.Fatal("CreateTransactionUser needs the fake auth client")
+	}
+	if auth.BusinessProfile == nil {
+		auth.BusinessProfile = &external_models.BusinessProfile{
+			ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+			AccountID: accountID,
+			Country:   "NG",
+			Currency:  "NGN",
+		}
+	}
 
 	createTransactionReq := models.CreateTransactionRequest{
 		BusinessID: accountID,
@@ -118,9 +137,9 @@ func CreateTransactionUser(t *testing.T, db postgresql.Databases, validator *val
 				Status:       "draft",
 				AccessLevel: models.PartyAccessLevel{
 					Approve:    true,
-					CanReceive: false,
+					CanReceive: true,
 					CanView:    true,
-					MarkAsDone: false,
+					MarkAsDone: true,
 				},
 			},
 		},
@@ -0,0 +1,447 @@
+package test_cronjobs
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vesicash/transactions-ms/cronjobs"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
+	"github.com/vesicash/transactions-ms/services/transactions"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+const (
+	pastDueDate   = "1577836800"
+	futureDueDate = "32472144000"
+)
+
+func createTransaction(t *testing.T, repo repository.Repositories, transactionID, status, dueDate string, amountPaid float64, partyStatus string) {
+	transaction := models.Transaction{
+		TransactionID: transactionID,
+		PartiesID:     transactionID,
+		Title:         "test transaction",
+		Type:          "oneoff",
+		Status:        status,
+		DueDate:       dueDate,
+		Currency:      "NGN",
+		AmountPaid:    amountPaid,
+	}
+	if err := repo.Transactions.Create(&transaction); err != nil {
+		t.Fatal(err)
+	}
+	for i, role := range []string{"buyer", "seller"} {
+		party := models.TransactionParty{
+			TransactionPartiesID: transactionID,
+			TransactionID:        transactionID,
+			AccountID:            i + 1,
+			Role:                 role,
+			Status:               partyStatus,
+		}
+		if err := repo.Parties.Create(&party); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHandleTransactionClose(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Status         string
+		DueDate        string
+		AmountPaid     float64
+		PartyStatus    string
+		ExpectedStatus string
+		ExpectedRefund bool
+	}{
+		{
+			Name:           "funded transaction is refunded",
+			Status:         transactions.GetTransactionStatus("draft"),
+			DueDate:        pastDueDate,
+			AmountPaid:     500,
+			PartyStatus:    "accepted",
+			ExpectedStatus: transactions.GetTransactionStatus("cr"),
+			ExpectedRefund: true,
+		},
+		{
+			Name:           "unfunded transaction is closed as not funded",
+			Status:         transactions.GetTransactionStatus("sac"),
+			DueDate:        pastDueDate,
+			PartyStatus:    "accepted",
+			ExpectedStatus: transactions.GetTransactionStatus("cnf"),
+		},
+		{
+			Name:           "transaction with a party yet to accept is closed",
+			Status:         transactions.GetTransactionStatus("sac"),
+			DueDate:        pastDueDate,
+			AmountPaid:     500,
+			PartyStatus:    "created",
+			ExpectedStatus: transactions.GetTransactionStatus("closed"),
+		},
+		{
+			Name:           "transaction not yet due is left alone",
+			Status:         transactions.GetTransactionStatus("draft"),
+			DueDate:        futureDueDate,
+			AmountPaid:     500,
+			PartyStatus:    "accepted",
+			ExpectedStatus: transactions.GetTransactionStatus("draft"),
+		},
+		{
+			Name:           "transaction without a unix due date is left alone",
+			Status:         transactions.GetTransactionStatus("draft"),
+			DueDate:        "2020-01-01 00:00:00",
+			AmountPaid:     500,
+			PartyStatus:    "accepted",
+			ExpectedStatus: transactions.GetTransactionStatus("draft"),
+		},
+		{
+			Name:           "closed transaction is left alone",
+			Status:         transactions.GetTransactionStatus("cr"),
+			DueDate:        pastDueDate,
+			AmountPaid:     500,
+			PartyStatus:    "accepted",
+			ExpectedStatus: transactions.GetTransactionStatus("cr"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				fake          = fakes.New()
+				repo          = memory.New().Repositories()
+				extReq        = fake.ExternalRequest(utility.NewLogger())
+				transactionID = utility.RandomString(20)
+			)
+			createTransaction(t, repo, transactionID, test.Status, test.DueDate, test.AmountPaid, test.PartyStatus)
+			closedLabels := map[string]string{"currency": "NGN", "source": "api", "status": test.ExpectedStatus}
+			closedBefore := counterValue(t, "transactions_closed_total", closedLabels)
+			refundedBefore := counterValue(t, "refund_amount_total", map[string]string{"currency": "NGN"})
+
+			cronjobs.HandleTransactionClose(extReq, repo)
+
+			transaction, _, err := repo.Transactions.GetByTransactionID(transactionID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if transaction.Status != test.ExpectedStatus {
+				t.Errorf("expected status %q, got %q", test.ExpectedStatus, transaction.Status)
+			}
+			if refunded := len(fake.Payment.Credits) > 0; refunded != test.ExpectedRefund {
+				t.Errorf("expected refund %v, got %v", test.ExpectedRefund, refunded)
+			}
+			expectedClosed := 0.0
+			if test.ExpectedStatus != test.Status {
+				expectedClosed = 1
+			}
+			if got := counterValue(t, "transactions_closed_total", closedLabels) - closedBefore; got != expectedClosed {
+				t.Errorf("expected transactions_closed_total to grow by %v, got %v", expectedClosed, got)
+			}
+			expectedRefunded := 0.0
+			if test.ExpectedRefund {
+				expectedRefunded = test.AmountPaid
+			}
+			if got := counterValue(t, "refund_amount_total", map[string]string{"currency": "NGN"}) - refundedBefore; got != expectedRefunded {
+				t.Errorf("expected refund_amount_total to grow by %v, got %v", expectedRefunded, got)
+			}
+			if test.ExpectedRefund && (len(fake.Payment.Debits) != 1 || fake.Payment.Debits[0].Amount != test.AmountPaid || fake.Payment.Debits[0].BusinessID != 1) {
+				t.Errorf("expected the buyer's escrow wallet to be debited %v, got %+v", test.AmountPaid, fake.Payment.Debits)
+			}
+		})
+	}
+}
+
+// racingTransactions moves a transaction to status, as another writer would, right before the
+// first update made through it.
+type racingTransactions struct {
+	repository.TransactionRepository
+	status string
+	raced  bool
+}
+
+func (r *racingTransactions) Update(transaction *models.Transaction) error {
+	if !r.raced {
+		r.raced = true
+		concurrent, _, _ := r.TransactionRepository.GetByTransactionID(transaction.TransactionID)
+		concurrent.Status = r.status
+		if err := r.TransactionRepository.Update(&concurrent); err != nil {
+			return err
+		}
+	}
+	return r.TransactionRepository.Update(transaction)
+}
+
+func TestHandleTransactionCloseRace(t *testing.T) {
+	var (
+		fake          = fakes.New()
+		repo          = memory.New().Repositories()
+		extReq        = fake.ExternalRequest(utility.NewLogger())
+		transactionID = utility.RandomString(20)
+		disbursed     = transactions.GetTransactionStatus("cdc")
+	)
+	createTransaction(t, repo, transactionID, transactions.GetTransactionStatus("ip"), pastDueDate, 500, "accepted")
+	repo.Transactions = &racingTransactions{TransactionRepository: repo.Transactions, status: disbursed}
+
+	cronjobs.HandleTransactionClose(extReq, repo)
+
+	transaction, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+	if transaction.Status != disbursed {
+		t.Errorf("expected status %q to be kept, got %q", disbursed, transaction.Status)
+	}
+	if len(fake.Payment.Debits) != 0 || len(fake.Payment.Credits) != 0 {
+		t.Errorf("expected no refund for a transaction the job did not close, got debits %+v and credits %+v", fake.Payment.Debits, fake.Payment.Credits)
+	}
+}
+
+func TestHandleTransactionAutoClose(t *testing.T) {
+	var (
+		fake   = fakes.New()
+		repo   = memory.New().Repositories()
+		extReq = fake.ExternalRequest(utility.NewLogger())
+	)
+	createTransaction(t, repo, "disbursed", transactions.GetTransactionStatus("cdc"), futureDueDate, 500, "accepted")
+	createTransaction(t, repo, "in-progress", transactions.GetTransactionStatus("ip"), futureDueDate, 500, "accepted")
+
+	cronjobs.HandleTransactionAutoClose(extReq, repo)
+
+	states, _ := repo.States.ListByTransactionID("disbursed")
+	if len(states) != 1 || states[0].Status != transactions.GetTransactionStatus("closed") {
+		t.Errorf("expected a closed state for the disbursed transaction, got %+v", states)
+	}
+	states, _ = repo.States.ListByTransactionID("in-progress")
+	if len(states) != 0 {
+		t.Errorf("expected no state for the transaction in progress, got %+v", states)
+	}
+}
+
+func TestHandleUpdateStatus(t *testing.T) {
+	var (
+		fake          = fakes.New()
+		repo          = memory.New().Repositories()
+		extReq        = fake.ExternalRequest(utility.NewLogger())
+		transactionID = utility.RandomString(20)
+	)
+	createTransaction(t, repo, transactionID, transactions.GetTransactionStatus("da"), futureDueDate, 500, "accepted")
+
+	// without a payment record the transaction stays pending disbursement
+	cronjobs.HandleUpdateStatus(extReq, repo)
+
+	transaction, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+	if transaction.Status != transactions.GetTransactionStatus("cdp") {
+		t.Errorf("expected status %q, got %q", transactions.GetTransactionStatus("cdp"), transaction.Status)
+	}
+
+	activityLogs, _ := repo.ActivityLogs.ListByTransactionID(transactionID)
+	if len(activityLogs) != 1 {
+		t.Fatalf("expected one activity log, got %v", len(activityLogs))
+	}
+	if activityLogs[0].EventType != models.ActivityPaymentProcessing || activityLogs[0].ActorRole != "system" {
+		t.Errorf("unexpected activity log %+v", activityLogs[0])
+	}
+}
+
+func TestHandleUpdateStatusRelease(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Recipients     string
+		ExpectedStatus string
+	}{
+		{
+			Name:           "released funds are disbursed",
+			Recipients:     `[{"account_id":2,"amount":500}]`,
+			ExpectedStatus: transactions.GetTransactionStatus("cdc"),
+		},
+		{
+			Name:           "funds that could not be released wait for manual disbursement",
+			ExpectedStatus: transactions.GetTransactionStatus("cmdp"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				fake          = fakes.New()
+				repo          = memory.New().Repositories()
+				extReq        = fake.ExternalRequest(utility.NewLogger())
+				transactionID = utility.RandomString(20)
+			)
+			fake.Payment.ListPaymentObj = &external_models.ListPayment{ID: 1, IsPaid: true}
+			createTransaction(t, repo, transactionID, transactions.GetTransactionStatus("da"), futureDueDate, 500, "accepted")
+			transaction, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+			transaction.Amount, transaction.Recipients = 500, test.Recipients
+			repo.Transactions.Update(&transaction)
+
+			cronjobs.HandleUpdateStatus(extReq, repo)
+
+			transaction, _, _ = repo.Transactions.GetByTransactionID(transactionID)
+			if transaction.Status != test.ExpectedStatus {
+				t.Errorf("expected status %q, got %q", test.ExpectedStatus, transaction.Status)
+			}
+		})
+	}
+}
+
+// afterFirstUpdate runs hook once the first update made through it is saved.
+type afterFirstUpdate struct {
+	repository.TransactionRepository
+	hook func()
+	done bool
+}
+
+func (r *afterFirstUpdate) Update(transaction *models.Transaction) error {
+	if err := r.TransactionRepository.Update(transaction); err != nil {
+		return err
+	}
+	if !r.done {
+		r.done = true
+		r.hook()
+	}
+	return nil
+}
+
+func TestHandleUpdateStatusAcceptedMilestone(t *testing.T) {
+	var (
+		fake          = fakes.New()
+		logger        = utility.NewLogger()
+		repo          = memory.New().Repositories()
+		extReq        = fake.ExternalRequest(logger)
+		transactionID = utility.RandomString(20)
+		buyer         = external_models.User{ID: 1, AccountID: 1}
+	)
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{ID: 1, IsPaid: true}
+	createTransaction(t, repo, transactionID, transactions.GetTransactionStatus("d"), futureDueDate, 500, "accepted")
+	milestone, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+	milestone.MilestoneID, milestone.Amount, milestone.Recipients = transactionID, 500, `[{"account_id":2,"amount":500}]`
+	repo.Transactions.Update(&milestone)
+	buyerParty, _, _ := repo.Parties.GetByTransactionIDAndRole(transactionID, "buyer")
+	buyerParty.RoleCapabilities = map[string]interface{}{"approve": true}
+	repo.Parties.Update(&buyerParty)
+
+	// the update-status job runs as soon as accepting the milestone saves its first status change
+	repo.Transactions = &afterFirstUpdate{TransactionRepository: repo.Transactions, hook: func() {
+		cronjobs.HandleUpdateStatus(extReq, repo)
+	}}
+
+	_, code, err := transactions.AcceptMilestoneService(extReq, logger, repo, models.MilestoneRequest{TransactionID: transactionID, MilestoneID: transactionID}, buyer)
+	if err != nil {
+		t.Fatalf("expected the milestone to be accepted, got %v, %v", code, err)
+	}
+	if len(fake.Payment.Transfers) != 1 {
+		t.Errorf("expected the milestone to be paid out once, got %+v", fake.Payment.Transfers)
+	}
+	milestone, _, _ = repo.Transactions.GetByTransactionID(transactionID)
+	if milestone.Status != transactions.GetTransactionStatus("cdc") {
+		t.Errorf("expected status %q, got %q", transactions.GetTransactionStatus("cdc"), milestone.Status)
+	}
+}
+
+func TestHandleDeletedTransactionPurge(t *testing.T) {
+	config.Config = &config.Configuration{Retention: config.Retention{DeletedTransactionDays: 1}}
+	var (
+		fake          = fakes.New()
+		repo          = memory.New().Repositories()
+		extReq        = fake.ExternalRequest(utility.NewLogger())
+		transactionID = utility.RandomString(20)
+	)
+	createTransaction(t, repo, transactionID, transactions.GetTransactionStatus("ip"), futureDueDate, 0, "accepted")
+	transaction, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+	if err := repo.Transactions.Delete(&transaction); err != nil {
+		t.Fatal(err)
+	}
+
+	cronjobs.HandleDeletedTransactionPurge(extReq, repo)
+
+	if _, _, err := repo.Transactions.GetDeletedByTransactionID(transactionID); err != nil {
+		t.Errorf("expected a transaction deleted within the retention period to be kept, got %v", err)
+	}
+}
+
+func TestHandleDomainMetrics(t *testing.T) {
+	var (
+		fake   = fakes.New()
+		repo   = memory.New().Repositories()
+		extReq = fake.ExternalRequest(utility.NewLogger())
+	)
+	inProgress := transactions.GetTransactionStatus("ip")
+	createTransaction(t, repo, utility.RandomString(20), inProgress, futureDueDate, 0, "accepted")
+	createTransaction(t, repo, utility.RandomString(20), inProgress, futureDueDate, 0, "accepted")
+	createTransaction(t, repo, utility.RandomString(20), "legacy status", futureDueDate, 0, "accepted")
+	repo.Disputes.Create(&models.TransactionDispute{TransactionID: utility.RandomString(20)})
+	repo.Disputes.Create(&models.TransactionDispute{TransactionID: utility.RandomString(20), Decision: "refund"})
+
+	cronjobs.HandleDomainMetrics(extReq, repo)
+
+	tests := []struct {
+		Name     string
+		Labels   map[string]string
+		Expected float64
+	}{
+		{Name: "transactions", Labels: map[string]string{"status": inProgress}, Expected: 2},
+		{Name: "transactions", Labels: map[string]string{"status": "other"}, Expected: 1},
+		{Name: "transactions", Labels: map[string]string{"status": transactions.GetTransactionStatus("cr")}, Expected: 0},
+		{Name: "disputes_open", Expected: 1},
+	}
+	for _, test := range tests {
+		if got := gaugeValue(t, test.Name, test.Labels); got != test.Expected {
+			t.Errorf("expected %v %v to be %v, got %v", test.Name, test.Labels, test.Expected, got)
+		}
+	}
+}
+
+func TestRefundMetricCurrency(t *testing.T) {
+	before := counterValue(t, "refund_amount_total", map[string]string{"currency": "other"})
+	transactions.RecordRefund("not a currency", 10)
+	if got := counterValue(t, "refund_amount_total", map[string]string{"currency": "other"}); got != before+10 {
+		t.Errorf("expected an unknown currency to be counted as other, got %v", got-before)
+	}
+	if got := counterValue(t, "refund_amount_total", map[string]string{"currency": "NOT A CURRENCY"}); got != 0 {
+		t.Errorf("expected no series for an unknown currency, got %v", got)
+	}
+}
+
+func counterValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value != label.GetValue() {
+					continue metrics
+				}
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func gaugeValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value != label.GetValue() {
+					continue metrics
+				}
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+	return -1
+}
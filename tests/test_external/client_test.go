@@ -0,0 +1,184 @@
+package test_external
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vesicash/transactions-ms/external"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func TestSendRequest(t *testing.T) {
+	logger := utility.NewLogger()
+
+	tests := []struct {
+		Name          string
+		Method        string
+		Statuses      []int
+		Body          string
+		ExpectedCalls int32
+		ExpectedCode  int
+		ExpectedError bool
+	}{
+		{
+			Name:          "idempotent request is retried",
+			Method:        http.MethodGet,
+			Statuses:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			Body:          `{"status":"ok"}`,
+			ExpectedCalls: 2,
+			ExpectedCode:  http.StatusOK,
+		},
+		{
+			Name:          "non idempotent request is not retried",
+			Method:        http.MethodPost,
+			Statuses:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			Body:          `{"status":"ok"}`,
+			ExpectedCalls: 1,
+			ExpectedCode:  http.StatusServiceUnavailable,
+			ExpectedError: true,
+		},
+		{
+			Name:          "non json error body reports the status",
+			Method:        http.MethodPost,
+			Statuses:      []int{http.StatusBadRequest},
+			Body:          `<html>bad request</html>`,
+			ExpectedCalls: 1,
+			ExpectedCode:  http.StatusBadRequest,
+			ExpectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			external.ResetCircuitBreakers()
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+				status := test.Statuses[len(test.Statuses)-1]
+				if int(call) <= len(test.Statuses) {
+					status = test.Statuses[call-1]
+				}
+				w.WriteHeader(status)
+				w.Write([]byte(test.Body))
+			}))
+			defer server.Close()
+
+			var response map[string]interface{}
+			req := external.GetNewSendRequestObject(logger, "test", test.Name, server.URL, test.Method, "/", external.JsonDecodeMethod, map[string]string{}, http.StatusOK, nil)
+			err := req.SendRequest(&response)
+
+			if test.ExpectedError && err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !test.ExpectedError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := atomic.LoadInt32(&calls); got != test.ExpectedCalls {
+				t.Errorf("expected %v calls, got %v", test.ExpectedCalls, got)
+			}
+			code := req.ResponseCode
+			if err != nil {
+				code = external.GetResponseCode(err)
+			}
+			if code != test.ExpectedCode {
+				t.Errorf("expected code %v, got %v", test.ExpectedCode, code)
+			}
+		})
+	}
+}
+
+func TestSendRequestCancelledDuringRetry(t *testing.T) {
+	logger := utility.NewLogger()
+	external.ResetCircuitBreakers()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var response map[string]interface{}
+	err := external.GetNewSendRequestObject(logger, "test", "cancelled", server.URL, http.MethodGet, "/", external.JsonDecodeMethod, map[string]string{}, http.StatusOK, nil).WithContext(ctx).SendRequest(&response)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the retry to stop when the context is cancelled, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("expected 1 call, got %v", got)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	logger := utility.NewLogger()
+	external.ResetCircuitBreakers()
+	policy := external.GetClientPolicy("test")
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	for i := 0; i < policy.BreakerThreshold; i++ {
+		var response map[string]interface{}
+		err := external.GetNewSendRequestObject(logger, "test", "circuit", server.URL, http.MethodPost, "/", external.JsonDecodeMethod, map[string]string{}, http.StatusOK, nil).SendRequest(&response)
+		if err == nil || errors.Is(err, external.ErrCircuitOpen) {
+			t.Fatalf("expected a server error on call %v, got %v", i+1, err)
+		}
+	}
+
+	var response map[string]interface{}
+	err := external.GetNewSendRequestObject(logger, "test", "circuit", server.URL, http.MethodPost, "/", external.JsonDecodeMethod, map[string]string{}, http.StatusOK, nil).SendRequest(&response)
+	if !errors.Is(err, external.ErrCircuitOpen) {
+		t.Errorf("expected circuit to be open, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != int32(policy.BreakerThreshold) {
+		t.Errorf("expected %v calls to reach the server, got %v", policy.BreakerThreshold, got)
+	}
+}
+
+func TestConcurrentResponseCodes(t *testing.T) {
+	logger := utility.NewLogger()
+	external.ResetCircuitBreakers()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/created" {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		path, expected := "/ok", http.StatusOK
+		if i%2 == 0 {
+			path, expected = "/created", http.StatusCreated
+		}
+		wg.Add(1)
+		go func(path string, expected int) {
+			defer wg.Done()
+			var response map[string]interface{}
+			req := external.GetNewSendRequestObject(logger, "test", "concurrent", server.URL, http.MethodGet, path, external.JsonDecodeMethod, map[string]string{}, expected, nil)
+			if err := req.SendRequest(&response); err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if req.ResponseCode != expected {
+				t.Errorf("expected code %v, got %v", expected, req.ResponseCode)
+			}
+		}(path, expected)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,167 @@
+package test_external
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/external/request"
+)
+
+type countingAuthClient struct {
+	*fakes.AuthClient
+	calls int32
+	delay time.Duration
+}
+
+func (c *countingAuthClient) GetUser(data external_models.GetUserRequestModel) (external_models.User, error) {
+	atomic.AddInt32(&c.calls, 1)
+	time.Sleep(c.delay)
+	return c.AuthClient.GetUser(data)
+}
+
+// panickingAuthClient panics on its first GetUser call, after delay.
+type panickingAuthClient struct {
+	countingAuthClient
+}
+
+func (c *panickingAuthClient) GetUser(data external_models.GetUserRequestModel) (external_models.User, error) {
+	if atomic.AddInt32(&c.calls, 1) == 1 {
+		time.Sleep(c.delay)
+		panic("auth client failed")
+	}
+	return c.AuthClient.GetUser(data)
+}
+
+func TestLookupCache(t *testing.T) {
+	user := &external_models.User{ID: 1, AccountID: 10}
+
+	tests := []struct {
+		Name          string
+		Policy        request.LookupCachePolicy
+		User          *external_models.User
+		Delay         time.Duration
+		Lookups       []uint
+		Wait          time.Duration
+		Concurrent    bool
+		ExpectedCalls int32
+	}{
+		{
+			Name:          "repeated lookup is served from cache",
+			Policy:        request.LookupCachePolicy{TTL: time.Minute, MaxEntries: 10},
+			User:          user,
+			Lookups:       []uint{10, 10, 10},
+			ExpectedCalls: 1,
+		},
+		{
+			Name:          "concurrent lookups share one call",
+			Policy:        request.LookupCachePolicy{TTL: time.Minute, MaxEntries: 10},
+			User:          user,
+			Delay:         50 * time.Millisecond,
+			Lookups:       []uint{10, 10, 10, 10, 10},
+			Concurrent:    true,
+			ExpectedCalls: 1,
+		},
+		{
+			Name:          "expired entry is loaded again",
+			Policy:        request.LookupCachePolicy{TTL: 10 * time.Millisecond, MaxEntries: 10},
+			User:          user,
+			Lookups:       []uint{10, 10},
+			Wait:          20 * time.Millisecond,
+			ExpectedCalls: 2,
+		},
+		{
+			Name:          "least recently used entry is evicted",
+			Policy:        request.LookupCachePolicy{TTL: time.Minute, MaxEntries: 1},
+			User:          user,
+			Lookups:       []uint{10, 11, 10},
+			ExpectedCalls: 3,
+		},
+		{
+			Name:          "failed lookup is not cached",
+			Policy:        request.LookupCachePolicy{TTL: time.Minute, MaxEntries: 10},
+			Lookups:       []uint{10, 10},
+			ExpectedCalls: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			auth := &countingAuthClient{AuthClient: &fakes.AuthClient{User: test.User}, delay: test.Delay}
+			client := request.NewCachedAuthClient(auth, test.Policy)
+
+			if test.Concurrent {
+				var wg sync.WaitGroup
+				for _, accountID := range test.Lookups {
+					wg.Add(1)
+					go func(accountID uint) {
+						defer wg.Done()
+						client.GetUser(external_models.GetUserRequestModel{AccountID: accountID})
+					}(accountID)
+				}
+				wg.Wait()
+			} else {
+				for _, accountID := range test.Lookups {
+					client.GetUser(external_models.GetUserRequestModel{AccountID: accountID})
+					time.Sleep(test.Wait)
+				}
+			}
+
+			if calls := atomic.LoadInt32(&auth.calls); calls != test.ExpectedCalls {
+				t.Errorf("expected %v calls to the auth service, got %v", test.ExpectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestLookupCachePanic(t *testing.T) {
+	auth := &panickingAuthClient{countingAuthClient{AuthClient: &fakes.AuthClient{User: &external_models.User{ID: 1, AccountID: 10}}, delay: 50 * time.Millisecond}}
+	client := request.NewCachedAuthClient(auth, request.LookupCachePolicy{TTL: time.Minute, MaxEntries: 10})
+	lookup := external_models.GetUserRequestModel{AccountID: 10}
+
+	var (
+		wg        sync.WaitGroup
+		recovered interface{}
+		sharedErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer func() { recovered = recover() }()
+		client.GetUser(lookup)
+	}()
+	go func() {
+		defer wg.Done()
+		time.Sleep(10 * time.Millisecond)
+		_, sharedErr = client.GetUser(lookup)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lookup waiting on the panicking call never returned")
+	}
+
+	if recovered == nil {
+		t.Error("expected the panic to reach the caller that made the call")
+	}
+	if sharedErr == nil {
+		t.Error("expected the waiting lookup to get an error")
+	}
+
+	user, err := client.GetUser(lookup)
+	if err != nil || user.ID != 1 {
+		t.Errorf("expected the next lookup to call the auth service again, got %+v, %v", user, err)
+	}
+	if calls := atomic.LoadInt32(&auth.calls); calls != 2 {
+		t.Errorf("expected 2 calls to the auth service, got %v", calls)
+	}
+}
@@ -0,0 +1,75 @@
+package test_external
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vesicash/transactions-ms/external"
+	"github.com/vesicash/transactions-ms/utility"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSendRequestTracing(t *testing.T) {
+	logger := utility.NewLogger()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+	external.ResetCircuitBreakers()
+
+	var received trace.SpanContext
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = trace.SpanContextFromContext(propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header)))
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "create_transaction")
+	var response map[string]interface{}
+	err := external.GetNewSendRequestObject(logger, "test", "get_user", server.URL, http.MethodGet, "/", external.JsonDecodeMethod, map[string]string{}, http.StatusOK, nil).
+		WithContext(ctx).
+		SendRequest(&response)
+	parent.End()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %v", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "external.get_user" {
+		t.Errorf("expected span external.get_user, got %v", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected the request span to be a child of the caller's span")
+	}
+	if !hasAttribute(span.Attributes(), attribute.String("request.name", "get_user")) {
+		t.Errorf("expected request.name attribute, got %v", span.Attributes())
+	}
+	if received.TraceID() != parent.SpanContext().TraceID() || received.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("expected the service to receive the request span's trace context, got %v", received)
+	}
+}
+
+func hasAttribute(attributes []attribute.KeyValue, expected attribute.KeyValue) bool {
+	for _, a := range attributes {
+		if a == expected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package test_health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/controller/health"
+	healthService "github.com/vesicash/transactions-ms/services/health"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func passing(ctx context.Context) (interface{}, error) { return nil, nil }
+func failing(ctx context.Context) (interface{}, error) { return nil, errors.New("connection refused") }
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Checkers   []healthService.Checker
+		StatusCode int
+		Status     string
+	}{
+		{
+			Name: "all dependencies up",
+			Checkers: []healthService.Checker{
+				{Name: "database", Critical: true, Check: passing},
+				{Name: "payment", Check: passing},
+			},
+			StatusCode: http.StatusOK,
+			Status:     models.HealthStatusOk,
+		},
+		{
+			Name: "non critical dependency down",
+			Checkers: []healthService.Checker{
+				{Name: "database", Critical: true, Check: passing},
+				{Name: "payment", Check: failing},
+			},
+			StatusCode: http.StatusOK,
+			Status:     models.HealthStatusDegraded,
+		},
+		{
+			Name: "critical dependency down",
+			Checkers: []healthService.Checker{
+				{Name: "database", Critical: true, Check: failing},
+				{Name: "payment", Check: passing},
+			},
+			StatusCode: http.StatusServiceUnavailable,
+			Status:     models.HealthStatusDown,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			controller := health.Controller{Logger: utility.NewLogger(), Checkers: test.Checkers}
+			r := gin.New()
+			r.GET("/v2/health/ready", controller.Ready)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v2/health/ready", nil))
+			if rr.Code != test.StatusCode {
+				t.Fatalf("expected status %v, got %v", test.StatusCode, rr.Code)
+			}
+
+			var body struct {
+				Data models.HealthReport `json:"data"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Data.Status != test.Status {
+				t.Errorf("expected report status %v, got %v", test.Status, body.Data.Status)
+			}
+			for _, checker := range test.Checkers {
+				check, ok := body.Data.Checks[checker.Name]
+				if !ok {
+					t.Errorf("missing check %v", checker.Name)
+					continue
+				}
+				if check.Critical != checker.Critical {
+					t.Errorf("expected %v critical to be %v", checker.Name, checker.Critical)
+				}
+				if check.Status == models.HealthStatusDown && check.Error == "" {
+					t.Errorf("expected an error for failed check %v", checker.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := health.Controller{Logger: utility.NewLogger(), Checkers: []healthService.Checker{
+		{Name: "database", Critical: true, Check: failing},
+	}}
+	r := gin.New()
+	r.GET("/v2/health/live", controller.Live)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v2/health/live", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected liveness to ignore dependencies, got %v", rr.Code)
+	}
+}
+
+func TestServiceCheck(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/health" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer down.Close()
+
+	if _, err := healthService.ServiceCheck(up.URL)(context.Background()); err != nil {
+		t.Errorf("expected healthy service, got %v", err)
+	}
+	if _, err := healthService.ServiceCheck(down.URL)(context.Background()); err == nil {
+		t.Error("expected an error for a failing service")
+	}
+	if _, err := healthService.ServiceCheck("")(context.Background()); err == nil {
+		t.Error("expected an error for an unconfigured service")
+	}
+}
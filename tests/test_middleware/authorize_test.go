@@ -0,0 +1,112 @@
+package test_middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+// TestAuthorizeConcurrentPrincipals runs callers of every authorization type at once and checks
+// that each request only ever sees its own principal. Run it with -race.
+func TestAuthorizeConcurrentPrincipals(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.Config = &config.Configuration{App: config.App{Key: "app-key"}}
+
+	fake := fakes.New()
+	fake.Auth.Credentials = map[string]external_models.User{}
+	extReq := fake.ExternalRequest(utility.NewLogger())
+
+	type caller struct {
+		headers  map[string]string
+		expected models.Principal
+	}
+	callers := []caller{{
+		headers:  map[string]string{"v-app": "app-key"},
+		expected: models.Principal{Kind: models.PrincipalApp, AuthType: string(middleware.AppType)},
+	}}
+	for i := 1; i <= 20; i++ {
+		token, privateKey := fmt.Sprintf("token-%v", i), fmt.Sprintf("private-key-%v", i)
+		fake.Auth.Credentials[token] = external_models.User{AccountID: uint(i)}
+		fake.Auth.Credentials[privateKey] = external_models.User{AccountID: uint(1000 + i)}
+		callers = append(callers,
+			caller{
+				headers:  map[string]string{"Authorization": "Bearer " + token},
+				expected: models.Principal{Kind: models.PrincipalUser, AuthType: string(middleware.AuthType), AccountID: i, Token: token},
+			},
+			caller{
+				headers:  map[string]string{"v-private-key": privateKey, "v-public-key": "public"},
+				expected: models.Principal{Kind: models.PrincipalBusiness, AuthType: string(middleware.ApiType), AccountID: 1000 + i},
+			},
+		)
+	}
+
+	r := gin.New()
+	authorize := middleware.Authorize(postgresql.Databases{}, extReq, middleware.AuthType, middleware.ApiType, middleware.AppType)
+	r.GET("/whoami", authorize, func(c *gin.Context) {
+		// Give the other requests a chance to be authorized while this one is in flight.
+		time.Sleep(time.Millisecond)
+		principal, ok := middleware.GetPrincipal(c)
+		fromContext, _ := models.PrincipalFromContext(c.Request.Context())
+		if !ok || fromContext.AccountID != principal.AccountID || fromContext.Kind != principal.Kind {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"kind": principal.Kind, "auth_type": principal.AuthType, "account_id": principal.AccountID, "token": principal.Token})
+	})
+
+	var wg sync.WaitGroup
+	for round := 0; round < 5; round++ {
+		for _, cl := range callers {
+			wg.Add(1)
+			go func(cl caller) {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+				for key, value := range cl.headers {
+					req.Header.Set(key, value)
+				}
+				rr := httptest.NewRecorder()
+				r.ServeHTTP(rr, req)
+				if rr.Code != http.StatusOK {
+					t.Errorf("expected status 200, got %v", rr.Code)
+					return
+				}
+				expected := fmt.Sprintf(`{"account_id":%v,"auth_type":%q,"kind":%q,"token":%q}`, cl.expected.AccountID, cl.expected.AuthType, cl.expected.Kind, cl.expected.Token)
+				if rr.Body.String() != expected {
+					t.Errorf("expected principal %v, got %v", expected, rr.Body.String())
+				}
+			}(cl)
+		}
+	}
+	wg.Wait()
+}
+
+func TestAuthorizeRejectsUnknownCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := fakes.New()
+	fake.Auth.Credentials = map[string]external_models.User{}
+
+	r := gin.New()
+	r.GET("/whoami", middleware.Authorize(postgresql.Databases{}, fake.ExternalRequest(utility.NewLogger()), middleware.AuthType), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer unknown")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %v", rr.Code)
+	}
+}
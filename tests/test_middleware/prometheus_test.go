@@ -0,0 +1,58 @@
+package test_middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+)
+
+func TestPrometheusMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.PrometheusMiddleware("transactions"))
+	r.GET("/v2/listById/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	before := requestCount(t, "/v2/listById/:id", "200")
+	unmatchedBefore := requestCount(t, "unmatched", "404")
+	for _, path := range []string{"/v2/listById/1", "/v2/listById/2", "/v2/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := requestCount(t, "/v2/listById/:id", "200") - before; got != 2 {
+		t.Errorf("expected 2 requests labelled with the route template, got %v", got)
+	}
+	if got := requestCount(t, "unmatched", "404") - unmatchedBefore; got != 1 {
+		t.Errorf("expected 1 unmatched request, got %v", got)
+	}
+	if got := requestCount(t, "/v2/listById/1", "200"); got != 0 {
+		t.Errorf("expected no series for the raw path, got %v", got)
+	}
+}
+
+func requestCount(t *testing.T, path, status string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "http_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["service"] == "transactions" && labels["path"] == path && labels["status"] == status {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
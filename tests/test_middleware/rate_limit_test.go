@@ -0,0 +1,173 @@
+package test_middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+// rateLimitedRouter is one replica of the service: its own limiter over the shared store.
+func rateLimitedRouter(store repository.RateLimitRepository, plans map[string]int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	config.Config = &config.Configuration{App: config.App{Key: "app-key"}}
+
+	fake := fakes.New()
+	fake.Auth.Credentials = map[string]external_models.User{
+		"private-1": {AccountID: 1},
+		"private-2": {AccountID: 2},
+	}
+	extReq := fake.ExternalRequest(utility.NewLogger())
+	limiter := &middleware.RateLimiter{
+		Store:       store,
+		Logger:      extReq.Logger,
+		Window:      time.Minute,
+		IPLimit:     2,
+		Plans:       plans,
+		DailyQuotas: map[string]int{"import": 1},
+	}
+
+	r := gin.New()
+	r.Use(middleware.RateLimit(limiter))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/public", ok)
+	authorize := middleware.Authorize(postgresql.Databases{}, extReq, middleware.ApiType, middleware.AppType)
+	r.GET("/private", authorize, ok)
+	r.POST("/import", authorize, middleware.Quota("import"), ok)
+	return r
+}
+
+func rateLimitedRequest(r *gin.Engine, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func apiKey(privateKey string) map[string]string {
+	return map[string]string{"v-private-key": privateKey, "v-public-key": "public-" + privateKey}
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	r := rateLimitedRouter(memory.New().Repositories().RateLimits, map[string]int{middleware.DefaultRateLimitPlan: 5})
+
+	for i, expected := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		rr := rateLimitedRequest(r, http.MethodGet, "/public", nil)
+		if rr.Code != expected {
+			t.Fatalf("request %v: expected status %v, got %v", i+1, expected, rr.Code)
+		}
+		if rr.Header().Get("RateLimit-Limit") != "2" || rr.Header().Get("RateLimit-Reset") == "" {
+			t.Errorf("request %v: unexpected rate limit headers %v", i+1, rr.Header())
+		}
+	}
+	rr := rateLimitedRequest(r, http.MethodGet, "/public", nil)
+	if rr.Header().Get("RateLimit-Remaining") != "0" || rr.Header().Get("Retry-After") == "" {
+		t.Errorf("expected a spent limit with Retry-After, got %v", rr.Header())
+	}
+}
+
+func TestRateLimitByKey(t *testing.T) {
+	r := rateLimitedRouter(memory.New().Repositories().RateLimits, map[string]int{middleware.DefaultRateLimitPlan: 3})
+
+	// Both keys call from the same IP, past its anonymous limit, and each gets its own plan limit.
+	for i := 0; i < 3; i++ {
+		for _, key := range []string{"private-1", "private-2"} {
+			if rr := rateLimitedRequest(r, http.MethodGet, "/private", apiKey(key)); rr.Code != http.StatusOK {
+				t.Fatalf("request %v with %v: expected status 200, got %v", i+1, key, rr.Code)
+			}
+		}
+	}
+	rr := rateLimitedRequest(r, http.MethodGet, "/private", apiKey("private-1"))
+	if rr.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the fourth request with a key to be limited, got %v", rr.Code)
+	}
+	if rr.Header().Get("RateLimit-Limit") != "3" {
+		t.Errorf("expected the plan limit in the headers, got %v", rr.Header())
+	}
+
+	// Callers using the app key are other services and are not limited.
+	for i := 0; i < 5; i++ {
+		if rr := rateLimitedRequest(r, http.MethodGet, "/private", map[string]string{"v-app": "app-key"}); rr.Code != http.StatusOK {
+			t.Fatalf("app request %v: expected status 200, got %v", i+1, rr.Code)
+		}
+	}
+}
+
+func TestRateLimitUnidentifiedCredentials(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Path    string
+		Headers map[string]string
+		Code    int
+	}{
+		{Name: "wrong api key", Path: "/private", Headers: apiKey("wrong"), Code: http.StatusUnauthorized},
+		{Name: "garbage authorization header", Path: "/private", Headers: map[string]string{"Authorization": "garbage"}, Code: http.StatusUnauthorized},
+		{Name: "credentials on a route without authorize", Path: "/public", Headers: map[string]string{"v-app": "garbage"}, Code: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			r := rateLimitedRouter(memory.New().Repositories().RateLimits, map[string]int{middleware.DefaultRateLimitPlan: 5})
+			for i, expected := range []int{test.Code, test.Code, http.StatusTooManyRequests} {
+				if rr := rateLimitedRequest(r, http.MethodGet, test.Path, test.Headers); rr.Code != expected {
+					t.Fatalf("request %v: expected status %v, got %v", i+1, expected, rr.Code)
+				}
+			}
+			// The IP is spent, so even good credentials from it are turned away until the window resets.
+			if rr := rateLimitedRequest(r, http.MethodGet, "/private", apiKey("private-1")); rr.Code != http.StatusTooManyRequests {
+				t.Errorf("expected the spent IP to be limited, got %v", rr.Code)
+			}
+		})
+	}
+}
+
+func TestRateLimitSharedAcrossReplicas(t *testing.T) {
+	var (
+		store    = memory.New().Repositories().RateLimits
+		plans    = map[string]int{middleware.DefaultRateLimitPlan: 2}
+		replicas = []*gin.Engine{rateLimitedRouter(store, plans), rateLimitedRouter(store, plans)}
+	)
+
+	for i, expected := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		rr := rateLimitedRequest(replicas[i%2], http.MethodGet, "/private", apiKey("private-1"))
+		if rr.Code != expected {
+			t.Fatalf("request %v: expected status %v, got %v", i+1, expected, rr.Code)
+		}
+	}
+}
+
+func TestDailyQuota(t *testing.T) {
+	r := rateLimitedRouter(memory.New().Repositories().RateLimits, map[string]int{middleware.DefaultRateLimitPlan: 10})
+
+	rr := rateLimitedRequest(r, http.MethodPost, "/import", apiKey("private-1"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the first import to pass, got %v", rr.Code)
+	}
+	// The daily quota is nearer to running out than the per-minute limit, so it is the one reported.
+	if rr.Header().Get("RateLimit-Limit") != "1" || rr.Header().Get("RateLimit-Remaining") != "0" {
+		t.Errorf("expected the quota in the headers, got %v", rr.Header())
+	}
+	if rr := rateLimitedRequest(r, http.MethodPost, "/import", apiKey("private-1")); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the second import to be limited, got %v", rr.Code)
+	}
+	if rr := rateLimitedRequest(r, http.MethodPost, "/import", apiKey("private-2")); rr.Code != http.StatusOK {
+		t.Errorf("expected another key to have its own quota, got %v", rr.Code)
+	}
+	if rr := rateLimitedRequest(r, http.MethodGet, "/private", apiKey("private-1")); rr.Code != http.StatusOK {
+		t.Errorf("expected other endpoints to stay available, got %v", rr.Code)
+	}
+}
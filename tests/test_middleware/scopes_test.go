@@ -0,0 +1,88 @@
+package test_middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func TestRequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		Name     string
+		Headers  map[string]string
+		Scopes   []string
+		Scope    string
+		Expected int
+	}{
+		{
+			Name:     "api key with the scope",
+			Headers:  map[string]string{"v-private-key": "private", "v-public-key": "public"},
+			Scopes:   []string{middleware.ScopeTransactionsRead, middleware.ScopeFundsRelease},
+			Scope:    middleware.ScopeFundsRelease,
+			Expected: http.StatusOK,
+		},
+		{
+			Name:     "api key without the scope",
+			Headers:  map[string]string{"v-private-key": "private", "v-public-key": "public"},
+			Scopes:   []string{middleware.ScopeTransactionsRead},
+			Scope:    middleware.ScopeFundsRelease,
+			Expected: http.StatusForbidden,
+		},
+		{
+			Name:     "api key issued without scopes reads",
+			Headers:  map[string]string{"v-private-key": "private", "v-public-key": "public"},
+			Scope:    middleware.ScopeTransactionsRead,
+			Expected: http.StatusOK,
+		},
+		{
+			Name:     "api key issued without scopes writes",
+			Headers:  map[string]string{"v-private-key": "private", "v-public-key": "public"},
+			Scope:    middleware.ScopeTransactionsWrite,
+			Expected: http.StatusForbidden,
+		},
+		{
+			Name:     "user token is not scoped",
+			Headers:  map[string]string{"Authorization": "Bearer token"},
+			Scope:    middleware.ScopeFundsRelease,
+			Expected: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			fake := fakes.New()
+			fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+				Status: true,
+				Data:   external_models.User{AccountID: 1},
+				Scopes: test.Scopes,
+			}
+			extReq := fake.ExternalRequest(utility.NewLogger())
+
+			r := gin.New()
+			authorize := middleware.Authorize(postgresql.Databases{}, extReq, middleware.AuthType, middleware.ApiType)
+			r.GET("/scoped", authorize, middleware.RequireScope(test.Scope), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/scoped", nil)
+			for key, value := range test.Headers {
+				req.Header.Set(key, value)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != test.Expected {
+				t.Errorf("expected status %v, got %v: %v", test.Expected, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package test_middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func signedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	fake := fakes.New()
+	fake.Auth.AccessTokens = map[string]external_models.AccessToken{
+		"public-1": {AccountID: 1, PublicKey: "public-1", PrivateKey: "private-1"},
+	}
+	fake.Auth.Credentials = map[string]external_models.User{"private-1": {AccountID: 1}}
+	extReq := fake.ExternalRequest(utility.NewLogger())
+	verifier := &middleware.SignatureVerifier{Nonces: memory.New().Repositories().Nonces, ExtReq: extReq, MaxSkew: 5 * time.Minute}
+
+	r := gin.New()
+	r.Use(middleware.VerifySignature(verifier))
+	r.POST("/signed", middleware.Authorize(postgresql.Databases{}, extReq, middleware.ApiType), func(c *gin.Context) {
+		principal, _ := middleware.GetPrincipal(c)
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(http.StatusOK, gin.H{"account_id": principal.AccountID, "body": string(body)})
+	})
+	return r
+}
+
+type signedCall struct {
+	privateKey string
+	publicKey  string
+	timestamp  time.Time
+	nonce      string
+	signedBody string
+	sentBody   string
+	headers    map[string]string
+}
+
+func (s signedCall) send(r *gin.Engine) *httptest.ResponseRecorder {
+	timestamp := strconv.FormatInt(s.timestamp.Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/signed?page=1", strings.NewReader(s.sentBody))
+	req.Header.Set("v-public-key", s.publicKey)
+	req.Header.Set(middleware.TimestampHeader, timestamp)
+	req.Header.Set(middleware.NonceHeader, s.nonce)
+	req.Header.Set(middleware.SignatureHeader, middleware.SignRequest(s.privateKey, http.MethodPost, "/signed?page=1", timestamp, s.nonce, []byte(s.signedBody)))
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestVerifySignature(t *testing.T) {
+	valid := signedCall{privateKey: "private-1", publicKey: "public-1", timestamp: time.Now(), nonce: "nonce-1", signedBody: `{"amount": 10}`, sentBody: `{"amount": 10}`}
+
+	tests := []struct {
+		Name     string
+		Call     func(signedCall) signedCall
+		Expected int
+	}{
+		{Name: "valid signature", Call: func(s signedCall) signedCall { return s }, Expected: http.StatusOK},
+		{Name: "tampered body", Call: func(s signedCall) signedCall { s.sentBody = `{"amount": 1000}`; return s }, Expected: http.StatusUnauthorized},
+		{Name: "wrong key", Call: func(s signedCall) signedCall { s.privateKey = "guessed"; return s }, Expected: http.StatusUnauthorized},
+		{Name: "unknown public key", Call: func(s signedCall) signedCall { s.publicKey = "public-2"; return s }, Expected: http.StatusUnauthorized},
+		{Name: "stale timestamp", Call: func(s signedCall) signedCall { s.timestamp = time.Now().Add(-10 * time.Minute); return s }, Expected: http.StatusUnauthorized},
+		{Name: "future timestamp", Call: func(s signedCall) signedCall { s.timestamp = time.Now().Add(10 * time.Minute); return s }, Expected: http.StatusUnauthorized},
+		{Name: "missing nonce", Call: func(s signedCall) signedCall { s.nonce = ""; return s }, Expected: http.StatusUnauthorized},
+		{
+			Name:     "private key sent along",
+			Call:     func(s signedCall) signedCall { s.headers = map[string]string{"v-private-key": "private-1"}; return s },
+			Expected: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			rr := test.Call(valid).send(signedRouter())
+			if rr.Code != test.Expected {
+				t.Fatalf("expected status %v, got %v: %v", test.Expected, rr.Code, rr.Body.String())
+			}
+			if test.Expected == http.StatusOK && rr.Body.String() != `{"account_id":1,"body":"{\"amount\": 10}"}` {
+				t.Errorf("expected the handler to see the caller and the body, got %v", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestVerifySignatureRejectsReplay(t *testing.T) {
+	r := signedRouter()
+	call := signedCall{privateKey: "private-1", publicKey: "public-1", timestamp: time.Now(), nonce: "nonce-1", signedBody: "{}", sentBody: "{}"}
+
+	if rr := call.send(r); rr.Code != http.StatusOK {
+		t.Fatalf("expected the first request to pass, got %v: %v", rr.Code, rr.Body.String())
+	}
+	if rr := call.send(r); rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected the replay to be rejected, got %v", rr.Code)
+	}
+	call.nonce = "nonce-2"
+	if rr := call.send(r); rr.Code != http.StatusOK {
+		t.Errorf("expected a fresh nonce to pass, got %v: %v", rr.Code, rr.Body.String())
+	}
+}
+
+func TestUnsignedKeyPairStillAccepted(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/signed", strings.NewReader("{}"))
+	req.Header.Set("v-private-key", "private-1")
+	req.Header.Set("v-public-key", "public-1")
+	rr := httptest.NewRecorder()
+	signedRouter().ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %v: %v", rr.Code, rr.Body.String())
+	}
+}
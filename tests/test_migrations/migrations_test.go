@@ -0,0 +1,204 @@
+package test_migrations
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/models/migrations"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	tst "github.com/vesicash/transactions-ms/tests"
+	"github.com/vesicash/transactions-ms/utility"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestShippedMigrations(t *testing.T) {
+	migrator, err := migrations.NewMigrator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrator.Migrations) == 0 || migrator.Migrations[0].Name != "baseline" {
+		t.Fatalf("expected the baseline to be the first migration, got %+v", migrator.Migrations)
+	}
+	for i := 1; i < len(migrator.Migrations); i++ {
+		if migrator.Migrations[i].Version <= migrator.Migrations[i-1].Version {
+			t.Errorf("migrations out of order at %v_%v", migrator.Migrations[i].Version, migrator.Migrations[i].Name)
+		}
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(content string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(content)} }
+
+	tests := []struct {
+		Name     string
+		Files    fstest.MapFS
+		Versions []int64
+		Fails    bool
+	}{
+		{
+			Name: "ordered by version",
+			Files: fstest.MapFS{
+				"0010_later.up.sql":   file("SELECT 10;"),
+				"0010_later.down.sql": file("SELECT 10;"),
+				"0002_add.up.sql":     file("-- migrate:no-transaction\nSELECT 2;"),
+				"0002_add.down.sql":   file("SELECT 2;"),
+			},
+			Versions: []int64{2, 10},
+		},
+		{Name: "missing down", Files: fstest.MapFS{"0001_baseline.up.sql": file("SELECT 1;")}, Fails: true},
+		{Name: "bad name", Files: fstest.MapFS{"baseline.up.sql": file("SELECT 1;"), "baseline.down.sql": file("SELECT 1;")}, Fails: true},
+		{
+			Name: "names differ",
+			Files: fstest.MapFS{
+				"0001_baseline.up.sql": file("SELECT 1;"),
+				"0001_other.down.sql":  file("SELECT 1;"),
+			},
+			Fails: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			loaded, err := migrations.LoadMigrations(test.Files)
+			if test.Fails {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", loaded)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			versions := []int64{}
+			for _, m := range loaded {
+				versions = append(versions, m.Version)
+			}
+			if !reflect.DeepEqual(versions, test.Versions) {
+				t.Errorf("expected versions %v, got %v", test.Versions, versions)
+			}
+			if !loaded[0].UpNoTransaction || loaded[0].DownNoTransaction {
+				t.Errorf("expected only the up file of 0002 to run outside a transaction, got %+v", loaded[0])
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	sql := "-- migrate:no-transaction\n-- a comment\n\nCREATE INDEX CONCURRENTLY a\n    ON t (a);\nDROP INDEX b;\nSELECT 1"
+	expected := []string{"CREATE INDEX CONCURRENTLY a\n    ON t (a);", "DROP INDEX b;", "SELECT 1"}
+	if got := migrations.SplitStatements(sql); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+// preSeriesSchema turns the tables the baseline creates back into what gorm AutoMigrate had made of
+// them before the columns added since were introduced.
+const preSeriesSchema = `
+DROP TABLE audit_logs, fee_schedules, rate_limit_counters, request_nonces;
+ALTER TABLE transactions DROP COLUMN seller_escrow_charge, DROP COLUMN fee_schedule_id, DROP COLUMN fee_schedule_version;
+ALTER TABLE activity_logs DROP COLUMN milestone_id, DROP COLUMN event_type, DROP COLUMN actor_account_id,
+    DROP COLUMN actor_role, DROP COLUMN previous_status, DROP COLUMN new_status, DROP COLUMN metadata,
+    ALTER COLUMN description TYPE varchar(255);
+`
+
+func TestMigrateAutoMigratedSchema(t *testing.T) {
+	tst.Setup()
+	var (
+		db           = postgresql.Connection().Transaction
+		fresh        = scratchSchema(t, db)
+		autoMigrated = scratchSchema(t, db)
+	)
+
+	migrate := func(db *gorm.DB) {
+		migrator, err := migrations.NewMigrator(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := migrator.Up(0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	migrate(fresh.db)
+
+	baseline, err := migrations.NewMigrator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := autoMigrated.db.Exec(baseline.Migrations[0].Up).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := autoMigrated.db.Exec(preSeriesSchema).Error; err != nil {
+		t.Fatal(err)
+	}
+	migrate(autoMigrated.db)
+
+	expected, got := schemaColumns(t, db, fresh.name), schemaColumns(t, db, autoMigrated.name)
+	for column, definition := range expected {
+		if got[column] != definition {
+			t.Errorf("%v: expected %q, got %q", column, definition, got[column])
+		}
+	}
+	for column := range got {
+		if _, ok := expected[column]; !ok {
+			t.Errorf("%v: not in a freshly migrated schema", column)
+		}
+	}
+}
+
+type schema struct {
+	name string
+	db   *gorm.DB
+}
+
+// scratchSchema creates an empty schema, dropped when the test ends, and connects to the test
+// database with it as the search path.
+func scratchSchema(t *testing.T, db *gorm.DB) schema {
+	name := "migrations_" + strings.ToLower(utility.RandomString(10))
+	if err := db.Exec(fmt.Sprintf("CREATE SCHEMA %v", name)).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Exec(fmt.Sprintf("DROP SCHEMA %v CASCADE", name)) })
+
+	c := config.GetConfig().TestDatabases
+	dsn := fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v sslmode=%v TimeZone=%v search_path=%v", c.DB_HOST, c.USERNAME, c.PASSWORD, c.TRANSACTIONS_DB, c.DB_PORT, c.SSLMODE, c.TIMEZONE, name)
+	scratch, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := scratch.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return schema{name: name, db: scratch}
+}
+
+// schemaColumns maps table.column to the type, nullability and default of every column in the
+// schema. Sequence defaults name the schema, so they are left out.
+func schemaColumns(t *testing.T, db *gorm.DB, name string) map[string]string {
+	rows := []struct {
+		TableName     string
+		ColumnName    string
+		DataType      string
+		IsNullable    string
+		ColumnDefault string
+	}{}
+	err := db.Raw(`SELECT table_name, column_name, data_type, is_nullable, COALESCE(column_default, '') AS column_default
+		FROM information_schema.columns WHERE table_schema = ?`, name).Scan(&rows).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns := map[string]string{}
+	for _, row := range rows {
+		if strings.HasPrefix(row.ColumnDefault, "nextval(") {
+			row.ColumnDefault = ""
+		}
+		columns[row.TableName+"."+row.ColumnName] = fmt.Sprintf("%v null=%v default=%v", row.DataType, row.IsNullable, row.ColumnDefault)
+	}
+	return columns
+}
@@ -0,0 +1,96 @@
+// Shutdown cannot be undone within a process, so these tests live in their own package.
+package test_shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vesicash/transactions-ms/cronjobs"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func jobStatus(name string) (cronjobs.JobStatus, bool) {
+	for _, status := range cronjobs.Statuses() {
+		if status.Name == name {
+			return status, true
+		}
+	}
+	return cronjobs.JobStatus{}, false
+}
+
+func TestCronJobsShutdown(t *testing.T) {
+	var (
+		repo   = memory.New().Repositories()
+		extReq = fakes.New().ExternalRequest(utility.NewLogger())
+	)
+
+	cronjobs.StartCronJob(extReq, repo, "update-status")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, ok := jobStatus("update-status")
+		if ok && status.Running && status.LastRun != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("cronjob did not run")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cronjobs.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown did not finish: %v", err)
+	}
+	if status, _ := jobStatus("update-status"); status.Running {
+		t.Error("expected cronjob to be stopped")
+	}
+
+	t.Run("stop after shutdown does not block", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			cronjobs.StopCronJob("update-status")
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("StopCronJob blocked after shutdown")
+		}
+	})
+
+	t.Run("jobs are not started after shutdown", func(t *testing.T) {
+		cronjobs.StartCronJob(extReq, repo, "transaction-close")
+		if _, ok := jobStatus("transaction-close"); ok {
+			t.Error("expected cronjob not to start")
+		}
+	})
+
+	t.Run("jobs stop before their next item", func(t *testing.T) {
+		transaction := models.Transaction{
+			TransactionID: "shutdown-tx",
+			Title:         "test transaction",
+			Type:          "oneoff",
+			Status:        "Draft",
+			DueDate:       "2020-01-01 00:00:00",
+			Currency:      "NGN",
+		}
+		if err := repo.Transactions.Create(&transaction); err != nil {
+			t.Fatal(err)
+		}
+
+		cronjobs.HandleTransactionClose(extReq, repo)
+
+		transaction, _, err := repo.Transactions.GetByTransactionID("shutdown-tx")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if transaction.Status != "Draft" {
+			t.Errorf("expected transaction to be left alone, got status %v", transaction.Status)
+		}
+	})
+}
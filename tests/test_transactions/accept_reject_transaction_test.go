@@ -13,12 +13,11 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 	"github.com/vesicash/transactions-ms/external/external_models"
-	"github.com/vesicash/transactions-ms/external/mocks/auth_mocks"
-	"github.com/vesicash/transactions-ms/external/mocks/payment_mocks"
-	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/external/fakes"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	tst "github.com/vesicash/transactions-ms/tests"
 	"github.com/vesicash/transactions-ms/utility"
@@ -26,6 +25,7 @@ import (
 
 func TestAcceptTransaction(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -46,27 +46,27 @@ func TestAcceptTransaction(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -79,7 +79,7 @@ func TestAcceptTransaction(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -92,9 +92,9 @@ func TestAcceptTransaction(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -106,10 +106,7 @@ func TestAcceptTransaction(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -203,6 +200,7 @@ func TestAcceptTransaction(t *testing.T) {
 
 func TestRejectTransaction(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -223,27 +221,27 @@ func TestRejectTransaction(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -256,7 +254,7 @@ func TestRejectTransaction(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -269,9 +267,9 @@ func TestRejectTransaction(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -283,10 +281,7 @@ func TestRejectTransaction(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -381,6 +376,7 @@ func TestRejectTransaction(t *testing.T) {
 
 func TestRejectTransactionDelivery(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -401,27 +397,27 @@ func TestRejectTransactionDelivery(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -434,7 +430,7 @@ func TestRejectTransactionDelivery(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -447,9 +443,9 @@ func TestRejectTransactionDelivery(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -461,10 +457,7 @@ func TestRejectTransactionDelivery(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -13,13 +13,12 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 	"github.com/vesicash/transactions-ms/external/external_models"
-	"github.com/vesicash/transactions-ms/external/mocks/auth_mocks"
-	"github.com/vesicash/transactions-ms/external/mocks/payment_mocks"
-	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/external/fakes"
 	"github.com/vesicash/transactions-ms/internal/config"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	tst "github.com/vesicash/transactions-ms/tests"
 	"github.com/vesicash/transactions-ms/utility"
@@ -27,6 +26,7 @@ import (
 
 func TestCreateActivityLog(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	app := config.GetConfig().App
@@ -47,27 +47,27 @@ func TestCreateActivityLog(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -80,7 +80,7 @@ func TestCreateActivityLog(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -93,9 +93,9 @@ func TestCreateActivityLog(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -107,10 +107,7 @@ func TestCreateActivityLog(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -225,3 +222,183 @@ func TestCreateActivityLog(t *testing.T) {
 	}
 
 }
+
+func TestListActivityLogs(t *testing.T) {
+	logger := tst.Setup()
+	fake := fakes.New()
+	gin.SetMode(gin.TestMode)
+	validatorRef := validator.New()
+	db := postgresql.Connection()
+	var (
+		muuid, _  = uuid.NewV4()
+		accountID = uint(utility.GetRandomNumbersInRange(1000000000, 9999999999))
+		testUser  = external_models.User{
+			ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+			AccountID:    accountID,
+			EmailAddress: fmt.Sprintf("testuser%v@qa.team", muuid.String()),
+			PhoneNumber:  fmt.Sprintf("+234%v", utility.GetRandomNumbersInRange(7000000000, 9099999999)),
+			AccountType:  "individual",
+			Firstname:    "test",
+			Lastname:     "user",
+			Username:     fmt.Sprintf("test_username%v", muuid.String()),
+		}
+	)
+
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+		Status:  true,
+		Message: "authorized",
+		Data:    testUser,
+	}
+	fake.Auth.UserProfile = &external_models.UserProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+
+	fake.Auth.Country = &external_models.Country{
+		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		Name:         "nigeria",
+		CountryCode:  "NG",
+		CurrencyCode: "NGN",
+	}
+
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
+		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		BusinessId:          int(testUser.AccountID),
+		Country:             "NG",
+		Currency:            "NGN",
+		BusinessCharge:      "0",
+		VesicashCharge:      "2.5",
+		ProcessingFee:       "0",
+		PaymentGateway:      "rave",
+		DisbursementGateway: "rave_momo",
+		ProcessingFeeMode:   "fixed",
+	}
+
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
+	r := gin.Default()
+	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
+
+	pvKey := utility.RandomString(20)
+	pbKey := utility.RandomString(20)
+
+	tests := []struct {
+		Name          string
+		ExpectedCode  int
+		Headers       map[string]string
+		Message       string
+		Path          string
+		ExpectedCount int
+	}{
+		{
+			Name:         "OK list activities by transaction id",
+			ExpectedCode: http.StatusOK,
+			Message:      "successful",
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"v-private-key": pvKey,
+				"v-public-key":  pbKey,
+			},
+			Path:          "/v2/activities/" + transaction.TransactionID,
+			ExpectedCount: 1,
+		},
+		{
+			Name:         "OK filter activities by event type",
+			ExpectedCode: http.StatusOK,
+			Message:      "successful",
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"v-private-key": pvKey,
+				"v-public-key":  pbKey,
+			},
+			Path:          "/v2/activities/" + transaction.TransactionID + "?event_type=" + models.ActivityTransactionCreated,
+			ExpectedCount: 1,
+		},
+		{
+			Name:         "OK filter activities by unknown event type",
+			ExpectedCode: http.StatusOK,
+			Message:      "successful",
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"v-private-key": pvKey,
+				"v-public-key":  pbKey,
+			},
+			Path:          "/v2/activities/" + transaction.TransactionID + "?event_type=unknown",
+			ExpectedCount: 0,
+		},
+		{
+			Name:         "OK list activities by business",
+			ExpectedCode: http.StatusOK,
+			Message:      "successful",
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"v-private-key": pvKey,
+				"v-public-key":  pbKey,
+			},
+			Path:          fmt.Sprintf("/v2/activities/business/%v", testUser.AccountID),
+			ExpectedCount: 1,
+		},
+		{
+			Name:         "invalid business id",
+			ExpectedCode: http.StatusBadRequest,
+			Message:      "invalid business id",
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"v-private-key": pvKey,
+				"v-public-key":  pbKey,
+			},
+			Path: "/v2/activities/business/not",
+		},
+	}
+
+	transactionApiUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.ApiType))
+	{
+		transactionApiUrl.GET("/activities/:transaction_id", trans.ListActivityLogs)
+		transactionApiUrl.GET("/activities/business/:business_id", trans.ListBusinessActivityLogs)
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, test.Path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, v := range test.Headers {
+				req.Header.Set(i, v)
+			}
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			tst.AssertStatusCode(t, rr.Code, test.ExpectedCode)
+
+			data := tst.ParseResponse(rr)
+
+			code := int(data["code"].(float64))
+			tst.AssertStatusCode(t, code, test.ExpectedCode)
+
+			if test.Message != "" {
+				message := data["message"]
+				if message != nil {
+					tst.AssertResponseMessage(t, message.(string), test.Message)
+				} else {
+					tst.AssertResponseMessage(t, "", test.Message)
+				}
+
+			}
+
+			if test.ExpectedCode == http.StatusOK {
+				activities, _ := data["data"].([]interface{})
+				if len(activities) != test.ExpectedCount {
+					t.Errorf("handler returned wrong number of activities: got %v expected %v", len(activities), test.ExpectedCount)
+				}
+			}
+
+		})
+
+	}
+
+}
@@ -0,0 +1,240 @@
+package test_transactions
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofrs/uuid"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	tst "github.com/vesicash/transactions-ms/tests"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func TestAuditLog(t *testing.T) {
+	logger := tst.Setup()
+	fake := fakes.New()
+	gin.SetMode(gin.TestMode)
+	validatorRef := validator.New()
+	app := config.GetConfig().App
+	db := postgresql.Connection()
+	var (
+		muuid, _  = uuid.NewV4()
+		accountID = uint(utility.GetRandomNumbersInRange(1000000000, 9999999999))
+		testUser  = external_models.User{
+			ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+			AccountID:    accountID,
+			EmailAddress: fmt.Sprintf("testuser%v@qa.team", muuid.String()),
+			PhoneNumber:  fmt.Sprintf("+234%v", utility.GetRandomNumbersInRange(7000000000, 9099999999)),
+			AccountType:  "individual",
+			Firstname:    "test",
+			Lastname:     "user",
+			Username:     fmt.Sprintf("test_username%v", muuid.String()),
+		}
+	)
+
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+		Status:  true,
+		Message: "authorized",
+		Data:    testUser,
+	}
+	fake.Auth.UserProfile = &external_models.UserProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+
+	fake.Auth.Country = &external_models.Country{
+		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		Name:         "nigeria",
+		CountryCode:  "NG",
+		CurrencyCode: "NGN",
+	}
+
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
+		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		BusinessId:          int(testUser.AccountID),
+		Country:             "NG",
+		Currency:            "NGN",
+		BusinessCharge:      "0",
+		VesicashCharge:      "2.5",
+		ProcessingFee:       "0",
+		PaymentGateway:      "rave",
+		DisbursementGateway: "rave_momo",
+		ProcessingFeeMode:   "fixed",
+	}
+
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
+	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
+	r := gin.Default()
+
+	tests := []struct {
+		Name         string
+		Path         string
+		ExpectedCode int
+		Headers      map[string]string
+		Message      string
+		Check        func(t *testing.T, data map[string]interface{})
+	}{
+		{
+			Name:         "OK list audit logs",
+			Path:         "/v2/audit/transaction/" + transaction.TransactionID,
+			ExpectedCode: http.StatusOK,
+			Message:      "successful",
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+				"v-app":        app.Key,
+			},
+			Check: func(t *testing.T, data map[string]interface{}) {
+				logs, _ := data["data"].([]interface{})
+				if len(logs) == 0 {
+					t.Fatalf("expected audit entries for transaction %v", transaction.TransactionID)
+				}
+				first := logs[0].(map[string]interface{})
+				if first["action"] != models.AuditActionCreate || first["hash"] == "" {
+					t.Errorf("unexpected first audit entry: %v", first)
+				}
+			},
+		},
+		{
+			Name:         "OK verify audit chain",
+			Path:         "/v2/audit/verify",
+			ExpectedCode: http.StatusOK,
+			Message:      "successful",
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+				"v-app":        app.Key,
+			},
+			Check: func(t *testing.T, data map[string]interface{}) {
+				result := data["data"].(map[string]interface{})
+				if result["valid"] != true {
+					t.Errorf("audit chain reported broken: %v", result)
+				}
+			},
+		},
+		{
+			Name:         "no app key",
+			Path:         "/v2/audit/verify",
+			ExpectedCode: http.StatusUnauthorized,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+		},
+	}
+
+	transactionsAppUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.AppType))
+	{
+		transactionsAppUrl.GET("/audit/transaction/:transaction_id", trans.ListAuditLogs)
+		transactionsAppUrl.GET("/audit/verify", trans.VerifyAuditChain)
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, test.Path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, v := range test.Headers {
+				req.Header.Set(i, v)
+			}
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			tst.AssertStatusCode(t, rr.Code, test.ExpectedCode)
+
+			data := tst.ParseResponse(rr)
+
+			code := int(data["code"].(float64))
+			tst.AssertStatusCode(t, code, test.ExpectedCode)
+
+			if test.Message != "" {
+				message := data["message"]
+				if message != nil {
+					tst.AssertResponseMessage(t, message.(string), test.Message)
+				} else {
+					tst.AssertResponseMessage(t, "", test.Message)
+				}
+
+			}
+
+			if test.Check != nil {
+				test.Check(t, data)
+			}
+
+		})
+
+	}
+
+}
+
+func TestAuditLogRestorePurge(t *testing.T) {
+	logger := tst.Setup()
+	fake := fakes.New()
+	validatorRef := validator.New()
+	db := postgresql.Connection()
+	repo := gormrepo.New(db.Transaction)
+	accountID := utility.GetRandomNumbersInRange(1000000000, 9999999999)
+	created := tst.CreateTransactionUser(t, db, validatorRef, fake.ExternalRequest(logger), accountID, false)
+
+	actions := func(t *testing.T) map[string]int {
+		entries, _, err := (&models.AuditLog{TransactionID: created.TransactionID}).GetAllByTransactionID(db.Transaction, postgresql.Pagination{Page: 1, Limit: 1000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := map[string]int{}
+		for _, entry := range entries {
+			counts[entry.Action]++
+		}
+		return counts
+	}
+
+	transaction, _, err := repo.Transactions.GetByTransactionID(created.TransactionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Transactions.Delete(&transaction); err != nil {
+		t.Fatal(err)
+	}
+	deleted, _, err := repo.Transactions.GetDeletedByTransactionID(created.TransactionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Transactions.Restore(&deleted); err != nil {
+		t.Fatal(err)
+	}
+	counts := actions(t)
+	if counts[models.AuditActionRestore] == 0 || counts[models.AuditActionRestore] != counts[models.AuditActionDelete] {
+		t.Errorf("expected a restore entry for every deleted row, got %v", counts)
+	}
+
+	if err := repo.Transactions.Delete(&transaction); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Transactions.PurgeDeleted(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	counts = actions(t)
+	if counts[models.AuditActionPurge] == 0 || counts[models.AuditActionPurge] != counts[models.AuditActionRestore] {
+		t.Errorf("expected a purge entry for every deleted row, got %v", counts)
+	}
+
+	verification, err := (&models.AuditLog{}).VerifyChain(db.Transaction)
+	if err != nil || !verification.Valid {
+		t.Errorf("expected the audit chain to hold, got %+v, %v", verification, err)
+	}
+}
@@ -13,20 +13,22 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 	"github.com/vesicash/transactions-ms/external/external_models"
-	"github.com/vesicash/transactions-ms/external/mocks/auth_mocks"
-	"github.com/vesicash/transactions-ms/external/mocks/payment_mocks"
-	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/external/fakes"
 	"github.com/vesicash/transactions-ms/internal/config"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	tsvc "github.com/vesicash/transactions-ms/services/transactions"
 	tst "github.com/vesicash/transactions-ms/tests"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
 func TestCreateTransaction(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -47,27 +49,33 @@ func TestCreateTransaction(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -80,7 +88,7 @@ func TestCreateTransaction(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -93,10 +101,7 @@ func TestCreateTransaction(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	r := gin.Default()
 
 	tests := []struct {
@@ -626,6 +631,7 @@ func TestCreateTransaction(t *testing.T) {
 
 func TestCheckTransactionAmount(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -646,27 +652,33 @@ func TestCheckTransactionAmount(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -679,7 +691,7 @@ func TestCheckTransactionAmount(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -692,9 +704,9 @@ func TestCheckTransactionAmount(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -706,10 +718,7 @@ func TestCheckTransactionAmount(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -802,6 +811,7 @@ func TestCheckTransactionAmount(t *testing.T) {
 }
 func TestUpdateTransactionAmountPaid(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	app := config.GetConfig().App
@@ -822,27 +832,33 @@ func TestUpdateTransactionAmountPaid(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -855,7 +871,7 @@ func TestUpdateTransactionAmountPaid(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -868,9 +884,9 @@ func TestUpdateTransactionAmountPaid(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -882,15 +898,21 @@ func TestUpdateTransactionAmountPaid(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
+	broker := models.TransactionBroker{
+		TransactionBrokerID: utility.RandomString(20),
+		TransactionID:       transaction.TransactionID,
+		BrokerCharge:        "50",
+		BrokerChargeBearer:  "buyer",
+		BrokerChargeType:    "fixed",
+	}
+
 	tests := []struct {
 		Name         string
+		Before       func(t *testing.T)
 		RequestBody  models.UpdateTransactionAmountPaid
 		ExpectedCode int
 		Headers      map[string]string
@@ -955,6 +977,45 @@ func TestUpdateTransactionAmountPaid(t *testing.T) {
 				"v-app":        app.Key,
 			},
 		},
+		{
+			Name: "OK broker charge not accepted",
+			Before: func(t *testing.T) {
+				if err := broker.CreateTransactionBroker(db.Transaction); err != nil {
+					t.Fatal(err)
+				}
+			},
+			RequestBody: models.UpdateTransactionAmountPaid{
+				TransactionID: transaction.TransactionID,
+				Amount:        200,
+				Action:        "+",
+			},
+			ExpectedCode: http.StatusOK,
+			Message:      "successful",
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+				"v-app":        app.Key,
+			},
+		},
+		{
+			Name: "OK broker charge accepted",
+			Before: func(t *testing.T) {
+				broker.IsBuyerAccepted, broker.IsSellerAccepted = true, true
+				if err := broker.UpdateAllFields(db.Transaction); err != nil {
+					t.Fatal(err)
+				}
+			},
+			RequestBody: models.UpdateTransactionAmountPaid{
+				TransactionID: transaction.TransactionID,
+				Amount:        200,
+				Action:        "+",
+			},
+			ExpectedCode: http.StatusOK,
+			Message:      "successful",
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+				"v-app":        app.Key,
+			},
+		},
 	}
 
 	transactionsAppUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.AppType))
@@ -964,6 +1025,9 @@ func TestUpdateTransactionAmountPaid(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
+			if test.Before != nil {
+				test.Before(t)
+			}
 
 			var b bytes.Buffer
 			json.NewEncoder(&b).Encode(test.RequestBody)
@@ -1003,3 +1067,72 @@ func TestUpdateTransactionAmountPaid(t *testing.T) {
 	}
 
 }
+
+func TestCreateTransactionServices(t *testing.T) {
+	var (
+		fake   = fakes.New()
+		logger = utility.NewLogger()
+		extReq = fake.ExternalRequest(logger)
+		repo   = memory.New().Repositories()
+		seller = external_models.User{ID: 1, AccountID: 1, AccountType: "individual"}
+		buyer  = 2
+	)
+	fake.Auth.User = &seller
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{ID: 1, BusinessId: 1, Country: "NG", Currency: "NGN", BusinessCharge: "0", VesicashCharge: "2.5", ProcessingFee: "0"}
+	fake.Payment.Payment = &external_models.Payment{ID: 1, TotalAmount: 1500, Currency: "NGN"}
+
+	req := models.CreateTransactionRequest{
+		Parties: []models.Party{
+			{AccountID: int(seller.AccountID), Role: "seller", Status: "draft", AccessLevel: models.PartyAccessLevel{CanView: true, CanReceive: true}},
+			{AccountID: buyer, Role: "buyer", Status: "draft", AccessLevel: models.PartyAccessLevel{CanView: true, Approve: true}},
+		},
+		Title:        "test title",
+		Type:         "milestone",
+		EscrowWallet: "yes",
+		Files:        []models.File{{Name: "file name", URL: "https://linktofile.com"}},
+		Milestones: []models.MileStone{
+			{Title: "first", Amount: 1000, InspectionPeriod: 4, DueDate: "2023-03-16", GracePeriod: "2023-03-18", Status: "draft", Quantity: 1, Recipients: []models.MileStoneRecipient{{AccountID: int(seller.AccountID), Amount: 1000}}},
+			{Title: "second", Amount: 500, InspectionPeriod: 4, DueDate: "2023-03-20", GracePeriod: "2023-03-22", Status: "draft", Quantity: 1, Recipients: []models.MileStoneRecipient{{AccountID: int(seller.AccountID), Amount: 500}}},
+		},
+		Amount:   1500,
+		DueDate:  "2023-03-20",
+		Currency: "ngn",
+	}
+
+	t.Run("amount below milestones", func(t *testing.T) {
+		short := req
+		short.Amount = 1000
+		_, code, err := tsvc.CreateTransactionService(extReq, logger, repo, short, models.Principal{User: &seller})
+		if err == nil || code != http.StatusBadRequest {
+			t.Errorf("expected bad request, got %v, %v", code, err)
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		created, code, err := tsvc.CreateTransactionService(extReq, logger, repo, req, models.Principal{User: &seller})
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected transaction to be created, got %v, %v", code, err)
+		}
+		if created.Currency != "NGN" || len(created.Parties) != 2 || len(created.Files) != 1 || len(created.Milestones) != 2 {
+			t.Errorf("unexpected response %+v", created)
+		}
+
+		milestones, _ := repo.Transactions.ListByTransactionID(created.TransactionID)
+		if len(milestones) != 2 {
+			t.Fatalf("expected two milestones to be stored, got %v", len(milestones))
+		}
+		for _, m := range milestones {
+			if m.EscrowCharge != 37.5 || m.Currency != "NGN" {
+				t.Errorf("unexpected milestone %+v", m)
+			}
+		}
+		parties, _ := repo.Parties.ListByTransactionID(created.TransactionID)
+		if len(parties) != 2 {
+			t.Errorf("expected two parties to be stored, got %v", len(parties))
+		}
+		activities, _ := repo.ActivityLogs.ListByTransactionID(created.TransactionID)
+		if len(activities) != 1 || activities[0].EventType != models.ActivityTransactionCreated || activities[0].ActorRole != "seller" {
+			t.Errorf("expected a created activity by the seller, got %+v", activities)
+		}
+	})
+}
@@ -13,12 +13,11 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 	"github.com/vesicash/transactions-ms/external/external_models"
-	"github.com/vesicash/transactions-ms/external/mocks/auth_mocks"
-	"github.com/vesicash/transactions-ms/external/mocks/payment_mocks"
-	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/external/fakes"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	tst "github.com/vesicash/transactions-ms/tests"
 	"github.com/vesicash/transactions-ms/utility"
@@ -26,6 +25,7 @@ import (
 
 func TestTransactionDelivered(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -46,27 +46,27 @@ func TestTransactionDelivered(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -79,7 +79,7 @@ func TestTransactionDelivered(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -92,9 +92,9 @@ func TestTransactionDelivered(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -106,10 +106,7 @@ func TestTransactionDelivered(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -217,6 +214,7 @@ func TestTransactionDelivered(t *testing.T) {
 
 func TestSatisfied(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -237,27 +235,27 @@ func TestSatisfied(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -270,7 +268,7 @@ func TestSatisfied(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -283,9 +281,9 @@ func TestSatisfied(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -297,10 +295,7 @@ func TestSatisfied(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -393,6 +388,7 @@ func TestSatisfied(t *testing.T) {
 }
 func TestSatisfiedApi(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -412,15 +408,15 @@ func TestSatisfiedApi(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
 	pvKey := utility.RandomString(20)
 	pbKey := utility.RandomString(20)
-	auth_mocks.AccessToken = external_models.AccessToken{
+	fake.Auth.AccessToken = external_models.AccessToken{
 		ID:         uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID:  int(testUser.AccountID),
 		PublicKey:  pbKey,
@@ -428,21 +424,21 @@ func TestSatisfiedApi(t *testing.T) {
 		IsLive:     true,
 	}
 
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -455,7 +451,7 @@ func TestSatisfiedApi(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -468,9 +464,9 @@ func TestSatisfiedApi(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -482,10 +478,7 @@ func TestSatisfiedApi(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -13,19 +13,21 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 	"github.com/vesicash/transactions-ms/external/external_models"
-	"github.com/vesicash/transactions-ms/external/mocks/auth_mocks"
-	"github.com/vesicash/transactions-ms/external/mocks/payment_mocks"
-	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/external/fakes"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	tsvc "github.com/vesicash/transactions-ms/services/transactions"
 	tst "github.com/vesicash/transactions-ms/tests"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
 func TestCreateDispute(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -46,27 +48,27 @@ func TestCreateDispute(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -79,7 +81,7 @@ func TestCreateDispute(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -92,9 +94,9 @@ func TestCreateDispute(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -106,10 +108,7 @@ func TestCreateDispute(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -205,6 +204,7 @@ func TestCreateDispute(t *testing.T) {
 }
 func TestGetDisputeByTransactionID(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -225,27 +225,27 @@ func TestGetDisputeByTransactionID(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -258,7 +258,7 @@ func TestGetDisputeByTransactionID(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -271,9 +271,9 @@ func TestGetDisputeByTransactionID(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -285,10 +285,7 @@ func TestGetDisputeByTransactionID(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -380,6 +377,7 @@ func TestGetDisputeByTransactionID(t *testing.T) {
 
 func TestUpdateDispute(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -400,27 +398,27 @@ func TestUpdateDispute(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -433,7 +431,7 @@ func TestUpdateDispute(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -446,9 +444,9 @@ func TestUpdateDispute(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -460,10 +458,7 @@ func TestUpdateDispute(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -570,6 +565,7 @@ func TestUpdateDispute(t *testing.T) {
 
 func TestGetDisputeByUser(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -590,27 +586,27 @@ func TestGetDisputeByUser(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -623,7 +619,7 @@ func TestGetDisputeByUser(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -636,9 +632,9 @@ func TestGetDisputeByUser(t *testing.T) {
 		DisburseCurrency: "NGN",
 	}
 
-	payment_mocks.ListPaymentObj = &external_models.ListPayment{
+	fake.Payment.ListPaymentObj = &external_models.ListPayment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
-		PaymentID:        payment_mocks.Payment.PaymentID,
+		PaymentID:        fake.Payment.Payment.PaymentID,
 		TransactionID:    transactionID,
 		TotalAmount:      3000,
 		EscrowCharge:     10,
@@ -650,10 +646,7 @@ func TestGetDisputeByUser(t *testing.T) {
 		SummedAmount:     4000,
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 	r := gin.Default()
 
@@ -731,3 +724,71 @@ func TestGetDisputeByUser(t *testing.T) {
 	}
 
 }
+
+func TestDisputeServices(t *testing.T) {
+	var (
+		fake          = fakes.New()
+		logger        = utility.NewLogger()
+		extReq        = fake.ExternalRequest(logger)
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+		buyer         = external_models.User{ID: 1, AccountID: 1}
+		stranger      = external_models.User{ID: 2, AccountID: 2}
+	)
+	repo.Transactions.Create(&models.Transaction{TransactionID: transactionID, PartiesID: transactionID, Status: tsvc.GetTransactionStatus("ip")})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 1, Role: "buyer"})
+
+	t.Run("no dispute yet", func(t *testing.T) {
+		dispute, code, err := tsvc.GetDisputeByTransactionIDService(extReq, logger, repo, transactionID, buyer)
+		if err != nil || code != http.StatusOK || dispute != nil {
+			t.Errorf("expected no dispute, got %+v, %v, %v", dispute, code, err)
+		}
+	})
+
+	t.Run("create on missing transaction", func(t *testing.T) {
+		code, err := tsvc.CreateDisputeService(extReq, logger, repo, models.CreateDisputeRequest{TransactionID: "missing"}, buyer)
+		if err == nil || code != http.StatusBadRequest {
+			t.Errorf("expected bad request, got %v, %v", code, err)
+		}
+	})
+
+	t.Run("create by non party", func(t *testing.T) {
+		code, err := tsvc.CreateDisputeService(extReq, logger, repo, models.CreateDisputeRequest{TransactionID: transactionID, Reason: "not delivered"}, stranger)
+		if err == nil || code != http.StatusForbidden {
+			t.Errorf("expected forbidden, got %v, %v", code, err)
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		code, err := tsvc.CreateDisputeService(extReq, logger, repo, models.CreateDisputeRequest{TransactionID: transactionID, Reason: "not delivered", DisputeStatus: "open"}, buyer)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected dispute to be created, got %v, %v", code, err)
+		}
+		transaction, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+		if transaction.Status != tsvc.GetTransactionStatus("cd") {
+			t.Errorf("expected transaction status %q, got %q", tsvc.GetTransactionStatus("cd"), transaction.Status)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		code, err := tsvc.UpdateDisputeService(extReq, logger, repo, models.CreateDisputeRequest{TransactionID: transactionID, Decision: "refund"}, buyer)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected dispute to be updated, got %v, %v", code, err)
+		}
+		dispute, _, _ := tsvc.GetDisputeByTransactionIDService(extReq, logger, repo, transactionID, buyer)
+		if dispute == nil || dispute.Reason != "not delivered" || dispute.Decision != "refund" {
+			t.Errorf("unexpected dispute %+v", dispute)
+		}
+	})
+
+	t.Run("by user", func(t *testing.T) {
+		disputes, _, code, err := tsvc.GetDisputeByUserService(extReq, logger, repo, buyer, postgresql.Pagination{})
+		if err != nil || code != http.StatusOK || len(disputes) != 1 {
+			t.Errorf("expected one dispute for the buyer, got %v, %v, %v", disputes, code, err)
+		}
+		disputes, _, _, _ = tsvc.GetDisputeByUserService(extReq, logger, repo, stranger, postgresql.Pagination{})
+		if len(disputes) != 0 {
+			t.Errorf("expected no disputes for another account, got %v", disputes)
+		}
+	})
+}
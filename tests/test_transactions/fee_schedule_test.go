@@ -0,0 +1,289 @@
+package test_transactions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofrs/uuid"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	tst "github.com/vesicash/transactions-ms/tests"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func TestFeeSchedules(t *testing.T) {
+	logger := tst.Setup()
+	fake := fakes.New()
+	gin.SetMode(gin.TestMode)
+	validatorRef := validator.New()
+	app := config.GetConfig().App
+	db := postgresql.Connection()
+	var (
+		muuid, _  = uuid.NewV4()
+		accountID = uint(utility.GetRandomNumbersInRange(1000000000, 9999999999))
+		testUser  = external_models.User{
+			ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+			AccountID:    accountID,
+			EmailAddress: fmt.Sprintf("testuser%v@qa.team", muuid.String()),
+			PhoneNumber:  fmt.Sprintf("+234%v", utility.GetRandomNumbersInRange(7000000000, 9099999999)),
+			AccountType:  "individual",
+			Firstname:    "test",
+			Lastname:     "user",
+			Username:     fmt.Sprintf("test_username%v", muuid.String()),
+		}
+		feeScheduleID string
+		sellerBearer  = "seller"
+	)
+
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+		Status:  true,
+		Message: "authorized",
+		Data:    testUser,
+	}
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
+		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		BusinessId:          int(testUser.AccountID),
+		Country:             "NG",
+		Currency:            "NGN",
+		BusinessCharge:      "0",
+		VesicashCharge:      "2.5",
+		ProcessingFee:       "0",
+		PaymentGateway:      "rave",
+		DisbursementGateway: "rave_momo",
+		ProcessingFeeMode:   "fixed",
+	}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
+	r := gin.Default()
+
+	appHeaders := map[string]string{
+		"Content-Type": "application/json",
+		"v-app":        app.Key,
+	}
+	apiHeaders := map[string]string{
+		"Content-Type":  "application/json",
+		"v-private-key": utility.RandomString(20),
+		"v-public-key":  utility.RandomString(20),
+	}
+
+	tests := []struct {
+		Name         string
+		Method       string
+		Path         func() string
+		RequestBody  interface{}
+		ExpectedCode int
+		Headers      map[string]string
+		Message      string
+		Check        func(t *testing.T, data map[string]interface{})
+	}{
+		{
+			Name:   "OK create fee schedule",
+			Method: http.MethodPost,
+			Path:   func() string { return "/v2/fees/schedules" },
+			RequestBody: models.CreateFeeScheduleRequest{
+				BusinessID: int(testUser.AccountID),
+				Currency:   "ngn",
+				Tiers: []models.FeeTier{
+					{UpTo: 1000, Percentage: 1, Flat: 10},
+					{Percentage: 0.5},
+				},
+				MaxCharge:     100,
+				ChargeBearer:  "split",
+				BuyerSplit:    40,
+				EffectiveFrom: "2020-01-01",
+			},
+			ExpectedCode: http.StatusOK,
+			Message:      "Fee schedule created",
+			Headers:      appHeaders,
+			Check: func(t *testing.T, data map[string]interface{}) {
+				schedule := data["data"].(map[string]interface{})
+				feeScheduleID = schedule["fee_schedule_id"].(string)
+				if schedule["currency"] != "NGN" || schedule["version"].(float64) != 1 {
+					t.Errorf("unexpected fee schedule: %v", schedule)
+				}
+			},
+		},
+		{
+			Name:   "escrow charge uses the fee schedule",
+			Method: http.MethodPost,
+			Path:   func() string { return "/v2/escrowcharge" },
+			RequestBody: models.GetEscrowChargeRequest{
+				BusinessID: int(testUser.AccountID),
+				Amount:     700,
+			},
+			ExpectedCode: http.StatusOK,
+			Message:      "Data Retrieved",
+			Headers:      apiHeaders,
+			Check: func(t *testing.T, data map[string]interface{}) {
+				charge := data["data"].(map[string]interface{})
+				if charge["charge"].(float64) != 17 || charge["buyer_charge"].(float64) != 6.8 || charge["seller_charge"].(float64) != 10.2 {
+					t.Errorf("unexpected escrow charge: %v", charge)
+				}
+			},
+		},
+		{
+			Name:   "escrow charge is capped",
+			Method: http.MethodPost,
+			Path:   func() string { return "/v2/escrowcharge" },
+			RequestBody: models.GetEscrowChargeRequest{
+				BusinessID: int(testUser.AccountID),
+				Amount:     50000,
+			},
+			ExpectedCode: http.StatusOK,
+			Headers:      apiHeaders,
+			Check: func(t *testing.T, data map[string]interface{}) {
+				charge := data["data"].(map[string]interface{})
+				if charge["charge"].(float64) != 100 {
+					t.Errorf("expected charge to be capped at 100, got %v", charge["charge"])
+				}
+			},
+		},
+		{
+			Name:   "OK update fee schedule",
+			Method: http.MethodPatch,
+			Path:   func() string { return "/v2/fees/schedules/" + feeScheduleID },
+			RequestBody: models.UpdateFeeScheduleRequest{
+				ChargeBearer: sellerBearer,
+			},
+			ExpectedCode: http.StatusOK,
+			Message:      "Fee schedule updated",
+			Headers:      appHeaders,
+			Check: func(t *testing.T, data map[string]interface{}) {
+				schedule := data["data"].(map[string]interface{})
+				if schedule["version"].(float64) != 2 || schedule["charge_bearer"] != sellerBearer {
+					t.Errorf("unexpected fee schedule version: %v", schedule)
+				}
+			},
+		},
+		{
+			Name:   "escrow charge uses the latest version",
+			Method: http.MethodPost,
+			Path:   func() string { return "/v2/escrowcharge" },
+			RequestBody: models.GetEscrowChargeRequest{
+				BusinessID: int(testUser.AccountID),
+				Amount:     700,
+			},
+			ExpectedCode: http.StatusOK,
+			Headers:      apiHeaders,
+			Check: func(t *testing.T, data map[string]interface{}) {
+				charge := data["data"].(map[string]interface{})
+				if charge["seller_charge"].(float64) != 17 || charge["version"].(float64) != 2 {
+					t.Errorf("unexpected escrow charge: %v", charge)
+				}
+			},
+		},
+		{
+			Name:         "OK list fee schedule versions",
+			Method:       http.MethodGet,
+			Path:         func() string { return "/v2/fees/schedules/" + feeScheduleID + "/versions" },
+			ExpectedCode: http.StatusOK,
+			Headers:      appHeaders,
+			Check: func(t *testing.T, data map[string]interface{}) {
+				versions := data["data"].([]interface{})
+				if len(versions) != 2 {
+					t.Errorf("expected 2 versions, got %v", len(versions))
+				}
+			},
+		},
+		{
+			Name:   "min charge greater than max charge",
+			Method: http.MethodPost,
+			Path:   func() string { return "/v2/fees/schedules" },
+			RequestBody: models.CreateFeeScheduleRequest{
+				Tiers:        []models.FeeTier{{Percentage: 1}},
+				MinCharge:    200,
+				MaxCharge:    100,
+				ChargeBearer: "buyer",
+			},
+			ExpectedCode: http.StatusBadRequest,
+			Message:      "min_charge cannot be greater than max_charge",
+			Headers:      appHeaders,
+		},
+		{
+			Name:   "invalid charge bearer",
+			Method: http.MethodPost,
+			Path:   func() string { return "/v2/fees/schedules" },
+			RequestBody: models.CreateFeeScheduleRequest{
+				Tiers:        []models.FeeTier{{Percentage: 1}},
+				ChargeBearer: "broker",
+			},
+			ExpectedCode: http.StatusBadRequest,
+			Message:      "Validation failed",
+			Headers:      appHeaders,
+		},
+	}
+
+	transactionsAppUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.AppType))
+	{
+		transactionsAppUrl.POST("/fees/schedules", trans.CreateFeeSchedule)
+		transactionsAppUrl.PATCH("/fees/schedules/:fee_schedule_id", trans.UpdateFeeSchedule)
+		transactionsAppUrl.GET("/fees/schedules/:fee_schedule_id/versions", trans.GetFeeScheduleVersions)
+	}
+	transactionApiUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.ApiType))
+	{
+		transactionApiUrl.POST("/escrowcharge", trans.GetEscrowCharge)
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var b bytes.Buffer
+			if test.RequestBody != nil {
+				json.NewEncoder(&b).Encode(test.RequestBody)
+			}
+
+			req, err := http.NewRequest(test.Method, test.Path(), &b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, v := range test.Headers {
+				req.Header.Set(i, v)
+			}
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			tst.AssertStatusCode(t, rr.Code, test.ExpectedCode)
+
+			data := tst.ParseResponse(rr)
+
+			code := int(data["code"].(float64))
+			tst.AssertStatusCode(t, code, test.ExpectedCode)
+
+			if test.Message != "" {
+				message := data["message"]
+				if message != nil {
+					tst.AssertResponseMessage(t, message.(string), test.Message)
+				} else {
+					tst.AssertResponseMessage(t, "", test.Message)
+				}
+
+			}
+
+			if test.Check != nil {
+				test.Check(t, data)
+			}
+
+		})
+
+	}
+
+}
@@ -9,24 +9,27 @@ import (
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 	"github.com/vesicash/transactions-ms/external/external_models"
-	"github.com/vesicash/transactions-ms/external/mocks/auth_mocks"
-	"github.com/vesicash/transactions-ms/external/mocks/payment_mocks"
-	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/external/fakes"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	tsvc "github.com/vesicash/transactions-ms/services/transactions"
 	tst "github.com/vesicash/transactions-ms/tests"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
 func TestListTransactionsByUser(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -47,27 +50,33 @@ func TestListTransactionsByUser(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -79,7 +88,7 @@ func TestListTransactionsByUser(t *testing.T) {
 		DisbursementGateway: "rave_momo",
 		ProcessingFeeMode:   "fixed",
 	}
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -93,10 +102,7 @@ func TestListTransactionsByUser(t *testing.T) {
 	}
 
 	r := gin.Default()
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 
 	tests := []struct {
@@ -184,6 +190,7 @@ func TestListTransactionsByUser(t *testing.T) {
 
 func TestListArchivedTransactionsByUser(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -204,27 +211,33 @@ func TestListArchivedTransactionsByUser(t *testing.T) {
 		transactionID = utility.RandomString(20)
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -236,7 +249,7 @@ func TestListArchivedTransactionsByUser(t *testing.T) {
 		DisbursementGateway: "rave_momo",
 		ProcessingFeeMode:   "fixed",
 	}
-	payment_mocks.Payment = &external_models.Payment{
+	fake.Payment.Payment = &external_models.Payment{
 		ID:               int64(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		PaymentID:        utility.RandomString(20),
 		TransactionID:    transactionID,
@@ -250,10 +263,7 @@ func TestListArchivedTransactionsByUser(t *testing.T) {
 	}
 
 	r := gin.Default()
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), true)
 
 	tests := []struct {
@@ -323,6 +333,7 @@ func TestListArchivedTransactionsByUser(t *testing.T) {
 
 func TestListTransactionsByID(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -341,27 +352,33 @@ func TestListTransactionsByID(t *testing.T) {
 		}
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -374,10 +391,7 @@ func TestListTransactionsByID(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	r := gin.Default()
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 
@@ -466,6 +480,7 @@ func TestListTransactionsByID(t *testing.T) {
 }
 func TestListTransactions(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -484,27 +499,33 @@ func TestListTransactions(t *testing.T) {
 		}
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -517,10 +538,7 @@ func TestListTransactions(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	r := gin.Default()
 	tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 
@@ -530,6 +548,7 @@ func TestListTransactions(t *testing.T) {
 	tests := []struct {
 		Name         string
 		RequestBody  models.ListTransactionsRequest
+		Fields       string
 		ExpectedCode int
 		Headers      map[string]string
 		Message      string
@@ -549,6 +568,36 @@ func TestListTransactions(t *testing.T) {
 				"v-public-key":  pbKey,
 			},
 		},
+		{
+			Name: "OK list transactions with selected fields",
+			RequestBody: models.ListTransactionsRequest{
+				Status: "draft",
+				Filter: "day",
+			},
+			Fields:       "parties,milestones",
+			ExpectedCode: http.StatusOK,
+			Message:      "successful",
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"v-private-key": pvKey,
+				"v-public-key":  pbKey,
+			},
+		},
+		{
+			Name: "unknown field",
+			RequestBody: models.ListTransactionsRequest{
+				Status: "draft",
+				Filter: "day",
+			},
+			Fields:       "parties,secrets",
+			ExpectedCode: http.StatusBadRequest,
+			Message:      "unknown field secrets, expected any of products, parties, files, milestones, broker, activities, country, closed_at, dispute, payment",
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"v-private-key": pvKey,
+				"v-public-key":  pbKey,
+			},
+		},
 	}
 
 	transactionApiUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.ApiType))
@@ -562,6 +611,9 @@ func TestListTransactions(t *testing.T) {
 			var b bytes.Buffer
 			json.NewEncoder(&b).Encode(test.RequestBody)
 			URI := url.URL{Path: "/v2/list"}
+			if test.Fields != "" {
+				URI.RawQuery = url.Values{"fields": {test.Fields}}.Encode()
+			}
 
 			req, err := http.NewRequest(http.MethodPost, URI.String(), &b)
 			if err != nil {
@@ -600,6 +652,7 @@ func TestListTransactions(t *testing.T) {
 
 func TestListTransactionsByUssdCode(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -618,27 +671,33 @@ func TestListTransactionsByUssdCode(t *testing.T) {
 		}
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -651,10 +710,7 @@ func TestListTransactionsByUssdCode(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	r := gin.Default()
 	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 
@@ -744,6 +800,7 @@ func TestListTransactionsByUssdCode(t *testing.T) {
 
 func TestListTransactionsByBusiness(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -762,27 +819,33 @@ func TestListTransactionsByBusiness(t *testing.T) {
 		}
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -795,10 +858,7 @@ func TestListTransactionsByBusiness(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	r := gin.Default()
 	tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 
@@ -879,6 +939,7 @@ func TestListTransactionsByBusiness(t *testing.T) {
 }
 func TestListByBusinessFromMondayToThursday(t *testing.T) {
 	logger := tst.Setup()
+	fake := fakes.New()
 	gin.SetMode(gin.TestMode)
 	validatorRef := validator.New()
 	db := postgresql.Connection()
@@ -897,27 +958,33 @@ func TestListByBusinessFromMondayToThursday(t *testing.T) {
 		}
 	)
 
-	auth_mocks.User = &testUser
-	auth_mocks.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
 		Status:  true,
 		Message: "authorized",
 		Data:    testUser,
 	}
-	auth_mocks.UserProfile = &external_models.UserProfile{
+	fake.Auth.UserProfile = &external_models.UserProfile{
 		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		AccountID: int(testUser.AccountID),
 		Country:   "NG",
 		Currency:  "NGN",
 	}
 
-	auth_mocks.Country = &external_models.Country{
+	fake.Auth.Country = &external_models.Country{
 		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		Name:         "nigeria",
 		CountryCode:  "NG",
 		CurrencyCode: "NGN",
 	}
 
-	auth_mocks.BusinessCharge = &external_models.BusinessCharge{
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
 		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
 		BusinessId:          int(testUser.AccountID),
 		Country:             "NG",
@@ -930,10 +997,7 @@ func TestListByBusinessFromMondayToThursday(t *testing.T) {
 		ProcessingFeeMode:   "fixed",
 	}
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 	r := gin.Default()
 	tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
 
@@ -1011,3 +1075,72 @@ func TestListByBusinessFromMondayToThursday(t *testing.T) {
 	}
 
 }
+
+func TestListTransactionsServices(t *testing.T) {
+	var (
+		fake       = fakes.New()
+		logger     = utility.NewLogger()
+		extReq     = fake.ExternalRequest(logger)
+		repo       = memory.New().Repositories()
+		buyer      = external_models.User{ID: 2, AccountID: 2}
+		milestone  = utility.RandomString(20)
+		oneoff     = utility.RandomString(20)
+		archivedAt = time.Now()
+		fields     = models.AllTransactionFields()
+	)
+	fake.Auth.User = &buyer
+	repo.Transactions.Create(&models.Transaction{TransactionID: milestone, PartiesID: milestone, MilestoneID: utility.RandomString(20), Type: "milestone", Title: "milestone;first", Amount: 1000, BusinessID: 1, Status: tsvc.GetTransactionStatus("ip")})
+	repo.Transactions.Create(&models.Transaction{TransactionID: milestone, PartiesID: milestone, MilestoneID: utility.RandomString(20), Type: "milestone", Title: "milestone;second", Amount: 500, BusinessID: 1, Status: tsvc.GetTransactionStatus("draft")})
+	repo.Transactions.Create(&models.Transaction{TransactionID: oneoff, PartiesID: oneoff, MilestoneID: utility.RandomString(20), Type: "oneoff", Title: "oneoff", Amount: 200, BusinessID: 1, Status: tsvc.GetTransactionStatus("draft")})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: milestone, TransactionPartiesID: milestone, AccountID: 1, Role: "seller"})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: milestone, TransactionPartiesID: milestone, AccountID: 2, Role: "buyer"})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: oneoff, TransactionPartiesID: oneoff, AccountID: 2, Role: "buyer", ArchivedAt: &archivedAt})
+
+	t.Run("by id", func(t *testing.T) {
+		transaction, code, err := tsvc.ListTransactionsByIDService(extReq, logger, repo, milestone)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected transaction, got %v, %v", code, err)
+		}
+		if len(transaction.Milestones) != 2 || len(transaction.Parties) != 2 || transaction.Title != "milestone" {
+			t.Errorf("unexpected transaction %+v", transaction)
+		}
+	})
+
+	t.Run("by missing id", func(t *testing.T) {
+		_, code, err := tsvc.ListTransactionsByIDService(extReq, logger, repo, "missing")
+		if err == nil || code != http.StatusBadRequest {
+			t.Errorf("expected bad request, got %v, %v", code, err)
+		}
+	})
+
+	t.Run("by business", func(t *testing.T) {
+		transactions, _, code, err := tsvc.ListTransactionsService(extReq, logger, repo, models.ListTransactionsRequest{BusinessID: 1}, postgresql.Pagination{}, fields)
+		if err != nil || code != http.StatusOK || len(transactions) != 2 {
+			t.Fatalf("expected both transactions, got %v, %v, %v", transactions, code, err)
+		}
+		if transactions[0].TransactionID != oneoff {
+			t.Errorf("expected the newest transaction first, got %v", transactions[0].TransactionID)
+		}
+	})
+
+	t.Run("by business and status", func(t *testing.T) {
+		transactions, _, code, err := tsvc.ListTransactionsService(extReq, logger, repo, models.ListTransactionsRequest{BusinessID: 1, StatusCode: "ip"}, postgresql.Pagination{}, fields)
+		if err != nil || code != http.StatusOK || len(transactions) != 1 || transactions[0].TransactionID != milestone {
+			t.Errorf("expected the transaction in progress, got %v, %v, %v", transactions, code, err)
+		}
+	})
+
+	t.Run("by user", func(t *testing.T) {
+		transactions, _, code, err := tsvc.ListTransactionsByUserService(extReq, logger, repo, models.ListTransactionByUserRequest{Role: "buyer"}, postgresql.Pagination{}, buyer, fields)
+		if err != nil || code != http.StatusOK || len(transactions) != 1 || transactions[0].TransactionID != milestone {
+			t.Errorf("expected the transaction the buyer has not archived, got %v, %v, %v", transactions, code, err)
+		}
+	})
+
+	t.Run("archived by user", func(t *testing.T) {
+		transactions, _, code, err := tsvc.ListArchivedTransactionsService(extReq, logger, repo, postgresql.Pagination{}, buyer, fields)
+		if err != nil || code != http.StatusOK || len(transactions) != 1 || transactions[0].TransactionID != oneoff {
+			t.Errorf("expected the archived transaction, got %v, %v, %v", transactions, code, err)
+		}
+	})
+}
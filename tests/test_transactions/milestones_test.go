@@ -0,0 +1,400 @@
+package test_transactions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofrs/uuid"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	tsvc "github.com/vesicash/transactions-ms/services/transactions"
+	tst "github.com/vesicash/transactions-ms/tests"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func TestMilestoneLifecycle(t *testing.T) {
+	logger := tst.Setup()
+	fake := fakes.New()
+	gin.SetMode(gin.TestMode)
+	validatorRef := validator.New()
+	app := config.GetConfig().App
+	db := postgresql.Connection()
+	var (
+		muuid, _  = uuid.NewV4()
+		accountID = uint(utility.GetRandomNumbersInRange(1000000000, 9999999999))
+		testUser  = external_models.User{
+			ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+			AccountID:    accountID,
+			EmailAddress: fmt.Sprintf("testuser%v@qa.team", muuid.String()),
+			PhoneNumber:  fmt.Sprintf("+234%v", utility.GetRandomNumbersInRange(7000000000, 9099999999)),
+			AccountType:  "individual",
+			Firstname:    "test",
+			Lastname:     "user",
+			Username:     fmt.Sprintf("test_username%v", muuid.String()),
+		}
+		token, _ = uuid.NewV4()
+	)
+
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+		Status:  true,
+		Message: "authorized",
+		Data:    testUser,
+	}
+	fake.Auth.UserProfile = &external_models.UserProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+
+	fake.Auth.Country = &external_models.Country{
+		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		Name:         "nigeria",
+		CountryCode:  "NG",
+		CurrencyCode: "NGN",
+	}
+
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
+		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		BusinessId:          int(testUser.AccountID),
+		Country:             "NG",
+		Currency:            "NGN",
+		BusinessCharge:      "0",
+		VesicashCharge:      "2.5",
+		ProcessingFee:       "0",
+		PaymentGateway:      "rave",
+		DisbursementGateway: "rave_momo",
+		ProcessingFeeMode:   "fixed",
+	}
+
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
+	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
+	r := gin.Default()
+
+	var (
+		authHeaders = map[string]string{
+			"Content-Type":  "application/json",
+			"Authorization": "Bearer " + token.String(),
+		}
+		appHeaders = map[string]string{
+			"Content-Type": "application/json",
+			"v-app":        app.Key,
+		}
+		milestoneReq = models.MilestoneRequest{
+			TransactionID: transaction.TransactionID,
+			MilestoneID:   transaction.MilestoneID,
+		}
+	)
+
+	// the cases run in order and walk a single milestone through its lifecycle
+	tests := []struct {
+		Name           string
+		Method         string
+		Path           string
+		RequestBody    interface{}
+		ExpectedCode   int
+		ExpectedStatus string
+		Headers        map[string]string
+		Message        string
+	}{
+		{
+			Name:         "deliver unfunded milestone",
+			Method:       http.MethodPost,
+			Path:         "/v2/milestone/delivered",
+			RequestBody:  milestoneReq,
+			ExpectedCode: http.StatusBadRequest,
+			Headers:      authHeaders,
+		},
+		{
+			Name:   "OK fund milestone",
+			Method: http.MethodPatch,
+			Path:   "/v2/milestone/fund",
+			RequestBody: models.FundMilestoneRequest{
+				TransactionID: transaction.TransactionID,
+				MilestoneID:   transaction.MilestoneID,
+				Amount:        transaction.Amount + transaction.ShippingFee,
+				Action:        "+",
+			},
+			ExpectedCode:   http.StatusOK,
+			ExpectedStatus: tsvc.GetTransactionStatus("af"),
+			Message:        "Milestone funding updated",
+			Headers:        appHeaders,
+		},
+		{
+			Name:           "OK deliver milestone",
+			Method:         http.MethodPost,
+			Path:           "/v2/milestone/delivered",
+			RequestBody:    milestoneReq,
+			ExpectedCode:   http.StatusOK,
+			ExpectedStatus: tsvc.GetTransactionStatus("d"),
+			Message:        "Milestone Delivered",
+			Headers:        authHeaders,
+		},
+		{
+			Name:           "OK accept milestone",
+			Method:         http.MethodPost,
+			Path:           "/v2/milestone/accept",
+			RequestBody:    milestoneReq,
+			ExpectedCode:   http.StatusOK,
+			ExpectedStatus: tsvc.GetTransactionStatus("cdc"),
+			Message:        "Milestone Delivery Accepted",
+			Headers:        authHeaders,
+		},
+		{
+			Name:   "reject released milestone",
+			Method: http.MethodPost,
+			Path:   "/v2/milestone/reject",
+			RequestBody: models.RejectMilestoneRequest{
+				TransactionID: transaction.TransactionID,
+				MilestoneID:   transaction.MilestoneID,
+				Reason:        "late",
+			},
+			ExpectedCode: http.StatusBadRequest,
+			Headers:      authHeaders,
+		},
+		{
+			Name:         "empty request",
+			Method:       http.MethodPost,
+			Path:         "/v2/milestone/accept",
+			RequestBody:  models.MilestoneRequest{},
+			ExpectedCode: http.StatusBadRequest,
+			Headers:      authHeaders,
+		},
+	}
+
+	transactionsAuthUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.AuthType))
+	{
+		transactionsAuthUrl.POST("/milestone/delivered", trans.MilestoneDelivered)
+		transactionsAuthUrl.POST("/milestone/accept", trans.AcceptMilestone)
+		transactionsAuthUrl.POST("/milestone/reject", trans.RejectMilestone)
+	}
+
+	transactionsAppUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.AppType))
+	{
+		transactionsAppUrl.PATCH("/milestone/fund", trans.FundMilestone)
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			var b bytes.Buffer
+			json.NewEncoder(&b).Encode(test.RequestBody)
+			URI := url.URL{Path: test.Path}
+
+			req, err := http.NewRequest(test.Method, URI.String(), &b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, v := range test.Headers {
+				req.Header.Set(i, v)
+			}
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			tst.AssertStatusCode(t, rr.Code, test.ExpectedCode)
+
+			data := tst.ParseResponse(rr)
+
+			code := int(data["code"].(float64))
+			tst.AssertStatusCode(t, code, test.ExpectedCode)
+
+			if test.Message != "" {
+				message := data["message"]
+				if message != nil {
+					tst.AssertResponseMessage(t, message.(string), test.Message)
+				} else {
+					tst.AssertResponseMessage(t, "", test.Message)
+				}
+
+			}
+
+			if test.ExpectedStatus != "" {
+				milestone := data["data"].(map[string]interface{})
+				tst.AssertResponseMessage(t, milestone["status"].(string), test.ExpectedStatus)
+			}
+
+		})
+
+	}
+
+}
+
+func TestFundMilestoneWithBrokerCharge(t *testing.T) {
+	logger := tst.Setup()
+	fake := fakes.New()
+	gin.SetMode(gin.TestMode)
+	validatorRef := validator.New()
+	app := config.GetConfig().App
+	db := postgresql.Connection()
+	var (
+		muuid, _  = uuid.NewV4()
+		accountID = uint(utility.GetRandomNumbersInRange(1000000000, 9999999999))
+		testUser  = external_models.User{
+			ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+			AccountID:    accountID,
+			EmailAddress: fmt.Sprintf("testuser%v@qa.team", muuid.String()),
+			PhoneNumber:  fmt.Sprintf("+234%v", utility.GetRandomNumbersInRange(7000000000, 9099999999)),
+			AccountType:  "individual",
+			Firstname:    "test",
+			Lastname:     "user",
+			Username:     fmt.Sprintf("test_username%v", muuid.String()),
+		}
+	)
+
+	fake.Auth.User = &testUser
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+		Status:  true,
+		Message: "authorized",
+		Data:    testUser,
+	}
+	fake.Auth.UserProfile = &external_models.UserProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+
+	fake.Auth.Country = &external_models.Country{
+		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		Name:         "nigeria",
+		CountryCode:  "NG",
+		CurrencyCode: "NGN",
+	}
+
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
+		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		BusinessId:          int(testUser.AccountID),
+		Country:             "NG",
+		Currency:            "NGN",
+		BusinessCharge:      "0",
+		VesicashCharge:      "2.5",
+		ProcessingFee:       "0",
+		PaymentGateway:      "rave",
+		DisbursementGateway: "rave_momo",
+		ProcessingFeeMode:   "fixed",
+	}
+
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
+	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
+	r := gin.Default()
+
+	broker := models.TransactionBroker{
+		TransactionBrokerID: utility.RandomString(20),
+		TransactionID:       transaction.TransactionID,
+		BrokerCharge:        "50",
+		BrokerChargeBearer:  "buyer",
+		BrokerChargeType:    "fixed",
+	}
+	err := broker.CreateTransactionBroker(db.Transaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		headers = map[string]string{
+			"Content-Type": "application/json",
+			"v-app":        app.Key,
+		}
+		fundReq = models.FundMilestoneRequest{
+			TransactionID: transaction.TransactionID,
+			MilestoneID:   transaction.MilestoneID,
+			Amount:        transaction.Amount,
+			Action:        "+",
+		}
+	)
+
+	tests := []struct {
+		Name         string
+		Before       func(t *testing.T)
+		RequestBody  models.FundMilestoneRequest
+		ExpectedCode int
+		Headers      map[string]string
+		Message      string
+	}{
+		{
+			Name:         "OK broker charge not accepted",
+			RequestBody:  fundReq,
+			ExpectedCode: http.StatusOK,
+			Message:      "Milestone funding updated",
+			Headers:      headers,
+		},
+		{
+			Name: "OK broker charge accepted",
+			Before: func(t *testing.T) {
+				broker.IsBuyerAccepted, broker.IsSellerAccepted = true, true
+				if err := broker.UpdateAllFields(db.Transaction); err != nil {
+					t.Fatal(err)
+				}
+			},
+			RequestBody:  fundReq,
+			ExpectedCode: http.StatusOK,
+			Message:      "Milestone funding updated",
+			Headers:      headers,
+		},
+	}
+
+	transactionsAppUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.AppType))
+	{
+		transactionsAppUrl.PATCH("/milestone/fund", trans.FundMilestone)
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.Before != nil {
+				test.Before(t)
+			}
+
+			var b bytes.Buffer
+			json.NewEncoder(&b).Encode(test.RequestBody)
+			URI := url.URL{Path: "/v2/milestone/fund"}
+
+			req, err := http.NewRequest(http.MethodPatch, URI.String(), &b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, v := range test.Headers {
+				req.Header.Set(i, v)
+			}
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			tst.AssertStatusCode(t, rr.Code, test.ExpectedCode)
+
+			data := tst.ParseResponse(rr)
+
+			code := int(data["code"].(float64))
+			tst.AssertStatusCode(t, code, test.ExpectedCode)
+
+			if test.Message != "" {
+				message := data["message"]
+				if message != nil {
+					tst.AssertResponseMessage(t, message.(string), test.Message)
+				} else {
+					tst.AssertResponseMessage(t, "", test.Message)
+				}
+
+			}
+
+		})
+
+	}
+
+}
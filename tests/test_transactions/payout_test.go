@@ -0,0 +1,293 @@
+package test_transactions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofrs/uuid"
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
+	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/gormrepo"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	tsvc "github.com/vesicash/transactions-ms/services/transactions"
+	tst "github.com/vesicash/transactions-ms/tests"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func TestPayoutPreview(t *testing.T) {
+	logger := tst.Setup()
+	fake := fakes.New()
+	gin.SetMode(gin.TestMode)
+	validatorRef := validator.New()
+	db := postgresql.Connection()
+	var (
+		muuid, _  = uuid.NewV4()
+		accountID = uint(utility.GetRandomNumbersInRange(1000000000, 9999999999))
+		testUser  = external_models.User{
+			ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+			AccountID:    accountID,
+			EmailAddress: fmt.Sprintf("testuser%v@qa.team", muuid.String()),
+			PhoneNumber:  fmt.Sprintf("+234%v", utility.GetRandomNumbersInRange(7000000000, 9099999999)),
+			AccountType:  "individual",
+			Firstname:    "test",
+			Lastname:     "user",
+			Username:     fmt.Sprintf("test_username%v", muuid.String()),
+		}
+		token, _ = uuid.NewV4()
+	)
+
+	fake.Auth.User = &testUser
+	fake.Auth.BusinessProfile = &external_models.BusinessProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+	fake.Auth.ValidateAuthorizationRes = &external_models.ValidateAuthorizationDataModel{
+		Status:  true,
+		Message: "authorized",
+		Data:    testUser,
+	}
+	fake.Auth.UserProfile = &external_models.UserProfile{
+		ID:        uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		AccountID: int(testUser.AccountID),
+		Country:   "NG",
+		Currency:  "NGN",
+	}
+
+	fake.Auth.Country = &external_models.Country{
+		ID:           uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		Name:         "nigeria",
+		CountryCode:  "NG",
+		CurrencyCode: "NGN",
+	}
+
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{
+		ID:                  uint(utility.GetRandomNumbersInRange(1000000000, 9999999999)),
+		BusinessId:          int(testUser.AccountID),
+		Country:             "NG",
+		Currency:            "NGN",
+		BusinessCharge:      "0",
+		VesicashCharge:      "2.5",
+		ProcessingFee:       "0",
+		PaymentGateway:      "rave",
+		DisbursementGateway: "rave_momo",
+		ProcessingFeeMode:   "fixed",
+	}
+
+	trans := transactions.Controller{Db: db, Repo: gormrepo.New(db.Transaction), Validator: validatorRef, Logger: logger, ExtReq: fake.ExternalRequest(logger)}
+	transaction := tst.CreateTransactionUser(t, db, validatorRef, trans.ExtReq, int(testUser.AccountID), false)
+	r := gin.Default()
+
+	var (
+		headers = map[string]string{
+			"Content-Type":  "application/json",
+			"Authorization": "Bearer " + token.String(),
+		}
+		draftMilestone = func(title string, shippingFee float64) models.MileStone {
+			return models.MileStone{
+				Title:            title,
+				Amount:           1000,
+				InspectionPeriod: 4,
+				DueDate:          "2023-03-16",
+				Status:           "draft",
+				Quantity:         1,
+				ShippingFee:      shippingFee,
+				GracePeriod:      "2023-03-18",
+				Recipients: []models.MileStoneRecipient{
+					{AccountID: utility.GetRandomNumbersInRange(1000000000, 9999999999), Amount: 600},
+					{AccountID: utility.GetRandomNumbersInRange(1000000000, 9999999999), Amount: 400},
+				},
+			}
+		}
+		draft = models.CreateTransactionRequest{
+			BusinessID: int(testUser.AccountID),
+			Parties: []models.Party{
+				{AccountID: int(testUser.AccountID), Role: "buyer", AccessLevel: models.PartyAccessLevel{CanView: true}},
+				{AccountID: int(testUser.AccountID), Role: "seller", AccessLevel: models.PartyAccessLevel{CanView: true}},
+			},
+			Title:        "test title",
+			Type:         "milestone",
+			EscrowWallet: "yes",
+			Milestones:   []models.MileStone{draftMilestone("first", 100), draftMilestone("second", 0)},
+			Amount:       2000,
+			Currency:     "NGN",
+			Source:       "transfer",
+		}
+	)
+
+	tests := []struct {
+		Name               string
+		RequestBody        models.PayoutPreviewRequest
+		ExpectedCode       int
+		ExpectedBalanced   bool
+		ExpectedBuyerTotal float64
+		Headers            map[string]string
+		Message            string
+	}{
+		{
+			Name:             "OK preview stored transaction with unallocated amounts",
+			RequestBody:      models.PayoutPreviewRequest{TransactionID: transaction.TransactionID},
+			ExpectedCode:     http.StatusOK,
+			ExpectedBalanced: false,
+			Message:          "successful",
+			Headers:          headers,
+		},
+		{
+			Name: "OK preview draft with seller borne broker charge",
+			RequestBody: models.PayoutPreviewRequest{
+				Draft:              &draft,
+				BrokerCharge:       "10",
+				BrokerChargeType:   "percentage",
+				BrokerChargeBearer: "seller",
+			},
+			ExpectedCode:       http.StatusOK,
+			ExpectedBalanced:   true,
+			ExpectedBuyerTotal: 2102,
+			Message:            "successful",
+			Headers:            headers,
+		},
+		{
+			Name: "invalid broker charge type",
+			RequestBody: models.PayoutPreviewRequest{
+				Draft:            &draft,
+				BrokerChargeType: "flat",
+			},
+			ExpectedCode: http.StatusBadRequest,
+			Headers:      headers,
+		},
+		{
+			Name:         "empty request",
+			RequestBody:  models.PayoutPreviewRequest{},
+			ExpectedCode: http.StatusBadRequest,
+			Message:      "either transaction_id or draft is required",
+			Headers:      headers,
+		},
+	}
+
+	transactionsAuthUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.AuthType))
+	{
+		transactionsAuthUrl.POST("/payout/preview", trans.PayoutPreview)
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+
+			var b bytes.Buffer
+			json.NewEncoder(&b).Encode(test.RequestBody)
+			URI := url.URL{Path: "/v2/payout/preview"}
+
+			req, err := http.NewRequest(http.MethodPost, URI.String(), &b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, v := range test.Headers {
+				req.Header.Set(i, v)
+			}
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			tst.AssertStatusCode(t, rr.Code, test.ExpectedCode)
+
+			data := tst.ParseResponse(rr)
+
+			code := int(data["code"].(float64))
+			tst.AssertStatusCode(t, code, test.ExpectedCode)
+
+			if test.Message != "" {
+				message := data["message"]
+				if message != nil {
+					tst.AssertResponseMessage(t, message.(string), test.Message)
+				} else {
+					tst.AssertResponseMessage(t, "", test.Message)
+				}
+
+			}
+
+			if test.ExpectedCode == http.StatusOK {
+				preview := data["data"].(map[string]interface{})
+				tst.AssertBool(t, preview["balanced"].(bool), test.ExpectedBalanced)
+				if test.ExpectedBuyerTotal != 0 && preview["buyer_total"].(float64) != test.ExpectedBuyerTotal {
+					t.Errorf("handler returned wrong buyer total: got %v expected %v", preview["buyer_total"], test.ExpectedBuyerTotal)
+				}
+			}
+
+		})
+
+	}
+
+}
+
+func TestPayoutPreviewServices(t *testing.T) {
+	var (
+		fake          = fakes.New()
+		logger        = utility.NewLogger()
+		extReq        = fake.ExternalRequest(logger)
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+		buyer         = external_models.User{ID: 1, AccountID: 1}
+		stranger      = external_models.User{ID: 3, AccountID: 3}
+	)
+	fake.Auth.BusinessCharge = &external_models.BusinessCharge{ID: 1, BusinessId: 1, Country: "NG", Currency: "NGN", BusinessCharge: "0", VesicashCharge: "2.5", ProcessingFee: "0"}
+	repo.Transactions.Create(&models.Transaction{TransactionID: transactionID, PartiesID: transactionID, MilestoneID: utility.RandomString(20), Type: "oneoff", Amount: 1000, Currency: "NGN", EscrowCharge: 25, Recipients: `[{"account_id":2,"amount":1000}]`})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 1, Role: "buyer"})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 2, Role: "seller"})
+
+	t.Run("stored transaction", func(t *testing.T) {
+		preview, code, err := tsvc.PayoutPreviewService(extReq, logger, repo, models.PayoutPreviewRequest{TransactionID: transactionID}, buyer)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected preview, got %v, %v", code, err)
+		}
+		if preview.TransactionID != transactionID || preview.MilestonesAmount != 1000 || preview.EscrowCharge != 25 || len(preview.Milestones) != 1 {
+			t.Errorf("unexpected preview %+v", preview)
+		}
+	})
+
+	t.Run("stored transaction by non party", func(t *testing.T) {
+		_, code, err := tsvc.PayoutPreviewService(extReq, logger, repo, models.PayoutPreviewRequest{TransactionID: transactionID}, stranger)
+		if err == nil || code != http.StatusForbidden {
+			t.Errorf("expected forbidden, got %v, %v", code, err)
+		}
+	})
+
+	t.Run("draft", func(t *testing.T) {
+		draft := models.CreateTransactionRequest{
+			BusinessID: 1,
+			Parties: []models.Party{
+				{AccountID: 1, Role: "buyer", Status: "draft", AccessLevel: models.PartyAccessLevel{CanView: true, Approve: true}},
+				{AccountID: 2, Role: "seller", Status: "draft", AccessLevel: models.PartyAccessLevel{CanView: true, CanReceive: true}},
+			},
+			Type:       "oneoff",
+			Amount:     2000,
+			Currency:   "NGN",
+			Milestones: []models.MileStone{{Title: "only", Amount: 2000, InspectionPeriod: 4, DueDate: "2023-03-16", Recipients: []models.MileStoneRecipient{{AccountID: 2, Amount: 2000}}}},
+		}
+		preview, code, err := tsvc.PayoutPreviewService(extReq, logger, repo, models.PayoutPreviewRequest{Draft: &draft}, buyer)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected preview, got %v, %v", code, err)
+		}
+		if preview.MilestonesAmount != 2000 || preview.EscrowCharge != 50 || !preview.Balanced {
+			t.Errorf("unexpected preview %+v", preview)
+		}
+	})
+
+	t.Run("neither transaction nor draft", func(t *testing.T) {
+		_, code, err := tsvc.PayoutPreviewService(extReq, logger, repo, models.PayoutPreviewRequest{}, buyer)
+		if err == nil || code != http.StatusBadRequest {
+			t.Errorf("expected bad request, got %v, %v", code, err)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package test_transactions
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
+	tsvc "github.com/vesicash/transactions-ms/services/transactions"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func TestAuthorizePartyAction(t *testing.T) {
+	var (
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+		buyer         = 1
+		seller        = 2
+		viewOnly      = 3
+		stranger      = 4
+		broker        = 5
+	)
+	parties := []models.TransactionParty{
+		{AccountID: buyer, Role: "buyer", RoleCapabilities: map[string]interface{}{"can_view": true, "approve": true}},
+		{AccountID: seller, Role: "seller", RoleCapabilities: map[string]interface{}{"can_view": true, "mark_as_done": true, "approve": false}},
+		{AccountID: viewOnly, Role: "seller", RoleCapabilities: map[string]interface{}{"can_view": true, "mark_as_done": false}},
+		{AccountID: broker, Role: "broker", RoleCapabilities: map[string]interface{}{"can_view": true}},
+	}
+	for _, party := range parties {
+		party.TransactionID, party.TransactionPartiesID = transactionID, transactionID
+		if err := repo.Parties.Create(&party); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		Name      string
+		AccountID int
+		Action    tsvc.Action
+		Code      int
+	}{
+		{Name: "buyer views", AccountID: buyer, Action: tsvc.ActionView, Code: http.StatusOK},
+		{Name: "stranger views", AccountID: stranger, Action: tsvc.ActionView, Code: http.StatusForbidden},
+		{Name: "seller delivers", AccountID: seller, Action: tsvc.ActionDeliver, Code: http.StatusOK},
+		{Name: "buyer delivers", AccountID: buyer, Action: tsvc.ActionDeliver, Code: http.StatusForbidden},
+		{Name: "seller without mark as done delivers", AccountID: viewOnly, Action: tsvc.ActionDeliver, Code: http.StatusForbidden},
+		{Name: "buyer approves release", AccountID: buyer, Action: tsvc.ActionApproveRelease, Code: http.StatusOK},
+		{Name: "seller approves release", AccountID: seller, Action: tsvc.ActionApproveRelease, Code: http.StatusForbidden},
+		{Name: "seller accepts", AccountID: seller, Action: tsvc.ActionAccept, Code: http.StatusOK},
+		{Name: "seller disputes", AccountID: seller, Action: tsvc.ActionDispute, Code: http.StatusOK},
+		{Name: "stranger disputes", AccountID: stranger, Action: tsvc.ActionDispute, Code: http.StatusForbidden},
+		{Name: "seller requests extension", AccountID: seller, Action: tsvc.ActionRequestExtension, Code: http.StatusOK},
+		{Name: "buyer requests extension", AccountID: buyer, Action: tsvc.ActionRequestExtension, Code: http.StatusForbidden},
+		{Name: "buyer approves extension", AccountID: buyer, Action: tsvc.ActionApproveExtension, Code: http.StatusOK},
+		{Name: "seller edits", AccountID: seller, Action: tsvc.ActionEdit, Code: http.StatusOK},
+		{Name: "broker edits", AccountID: broker, Action: tsvc.ActionEdit, Code: http.StatusForbidden},
+		{Name: "stranger edits", AccountID: stranger, Action: tsvc.ActionEdit, Code: http.StatusForbidden},
+		{Name: "buyer deletes", AccountID: buyer, Action: tsvc.ActionDelete, Code: http.StatusOK},
+		{Name: "broker deletes", AccountID: broker, Action: tsvc.ActionDelete, Code: http.StatusForbidden},
+		{Name: "stranger deletes", AccountID: stranger, Action: tsvc.ActionDelete, Code: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code, err := tsvc.AuthorizePartyAction(repo, transactionID, test.AccountID, test.Action)
+			if code != test.Code {
+				t.Errorf("expected status %v, got %v (%v)", test.Code, code, err)
+			}
+			if (err == nil) != (test.Code == http.StatusOK) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeEditDelete(t *testing.T) {
+	var (
+		fake          = fakes.New()
+		logger        = utility.NewLogger()
+		extReq        = fake.ExternalRequest(logger)
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+		stranger      = external_models.User{ID: 2, AccountID: 2}
+		broker        = external_models.User{ID: 3, AccountID: 3}
+	)
+	repo.Transactions.Create(&models.Transaction{TransactionID: transactionID, PartiesID: transactionID, Title: "title"})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 1, Role: "buyer"})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 3, Role: "broker"})
+
+	for _, user := range []external_models.User{stranger, broker} {
+		_, code, err := tsvc.EditTransactionService(extReq, logger, repo, models.EditTransactionRequest{TransactionID: transactionID, Title: "changed"}, user)
+		if err == nil || code != http.StatusForbidden {
+			t.Errorf("expected account %v to be forbidden from editing, got %v, %v", user.AccountID, code, err)
+		}
+		code, err = tsvc.DeleteTransactionService(extReq, logger, repo, transactionID, user)
+		if err == nil || code != http.StatusForbidden {
+			t.Errorf("expected account %v to be forbidden from deleting, got %v, %v", user.AccountID, code, err)
+		}
+	}
+
+	transaction, _, err := repo.Transactions.GetByTransactionID(transactionID)
+	if err != nil || transaction.Title != "title" {
+		t.Errorf("expected the transaction to be left as it was, got %+v, %v", transaction, err)
+	}
+}
+
+func TestAuthorizeBusinessTransaction(t *testing.T) {
+	var (
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+	)
+	transaction := models.Transaction{TransactionID: transactionID, BusinessID: 7}
+	if err := repo.Transactions.Create(&transaction); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name          string
+		TransactionID string
+		BusinessID    int
+		Code          int
+	}{
+		{Name: "owning business", TransactionID: transactionID, BusinessID: 7, Code: http.StatusOK},
+		{Name: "other business", TransactionID: transactionID, BusinessID: 8, Code: http.StatusForbidden},
+		{Name: "unknown transaction", TransactionID: "unknown", BusinessID: 7, Code: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code, err := tsvc.AuthorizeBusinessTransaction(repo, test.TransactionID, test.BusinessID)
+			if code != test.Code {
+				t.Errorf("expected status %v, got %v (%v)", test.Code, code, err)
+			}
+		})
+	}
+}
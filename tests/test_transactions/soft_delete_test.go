@@ -0,0 +1,157 @@
+package test_transactions
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/config"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
+	tsvc "github.com/vesicash/transactions-ms/services/transactions"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func TestSoftDeleteServices(t *testing.T) {
+	config.Config = &config.Configuration{Retention: config.Retention{DeletedTransactionDays: 30}}
+	var (
+		fake          = fakes.New()
+		logger        = utility.NewLogger()
+		extReq        = fake.ExternalRequest(logger)
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+		user          = external_models.User{ID: 1, AccountID: 1}
+		inProgress    = tsvc.GetTransactionStatus("ip")
+	)
+	for _, milestoneID := range []string{"first", "second"} {
+		repo.Transactions.Create(&models.Transaction{TransactionID: transactionID, MilestoneID: milestoneID, PartiesID: transactionID, Status: inProgress})
+	}
+	repo.Parties.Create(&models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 1, Role: "buyer"})
+	repo.Brokers.Create(&models.TransactionBroker{TransactionID: transactionID})
+	other := utility.RandomString(20)
+	repo.Transactions.Create(&models.Transaction{TransactionID: other, PartiesID: other, Status: inProgress})
+
+	t.Run("delete", func(t *testing.T) {
+		code, err := tsvc.DeleteTransactionService(extReq, logger, repo, transactionID, user)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected the transaction to be deleted, got %v, %v", code, err)
+		}
+		if _, _, err := repo.Transactions.GetByTransactionID(transactionID); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("expected deleted milestones to be left out, got %v", err)
+		}
+		if parties, _ := repo.Parties.ListByTransactionID(transactionID); len(parties) != 0 {
+			t.Errorf("expected deleted parties to be left out, got %+v", parties)
+		}
+		if _, _, err := repo.Brokers.GetByTransactionID(transactionID); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("expected the deleted broker to be left out, got %v", err)
+		}
+		if _, _, err := repo.Transactions.GetByTransactionID(other); err != nil {
+			t.Errorf("expected other transactions to be kept, got %v", err)
+		}
+	})
+
+	t.Run("restore by a stranger", func(t *testing.T) {
+		stranger := external_models.User{ID: 2, AccountID: 2}
+		code, err := tsvc.RestoreTransactionService(extReq, logger, repo, transactionID, stranger)
+		if err == nil || code != http.StatusForbidden {
+			t.Fatalf("expected a stranger to be forbidden from restoring, got %v, %v", code, err)
+		}
+		if _, _, err := repo.Transactions.GetByTransactionID(transactionID); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("expected the transaction to stay deleted, got %v", err)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		code, err := tsvc.RestoreTransactionService(extReq, logger, repo, transactionID, user)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected the transaction to be restored, got %v, %v", code, err)
+		}
+		milestones, _ := repo.Transactions.ListByTransactionID(transactionID)
+		if len(milestones) != 2 || milestones[0].MilestoneID != "first" || milestones[0].DeletedAt.Valid {
+			t.Errorf("expected both milestones back in order, got %+v", milestones)
+		}
+		if parties, _ := repo.Parties.ListByTransactionID(transactionID); len(parties) != 1 {
+			t.Errorf("expected the party back, got %+v", parties)
+		}
+		states, _ := repo.States.ListByTransactionID(transactionID)
+		if len(states) != 2 || states[1].Status != inProgress {
+			t.Errorf("expected a deleted then an in progress state, got %+v", states)
+		}
+	})
+
+	t.Run("restore a transaction that is not deleted", func(t *testing.T) {
+		code, err := tsvc.RestoreTransactionService(extReq, logger, repo, transactionID, user)
+		if err == nil || code != http.StatusBadRequest {
+			t.Errorf("expected bad request, got %v, %v", code, err)
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		tsvc.DeleteTransactionService(extReq, logger, repo, transactionID, user)
+		if purged, _ := repo.Transactions.PurgeDeleted(time.Now().Add(-time.Hour)); purged != 0 {
+			t.Errorf("expected nothing deleted an hour ago to be purged, got %v", purged)
+		}
+		if purged, _ := repo.Transactions.PurgeDeleted(time.Now().Add(time.Hour)); purged != 2 {
+			t.Errorf("expected both milestones to be purged, got %v", purged)
+		}
+		code, err := tsvc.RestoreTransactionService(extReq, logger, repo, transactionID, user)
+		if err == nil || code != http.StatusBadRequest {
+			t.Errorf("expected a purged transaction not to be restorable, got %v, %v", code, err)
+		}
+	})
+}
+
+func TestSoftDeleteArchive(t *testing.T) {
+	var (
+		fake          = fakes.New()
+		logger        = utility.NewLogger()
+		extReq        = fake.ExternalRequest(logger)
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+		buyer         = external_models.User{ID: 1, AccountID: 1}
+		stranger      = external_models.User{ID: 2, AccountID: 2}
+	)
+	repo.Transactions.Create(&models.Transaction{TransactionID: transactionID, PartiesID: transactionID})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 1, Role: "buyer"})
+	repo.Parties.Create(&models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 3, Role: "seller"})
+
+	archivedBy := func() map[int]bool {
+		archived := map[int]bool{}
+		parties, _ := repo.Parties.ListByTransactionID(transactionID)
+		for _, party := range parties {
+			archived[party.AccountID] = party.ArchivedAt != nil
+		}
+		return archived
+	}
+
+	t.Run("archive by non party", func(t *testing.T) {
+		code, err := tsvc.ArchiveTransactionService(extReq, logger, repo, transactionID, stranger, true)
+		if err == nil || code != http.StatusForbidden {
+			t.Errorf("expected forbidden, got %v, %v", code, err)
+		}
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		code, err := tsvc.ArchiveTransactionService(extReq, logger, repo, transactionID, buyer, true)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected the transaction to be archived, got %v, %v", code, err)
+		}
+		if archived := archivedBy(); !archived[1] || archived[3] {
+			t.Errorf("expected only the buyer's party to be archived, got %v", archived)
+		}
+	})
+
+	t.Run("unarchive", func(t *testing.T) {
+		code, err := tsvc.ArchiveTransactionService(extReq, logger, repo, transactionID, buyer, false)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected the transaction to be unarchived, got %v, %v", code, err)
+		}
+		if archived := archivedBy(); archived[1] {
+			t.Errorf("expected the buyer's party to be unarchived, got %v", archived)
+		}
+	})
+}
@@ -11,22 +11,23 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/vesicash/transactions-ms/external/request"
+	"github.com/vesicash/transactions-ms/external/fakes"
 	"github.com/vesicash/transactions-ms/internal/config"
 	"github.com/vesicash/transactions-ms/internal/models"
 	"github.com/vesicash/transactions-ms/pkg/controller/transactions"
 	"github.com/vesicash/transactions-ms/pkg/middleware"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
 	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
 	tst "github.com/vesicash/transactions-ms/tests"
 	"github.com/vesicash/transactions-ms/utility"
 )
 
-func TestValidateOnDb(t *testing.T) {
-	logger := tst.Setup()
-	app := config.GetConfig().App
+func TestValidateChecks(t *testing.T) {
+	logger := utility.NewLogger()
+	fake := fakes.New()
+	config.Config = &config.Configuration{App: config.App{Key: "app-key"}}
 	gin.SetMode(gin.TestMode)
-	validatorRef := validator.New()
-	db := postgresql.Connection()
+	repo := memory.New().Repositories()
 
 	transaction := models.Transaction{
 		TransactionID: utility.RandomString(20),
@@ -34,149 +35,105 @@ func TestValidateOnDb(t *testing.T) {
 		MilestoneID:   utility.RandomString(20),
 		Title:         "test transaction;milestone title;2000;1",
 		Type:          "milestone",
-		Description:   "description",
 		Amount:        2000,
-		ShippingFee:   10,
-		AmountPaid:    0,
-		EscrowCharge:  10,
-		EscrowWallet:  "yes",
+		TransUssdCode: 12345,
 	}
-	err := transaction.CreateTransaction(db.Transaction)
-	if err != nil {
-		panic("error creating transaction: " + err.Error())
+	if err := repo.Transactions.Create(&transaction); err != nil {
+		t.Fatal(err)
+	}
+	party := models.TransactionParty{TransactionID: transaction.TransactionID, TransactionPartiesID: transaction.PartiesID, AccountID: 7, Role: "buyer"}
+	if err := repo.Parties.Create(&party); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Rates.Create(&models.Rate{FromCurrency: "NGN", ToCurrency: "USD", Amount: 0.0013}); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(name string, params map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"check": name, "params": params}
+	}
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"v-app":        "app-key",
 	}
 
 	tests := []struct {
-		Name              string
-		RequestBody       models.ValidateOnDBReq
-		ExpectedCode      int
-		Headers           map[string]string
-		Message           string
-		CheckResponseData bool
-		Response          bool
+		Name         string
+		RequestBody  interface{}
+		ExpectedCode int
+		Headers      map[string]string
+		Valid        bool
+		Results      []bool
 	}{
 		{
-			Name: "OK exists validate on db with value",
-			RequestBody: models.ValidateOnDBReq{
-				Table: "transactions",
-				Type:  "exists",
-				Query: "transaction_id = ?",
-				Value: transaction.TransactionID,
-			},
+			Name:         "transaction exists",
+			RequestBody:  map[string]interface{}{"checks": []interface{}{check("transaction_exists", map[string]interface{}{"transaction_id": transaction.TransactionID})}},
 			ExpectedCode: http.StatusOK,
-			Message:      "successful",
-			Headers: map[string]string{
-				"Content-Type": "application/json",
-				"v-app":        app.Key,
-			},
-			CheckResponseData: true,
-			Response:          true,
+			Headers:      headers,
+			Valid:        true,
+			Results:      []bool{true},
 		}, {
-			Name: "OK exists validate on db without value",
-			RequestBody: models.ValidateOnDBReq{
-				Table: "transactions",
-				Type:  "exists",
-				Query: "transaction_id = '" + transaction.TransactionID + "'",
-			},
+			Name: "batch with a failing check",
+			RequestBody: map[string]interface{}{"checks": []interface{}{
+				check("milestone_exists", map[string]interface{}{"transaction_id": transaction.TransactionID, "milestone_id": transaction.MilestoneID}),
+				check("party_exists_for_account", map[string]interface{}{"transaction_id": transaction.TransactionID, "account_id": 7}),
+				check("party_exists_for_role", map[string]interface{}{"transaction_id": transaction.TransactionID, "role": "seller"}),
+				check("ussd_code_unused", map[string]interface{}{"ussd_code": 12345}),
+				check("ussd_code_unused", map[string]interface{}{"ussd_code": 54321}),
+				check("rate_exists", map[string]interface{}{"from_currency": "ngn", "to_currency": "usd"}),
+				check("dispute_exists", map[string]interface{}{"transaction_id": transaction.TransactionID}),
+			}},
 			ExpectedCode: http.StatusOK,
-			Message:      "successful",
-			Headers: map[string]string{
-				"Content-Type": "application/json",
-				"v-app":        app.Key,
-			},
-			CheckResponseData: true,
-			Response:          true,
+			Headers:      headers,
+			Valid:        false,
+			Results:      []bool{true, true, false, false, true, true, false},
 		}, {
-			Name: "OK notexists validate on db with value",
-			RequestBody: models.ValidateOnDBReq{
-				Table: "transactions",
-				Type:  "notexists",
-				Query: "transaction_id = ?",
-				Value: utility.RandomString(20) + "not",
-			},
-			ExpectedCode: http.StatusOK,
-			Message:      "successful",
-			Headers: map[string]string{
-				"Content-Type": "application/json",
-				"v-app":        app.Key,
-			},
-			CheckResponseData: true,
-			Response:          true,
-		}, {
-			Name: "OK exists validate on db without value",
-			RequestBody: models.ValidateOnDBReq{
-				Table: "transactions",
-				Type:  "notexists",
-				Query: "transaction_id = '" + transaction.TransactionID + "'",
-			},
-			ExpectedCode: http.StatusOK,
-			Message:      "successful",
-			Headers: map[string]string{
-				"Content-Type": "application/json",
-				"v-app":        app.Key,
-			},
-			CheckResponseData: true,
-			Response:          false,
+			Name:         "unknown check",
+			RequestBody:  map[string]interface{}{"checks": []interface{}{check("table_row_exists", map[string]interface{}{"table": "transactions"})}},
+			ExpectedCode: http.StatusBadRequest,
+			Headers:      headers,
 		}, {
-			Name: "table omitted",
-			RequestBody: models.ValidateOnDBReq{
-				Type:  "notexists",
-				Query: "transaction_id = '" + transaction.TransactionID + "'",
-			},
+			Name:         "raw query is not accepted",
+			RequestBody:  map[string]interface{}{"checks": []interface{}{check("transaction_exists", map[string]interface{}{"transaction_id": "x", "query": "1 = 1"})}},
 			ExpectedCode: http.StatusBadRequest,
-			Headers: map[string]string{
-				"Content-Type": "application/json",
-				"v-app":        app.Key,
-			},
+			Headers:      headers,
 		}, {
-			Name: "type omitted",
-			RequestBody: models.ValidateOnDBReq{
-				Table: "transactions",
-				Query: "transaction_id = '" + transaction.TransactionID + "'",
-			},
+			Name:         "missing params",
+			RequestBody:  map[string]interface{}{"checks": []interface{}{check("transaction_exists", nil)}},
 			ExpectedCode: http.StatusBadRequest,
-			Headers: map[string]string{
-				"Content-Type": "application/json",
-				"v-app":        app.Key,
-			},
+			Headers:      headers,
 		}, {
-			Name: "query omitted",
-			RequestBody: models.ValidateOnDBReq{
-				Table: "transactions",
-				Type:  "notexists",
-			},
+			Name:         "wrongly typed params",
+			RequestBody:  map[string]interface{}{"checks": []interface{}{check("ussd_code_unused", map[string]interface{}{"ussd_code": "12345; drop table transactions"})}},
 			ExpectedCode: http.StatusBadRequest,
-			Headers: map[string]string{
-				"Content-Type": "application/json",
-				"v-app":        app.Key,
-			},
+			Headers:      headers,
 		}, {
-			Name:         "no input",
+			Name:         "no checks",
+			RequestBody:  map[string]interface{}{"checks": []interface{}{}},
 			ExpectedCode: http.StatusBadRequest,
-			Headers: map[string]string{
-				"Content-Type": "application/json",
-				"v-app":        app.Key,
-			},
+			Headers:      headers,
+		}, {
+			Name:         "no app key",
+			RequestBody:  map[string]interface{}{"checks": []interface{}{check("transaction_exists", map[string]interface{}{"transaction_id": transaction.TransactionID})}},
+			ExpectedCode: http.StatusUnauthorized,
+			Headers:      map[string]string{"Content-Type": "application/json"},
 		},
 	}
 
-	r := gin.Default()
+	r := gin.New()
 
-	trans := transactions.Controller{Db: db, Validator: validatorRef, Logger: logger, ExtReq: request.ExternalRequest{
-		Logger: logger,
-		Test:   true,
-	}}
+	trans := transactions.Controller{Db: postgresql.Databases{}, Repo: repo, Validator: validator.New(), Logger: logger, ExtReq: fake.ExternalRequest(logger)}
 
-	transactionsAppUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(db, trans.ExtReq, middleware.AppType))
+	transactionsAppUrl := r.Group(fmt.Sprintf("%v", "v2"), middleware.Authorize(trans.Db, trans.ExtReq, middleware.AppType))
 	{
-		transactionsAppUrl.POST("/validate_on_db", trans.ValidateOnDB)
+		transactionsAppUrl.POST("/validate", trans.Traced((*transactions.Controller).ValidateChecks))
 	}
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
 			var b bytes.Buffer
 			json.NewEncoder(&b).Encode(test.RequestBody)
-			URI := url.URL{Path: "/v2/validate_on_db"}
+			URI := url.URL{Path: "/v2/validate"}
 
 			req, err := http.NewRequest(http.MethodPost, URI.String(), &b)
 			if err != nil {
@@ -191,29 +148,25 @@ func TestValidateOnDb(t *testing.T) {
 			r.ServeHTTP(rr, req)
 
 			tst.AssertStatusCode(t, rr.Code, test.ExpectedCode)
-
-			data := tst.ParseResponse(rr)
-
-			code := int(data["code"].(float64))
-			tst.AssertStatusCode(t, code, test.ExpectedCode)
-
-			if test.CheckResponseData {
-				resData := data["data"].(bool)
-				tst.AssertBool(t, resData, test.Response)
+			if test.ExpectedCode != http.StatusOK {
+				return
 			}
 
-			if test.Message != "" {
-				message := data["message"]
-				if message != nil {
-					tst.AssertResponseMessage(t, message.(string), test.Message)
-				} else {
-					tst.AssertResponseMessage(t, "", test.Message)
+			var res struct {
+				Data models.ValidateChecksResponse `json:"data"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+			tst.AssertBool(t, res.Data.Valid, test.Valid)
+			if len(res.Data.Results) != len(test.Results) {
+				t.Fatalf("expected %v results, got %+v", len(test.Results), res.Data.Results)
+			}
+			for i, result := range res.Data.Results {
+				if result.Valid != test.Results[i] {
+					t.Errorf("check %v (%v): expected %v, got %v", i, result.Check, test.Results[i], result.Valid)
 				}
-
 			}
-
 		})
-
 	}
-
 }
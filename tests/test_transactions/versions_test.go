@@ -0,0 +1,266 @@
+package test_transactions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/internal/models"
+	"github.com/vesicash/transactions-ms/pkg/repository"
+	"github.com/vesicash/transactions-ms/pkg/repository/memory"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	tsvc "github.com/vesicash/transactions-ms/services/transactions"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+func TestVersionConflicts(t *testing.T) {
+	var (
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+	)
+	repo.Transactions.Create(&models.Transaction{TransactionID: transactionID, MilestoneID: transactionID, Status: tsvc.GetTransactionStatus("ip")})
+
+	first, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+	second, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+
+	first.AmountPaid = 500
+	if err := repo.Transactions.Update(&first); err != nil {
+		t.Fatalf("expected the first update to succeed, got %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("expected version 2 after the update, got %v", first.Version)
+	}
+
+	second.Status = tsvc.GetTransactionStatus("d")
+	if err := repo.Transactions.Update(&second); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("expected a conflict for the stale update, got %v", err)
+	}
+	stored, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+	if stored.Status != tsvc.GetTransactionStatus("ip") || stored.AmountPaid != 500 {
+		t.Errorf("expected the stale update to be dropped, got %+v", stored)
+	}
+}
+
+func TestVersionRetry(t *testing.T) {
+	var (
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+		inProgress    = tsvc.GetTransactionStatus("ip")
+		delivered     = tsvc.GetTransactionStatus("d")
+	)
+	repo.Transactions.Create(&models.Transaction{TransactionID: transactionID, MilestoneID: transactionID, Status: inProgress})
+	moveOn := func(tx *models.Transaction) bool {
+		if tx.Status != inProgress {
+			return false
+		}
+		tx.Status = delivered
+		return true
+	}
+
+	t.Run("reloads after a conflict", func(t *testing.T) {
+		stale, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+		concurrent, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+		concurrent.AmountPaid = 750
+		repo.Transactions.Update(&concurrent)
+
+		updated, err := tsvc.UpdateTransactionWithRetry(repo, &stale, moveOn)
+		if err != nil || !updated {
+			t.Fatalf("expected the retry to succeed, got %v, %v", updated, err)
+		}
+		stored, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+		if stored.Status != delivered || stored.AmountPaid != 750 {
+			t.Errorf("expected both changes to be kept, got %+v", stored)
+		}
+	})
+
+	t.Run("skips rows moved on by someone else", func(t *testing.T) {
+		otherID := utility.RandomString(20)
+		repo.Transactions.Create(&models.Transaction{TransactionID: otherID, MilestoneID: otherID, Status: inProgress})
+		stale, _, _ := repo.Transactions.GetByTransactionID(otherID)
+		concurrent, _, _ := repo.Transactions.GetByTransactionID(otherID)
+		concurrent.Status = tsvc.GetTransactionStatus("cd")
+		repo.Transactions.Update(&concurrent)
+
+		updated, err := tsvc.UpdateTransactionWithRetry(repo, &stale, moveOn)
+		if err != nil || updated {
+			t.Errorf("expected no update, got %v, %v", updated, err)
+		}
+		stored, _, _ := repo.Transactions.GetByTransactionID(otherID)
+		if stored.Status != concurrent.Status {
+			t.Errorf("expected status %q to be kept, got %q", concurrent.Status, stored.Status)
+		}
+	})
+}
+
+func TestVersionETag(t *testing.T) {
+	var (
+		repo          = memory.New().Repositories()
+		transactionID = utility.RandomString(20)
+	)
+	if _, _, err := tsvc.TransactionETag(repo, transactionID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected not found for a missing transaction, got %v", err)
+	}
+
+	repo.Transactions.Create(&models.Transaction{TransactionID: transactionID, PartiesID: transactionID})
+	party := models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 1, Role: "buyer"}
+	repo.Parties.Create(&party)
+
+	before, _, err := tsvc.TransactionETag(repo, transactionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _, _ := tsvc.TransactionETag(repo, transactionID)
+	if before != again {
+		t.Errorf("expected a stable etag, got %v and %v", before, again)
+	}
+
+	party.Status = "accepted"
+	repo.Parties.Update(&party)
+	after, _, _ := tsvc.TransactionETag(repo, transactionID)
+	if after == before {
+		t.Errorf("expected the etag to change after a party update")
+	}
+
+	tests := []struct {
+		IfMatch string
+		Matches bool
+	}{
+		{IfMatch: after, Matches: true},
+		{IfMatch: "*", Matches: true},
+		{IfMatch: before + ", " + after, Matches: true},
+		{IfMatch: before, Matches: false},
+	}
+	for _, test := range tests {
+		if got := tsvc.ETagMatches(test.IfMatch, after); got != test.Matches {
+			t.Errorf("ETagMatches(%q) = %v, expected %v", test.IfMatch, got, test.Matches)
+		}
+	}
+}
+
+func TestVersionExpected(t *testing.T) {
+	var (
+		store         = memory.New()
+		transactionID = utility.RandomString(20)
+		inProgress    = tsvc.GetTransactionStatus("ip")
+	)
+	store.Repositories().Transactions.Create(&models.Transaction{TransactionID: transactionID, MilestoneID: transactionID, Status: inProgress})
+
+	pin := func(t *testing.T) repository.Repositories {
+		versions, _, err := tsvc.TransactionVersions(store.Repositories(), transactionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := postgresql.NewExpectedVersions()
+		for _, version := range versions {
+			expected.Expect(version.Key, version.Version)
+		}
+		return store.Repositories().WithContext(postgresql.WithExpectedVersions(context.Background(), expected))
+	}
+
+	t.Run("saves made after the check keep their pins", func(t *testing.T) {
+		repo := pin(t)
+		transaction, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+		transaction.AmountPaid = 100
+		if err := repo.Transactions.Update(&transaction); err != nil {
+			t.Fatalf("expected the first save to succeed, got %v", err)
+		}
+		transaction.AmountPaid = 200
+		if err := repo.Transactions.Update(&transaction); err != nil {
+			t.Fatalf("expected a second save by the same request to succeed, got %v", err)
+		}
+	})
+
+	t.Run("a change made after the check fails the save", func(t *testing.T) {
+		repo := pin(t)
+		concurrent, _, _ := store.Repositories().Transactions.GetByTransactionID(transactionID)
+		concurrent.AmountPaid = 750
+		store.Repositories().Transactions.Update(&concurrent)
+
+		transaction, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+		updated, err := tsvc.UpdateTransactionWithRetry(repo, &transaction, func(tx *models.Transaction) bool {
+			tx.Status = tsvc.GetTransactionStatus("d")
+			return true
+		})
+		if updated || !errors.Is(err, repository.ErrPreconditionFailed) {
+			t.Fatalf("expected the save to fail its precondition, got %v, %v", updated, err)
+		}
+		stored, _, _ := store.Repositories().Transactions.GetByTransactionID(transactionID)
+		if stored.Status != inProgress || stored.AmountPaid != 750 {
+			t.Errorf("expected the concurrent change to be kept, got %+v", stored)
+		}
+	})
+}
+
+// racingTransactions applies race to a transaction, as another writer would, right before the
+// first update made through it.
+type racingTransactions struct {
+	repository.TransactionRepository
+	race  func(*models.Transaction)
+	raced bool
+}
+
+func (r *racingTransactions) Update(transaction *models.Transaction) error {
+	if !r.raced {
+		r.raced = true
+		concurrent, _, _ := r.TransactionRepository.GetByTransactionIDAndMilestoneID(transaction.TransactionID, transaction.MilestoneID)
+		r.race(&concurrent)
+		if err := r.TransactionRepository.Update(&concurrent); err != nil {
+			return err
+		}
+	}
+	return r.TransactionRepository.Update(transaction)
+}
+
+func TestVersionStatusMove(t *testing.T) {
+	var (
+		fake       = fakes.New()
+		logger     = utility.NewLogger()
+		extReq     = fake.ExternalRequest(logger)
+		seller     = external_models.User{ID: 2, AccountID: 2}
+		inProgress = tsvc.GetTransactionStatus("ip")
+		closed     = tsvc.GetTransactionStatus("closed")
+	)
+	tests := []struct {
+		Name           string
+		Race           func(*models.Transaction)
+		ExpectedCode   int
+		ExpectedStatus string
+	}{
+		{
+			Name:           "an unrelated change is kept",
+			Race:           func(tx *models.Transaction) { tx.AmountPaid = 750 },
+			ExpectedCode:   http.StatusOK,
+			ExpectedStatus: tsvc.GetTransactionStatus("d"),
+		},
+		{
+			Name:           "a status moved on first is kept",
+			Race:           func(tx *models.Transaction) { tx.Status = closed },
+			ExpectedCode:   http.StatusConflict,
+			ExpectedStatus: closed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				repo          = memory.New().Repositories()
+				transactionID = utility.RandomString(20)
+			)
+			repo.Transactions.Create(&models.Transaction{TransactionID: transactionID, MilestoneID: transactionID, PartiesID: transactionID, Status: inProgress})
+			repo.Parties.Create(&models.TransactionParty{TransactionID: transactionID, TransactionPartiesID: transactionID, AccountID: 2, Role: "seller", RoleCapabilities: map[string]interface{}{"mark_as_done": true}})
+			repo.Transactions = &racingTransactions{TransactionRepository: repo.Transactions, race: test.Race}
+
+			code, err := tsvc.TransactionDeliveredService(extReq, logger, repo, models.TransactionDeliveredRequest{TransactionID: transactionID, MilestoneID: transactionID}, seller)
+			if code != test.ExpectedCode {
+				t.Errorf("expected code %v, got %v, %v", test.ExpectedCode, code, err)
+			}
+			stored, _, _ := repo.Transactions.GetByTransactionID(transactionID)
+			if stored.Status != test.ExpectedStatus {
+				t.Errorf("expected status %q, got %q", test.ExpectedStatus, stored.Status)
+			}
+		})
+	}
+}
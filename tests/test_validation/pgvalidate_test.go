@@ -0,0 +1,121 @@
+package test_validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/vesicash/transactions-ms/external/external_models"
+	"github.com/vesicash/transactions-ms/external/fakes"
+	"github.com/vesicash/transactions-ms/pkg/repository/storage/postgresql"
+	"github.com/vesicash/transactions-ms/utility"
+)
+
+type countingResolver struct {
+	rows  map[string]bool
+	calls int
+}
+
+func (r *countingResolver) Exists(target postgresql.ValidationTarget, value interface{}) (bool, error) {
+	r.calls++
+	return r.rows[fmt.Sprintf("%v.%v=%v", target.Table, target.Column, value)], nil
+}
+
+type item struct {
+	TransactionID string `pgvalidate:"exists=transaction$transactions$transaction_id"`
+	Email         string `pgvalidate:"email"`
+}
+
+type request struct {
+	TransactionID string `pgvalidate:"exists=transaction$transactions$transaction_id"`
+	UssdCode      int    `pgvalidate:"notexists=transaction$transactions$trans_ussd_code"`
+	AccountID     int    `pgvalidate:"exists=auth$users$account_id"`
+	BusinessID    int    `pgvalidate:"exists=auth$business_profiles$account_id"`
+	Items         []item
+	Skipped       string
+}
+
+func TestValidateRequest(t *testing.T) {
+	logger := utility.NewLogger()
+	fake := fakes.New()
+	fake.Auth.User = &external_models.User{ID: 1, AccountID: 7}
+	extReq := fake.ExternalRequest(logger)
+
+	resolver := &countingResolver{rows: map[string]bool{
+		"transactions.transaction_id=tr-1":  true,
+		"transactions.trans_ussd_code=1234": true,
+	}}
+	vr := postgresql.ValidateRequestM{
+		Logger:    logger,
+		Auth:      extReq.Auth,
+		Resolvers: map[string]postgresql.ExistenceResolver{"transaction": resolver},
+	}
+
+	t.Run("valid request", func(t *testing.T) {
+		fake.Auth.BusinessProfile = &external_models.BusinessProfile{ID: 3, AccountID: 7}
+		defer func() { fake.Auth.BusinessProfile = nil }()
+
+		err := vr.ValidateRequest(request{TransactionID: "tr-1", AccountID: 7, BusinessID: 7, Items: []item{{TransactionID: "tr-1"}}})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("every failing field is reported", func(t *testing.T) {
+		err := vr.ValidateRequest(request{
+			TransactionID: "tr-2",
+			UssdCode:      1234,
+			AccountID:     7,
+			BusinessID:    7,
+			Items:         []item{{TransactionID: "tr-1"}, {TransactionID: "tr-3", Email: "not-an-email"}},
+		})
+		errs, ok := err.(postgresql.ValidationErrors)
+		if !ok {
+			t.Fatalf("expected ValidationErrors, got %v", err)
+		}
+
+		fields := map[string]bool{}
+		for _, e := range errs {
+			fields[e.Field] = true
+		}
+		for _, field := range []string{"TransactionID", "UssdCode", "BusinessID", "Items[1].TransactionID", "Items[1].Email"} {
+			if !fields[field] {
+				t.Errorf("expected %v to be reported, got %+v", field, errs)
+			}
+		}
+		if len(errs) != 5 {
+			t.Errorf("expected 5 errors, got %+v", errs)
+		}
+
+		body, _ := json.Marshal(utility.BuildErrorResponse(400, "error", err.Error(), err, nil))
+		var res struct {
+			Error []postgresql.ValidationError `json:"error"`
+		}
+		if err := json.Unmarshal(body, &res); err != nil || len(res.Error) != 5 {
+			t.Errorf("expected the response to list the fields, got %s", body)
+		}
+	})
+
+	t.Run("lookups are cached within a request", func(t *testing.T) {
+		resolver.calls = 0
+		items := []item{{TransactionID: "tr-1"}, {TransactionID: "tr-1"}, {TransactionID: "tr-1"}}
+		vr.ValidateRequest(request{TransactionID: "tr-1", Items: items})
+		if resolver.calls != 1 {
+			t.Errorf("expected one lookup, got %v", resolver.calls)
+		}
+
+		vr.ValidateRequest(request{TransactionID: "tr-1"})
+		if resolver.calls != 2 {
+			t.Errorf("expected a new request to look up again, got %v calls", resolver.calls)
+		}
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		err := vr.ValidateRequest(struct {
+			ID int `pgvalidate:"exists=payment$payments$id"`
+		}{ID: 1})
+		if err == nil {
+			t.Error("expected an error for a source without a resolver")
+		}
+	})
+}
@@ -145,6 +145,13 @@ func (l *Logger) Fatal(arg0 interface{}, args ...interface{}) {
 	os.Exit(1)
 }
 
+// Close flushes buffered records and closes the log writers
+func (l *Logger) Close() {
+	if l.logger != nil {
+		l.logger.Close()
+	}
+}
+
 // Audit : log information on api request and response
 func (l *Logger) Audit(record *AuditLog) {
 	js, _ := json.Marshal(record)
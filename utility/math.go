@@ -1,5 +1,12 @@
 package utility
 
+import "math"
+
 func PercentageOf(actualNumber, percentage float64) float64 {
 	return (percentage / 100) * actualNumber
 }
+
+func RoundFloat(value float64, places int) float64 {
+	shift := math.Pow(10, float64(places))
+	return math.Round(value*shift) / shift
+}